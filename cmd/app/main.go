@@ -1,19 +1,53 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/AntonRadchenko/WebPet1/internal/attachmentService"
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore"
+	"github.com/AntonRadchenko/WebPet1/internal/commentService"
+	"github.com/AntonRadchenko/WebPet1/internal/config"
 	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"github.com/AntonRadchenko/WebPet1/internal/encryption"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/idempotency"
+	"github.com/AntonRadchenko/WebPet1/internal/mailer"
+	"github.com/AntonRadchenko/WebPet1/internal/oidc"
+	"github.com/AntonRadchenko/WebPet1/internal/ratelimit"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
 	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/AntonRadchenko/WebPet1/internal/webhookService"
+	"github.com/AntonRadchenko/WebPet1/internal/workspaceService"
+    "github.com/AntonRadchenko/WebPet1/internal/web/attachments"
+    webaudit "github.com/AntonRadchenko/WebPet1/internal/web/audit"
+    "github.com/AntonRadchenko/WebPet1/internal/web/auth"
+    "github.com/AntonRadchenko/WebPet1/internal/web/authn"
+    "github.com/AntonRadchenko/WebPet1/internal/web/authz"
+    "github.com/AntonRadchenko/WebPet1/internal/web/comments"
+    "github.com/AntonRadchenko/WebPet1/internal/web/custommethod"
+    "github.com/AntonRadchenko/WebPet1/internal/web/requestid"
+    "github.com/AntonRadchenko/WebPet1/internal/web/sse"
+    "github.com/AntonRadchenko/WebPet1/internal/web/ws"
     "github.com/AntonRadchenko/WebPet1/internal/web/tasks"
     "github.com/AntonRadchenko/WebPet1/internal/web/users" // users пакет // users API
+    "github.com/AntonRadchenko/WebPet1/internal/web/webhooks"
+    "github.com/AntonRadchenko/WebPet1/internal/web/workspaces"
 )
 
 // 5. верхний слой (все связывается вместе)
 
 func main() {
+	// читаем конфигурацию из окружения
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
 	// инициализируем бд
 	db.InitDB()
 
@@ -26,24 +60,255 @@ func main() {
 	usersRepo := &userService.UserRepo{}
 	usersSevice := userService.NewUserService(usersRepo)
 
-	// создаём handlers (TaskHandler и UserHandler)
+	// журнал аудита (пишут repo задач и пользователей, читают история задачи и админский поиск)
+	auditService := audit.NewAuditService(&audit.AuditRepo{})
+	tasksService.WithHistory(auditService)
+
+	// комментарии к задачам (права - как у задачи, поэтому через tasksService);
+	// число комментариев у задач в ответах считается одним запросом на список
+	commentsRepo := &commentService.CommentRepo{}
+	commentsService := commentService.NewCommentService(commentsRepo, tasksService)
+	tasksService.WithCommentCounts(commentsRepo)
+	usersSevice.WithCommentCounts(commentsRepo)
+
+	// вложения задач: метаданные в бд, содержимое - в каталоге или S3 (из конфига)
+	blobStore, err := newBlobStore(cfg.Attachments)
+	if err != nil {
+		log.Fatalf("Could not init attachments storage: %v", err)
+	}
+	attachmentsService := attachmentService.NewAttachmentService(&attachmentService.AttachmentRepo{}, blobStore, tasksService, cfg.Attachments.MaxSize)
+	tasksService.WithCleanup(attachmentsService) // задачи удаляются из бд насовсем - вместе с ними удаляем и файлы
+	usersSevice.WithTaskCleanup(attachmentsService) // и задачи удаленного пользователя (их удаляет каскад в бд)
+
+	// политика паролей (длина и классы символов - из конфига)
+	passwordPolicy := userService.DefaultPasswordPolicy()
+	passwordPolicy.MinLength = cfg.Password.MinLength
+	passwordPolicy.MinClasses = cfg.Password.MinClasses
+	passwordPolicy.CheckCommon = cfg.Password.CheckCommon
+	if passwordPolicy.CheckCommon {
+		if err := userService.LoadCommonPasswords(); err != nil {
+			log.Fatalf("Could not load common passwords list: %v", err)
+		}
+	}
+	usersSevice.WithPasswordPolicy(passwordPolicy)
+
+	// журнал входов и блокировка после серии неудачных попыток (пороги - из конфига)
+	usersSevice.WithAuthEvents(&userService.AuthEventRepo{}).
+		WithLockoutPolicy(userService.LockoutPolicy{
+			MaxFailures:  cfg.Lockout.MaxFailures,
+			BaseDuration: cfg.Lockout.BaseDuration,
+			MaxDuration:  cfg.Lockout.MaxDuration,
+		})
+
+	// отправка писем (способ - из конфига)
+	mail := newMailer(cfg.Mailer)
+
+	// auth-слои (сброс пароля и подтверждение email по токенам из писем)
+	authSvc := authService.NewAuthService(usersSevice, &authService.PasswordResetRepo{}, &authService.EmailVerificationRepo{},
+		&authService.SessionRepo{}, &authService.PersonalTokenRepo{}, mail).
+		WithPasswordReset(cfg.Auth.PasswordResetURL, cfg.Auth.PasswordResetTTL).
+		WithEmailVerification(cfg.Auth.VerifyEmailURL, cfg.Auth.VerifyEmailTTL)
+
+	// вход и сессии (без JWT_SECRET секрет случайный - после перезапуска всем придется войти заново)
+	if cfg.Auth.JWTSecret == "" {
+		log.Println("JWT_SECRET is not set, using a random secret (sessions will not survive a restart)")
+	}
+	authSvc.WithTokens([]byte(cfg.Auth.JWTSecret), cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+
+	// вход через внешний OIDC-провайдер (если задан OIDC_ISSUER)
+	if cfg.OIDC.Enabled() {
+		oidcClient := oidc.NewClient(oidc.Config{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+		}).WithHTTPClient(&http.Client{Timeout: 10 * time.Second})
+		authSvc.WithOIDC(oidcClient, &authService.OIDCStateRepo{}, &authService.IdentityRepo{})
+	}
+
+	// двухфакторная аутентификация: без TOTP_ENCRYPTION_KEY подключить TOTP нельзя,
+	// но у тех, кто уже подключил, второй шаг входа остается (кодами восстановления)
+	var totpCipher *encryption.Cipher
+	if len(cfg.TwoFactor.EncryptionKey) > 0 {
+		totpCipher, err = encryption.NewCipher(cfg.TwoFactor.EncryptionKey)
+		if err != nil {
+			log.Fatalf("Could not init TOTP encryption: %v", err)
+		}
+	} else {
+		log.Println("TOTP_ENCRYPTION_KEY is not set, enrolling in two-factor authentication is disabled")
+	}
+	authSvc.WithTwoFactor(totpCipher, cfg.TwoFactor.Issuer, &authService.TwoFactorRepo{}, &authService.MFAChallengeRepo{})
+
+	// смена пароля и удаление пользователя отзывают его сессии
+	usersSevice.WithSessionRevoker(authSvc)
+
+	// после регистрации и смены email пользователю уходит ссылка для подтверждения
+	usersSevice.WithEmailVerifier(authSvc)
+
+	// рабочие пространства: участники видят задачи пространства, новых зовут приглашением на email
+	workspacesService := workspaceService.NewWorkspaceService(&workspaceService.WorkspaceRepo{}, usersSevice, mail).
+		WithInvitations(cfg.Workspaces.InviteURL, cfg.Workspaces.InviteTTL)
+	tasksService.WithWorkspaces(workspacesService)
+
+	// события об изменении задач для GET /events: шина внутри процесса, а между экземплярами -
+	// Postgres LISTEN/NOTIFY (если включено в конфиге)
+	eventBus := events.NewBus(cfg.Events.ReplaySize)
+	var eventPublisher events.Publisher = eventBus
+	if cfg.Events.Broker == config.EventsPostgres {
+		notifier := events.NewPGNotifier(db.DB, eventBus)
+		go notifier.Listen(context.Background())
+		eventPublisher = notifier
+	}
+	tasksService.WithEvents(eventPublisher)
+
+	// исходящие вебхуки: события пишутся в очередь в бд, фоновый цикл отправляет их с повторами;
+	// без WEBHOOK_ENCRYPTION_KEY секреты подписи хранить негде - все ручки /webhooks отвечают 503
+	var webhookCipher *encryption.Cipher
+	if len(cfg.Webhooks.EncryptionKey) > 0 {
+		webhookCipher, err = encryption.NewCipher(cfg.Webhooks.EncryptionKey)
+		if err != nil {
+			log.Fatalf("Could not init webhook encryption: %v", err)
+		}
+	} else {
+		log.Println("WEBHOOK_ENCRYPTION_KEY is not set, webhooks are disabled")
+	}
+	webhooksService := webhookService.NewWebhookService(&webhookService.WebhookRepo{}, webhookCipher).
+		WithVisibility(tasksService).
+		WithDelivery(cfg.Webhooks.MaxAttempts, cfg.Webhooks.Timeout)
+	if webhookCipher != nil {
+		tasksRepo.WithOutbox(webhooksService) // доставки пишутся той же транзакцией, что меняет задачу
+		go webhooksService.RunDeliveries(context.Background(), cfg.Webhooks.PollInterval)
+	}
+
+	// без подтвержденного email задачи создавать нельзя (если включено в конфиге)
+	if cfg.Auth.RequireVerifiedEmail {
+		tasksService.WithVerifiedEmailRequired(usersSevice)
+	}
+
+	// создаём handlers (TaskHandler, UserHandler и AuthHandler)
 	taskHandler := tasks.NewTaskHandler(tasksService)
 	userHandler := users.NewUserHandler(usersSevice)
+	authHandler := auth.NewAuthHandler(authSvc)
+	auditHandler := webaudit.NewAuditHandler(auditService)
+	commentHandler := comments.NewCommentHandler(commentsService)
+	attachmentHandler := attachments.NewAttachmentHandler(attachmentsService)
+	workspaceHandler := workspaces.NewWorkspaceHandler(workspacesService)
+	webhookHandler := webhooks.NewWebhookHandler(webhooksService)
+	// поток событий держим не дольше access-токена: после переподключения права проверяются заново
+	eventsHandler := sse.NewHandler(eventBus, tasksService).WithTimeouts(sse.DefaultHeartbeat, cfg.Auth.AccessTokenTTL)
+	// WebSocket для доски: те же события плюс команды toggle_done и reorder (через tasksService)
+	wsHandler := ws.NewHandler(eventBus, tasksService, usersSevice).WithTimeouts(ws.DefaultPingPeriod, cfg.Auth.AccessTokenTTL)
 
 	// оборачиваем API-хендлеры в strict-server 
     strictTaskHandler := tasks.NewStrictHandler(taskHandler, nil)
     strictUserHandler := users.NewStrictHandler(userHandler, nil)
+	strictAuthHandler := auth.NewStrictHandler(authHandler, nil)
+	strictAuditHandler := webaudit.NewStrictHandler(auditHandler, nil)
+	strictCommentHandler := comments.NewStrictHandler(commentHandler, nil)
+	strictAttachmentHandler := attachments.NewStrictHandler(attachmentHandler, nil)
+	strictWorkspaceHandler := workspaces.NewStrictHandler(workspaceHandler, nil)
+	strictWebhookHandler := webhooks.NewStrictHandler(webhookHandler, nil)
+
+	// middleware для Idempotency-Key (повторные POST не создают дубликаты)
+	// ключи разных пользователей не пересекаются
+	// ответ POST /webhooks содержит секрет подписи - его в бд открытым текстом не храним
+	idempotencyMiddleware := idempotency.NewMiddleware(&idempotency.IdempotencyRepo{}, idempotency.DefaultTTL).WithUser(authn.UserID).
+		WithoutStorage("POST /webhooks")
+	go idempotencyMiddleware.RunPurge(context.Background(), idempotency.DefaultPurgeInterval)
+
+	// проверка access-токена (Authorization: Bearer ...)
+	authMiddleware := authn.NewMiddleware(authSvc)
+
+	// какие маршруты открыты, какие требуют входа, а какие - роли admin;
+	// персональные токены пускаются только на маршруты со скоупом
+	authzMiddleware := authz.NewMiddleware(authz.DefaultRules()).WithScopes(authz.DefaultScopes())
+
+	// ограничение частоты запросов (лимиты по маршрутам - из конфига)
+	// для вошедших пользователей лимит считается еще и по пользователю
+	rateLimiter := ratelimit.NewMiddleware(ratelimit.NewMemoryStore(), cfg.RateLimit).WithUser(authn.UserID)
 
 	// создаём наш router
 	mux := http.NewServeMux()
 
-	// регистрируем OpenAPI маршруты в mux
-	tasks.HandlerFromMux(strictTaskHandler, mux)
-	users.HandlerFromMux(strictUserHandler, mux)
+	// регистрируем OpenAPI маршруты в mux (вместе с middleware вокруг strict-хендлеров)
+	// последняя middleware в списке - самая внешняя: сначала лимит неудачных входов по IP (до проверки токена,
+	// иначе перебор токенов ничем не ограничен), потом проверяем токен, потом лимитер (уже знает пользователя),
+	// потом права на маршрут
+	tasks.HandlerWithOptions(strictTaskHandler, tasks.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []tasks.MiddlewareFunc{idempotencyMiddleware.Handler, authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	users.HandlerWithOptions(strictUserHandler, users.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []users.MiddlewareFunc{idempotencyMiddleware.Handler, authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	auth.HandlerWithOptions(strictAuthHandler, auth.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []auth.MiddlewareFunc{authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	comments.HandlerWithOptions(strictCommentHandler, comments.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []comments.MiddlewareFunc{idempotencyMiddleware.Handler, authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	// без idempotency: она читает тело запроса в память целиком, а файлы должны идти в хранилище потоком
+	attachments.HandlerWithOptions(strictAttachmentHandler, attachments.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []attachments.MiddlewareFunc{authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	workspaces.HandlerWithOptions(strictWorkspaceHandler, workspaces.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []workspaces.MiddlewareFunc{idempotencyMiddleware.Handler, authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	webhooks.HandlerWithOptions(strictWebhookHandler, webhooks.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []webhooks.MiddlewareFunc{idempotencyMiddleware.Handler, authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
+	// поток SSE написан руками (не через oapi-codegen), middleware те же, кроме idempotency
+	mux.Handle("GET /events", rateLimiter.AuthFailures(authMiddleware.Handler(rateLimiter.Handler(authzMiddleware.Handler(eventsHandler)))))
+	mux.Handle("GET /ws", rateLimiter.AuthFailures(authMiddleware.Handler(rateLimiter.Handler(authzMiddleware.Handler(wsHandler)))))
+	webaudit.HandlerWithOptions(strictAuditHandler, webaudit.StdHTTPServerOptions{
+		BaseRouter:  mux,
+		Middlewares: []webaudit.MiddlewareFunc{authzMiddleware.Handler, rateLimiter.Handler, authMiddleware.Handler, rateLimiter.AuthFailures},
+	})
 
 	// запускаем сервер
 	log.Println("Server is running on :9092")
-	if err := http.ListenAndServe(":9092", mux); err != nil { // слушаем порт 9092
+	// X-Request-ID - снаружи всего, чтобы id был и в журнале аудита, и в ответе на любой запрос;
+	// ".../{rev}:restore" переписываем в ".../{rev}/restore" до роутера
+	handler := requestid.Handler(custommethod.Handler(mux, "restore"))
+	if err := http.ListenAndServe(":9092", handler); err != nil { // слушаем порт 9092
 		log.Fatal(err)
 	}
 }
+
+// newBlobStore - выбирает хранилище содержимого вложений по конфигу
+func newBlobStore(cfg config.AttachmentsConfig) (blobstore.BlobStore, error) {
+	if cfg.Store != config.AttachmentsS3 {
+		return blobstore.NewLocalStore(cfg.Dir)
+	}
+
+	store, err := blobstore.NewS3Store(blobstore.S3Config{
+		Endpoint:        cfg.S3Endpoint,
+		Region:          cfg.S3Region,
+		Bucket:          cfg.S3Bucket,
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// newMailer - выбирает реализацию Mailer по конфигу
+func newMailer(cfg config.MailerConfig) mailer.Mailer {
+	switch cfg.Driver {
+	case config.MailerSMTP:
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	case config.MailerFile:
+		return mailer.NewFileMailer(cfg.FilePath, cfg.From)
+	default:
+		return mailer.NewLogMailer(cfg.From)
+	}
+}
@@ -0,0 +1,39 @@
+package attachmentService
+
+import "github.com/stretchr/testify/mock"
+
+type MockAttachmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepo) Create(attachment *AttachmentStruct) (*AttachmentStruct, error) {
+	args := m.Called(attachment)
+	var a *AttachmentStruct
+	if res := args.Get(0); res != nil {
+		a = res.(*AttachmentStruct)
+	}
+	return a, args.Error(1)
+}
+
+func (m *MockAttachmentRepo) GetByID(id uint) (AttachmentStruct, error) {
+	args := m.Called(id)
+	var a AttachmentStruct
+	if res := args.Get(0); res != nil {
+		a = res.(AttachmentStruct)
+	}
+	return a, args.Error(1)
+}
+
+func (m *MockAttachmentRepo) GetByTask(taskID uint) ([]AttachmentStruct, error) {
+	args := m.Called(taskID)
+	var attachments []AttachmentStruct
+	if res := args.Get(0); res != nil {
+		attachments = res.([]AttachmentStruct)
+	}
+	return attachments, args.Error(1)
+}
+
+func (m *MockAttachmentRepo) Delete(attachment *AttachmentStruct) error {
+	args := m.Called(attachment)
+	return args.Error(0)
+}
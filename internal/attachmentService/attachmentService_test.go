@@ -0,0 +1,300 @@
+package attachmentService
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	owner    = rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	stranger = rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	admin    = rbac.Actor{UserID: 3, Role: rbac.RoleAdmin}
+	assignee = rbac.Actor{UserID: 4, Role: rbac.RoleUser}
+	guest    = rbac.Actor{UserID: 5, Role: rbac.RoleUser} // гость пространства задачи
+	wsAdmin  = rbac.Actor{UserID: 6, Role: rbac.RoleUser} // admin пространства задачи
+)
+
+// fakeTasks - задача 7 из пространства принадлежит owner; ее видят owner, исполнитель assignee, админ,
+// admin пространства wsAdmin (управляет любыми его задачами) и гость guest (только смотрит)
+type fakeTasks struct{}
+
+func (f fakeTasks) GetTask(actor rbac.Actor, id uint) (*taskService.Task, error) {
+	task, _, err := f.GetTaskPermissions(actor, id)
+	return task, err
+}
+
+func (fakeTasks) GetTaskPermissions(actor rbac.Actor, id uint) (*taskService.Task, taskService.TaskPermissions, error) {
+	var perms taskService.TaskPermissions
+	switch {
+	case id != 7:
+		return nil, perms, errors.New("task not found")
+	case actor.CanAccessUser(1), actor.UserID == wsAdmin.UserID:
+		perms = taskService.TaskPermissions{Contribute: true, Manage: true}
+	case actor.UserID == assignee.UserID:
+		perms = taskService.TaskPermissions{Contribute: true}
+	case actor.UserID == guest.UserID:
+	default:
+		return nil, perms, errors.New("task not found")
+	}
+	workspaceID := uint(2)
+	return &taskService.Task{ID: 7, UserId: 1, WorkspaceID: &workspaceID}, perms, nil
+}
+
+// newTestService - сервис поверх настоящего LocalStore во временном каталоге
+func newTestService(t *testing.T, repo AttachmentRepoInterface, maxSize int64) (*AttachmentService, string) {
+	dir := t.TempDir()
+	store, err := blobstore.NewLocalStore(dir)
+	require.NoError(t, err)
+	return NewAttachmentService(repo, store, fakeTasks{}, maxSize), dir
+}
+
+// blobCount - сколько файлов лежит в хранилище
+func blobCount(t *testing.T, dir string) int {
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUpload(t *testing.T) {
+	const png = "\x89PNG\r\n\x1a\n" + "rest of the image"
+
+	tests := []struct {
+		name            string
+		actor           rbac.Actor
+		fileName        string
+		contentType     string
+		body            string
+		repoErr         error
+		wantErr         string
+		wantName        string
+		wantContentType string
+	}{
+		{
+			name:     "текстовый файл с типом из заголовка",
+			actor:    owner,
+			fileName: "notes.txt", contentType: "text/plain; charset=utf-8", body: "hello",
+			wantName:        "notes.txt",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "тип определяется по содержимому",
+			actor:    admin,
+			fileName: `C:\Users\anton\photo.png`, contentType: "application/octet-stream", body: png,
+			wantName:        "photo.png",
+			wantContentType: "image/png",
+		},
+		{
+			name:     "битый тип и пустое имя",
+			actor:    owner,
+			fileName: "../\n", contentType: "nonsense", body: "hello",
+			wantName:        "file",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "чужая задача",
+			actor:    stranger,
+			fileName: "a.txt", body: "hello",
+			wantErr: "task not found",
+		},
+		{
+			name:     "гость пространства только смотрит",
+			actor:    guest,
+			fileName: "a.txt", body: "hello",
+			wantErr: "forbidden",
+		},
+		{
+			name:     "исполнитель задачи",
+			actor:    assignee,
+			fileName: "a.txt", contentType: "text/plain", body: "hello",
+			wantName:        "a.txt",
+			wantContentType: "text/plain",
+		},
+		{
+			name:     "пустой файл",
+			actor:    owner,
+			fileName: "a.txt", body: "",
+			wantErr: "file is empty",
+		},
+		{
+			name:     "больше лимита",
+			actor:    owner,
+			fileName: "a.txt", body: strings.Repeat("x", 1025),
+			wantErr: "file is too large",
+		},
+		{
+			name:     "ошибка бд - содержимое удаляется",
+			actor:    owner,
+			fileName: "a.txt", body: "hello",
+			repoErr: errors.New("db is down"),
+			wantErr: "db is down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAttachmentRepo)
+			var created any = &AttachmentStruct{ID: 10}
+			if tt.repoErr != nil {
+				created = nil
+			}
+			mockRepo.On("Create", mock.Anything).Return(created, tt.repoErr).Maybe()
+
+			service, dir := newTestService(t, mockRepo, 1024)
+			params := UploadParams{Name: tt.fileName, ContentType: tt.contentType, Body: strings.NewReader(tt.body)}
+			attachment, err := service.Upload(context.Background(), tt.actor, 7, params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Zero(t, blobCount(t, dir)) // ничего не осталось в хранилище
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(10), attachment.ID)
+			assert.Equal(t, 1, blobCount(t, dir))
+
+			saved := mockRepo.Calls[0].Arguments.Get(0).(*AttachmentStruct)
+			assert.Equal(t, uint(7), saved.TaskID)
+			assert.Equal(t, tt.actor.UserID, saved.UploaderID)
+			assert.Equal(t, tt.wantName, saved.Name)
+			assert.Equal(t, tt.wantContentType, saved.ContentType)
+			assert.Equal(t, int64(len(tt.body)), saved.Size)
+			assert.Equal(t, sha256Hex(tt.body), saved.SHA256)
+			assert.True(t, strings.HasPrefix(saved.StorageKey, "tasks/7/"))
+		})
+	}
+}
+
+func TestGetAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		taskID  uint
+		id      uint
+		wantErr string
+	}{
+		{name: "владелец задачи", actor: owner, taskID: 7, id: 10},
+		{name: "админ", actor: admin, taskID: 7, id: 10},
+		{name: "чужая задача", actor: stranger, taskID: 7, id: 10, wantErr: "task not found"},
+		{name: "вложение другой задачи", actor: owner, taskID: 7, id: 11, wantErr: "attachment not found"},
+		{name: "нет такого вложения", actor: owner, taskID: 7, id: 12, wantErr: "attachment not found"},
+	}
+
+	mockRepo := new(MockAttachmentRepo)
+	mockRepo.On("GetByID", uint(10)).Return(AttachmentStruct{ID: 10, TaskID: 7, Name: "a.txt"}, nil)
+	mockRepo.On("GetByID", uint(11)).Return(AttachmentStruct{ID: 11, TaskID: 8, Name: "b.txt"}, nil)
+	mockRepo.On("GetByID", uint(12)).Return(nil, errors.New("record not found"))
+	service, _ := newTestService(t, mockRepo, 1024)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment, err := service.GetAttachment(tt.actor, tt.taskID, tt.id)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "a.txt", attachment.Name)
+		})
+	}
+}
+
+func TestOpenAndDelete(t *testing.T) {
+	mockRepo := new(MockAttachmentRepo)
+	mockRepo.On("Create", mock.Anything).Return(&AttachmentStruct{ID: 10}, nil).Once()
+	service, dir := newTestService(t, mockRepo, 1024)
+
+	ctx := context.Background()
+	_, err := service.Upload(ctx, owner, 7, UploadParams{Name: "a.txt", Body: strings.NewReader("0123456789")})
+	require.NoError(t, err)
+	saved := *mockRepo.Calls[0].Arguments.Get(0).(*AttachmentStruct)
+	saved.ID = 10
+
+	mockRepo.On("GetByID", uint(10)).Return(saved, nil)
+	attachment, err := service.GetAttachment(owner, 7, 10)
+	require.NoError(t, err)
+
+	// диапазон читается из хранилища, а не из полного файла
+	rc, err := service.Open(ctx, attachment, 2, 3)
+	require.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "234", string(content))
+
+	// чужой не удалит, исполнитель задачи и гость пространства - тоже (файл загружали не они)
+	assert.EqualError(t, service.Delete(stranger, 7, 10), "task not found")
+	assert.EqualError(t, service.Delete(assignee, 7, 10), "forbidden")
+	assert.EqualError(t, service.Delete(guest, 7, 10), "forbidden")
+	assert.Equal(t, 1, blobCount(t, dir))
+
+	// admin пространства удаляет любые вложения его задач
+	mockRepo.On("Delete", mock.MatchedBy(func(a *AttachmentStruct) bool { return a.ID == 10 })).Return(nil).Once()
+	require.NoError(t, service.Delete(wsAdmin, 7, 10))
+	assert.Zero(t, blobCount(t, dir))
+
+	// метаданные остались, а содержимого нет - это внутренняя ошибка, а не 404
+	_, err = service.Open(ctx, attachment, 0, -1)
+	assert.ErrorContains(t, err, "content is missing")
+}
+
+func TestCleanupTask(t *testing.T) {
+	mockRepo := new(MockAttachmentRepo)
+	mockRepo.On("Create", mock.Anything).Return(&AttachmentStruct{ID: 10}, nil)
+	service, dir := newTestService(t, mockRepo, 1024)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := service.Upload(ctx, owner, 7, UploadParams{Name: "a.txt", Body: strings.NewReader("hello")})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, blobCount(t, dir))
+
+	service.CleanupTask(7)
+	assert.Zero(t, blobCount(t, dir))
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "обычное имя", in: "отчет 2025.pdf", want: "отчет 2025.pdf"},
+		{name: "unix-путь", in: "/home/anton/report.pdf", want: "report.pdf"},
+		{name: "windows-путь", in: `C:\docs\report.pdf`, want: "report.pdf"},
+		{name: "управляющие символы", in: "a\r\nb.txt", want: "ab.txt"},
+		{name: "кавычки остаются (экранирует Content-Disposition)", in: `say "hi".txt`, want: `say "hi".txt`},
+		{name: "пустое", in: "  ", want: "file"},
+		{name: "точки", in: "..", want: "file"},
+		{name: "слишком длинное", in: strings.Repeat("я", maxNameLength+10), want: strings.Repeat("я", maxNameLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeName(tt.in))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package attachmentService
+
+import "time"
+
+// модель базы данных: файл, приложенный к задаче
+// здесь только метаданные - само содержимое лежит в blobstore под ключом StorageKey
+type AttachmentStruct struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	TaskID      uint   `gorm:"not null;index"`
+	UploaderID  uint   `gorm:"not null"`
+	Name        string `gorm:"not null"`
+	Size        int64  `gorm:"not null"`
+	ContentType string `gorm:"not null"`
+	SHA256      string `gorm:"column:sha256;not null"` // hex; он же ETag при скачивании
+	StorageKey  string `gorm:"not null;uniqueIndex"`
+	CreatedAt   time.Time
+}
+
+func (AttachmentStruct) TableName() string {
+	return "task_attachments" // как в миграции
+}
@@ -0,0 +1,46 @@
+package attachmentService
+
+import (
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+)
+
+// 2. repo-слой вложений (только метаданные в бд; содержимым занимается service через blobstore)
+
+type AttachmentRepoInterface interface {
+	Create(attachment *AttachmentStruct) (*AttachmentStruct, error)
+	GetByID(id uint) (AttachmentStruct, error)
+	GetByTask(taskID uint) ([]AttachmentStruct, error)
+	Delete(attachment *AttachmentStruct) error
+}
+
+type AttachmentRepo struct{}
+
+// Create - сохраняет метаданные загруженного файла
+func (r *AttachmentRepo) Create(attachment *AttachmentStruct) (*AttachmentStruct, error) {
+	if err := db.DB.Create(attachment).Error; err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// GetByID - вложение по ID
+func (r *AttachmentRepo) GetByID(id uint) (AttachmentStruct, error) {
+	var attachment AttachmentStruct
+	err := db.DB.First(&attachment, "id = ?", id).Error
+	return attachment, err
+}
+
+// GetByTask - вложения задачи (сначала старые)
+func (r *AttachmentRepo) GetByTask(taskID uint) ([]AttachmentStruct, error) {
+	var attachments []AttachmentStruct
+	err := db.DB.Where("task_id = ?", taskID).Order("created_at, id").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// Delete - удаляет метаданные (содержимое удаляет service)
+func (r *AttachmentRepo) Delete(attachment *AttachmentStruct) error {
+	return db.DB.Delete(attachment).Error
+}
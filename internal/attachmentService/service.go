@@ -0,0 +1,295 @@
+package attachmentService
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/google/uuid"
+)
+
+// 3. service-слой вложений
+//   • содержимое идет потоком прямо в blobstore: по дороге считаем размер и sha256 и обрываем
+//     загрузку, как только она превысила лимит (целиком файл в памяти не держим)
+//   • метаданные пишутся в бд только после того, как содержимое сохранено; если запись
+//     не удалась - содержимое удаляем, чтобы не копить сирот
+//   • права - как у задачи: вложения чужой задачи выглядят как несуществующие;
+//     кто видит задачу (владелец, исполнитель, участник ее пространства или админ), тот может загружать ее файлы
+//     (кроме гостя пространства - он только смотрит), а удалять - тот, кто загрузил файл, или кто управляет
+//     задачей целиком (владелец задачи, owner/admin ее пространства, админ)
+
+// максимальная длина имени файла (в символах)
+const maxNameLength = 255
+
+// сколько байт смотрим, чтобы угадать Content-Type (столько же читает http.DetectContentType)
+const sniffLength = 512
+
+var errTooLarge = errors.New("file is too large")
+
+// структура параметров метода Upload
+type UploadParams struct {
+	Name        string
+	ContentType string // из заголовка части; пустой или application/octet-stream - определяем по содержимому
+	Body        io.Reader
+}
+
+// бизнес-модель вложения
+type Attachment struct {
+	ID          uint
+	TaskID      uint
+	UploaderID  uint
+	Name        string
+	Size        int64
+	ContentType string
+	SHA256      string
+	CreatedAt   time.Time
+
+	storageKey string
+}
+
+// TaskGetter - задача, если actor может ее видеть, и его права на нее (реализует taskService.TaskService)
+type TaskGetter interface {
+	GetTask(actor rbac.Actor, id uint) (*taskService.Task, error)
+	GetTaskPermissions(actor rbac.Actor, id uint) (*taskService.Task, taskService.TaskPermissions, error)
+}
+
+type AttachmentService struct {
+	repo    AttachmentRepoInterface
+	store   blobstore.BlobStore
+	tasks   TaskGetter
+	maxSize int64
+}
+
+func NewAttachmentService(r AttachmentRepoInterface, store blobstore.BlobStore, tasks TaskGetter, maxSize int64) *AttachmentService {
+	return &AttachmentService{repo: r, store: store, tasks: tasks, maxSize: maxSize}
+}
+
+// MaxSize - лимит размера одного файла в байтах
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// GetAttachments - вложения задачи (сначала старые)
+func (s *AttachmentService) GetAttachments(actor rbac.Actor, taskID uint) ([]Attachment, error) {
+	if _, err := s.tasks.GetTask(actor, taskID); err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	dbAttachments, err := s.repo.GetByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	attachments := make([]Attachment, 0, len(dbAttachments))
+	for i := range dbAttachments {
+		attachments = append(attachments, *toAttachment(&dbAttachments[i]))
+	}
+	return attachments, nil
+}
+
+// GetAttachment - метаданные вложения taskID/id
+func (s *AttachmentService) GetAttachment(actor rbac.Actor, taskID, id uint) (*Attachment, error) {
+	dbAttachment, _, err := s.getVisible(actor, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAttachment(&dbAttachment), nil
+}
+
+// Open - содержимое вложения: length байт начиная с offset (length < 0 - до конца)
+func (s *AttachmentService) Open(ctx context.Context, a *Attachment, offset, length int64) (io.ReadCloser, error) {
+	rc, err := s.store.Get(ctx, a.storageKey, offset, length)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		// метаданные есть, а содержимого нет - это поломка хранилища, а не "нет такого файла"
+		return nil, fmt.Errorf("attachment %d: content is missing in storage", a.ID)
+	}
+	return rc, err
+}
+
+// Upload - сохраняет файл и его метаданные
+func (s *AttachmentService) Upload(ctx context.Context, actor rbac.Actor, taskID uint, params UploadParams) (*Attachment, error) {
+	_, perms, err := s.tasks.GetTaskPermissions(actor, taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	if !perms.Contribute {
+		return nil, errors.New("forbidden")
+	}
+
+	// начало файла нужно до загрузки: пустой файл не сохраняем, а по первым байтам угадываем тип
+	body := bufio.NewReaderSize(params.Body, sniffLength)
+	head, err := body.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	contentType := normalizeContentType(params.ContentType)
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
+	}
+
+	key := taskPrefix(taskID) + uuid.NewString()
+	counter := &countingReader{r: body, hash: sha256.New(), limit: s.maxSize}
+	if err := s.store.Put(ctx, key, counter, contentType); err != nil {
+		if errors.Is(err, errTooLarge) {
+			return nil, errTooLarge
+		}
+		return nil, err
+	}
+
+	created, err := s.repo.Create(&AttachmentStruct{
+		TaskID:      taskID,
+		UploaderID:  actor.UserID,
+		Name:        sanitizeName(params.Name),
+		Size:        counter.n,
+		ContentType: contentType,
+		SHA256:      hex.EncodeToString(counter.hash.Sum(nil)),
+		StorageKey:  key,
+	})
+	if err != nil {
+		s.deleteBlob(key)
+		return nil, err
+	}
+	return toAttachment(created), nil
+}
+
+// Delete - удаляет вложение: сначала метаданные, потом содержимое
+// (если содержимое удалить не вышло, файл уже не виден - остается только мусор в хранилище)
+func (s *AttachmentService) Delete(actor rbac.Actor, taskID, id uint) error {
+	dbAttachment, perms, err := s.getVisible(actor, taskID, id)
+	if err != nil {
+		return err
+	}
+	// исполнитель или участник пространства удаляет только то, что загрузил сам;
+	// кто управляет задачей целиком - любые вложения
+	if dbAttachment.UploaderID != actor.UserID && !perms.Manage {
+		return errors.New("forbidden")
+	}
+
+	if err := s.repo.Delete(&dbAttachment); err != nil {
+		return err
+	}
+	s.deleteBlob(dbAttachment.StorageKey)
+	return nil
+}
+
+// CleanupTask - удаляет содержимое всех вложений удаленной задачи
+// (строки task_attachments к этому моменту уже удалил каскад в бд, поэтому удаляем по префиксу ключа)
+func (s *AttachmentService) CleanupTask(taskID uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := s.store.DeletePrefix(ctx, taskPrefix(taskID)); err != nil {
+		log.Printf("Failed to delete attachments of task %d: %v", taskID, err)
+	}
+}
+
+// taskPrefix - общий префикс ключей вложений задачи
+func taskPrefix(taskID uint) string {
+	return fmt.Sprintf("tasks/%d/", taskID)
+}
+
+// getVisible - вложение (и права actor на его задачу), если оно принадлежит задаче taskID и actor видит эту задачу
+func (s *AttachmentService) getVisible(actor rbac.Actor, taskID, id uint) (AttachmentStruct, taskService.TaskPermissions, error) {
+	_, perms, err := s.tasks.GetTaskPermissions(actor, taskID)
+	if err != nil {
+		return AttachmentStruct{}, perms, errors.New("task not found")
+	}
+	dbAttachment, err := s.repo.GetByID(id)
+	if err != nil || dbAttachment.ID == 0 || dbAttachment.TaskID != taskID {
+		return AttachmentStruct{}, perms, errors.New("attachment not found")
+	}
+	return dbAttachment, perms, nil
+}
+
+// deleteBlob - удаление содержимого не должно ломать ответ клиенту, поэтому ошибку только логируем
+func (s *AttachmentService) deleteBlob(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete attachment blob %s: %v", key, err)
+	}
+}
+
+// countingReader - считает байты и sha256 и возвращает errTooLarge, как только прочитано больше limit
+type countingReader struct {
+	r     io.Reader
+	hash  hash.Hash
+	n     int64
+	limit int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.n > c.limit {
+		return 0, errTooLarge
+	}
+	c.hash.Write(p[:n])
+	return n, err
+}
+
+// sanitizeName - имя файла без пути и управляющих символов (его отдаем в Content-Disposition)
+func sanitizeName(name string) string {
+	// браузеры на Windows присылают полный путь
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if utf8.RuneCountInString(name) > maxNameLength {
+		name = string([]rune(name)[:maxNameLength])
+	}
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
+
+// normalizeContentType - тип из заголовка в каноничном виде; "" - если его нет, он битый или ничего не говорит
+func normalizeContentType(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" || !strings.Contains(mediaType, "/") {
+		return ""
+	}
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// toAttachment - маппим бд-модель в бизнес-модель
+func toAttachment(a *AttachmentStruct) *Attachment {
+	return &Attachment{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		UploaderID:  a.UploaderID,
+		Name:        a.Name,
+		Size:        a.Size,
+		ContentType: a.ContentType,
+		SHA256:      a.SHA256,
+		CreatedAt:   a.CreatedAt,
+		storageKey:  a.StorageKey,
+	}
+}
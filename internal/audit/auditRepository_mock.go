@@ -0,0 +1,16 @@
+package audit
+
+import "github.com/stretchr/testify/mock"
+
+type MockAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepo) Query(filter Filter) ([]LogStruct, int64, error) {
+	args := m.Called(filter)
+	var entries []LogStruct
+	if res := args.Get(0); res != nil {
+		entries = res.([]LogStruct)
+	}
+	return entries, args.Get(1).(int64), args.Error(2)
+}
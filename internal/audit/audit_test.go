@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewLog(t *testing.T) {
+	actor := rbac.Actor{UserID: 5, Role: rbac.RoleUser, RequestID: "req-1"}
+
+	t.Run("создание - все поля с from: null", func(t *testing.T) {
+		entry, err := newLog(Record{
+			EntityType: EntityTask, EntityID: 7, Action: ActionCreated, Actor: actor,
+			After: Snapshot{"id": uint(7), "task": "Купить хлеб", "is_done": false, "note": nil},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), *entry.ActorID)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.Nil(t, entry.Before)
+		assert.NotNil(t, entry.After)
+
+		var changes map[string]FieldChange
+		assert.NoError(t, json.Unmarshal([]byte(entry.Changes), &changes))
+		assert.Equal(t, map[string]FieldChange{
+			"id":      {From: nil, To: float64(7)},
+			"task":    {From: nil, To: "Купить хлеб"},
+			"is_done": {From: nil, To: false},
+		}, changes)
+	})
+
+	t.Run("изменение - только изменившиеся поля", func(t *testing.T) {
+		verified := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+		entry, err := newLog(Record{
+			EntityType: EntityUser, EntityID: 7, Action: ActionUpdated, Actor: actor,
+			Before: Snapshot{"email": "old@example.com", "email_verified_at": &verified, "version": uint(1)},
+			After:  Snapshot{"email": "new@example.com", "email_verified_at": nil, "version": uint(2)},
+		})
+		assert.NoError(t, err)
+
+		var changes map[string]FieldChange
+		assert.NoError(t, json.Unmarshal([]byte(entry.Changes), &changes))
+		assert.Equal(t, map[string]FieldChange{
+			"email":             {From: "old@example.com", To: "new@example.com"},
+			"email_verified_at": {From: "2025-12-01T10:00:00Z", To: nil},
+			"version":           {From: float64(1), To: float64(2)},
+		}, changes)
+	})
+
+	t.Run("скрытое поле - без значений", func(t *testing.T) {
+		entry, err := newLog(Record{
+			EntityType: EntityUser, EntityID: 7, Action: ActionUpdated,
+			Before:   Snapshot{"version": 1},
+			After:    Snapshot{"version": 2},
+			Redacted: []string{"password"},
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, entry.ActorID) // изменение системы
+		assert.Contains(t, entry.Changes, `"password":{"from":"[redacted]","to":"[redacted]"}`)
+	})
+
+	t.Run("удаление - все поля с to: null", func(t *testing.T) {
+		entry, err := newLog(Record{
+			EntityType: EntityTask, EntityID: 7, Action: ActionDeleted, Actor: actor,
+			Before: Snapshot{"task": "Купить хлеб"},
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, entry.After)
+		assert.JSONEq(t, `{"task":{"from":"Купить хлеб","to":null}}`, entry.Changes)
+	})
+}
+
+func TestQuery(t *testing.T) {
+	admin := rbac.Actor{UserID: 1, Role: rbac.RoleAdmin}
+	intPtr := func(i int) *int { return &i }
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		params  QueryParams
+		wantErr string
+	}{
+		{name: "не админ", actor: rbac.Actor{UserID: 2, Role: rbac.RoleUser}, wantErr: "forbidden"},
+		{name: "неизвестный тип сущности", actor: admin, params: QueryParams{EntityType: "comment"}, wantErr: "unknown entity type"},
+		{name: "неизвестное действие", actor: admin, params: QueryParams{Action: "restored"}, wantErr: "unknown action"},
+		{name: "from позже to", actor: admin, params: QueryParams{From: &now, To: &earlier}, wantErr: "from must be before to"},
+		{name: "лимит больше максимального", actor: admin, params: QueryParams{Limit: intPtr(501)}, wantErr: "limit must be between 1 and 500"},
+		{name: "отрицательный offset", actor: admin, params: QueryParams{Offset: intPtr(-1)}, wantErr: "offset cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAuditRepo)
+
+			_, err := NewAuditService(mockRepo).Query(tt.actor, tt.params)
+			assert.EqualError(t, err, tt.wantErr)
+			mockRepo.AssertNotCalled(t, "Query", mock.Anything)
+		})
+	}
+
+	t.Run("фильтр и лимит по умолчанию", func(t *testing.T) {
+		entityID := uint(7)
+		before := `{"task":"Старое"}`
+		mockRepo := new(MockAuditRepo)
+		mockRepo.On("Query", Filter{EntityType: EntityTask, EntityID: &entityID, Limit: DefaultLimit}).
+			Return([]LogStruct{{ID: 3, EntityType: EntityTask, EntityID: 7, Action: ActionDeleted, Before: &before,
+				Changes: `{"task":{"from":"Старое","to":null}}`}}, int64(1), nil)
+
+		page, err := NewAuditService(mockRepo).Query(admin, QueryParams{EntityType: EntityTask, EntityID: &entityID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), page.Total)
+		assert.Equal(t, DefaultLimit, page.Limit)
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, map[string]any{"task": "Старое"}, page.Items[0].Before)
+		assert.Nil(t, page.Items[0].After)
+		assert.Equal(t, FieldChange{From: "Старое", To: nil}, page.Items[0].Changes["task"])
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "time"
+
+// запись журнала аудита: кто (ActorID), в рамках какого запроса (RequestID) и как изменил сущность
+// Before / After - снимки сущности до и после (JSON), Changes - только изменившиеся поля {"поле": {"from": ..., "to": ...}}
+type LogStruct struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	EntityType string `gorm:"not null"` // Entity*
+	EntityID   uint   `gorm:"not null"`
+	Action     string `gorm:"not null"` // Action*
+	ActorID    *uint  // nil - действие системы (или анонимного запроса)
+	RequestID  string
+	Before     *string `gorm:"type:jsonb"` // nil для созданной сущности
+	After      *string `gorm:"type:jsonb"` // nil для удаленной сущности
+	Changes    string  `gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time
+}
+
+func (LogStruct) TableName() string {
+	return "audit_log" // как в миграции
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"gorm.io/gorm"
+)
+
+// Write - пишет запись в журнал через tx - той же транзакцией, что и само изменение:
+// изменение без записи в журнале (и запись без изменения) в бд не попадет
+// вызывается из repo-слоя tasks и users
+func Write(tx *gorm.DB, rec Record) error {
+	entry, err := newLog(rec)
+	if err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// фильтр выборки из журнала (пустые поля не фильтруют)
+type Filter struct {
+	EntityType string
+	EntityID   *uint
+	ActorID    *uint
+	Action     string
+	RequestID  string
+	From       *time.Time // created_at >= From
+	To         *time.Time // created_at < To
+	Limit      int
+	Offset     int
+}
+
+type AuditRepoInterface interface {
+	Query(filter Filter) ([]LogStruct, int64, error)
+}
+
+type AuditRepo struct{}
+
+// Query - записи по фильтру (сначала новые) и их общее число
+func (r *AuditRepo) Query(filter Filter) ([]LogStruct, int64, error) {
+	query := db.DB.Model(&LogStruct{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []LogStruct
+	err := query.Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,302 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+)
+
+// журнал аудита: кто, когда и с какого на какое значение изменил задачу или пользователя
+//   • запись пишет repo-слой tasks / users в той же транзакции, что и изменение (Write)
+//   • в записи - снимки до и после и список изменившихся полей; секреты (хэш пароля) в снимки не попадают,
+//     о том, что они изменились, говорит только поле со значением Redacted
+//   • читать весь журнал может только админ, историю своей задачи - ее владелец (через taskService)
+
+// типы сущностей
+const (
+	EntityTask = "task"
+	EntityUser = "user"
+)
+
+// действия
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// значение скрытого поля в Changes
+const Redacted = "[redacted]"
+
+// лимиты выборки
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Snapshot - состояние сущности: имя поля (как в API) -> значение
+type Snapshot map[string]any
+
+// Record - изменение, которое нужно записать в журнал
+type Record struct {
+	EntityType string
+	EntityID   uint
+	Action     string
+	Actor      rbac.Actor // UserID == 0 - действие системы
+	Before     Snapshot   // nil - сущность создана
+	After      Snapshot   // nil - сущность удалена
+	Redacted   []string   // поля, которые изменились, но значения которых в журнал не пишем
+}
+
+// изменение одного поля
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// бизнес-модель записи журнала
+type Entry struct {
+	ID         uint
+	EntityType string
+	EntityID   uint
+	Action     string
+	ActorID    *uint
+	RequestID  string
+	Before     map[string]any
+	After      map[string]any
+	Changes    map[string]FieldChange
+	CreatedAt  time.Time
+}
+
+// структура параметров метода Query
+type QueryParams struct {
+	EntityType string
+	EntityID   *uint
+	ActorID    *uint
+	Action     string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Limit      *int // nil - DefaultLimit
+	Offset     *int
+}
+
+// страница записей журнала
+type Page struct {
+	Items  []Entry
+	Total  int64
+	Limit  int
+	Offset int
+}
+
+type AuditService struct {
+	repo AuditRepoInterface
+}
+
+func NewAuditService(r AuditRepoInterface) *AuditService {
+	return &AuditService{repo: r}
+}
+
+// Query - выборка из всего журнала по фильтру; только для админа
+func (s *AuditService) Query(actor rbac.Actor, params QueryParams) (*Page, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.New("forbidden")
+	}
+
+	switch params.EntityType {
+	case "", EntityTask, EntityUser:
+	default:
+		return nil, errors.New("unknown entity type")
+	}
+	switch params.Action {
+	case "", ActionCreated, ActionUpdated, ActionDeleted:
+	default:
+		return nil, errors.New("unknown action")
+	}
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	limit, err := normalizeLimit(params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 {
+			return nil, errors.New("offset cannot be negative")
+		}
+		offset = *params.Offset
+	}
+
+	dbEntries, total, err := s.repo.Query(Filter{
+		EntityType: params.EntityType,
+		EntityID:   params.EntityID,
+		ActorID:    params.ActorID,
+		Action:     params.Action,
+		RequestID:  params.RequestID,
+		From:       params.From,
+		To:         params.To,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := toEntries(dbEntries)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Items: items, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// EntityHistory - история одной сущности (сначала новые) без проверки прав:
+// права проверяет сервис, которому принадлежит сущность
+func (s *AuditService) EntityHistory(entityType string, entityID uint, limit *int) ([]Entry, error) {
+	n, err := normalizeLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntries, _, err := s.repo.Query(Filter{EntityType: entityType, EntityID: &entityID, Limit: n})
+	if err != nil {
+		return nil, err
+	}
+	return toEntries(dbEntries)
+}
+
+// normalizeLimit - nil - DefaultLimit, вне 1..MaxLimit - ошибка
+func normalizeLimit(limit *int) (int, error) {
+	if limit == nil {
+		return DefaultLimit, nil
+	}
+	if *limit < 1 || *limit > MaxLimit {
+		return 0, errors.New("limit must be between 1 and 500")
+	}
+	return *limit, nil
+}
+
+// newLog - бд-модель записи: снимки и изменившиеся поля в JSON
+func newLog(rec Record) (*LogStruct, error) {
+	before, err := normalize(rec.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := normalize(rec.After)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &LogStruct{
+		EntityType: rec.EntityType,
+		EntityID:   rec.EntityID,
+		Action:     rec.Action,
+		RequestID:  rec.Actor.RequestID,
+	}
+	if rec.Actor.UserID != 0 {
+		actorID := rec.Actor.UserID
+		entry.ActorID = &actorID
+	}
+	if entry.Before, err = marshalSnapshot(before); err != nil {
+		return nil, err
+	}
+	if entry.After, err = marshalSnapshot(after); err != nil {
+		return nil, err
+	}
+
+	changes := diff(before, after)
+	for _, field := range rec.Redacted {
+		changes[field] = FieldChange{From: Redacted, To: Redacted}
+	}
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	entry.Changes = string(raw)
+
+	return entry, nil
+}
+
+// normalize - снимок в том виде, в каком он будет лежать в бд (время - строкой, числа - float64),
+// чтобы до и после сравнивались одинаково
+func normalize(s Snapshot) (map[string]any, error) {
+	if s == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func marshalSnapshot(m map[string]any) (*string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	s := string(raw)
+	return &s, nil
+}
+
+// diff - поля, значения которых различаются (у созданной сущности - все поля с from: null,
+// у удаленной - все поля с to: null)
+func diff(before, after map[string]any) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for k, from := range before {
+		if to := after[k]; !reflect.DeepEqual(from, to) {
+			changes[k] = FieldChange{From: from, To: to}
+		}
+	}
+	for k, to := range after {
+		if _, ok := before[k]; !ok && to != nil {
+			changes[k] = FieldChange{From: nil, To: to}
+		}
+	}
+	return changes
+}
+
+// toEntries - маппим бд-модель в бизнес-модель
+func toEntries(dbEntries []LogStruct) ([]Entry, error) {
+	entries := make([]Entry, 0, len(dbEntries))
+	for _, e := range dbEntries {
+		entry := Entry{
+			ID:         e.ID,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Action:     e.Action,
+			ActorID:    e.ActorID,
+			RequestID:  e.RequestID,
+			Changes:    map[string]FieldChange{},
+			CreatedAt:  e.CreatedAt,
+		}
+		if e.Before != nil {
+			if err := json.Unmarshal([]byte(*e.Before), &entry.Before); err != nil {
+				return nil, err
+			}
+		}
+		if e.After != nil {
+			if err := json.Unmarshal([]byte(*e.After), &entry.After); err != nil {
+				return nil, err
+			}
+		}
+		if e.Changes != "" {
+			if err := json.Unmarshal([]byte(e.Changes), &entry.Changes); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
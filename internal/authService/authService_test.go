@@ -0,0 +1,383 @@
+package authService
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/mailer"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// fakeUsers - заглушка userService
+type fakeUsers struct {
+	byEmail     map[string]*userService.User
+	validateErr error
+	setErr      error
+	setCalls    []uint
+	verifyErr   error
+	verified    []string
+	created     []string
+	attempts    []userService.LoginAttempt
+	lockedUntil *time.Time
+	events      []authEvent
+}
+
+// authEvent - событие, записанное в журнал входов
+type authEvent struct {
+	UserID uint
+	Type   string
+	Reason string
+	IP     string
+}
+
+func (f *fakeUsers) GetUserByEmail(email string) (*userService.User, error) {
+	if u, ok := f.byEmail[email]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeUsers) ValidateNewPassword(id uint, password string) error {
+	return f.validateErr
+}
+
+func (f *fakeUsers) SetPassword(id uint, password string) (*userService.User, error) {
+	f.setCalls = append(f.setCalls, id)
+	if f.setErr != nil {
+		return nil, f.setErr
+	}
+	return &userService.User{ID: id}, nil
+}
+
+func (f *fakeUsers) MarkEmailVerified(id uint, email string) (*userService.User, error) {
+	if f.verifyErr != nil {
+		return nil, f.verifyErr
+	}
+	f.verified = append(f.verified, email)
+	now := testNow
+	return &userService.User{ID: id, Email: email, EmailVerifiedAt: &now}, nil
+}
+
+func (f *fakeUsers) CheckCredentials(attempt userService.LoginAttempt) (*userService.User, error) {
+	f.attempts = append(f.attempts, attempt)
+	if f.lockedUntil != nil {
+		return nil, &userService.LockedError{Until: *f.lockedUntil}
+	}
+	if u, ok := f.byEmail[attempt.Email]; ok && attempt.Password == "Str0ng-Passw0rd" {
+		if u.DisabledAt != nil {
+			return nil, errors.New("account is disabled")
+		}
+		return u, nil
+	}
+	return nil, errors.New("invalid credentials")
+}
+
+func (f *fakeUsers) GetAccount(id uint) (*userService.User, error) {
+	for _, u := range f.byEmail {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeUsers) CheckLockout(id uint) error {
+	if f.lockedUntil != nil {
+		return &userService.LockedError{Until: *f.lockedUntil}
+	}
+	return nil
+}
+
+func (f *fakeUsers) RecordAuthEvent(userID uint, eventType, reason string, attempt userService.LoginAttempt) {
+	f.events = append(f.events, authEvent{UserID: userID, Type: eventType, Reason: reason, IP: attempt.IP})
+}
+
+func (f *fakeUsers) CreateExternalUser(email string) (*userService.User, error) {
+	if _, ok := f.byEmail[email]; ok {
+		return nil, errors.New("email already exists")
+	}
+	f.created = append(f.created, email)
+	now := testNow
+	u := &userService.User{ID: uint(100 + len(f.created)), Email: email, EmailVerifiedAt: &now, Role: rbac.RoleUser}
+	if f.byEmail == nil {
+		f.byEmail = map[string]*userService.User{}
+	}
+	f.byEmail[email] = u
+	return u, nil
+}
+
+// fakeMailer - складывает письма в слайс
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+var testNow = time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+
+// tokenFromMail - достает токен из ссылки в письме
+func tokenFromMail(t *testing.T, body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			assert.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatal("no link in mail body")
+	return ""
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	users := &fakeUsers{byEmail: map[string]*userService.User{
+		"user@example.com": {ID: 7, Email: "user@example.com"},
+	}}
+
+	t.Run("письмо со ссылкой для существующего пользователя", func(t *testing.T) {
+		repo := new(MockPasswordResetRepo)
+		mail := &fakeMailer{}
+		service := NewAuthService(users, repo, new(MockEmailVerificationRepo), new(MockSessionRepo), new(MockPersonalTokenRepo), mail).WithPasswordReset("https://app.example.com/reset?lang=ru", 30*time.Minute)
+		service.now = func() time.Time { return testNow }
+
+		var stored *PasswordResetTokenStruct
+		repo.On("DeleteForUser", uint(7)).Return(nil)
+		repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*PasswordResetTokenStruct)
+		}).Return(nil)
+
+		err := service.RequestPasswordReset("user@example.com")
+		assert.NoError(t, err)
+		assert.Len(t, mail.sent, 1)
+		assert.Equal(t, "user@example.com", mail.sent[0].To)
+		assert.Contains(t, mail.sent[0].Body, "lang=ru")
+
+		// в бд - хэш токена из письма, а не сам токен
+		token := tokenFromMail(t, mail.sent[0].Body)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, uint(7), stored.UserID)
+		assert.Equal(t, hashToken(token), stored.TokenHash)
+		assert.NotEqual(t, token, stored.TokenHash)
+		assert.Equal(t, testNow.Add(30*time.Minute), stored.ExpiresAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("неизвестный email - без ошибки и без письма", func(t *testing.T) {
+		repo := new(MockPasswordResetRepo)
+		mail := &fakeMailer{}
+		service := NewAuthService(users, repo, new(MockEmailVerificationRepo), new(MockSessionRepo), new(MockPersonalTokenRepo), mail)
+
+		err := service.RequestPasswordReset("nobody@example.com")
+		assert.NoError(t, err)
+		assert.Empty(t, mail.sent)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка отправки письма", func(t *testing.T) {
+		repo := new(MockPasswordResetRepo)
+		mail := &fakeMailer{err: errors.New("smtp down")}
+		service := NewAuthService(users, repo, new(MockEmailVerificationRepo), new(MockSessionRepo), new(MockPersonalTokenRepo), mail)
+
+		repo.On("DeleteForUser", uint(7)).Return(nil)
+		repo.On("Create", mock.Anything).Return(nil)
+
+		err := service.RequestPasswordReset("user@example.com")
+		assert.ErrorContains(t, err, "smtp down")
+	})
+}
+
+func TestConfirmPasswordReset(t *testing.T) {
+	const token = "reset-token"
+	valid := PasswordResetTokenStruct{ID: 3, UserID: 7, TokenHash: hashToken(token)}
+
+	tests := []struct {
+		name        string
+		token       string
+		users       *fakeUsers
+		mockSetup   func(m *MockPasswordResetRepo)
+		wantErr     string
+		wantSetCall bool
+	}{
+		{
+			name:  "успешный сброс",
+			token: token,
+			users: &fakeUsers{},
+			mockSetup: func(m *MockPasswordResetRepo) {
+				m.On("GetValid", hashToken(token)).Return(valid, nil)
+				m.On("Consume", uint(3)).Return(true, nil)
+			},
+			wantSetCall: true,
+		},
+		{
+			name:      "пустой токен",
+			token:     " ",
+			users:     &fakeUsers{},
+			mockSetup: func(m *MockPasswordResetRepo) {},
+			wantErr:   "invalid or expired token",
+		},
+		{
+			name:  "неизвестный, истекший или использованный токен",
+			token: "other",
+			users: &fakeUsers{},
+			mockSetup: func(m *MockPasswordResetRepo) {
+				m.On("GetValid", hashToken("other")).Return(PasswordResetTokenStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: "invalid or expired token",
+		},
+		{
+			name:  "пароль не прошел политику - токен не сжигается",
+			token: token,
+			users: &fakeUsers{validateErr: errors.New("invalid password: min_length")},
+			mockSetup: func(m *MockPasswordResetRepo) {
+				m.On("GetValid", hashToken(token)).Return(valid, nil)
+			},
+			wantErr: "invalid password",
+		},
+		{
+			name:  "токен уже погасил параллельный запрос",
+			token: token,
+			users: &fakeUsers{},
+			mockSetup: func(m *MockPasswordResetRepo) {
+				m.On("GetValid", hashToken(token)).Return(valid, nil)
+				m.On("Consume", uint(3)).Return(false, nil)
+			},
+			wantErr: "invalid or expired token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPasswordResetRepo)
+			tt.mockSetup(repo)
+
+			service := NewAuthService(tt.users, repo, new(MockEmailVerificationRepo), new(MockSessionRepo), new(MockPersonalTokenRepo), &fakeMailer{})
+			err := service.ConfirmPasswordReset(tt.token, "New-Passw0rd")
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantSetCall {
+				assert.Equal(t, []uint{7}, tt.users.setCalls)
+			} else {
+				assert.Empty(t, tt.users.setCalls)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewResetToken(t *testing.T) {
+	a, err := newToken()
+	assert.NoError(t, err)
+	b, err := newToken()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Len(t, a, 43) // 32 байта в base64url без паддинга
+	assert.Len(t, hashToken(a), 64)
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	repo := new(MockEmailVerificationRepo)
+	mail := &fakeMailer{}
+	service := NewAuthService(&fakeUsers{}, new(MockPasswordResetRepo), repo, new(MockSessionRepo), new(MockPersonalTokenRepo), mail).
+		WithEmailVerification("https://api.example.com/auth/verify", 48*time.Hour)
+	service.now = func() time.Time { return testNow }
+
+	var stored *EmailVerificationTokenStruct
+	repo.On("DeleteForUser", uint(5)).Return(nil)
+	repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*EmailVerificationTokenStruct)
+	}).Return(nil)
+
+	err := service.SendEmailVerification(userService.User{ID: 5, Email: "new@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, mail.sent, 1)
+	assert.Equal(t, "new@example.com", mail.sent[0].To)
+
+	// токен привязан к адресу, на который ушло письмо
+	token := tokenFromMail(t, mail.sent[0].Body)
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.Equal(t, "new@example.com", stored.Email)
+	assert.Equal(t, testNow.Add(48*time.Hour), stored.ExpiresAt)
+	repo.AssertExpectations(t)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	const token = "verify-token"
+	record := EmailVerificationTokenStruct{ID: 1, UserID: 5, Email: "user@example.com", TokenHash: hashToken(token)}
+
+	tests := []struct {
+		name      string
+		token     string
+		users     *fakeUsers
+		mockSetup func(m *MockEmailVerificationRepo)
+		wantErr   string
+	}{
+		{
+			name:  "успешное подтверждение",
+			token: token,
+			users: &fakeUsers{},
+			mockSetup: func(m *MockEmailVerificationRepo) {
+				m.On("Take", hashToken(token)).Return(record, nil)
+			},
+		},
+		{
+			name:      "пустой токен",
+			token:     "",
+			users:     &fakeUsers{},
+			mockSetup: func(m *MockEmailVerificationRepo) {},
+			wantErr:   "invalid or expired token",
+		},
+		{
+			name:  "неизвестный или истекший токен",
+			token: "other",
+			users: &fakeUsers{},
+			mockSetup: func(m *MockEmailVerificationRepo) {
+				m.On("Take", hashToken("other")).Return(EmailVerificationTokenStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: "invalid or expired token",
+		},
+		{
+			name:  "email сменился после отправки письма",
+			token: token,
+			users: &fakeUsers{verifyErr: errors.New("email has changed since the verification was sent")},
+			mockSetup: func(m *MockEmailVerificationRepo) {
+				m.On("Take", hashToken(token)).Return(record, nil)
+			},
+			wantErr: "invalid or expired token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockEmailVerificationRepo)
+			tt.mockSetup(repo)
+
+			service := NewAuthService(tt.users, new(MockPasswordResetRepo), repo, new(MockSessionRepo), new(MockPersonalTokenRepo), &fakeMailer{})
+			user, err := service.VerifyEmail(tt.token)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, user.EmailVerifiedAt)
+				assert.Equal(t, []string{"user@example.com"}, tt.users.verified)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
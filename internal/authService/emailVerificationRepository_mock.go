@@ -0,0 +1,26 @@
+package authService
+
+import "github.com/stretchr/testify/mock"
+
+type MockEmailVerificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationRepo) Create(token *EmailVerificationTokenStruct) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationRepo) Take(tokenHash string) (EmailVerificationTokenStruct, error) {
+	args := m.Called(tokenHash)
+	var token EmailVerificationTokenStruct
+	if res := args.Get(0); res != nil {
+		token = res.(EmailVerificationTokenStruct)
+	}
+	return token, args.Error(1)
+}
+
+func (m *MockEmailVerificationRepo) DeleteForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
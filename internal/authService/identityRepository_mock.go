@@ -0,0 +1,21 @@
+package authService
+
+import "github.com/stretchr/testify/mock"
+
+type MockIdentityRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepo) Get(issuer, subject string) (UserIdentityStruct, error) {
+	args := m.Called(issuer, subject)
+	var identity UserIdentityStruct
+	if res := args.Get(0); res != nil {
+		identity = res.(UserIdentityStruct)
+	}
+	return identity, args.Error(1)
+}
+
+func (m *MockIdentityRepo) Create(identity *UserIdentityStruct) error {
+	args := m.Called(identity)
+	return args.Error(0)
+}
@@ -0,0 +1,31 @@
+package authService
+
+import "github.com/stretchr/testify/mock"
+
+type MockMFAChallengeRepo struct {
+	mock.Mock
+}
+
+func (m *MockMFAChallengeRepo) Create(challenge *MFAChallengeStruct) error {
+	args := m.Called(challenge)
+	return args.Error(0)
+}
+
+func (m *MockMFAChallengeRepo) GetValid(tokenHash string) (MFAChallengeStruct, error) {
+	args := m.Called(tokenHash)
+	var challenge MFAChallengeStruct
+	if res := args.Get(0); res != nil {
+		challenge = res.(MFAChallengeStruct)
+	}
+	return challenge, args.Error(1)
+}
+
+func (m *MockMFAChallengeRepo) RegisterAttempt(id uint) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,220 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/oidc"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+)
+
+// вход через внешний OIDC-провайдер (authorization code + PKCE):
+//   1) GET /auth/oidc/login - создаем state, nonce и code_verifier, сохраняем их и отправляем браузер к провайдеру
+//   2) GET /auth/oidc/callback?code=...&state=... - забираем state (один раз), меняем code на id_token
+//      и начинаем обычную сессию, как после входа по паролю
+// внешний аккаунт (issuer + subject) привязывается к пользователю при первом входе:
+//   • пользователь с таким email есть - привязываем к нему (только если провайдер подтвердил email)
+//   • нет - создаем нового пользователя без пароля (пароль можно задать потом через сброс пароля)
+// дальше вход идет по subject, поэтому смена email у провайдера ничего не ломает
+// блокировка после серии неудачных паролей (userService) закрывает и этот вход - ответ 429, как у /auth/login
+
+// сколько ждем возврата с провайдера
+const oidcStateTTL = 10 * time.Minute
+
+// IdentityProvider - внешний провайдер (oidc.Client; интерфейс, чтобы в тестах подставлять заглушку)
+type IdentityProvider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*oidc.Claims, error)
+}
+
+// проверка на этапе компиляции: oidc.Client подходит
+var _ IdentityProvider = (*oidc.Client)(nil)
+
+// структура параметров метода CompleteOIDCLogin (то, с чем провайдер вернул браузер)
+type OIDCCallbackParams struct {
+	Code      string
+	State     string
+	Error     string // провайдер отказал (например, пользователь нажал "отмена")
+	IP        string // для журнала входов
+	UserAgent string
+}
+
+// WithOIDC - включает вход через OIDC (без него StartOIDCLogin отвечает "oidc login is not configured")
+func (s *AuthService) WithOIDC(provider IdentityProvider, states OIDCStateRepoInterface, identities IdentityRepoInterface) *AuthService {
+	s.oidc = provider
+	s.oidcStates = states
+	s.identities = identities
+	return s
+}
+
+// StartOIDCLogin - адрес страницы входа у провайдера
+func (s *AuthService) StartOIDCLogin(ctx context.Context) (string, error) {
+	if s.oidc == nil {
+		return "", errors.New("oidc login is not configured")
+	}
+
+	// state - для нас (защита от CSRF на callback), nonce - для провайдера (попадет в id_token),
+	// verifier - секрет PKCE: без него перехваченный code бесполезен
+	var secrets [3]string
+	for i := range secrets {
+		token, err := newToken()
+		if err != nil {
+			return "", err
+		}
+		secrets[i] = token
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := s.oidc.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Printf("OIDC login failed to start: %v", err)
+		return "", errors.New("identity provider is unavailable")
+	}
+
+	record := &OIDCLoginStateStruct{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(oidcStateTTL),
+	}
+	if err := s.oidcStates.Create(record); err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteOIDCLogin - завершает вход по ответу провайдера и выдает пару токенов
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, params OIDCCallbackParams) (*TokenPair, error) {
+	if s.oidc == nil {
+		return nil, errors.New("oidc login is not configured")
+	}
+
+	params.State = strings.TrimSpace(params.State)
+	if params.State == "" {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	// state забираем в любом случае - даже отказ провайдера его гасит
+	record, err := s.oidcStates.Take(hashToken(params.State))
+	if err != nil || record.ID == 0 {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	// неверный state - не попытка входа, а мусорный запрос: в журнал пишем только то, что после него
+	attempt := userService.LoginAttempt{IP: params.IP, UserAgent: params.UserAgent}
+	if params.Error != "" {
+		s.users.RecordAuthEvent(0, userService.AuthEventOIDCLoginFailed, userService.LoginFailureProviderRejected, attempt)
+		return nil, errors.New("identity provider rejected the login: " + truncate(params.Error, 100))
+	}
+	if params.Code == "" {
+		return nil, errors.New("code is empty")
+	}
+
+	claims, err := s.oidc.Exchange(ctx, params.Code, record.CodeVerifier, record.Nonce)
+	if err != nil {
+		// подробности - в лог, клиенту хватит общего ответа
+		log.Printf("OIDC code exchange failed: %v", err)
+		s.users.RecordAuthEvent(0, userService.AuthEventOIDCLoginFailed, userService.LoginFailureProviderRejected, attempt)
+		return nil, errors.New("identity provider rejected the login")
+	}
+
+	attempt.Email = claims.Email
+	user, err := s.identityUser(claims, attempt)
+	if err != nil {
+		return nil, err
+	}
+	attempt.Email = user.Email
+
+	// провайдер подтверждает только первый фактор: включенный TOTP требуется и здесь, как при входе по паролю;
+	// удачным вход считаем, только когда выдана сессия - иначе дальше в журнале будет mfa_succeeded или mfa_failed
+	if err := s.requireSecondFactor(user.ID, "", params.UserAgent); err != nil {
+		var mfa *SecondFactorRequiredError
+		if errors.As(err, &mfa) {
+			s.users.RecordAuthEvent(user.ID, userService.AuthEventOIDCFirstFactorPassed, "", attempt)
+		}
+		return nil, err
+	}
+
+	pair, err := s.startSession(user.ID, "", params.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	s.users.RecordAuthEvent(user.ID, userService.AuthEventOIDCLoginSucceeded, "", attempt)
+	return pair, nil
+}
+
+// identityUser - пользователь, к которому привязан внешний аккаунт (привязывает или создает при первом входе)
+// отказы пишутся в журнал входов здесь - только тут известно, к какому пользователю они относятся
+func (s *AuthService) identityUser(claims *oidc.Claims, attempt userService.LoginAttempt) (*userService.User, error) {
+	identity, err := s.identities.Get(claims.Issuer, claims.Subject)
+	if err == nil && identity.ID != 0 {
+		attempt.Email = ""
+		user, err := s.activeAccount(identity.UserID)
+		if err != nil {
+			if strings.Contains(err.Error(), "account is disabled") {
+				s.users.RecordAuthEvent(identity.UserID, userService.AuthEventOIDCLoginFailed, userService.LoginFailureDisabled, attempt)
+			}
+			return nil, err
+		}
+		if err := s.checkLockout(user.ID, attempt); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// привязка по email безопасна только если провайдер сам проверил, что адрес принадлежит пользователю
+	if claims.Email == "" || !claims.EmailVerified {
+		s.users.RecordAuthEvent(0, userService.AuthEventOIDCLoginFailed, userService.LoginFailureEmailUnverified, attempt)
+		return nil, errors.New("email is not verified by the identity provider")
+	}
+
+	user, err := s.users.GetUserByEmail(claims.Email)
+	if err != nil {
+		user, err = s.users.CreateExternalUser(claims.Email)
+		if err != nil {
+			return nil, err
+		}
+	} else if user.EmailVerifiedAt == nil {
+		// кто-то зарегистрировался с этим адресом, но не подтвердил его - возможно, не владелец адреса;
+		// если привязать вход, его пароль продолжит открывать аккаунт
+		s.users.RecordAuthEvent(user.ID, userService.AuthEventOIDCLoginFailed, userService.LoginFailureEmailUnverified, attempt)
+		return nil, errors.New("account with this email has not verified it yet")
+	}
+
+	if user.DisabledAt != nil {
+		s.users.RecordAuthEvent(user.ID, userService.AuthEventOIDCLoginFailed, userService.LoginFailureDisabled, attempt)
+		return nil, errors.New("account is disabled")
+	}
+	if err := s.checkLockout(user.ID, attempt); err != nil {
+		return nil, err
+	}
+
+	err = s.identities.Create(&UserIdentityStruct{
+		UserID:  user.ID,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Linked OIDC identity %s (%s) to user %d", claims.Subject, claims.Issuer, user.ID)
+	return user, nil
+}
+
+// checkLockout - блокировка после серии неудачных паролей действует и при входе через провайдера,
+// иначе подбор пароля просто переключается на OIDC
+func (s *AuthService) checkLockout(userID uint, attempt userService.LoginAttempt) error {
+	err := s.users.CheckLockout(userID)
+	var locked *userService.LockedError
+	if errors.As(err, &locked) {
+		s.users.RecordAuthEvent(userID, userService.AuthEventOIDCLoginFailed, userService.LoginFailureLocked, attempt)
+	}
+	return err
+}
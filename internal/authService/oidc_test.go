@@ -0,0 +1,314 @@
+package authService
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/oidc"
+	"github.com/AntonRadchenko/WebPet1/internal/oidc/oidctest"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// интеграционные тесты входа через OIDC: настоящий oidc.Client против локального провайдера oidctest,
+// бд заменена моками
+
+type oidcFixture struct {
+	provider   *oidctest.Provider
+	service    *AuthService
+	users      *fakeUsers
+	states     *memStates
+	identities *MockIdentityRepo
+	sessions   *MockSessionRepo
+	linked     []*UserIdentityStruct
+}
+
+func newOIDCFixture(t *testing.T, users *fakeUsers) *oidcFixture {
+	provider := oidctest.NewProvider("webpet", "secret")
+	t.Cleanup(provider.Close)
+
+	f := &oidcFixture{
+		provider:   provider,
+		users:      users,
+		states:     &memStates{saved: map[string]OIDCLoginStateStruct{}},
+		identities: new(MockIdentityRepo),
+		sessions:   new(MockSessionRepo),
+	}
+
+	client := oidc.NewClient(oidc.Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     "webpet",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:9092/auth/oidc/callback",
+	})
+	f.service = NewAuthService(users, new(MockPasswordResetRepo), new(MockEmailVerificationRepo), f.sessions, new(MockPersonalTokenRepo), &fakeMailer{}).
+		WithTokens([]byte("test-secret"), 15*time.Minute, 24*time.Hour).
+		WithOIDC(client, f.states, f.identities)
+	f.service.now = func() time.Time { return testNow }
+
+	f.identities.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		f.linked = append(f.linked, args.Get(0).(*UserIdentityStruct))
+	}).Return(nil)
+	f.sessions.On("Create", mock.Anything).Return(nil)
+	return f
+}
+
+// memStates - хранилище state в памяти (запись живет до первого Take)
+type memStates struct {
+	saved   map[string]OIDCLoginStateStruct
+	created []OIDCLoginStateStruct
+}
+
+func (m *memStates) Create(state *OIDCLoginStateStruct) error {
+	state.ID = uint(len(m.created) + 1)
+	m.created = append(m.created, *state)
+	m.saved[state.StateHash] = *state
+	return nil
+}
+
+func (m *memStates) Take(stateHash string) (OIDCLoginStateStruct, error) {
+	state, ok := m.saved[stateHash]
+	if !ok {
+		return OIDCLoginStateStruct{}, gorm.ErrRecordNotFound
+	}
+	delete(m.saved, stateHash)
+	return state, nil
+}
+
+// login - весь путь браузера: наш /auth/oidc/login -> провайдер -> наш callback
+func (f *oidcFixture) login(t *testing.T) (*TokenPair, error) {
+	authURL, err := f.service.StartOIDCLogin(context.Background())
+	require.NoError(t, err)
+
+	code, state, err := f.provider.Authorize(authURL)
+	require.NoError(t, err)
+
+	return f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: code, State: state, IP: "10.0.0.1", UserAgent: "browser"})
+}
+
+func TestOIDCLogin(t *testing.T) {
+	t.Run("первый вход создает пользователя и привязывает внешний аккаунт", func(t *testing.T) {
+		f := newOIDCFixture(t, &fakeUsers{})
+		f.provider.SetUser(oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		f.identities.On("Get", f.provider.Issuer(), "sub-1").Return(nil, gorm.ErrRecordNotFound)
+
+		pair, err := f.login(t)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"new@example.com"}, f.users.created)
+		require.Len(t, f.linked, 1)
+		assert.Equal(t, UserIdentityStruct{UserID: 101, Issuer: f.provider.Issuer(), Subject: "sub-1", Email: "new@example.com"}, *f.linked[0])
+		assert.Equal(t, []authEvent{{UserID: 101, Type: userService.AuthEventOIDCLoginSucceeded, IP: "10.0.0.1"}}, f.users.events)
+
+		// выдана обычная сессия - access-токен проходит проверку
+		f.sessions.On("GetActive", pair.SessionID).Return(SessionStruct{UserID: 101, FamilyID: pair.SessionID}, nil)
+		principal, err := f.service.Authenticate(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, &Principal{UserID: 101, SessionID: pair.SessionID, Role: rbac.RoleUser}, principal)
+	})
+
+	t.Run("существующий пользователь с подтвержденным email привязывается по email", func(t *testing.T) {
+		users := &fakeUsers{byEmail: map[string]*userService.User{
+			"user@example.com": {ID: 7, Email: "user@example.com", EmailVerifiedAt: &testNow, Role: rbac.RoleAdmin},
+		}}
+		f := newOIDCFixture(t, users)
+		f.identities.On("Get", f.provider.Issuer(), "user-1").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.login(t)
+		require.NoError(t, err)
+		assert.Empty(t, users.created)
+		require.Len(t, f.linked, 1)
+		assert.Equal(t, uint(7), f.linked[0].UserID)
+	})
+
+	t.Run("повторный вход идет по subject, даже если email у провайдера сменился", func(t *testing.T) {
+		users := &fakeUsers{byEmail: map[string]*userService.User{
+			"user@example.com": {ID: 7, Email: "user@example.com", Role: rbac.RoleUser},
+		}}
+		f := newOIDCFixture(t, users)
+		f.provider.SetUser(oidctest.User{Subject: "sub-7", Email: "renamed@example.com", EmailVerified: false})
+		f.identities.On("Get", f.provider.Issuer(), "sub-7").Return(UserIdentityStruct{ID: 1, UserID: 7}, nil)
+
+		pair, err := f.login(t)
+		require.NoError(t, err)
+		assert.NotEmpty(t, pair.AccessToken)
+		assert.Empty(t, users.created)
+		assert.Empty(t, f.linked)
+	})
+
+	t.Run("включен второй фактор - вместо токенов mfa-токен", func(t *testing.T) {
+		users := &fakeUsers{byEmail: map[string]*userService.User{
+			"user@example.com": {ID: 7, Email: "user@example.com", Role: rbac.RoleUser},
+		}}
+		f := newOIDCFixture(t, users)
+		f.identities.On("Get", f.provider.Issuer(), "user-1").Return(UserIdentityStruct{ID: 1, UserID: 7}, nil)
+
+		twoFactor := newTwoFactorFixture(t)
+		twoFactor.repo.On("Get", uint(7)).Return(twoFactor.enabled(t, 7), nil)
+		var challenge *MFAChallengeStruct
+		twoFactor.challenges.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			challenge = args.Get(0).(*MFAChallengeStruct)
+		}).Return(nil)
+		f.service.WithTwoFactor(twoFactor.cipher, "WebPet1", twoFactor.repo, twoFactor.challenges)
+
+		_, err := f.login(t)
+		var mfa *SecondFactorRequiredError
+		require.ErrorAs(t, err, &mfa)
+		require.NotNil(t, challenge)
+		assert.Equal(t, uint(7), challenge.UserID)
+		assert.Equal(t, "browser", challenge.UserAgent)
+		assert.Equal(t, hashToken(mfa.Token), challenge.TokenHash)
+		// вход еще не состоялся - в журнале только пройденный первый фактор
+		assert.Equal(t, []authEvent{{UserID: 7, Type: userService.AuthEventOIDCFirstFactorPassed, IP: "10.0.0.1"}}, users.events)
+		f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	lockedUntil := testNow.Add(time.Hour)
+
+	t.Run("привязанный аккаунт заблокирован после серии неудачных паролей", func(t *testing.T) {
+		users := &fakeUsers{
+			byEmail: map[string]*userService.User{
+				"user@example.com": {ID: 7, Email: "user@example.com", Role: rbac.RoleUser},
+			},
+			lockedUntil: &lockedUntil,
+		}
+		f := newOIDCFixture(t, users)
+		f.identities.On("Get", f.provider.Issuer(), "user-1").Return(UserIdentityStruct{ID: 1, UserID: 7}, nil)
+
+		_, err := f.login(t)
+		var locked *userService.LockedError
+		require.ErrorAs(t, err, &locked)
+		assert.Equal(t, lockedUntil, locked.Until)
+		assert.Equal(t, []authEvent{{UserID: 7, Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureLocked, IP: "10.0.0.1"}}, users.events)
+		f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	tests := []struct {
+		name      string
+		users     *fakeUsers
+		user      oidctest.User
+		wantErr   string
+		wantEvent authEvent
+	}{
+		{
+			name:      "email не подтвержден провайдером",
+			users:     &fakeUsers{},
+			user:      oidctest.User{Subject: "sub-2", Email: "new@example.com", EmailVerified: false},
+			wantErr:   "email is not verified by the identity provider",
+			wantEvent: authEvent{Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureEmailUnverified, IP: "10.0.0.1"},
+		},
+		{
+			name: "локальный аккаунт с этим email не подтвержден",
+			users: &fakeUsers{byEmail: map[string]*userService.User{
+				"user@example.com": {ID: 7, Email: "user@example.com"},
+			}},
+			user:      oidctest.User{Subject: "sub-2", Email: "user@example.com", EmailVerified: true},
+			wantErr:   "account with this email has not verified it yet",
+			wantEvent: authEvent{UserID: 7, Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureEmailUnverified, IP: "10.0.0.1"},
+		},
+		{
+			name: "аккаунт отключен",
+			users: &fakeUsers{byEmail: map[string]*userService.User{
+				"off@example.com": {ID: 9, Email: "off@example.com", EmailVerifiedAt: &testNow, DisabledAt: &testNow},
+			}},
+			user:      oidctest.User{Subject: "sub-2", Email: "off@example.com", EmailVerified: true},
+			wantErr:   "account is disabled",
+			wantEvent: authEvent{UserID: 9, Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureDisabled, IP: "10.0.0.1"},
+		},
+		{
+			name: "вход заблокирован после серии неудачных паролей",
+			users: &fakeUsers{
+				byEmail: map[string]*userService.User{
+					"user@example.com": {ID: 7, Email: "user@example.com", EmailVerifiedAt: &testNow},
+				},
+				lockedUntil: &lockedUntil,
+			},
+			user:      oidctest.User{Subject: "sub-2", Email: "user@example.com", EmailVerified: true},
+			wantErr:   "account is temporarily locked",
+			wantEvent: authEvent{UserID: 7, Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureLocked, IP: "10.0.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t, tt.users)
+			f.provider.SetUser(tt.user)
+			f.identities.On("Get", f.provider.Issuer(), tt.user.Subject).Return(nil, gorm.ErrRecordNotFound)
+
+			_, err := f.login(t)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, f.linked)
+			assert.Equal(t, []authEvent{tt.wantEvent}, f.users.events)
+			f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestOIDCCallbackState(t *testing.T) {
+	f := newOIDCFixture(t, &fakeUsers{})
+	f.identities.On("Get", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	authURL, err := f.service.StartOIDCLogin(context.Background())
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	// state в бд - только хэш
+	require.Len(t, f.states.created, 1)
+	assert.Equal(t, hashToken(u.Query().Get("state")), f.states.created[0].StateHash)
+	assert.Equal(t, testNow.Add(oidcStateTTL), f.states.created[0].ExpiresAt)
+
+	code, state, err := f.provider.Authorize(authURL)
+	require.NoError(t, err)
+
+	// чужой state - отказ, и code для него не тратится
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: code, State: "forged"})
+	assert.EqualError(t, err, "invalid or expired state")
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: code, State: ""})
+	assert.EqualError(t, err, "invalid or expired state")
+	// мусорный callback в журнал входов не попадает
+	assert.Empty(t, f.users.events)
+
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: code, State: state})
+	assert.NoError(t, err)
+
+	// state одноразовый
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: code, State: state})
+	assert.EqualError(t, err, "invalid or expired state")
+
+	// провайдер отказал - state гасится
+	authURL, err = f.service.StartOIDCLogin(context.Background())
+	require.NoError(t, err)
+	_, state, err = f.provider.Authorize(authURL)
+	require.NoError(t, err)
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{State: state, Error: "access_denied"})
+	assert.EqualError(t, err, "identity provider rejected the login: access_denied")
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: "x", State: state})
+	assert.EqualError(t, err, "invalid or expired state")
+
+	// поддельный code - провайдер не меняет его на токены
+	authURL, err = f.service.StartOIDCLogin(context.Background())
+	require.NoError(t, err)
+	_, state, err = f.provider.Authorize(authURL)
+	require.NoError(t, err)
+	_, err = f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: "forged", State: state})
+	assert.EqualError(t, err, "identity provider rejected the login")
+
+	// успешный вход и оба отказа провайдера - в журнале входов
+	rejected := authEvent{Type: userService.AuthEventOIDCLoginFailed, Reason: userService.LoginFailureProviderRejected}
+	assert.Equal(t, []authEvent{{UserID: 101, Type: userService.AuthEventOIDCLoginSucceeded}, rejected, rejected}, f.users.events)
+}
+
+func TestOIDCNotConfigured(t *testing.T) {
+	service := newSessionService(new(MockSessionRepo))
+
+	_, err := service.StartOIDCLogin(context.Background())
+	assert.EqualError(t, err, "oidc login is not configured")
+	_, err = service.CompleteOIDCLogin(context.Background(), OIDCCallbackParams{Code: "c", State: "s"})
+	assert.EqualError(t, err, "oidc login is not configured")
+}
@@ -0,0 +1,146 @@
+package authService
+
+import "time"
+
+// модель базы данных
+// в бд хранится только sha256 от токена - сам токен знает лишь получатель письма
+type PasswordResetTokenStruct struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `gorm:"not null"`
+	TokenHash string     `gorm:"not null"` // sha256 токена (hex)
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // nil - токен еще не использован
+	CreatedAt time.Time
+}
+
+func (PasswordResetTokenStruct) TableName() string {
+	return "password_reset_tokens" // как в миграции
+}
+
+// токен подтверждения email: привязан к адресу, на который ушло письмо,
+// чтобы после смены email старая ссылка не подтвердила новый адрес
+type EmailVerificationTokenStruct struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"not null"`
+	Email     string    `gorm:"not null"`
+	TokenHash string    `gorm:"not null"` // sha256 токена (hex)
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (EmailVerificationTokenStruct) TableName() string {
+	return "email_verification_tokens" // как в миграции
+}
+
+// сессия входа = семейство refresh-токенов
+// каждая ротация создает новую строку с тем же FamilyID, а старую помечает RotatedAt;
+// активна только последняя строка семейства (RotatedAt и RevokedAt пустые)
+type SessionStruct struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement"`
+	FamilyID         string `gorm:"not null"` // id сессии для клиента (не меняется при ротации)
+	UserID           uint   `gorm:"not null"`
+	RefreshTokenHash string `gorm:"not null"` // sha256 refresh-токена (hex)
+	DeviceName       string
+	UserAgent        string
+	StartedAt        time.Time  `gorm:"not null"` // вход (одинаковый для всего семейства)
+	CreatedAt        time.Time  // когда выдан этот refresh-токен (= последнее обновление)
+	ExpiresAt        time.Time  `gorm:"not null"`
+	RotatedAt        *time.Time // токен уже обменян на новый - повторное использование значит утечку
+	RevokedAt        *time.Time
+}
+
+func (SessionStruct) TableName() string {
+	return "sessions" // как в миграции
+}
+
+// персональный токен для скриптов и CI: живет, пока его не отзовут (или до ExpiresAt, если задан)
+// в бд - только sha256 токена и его начало (чтобы пользователь узнал токен в списке)
+type PersonalTokenStruct struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	UserID      uint       `gorm:"not null"`
+	Name        string     `gorm:"not null"`
+	TokenPrefix string     `gorm:"not null"` // первые символы токена
+	TokenHash   string     `gorm:"not null"` // sha256 токена (hex)
+	Scopes      string     `gorm:"not null"` // скоупы через пробел (как в OAuth)
+	ExpiresAt   *time.Time // nil - бессрочный
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func (PersonalTokenStruct) TableName() string {
+	return "personal_access_tokens" // как в миграции
+}
+
+// начатый вход через OIDC: ждем, когда провайдер вернет браузер на callback
+// state одноразовый (по нему находим запись), nonce и code_verifier нужны при обмене code
+type OIDCLoginStateStruct struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	StateHash    string    `gorm:"not null"` // sha256 state (hex)
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"` // секрет PKCE, провайдер видел только его хэш
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (OIDCLoginStateStruct) TableName() string {
+	return "oidc_login_states" // как в миграции
+}
+
+// внешний аккаунт (issuer + subject), через который пользователь входит
+// связь по subject, а не по email: email у провайдера может смениться
+type UserIdentityStruct struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    uint   `gorm:"not null"`
+	Issuer    string `gorm:"not null"`
+	Subject   string `gorm:"not null"`
+	Email     string // email у провайдера при привязке (для информации)
+	CreatedAt time.Time
+}
+
+func (UserIdentityStruct) TableName() string {
+	return "user_identities" // как в миграции
+}
+
+// TOTP-секрет пользователя (двухфакторная аутентификация)
+// секрет зашифрован ключом из конфига; до подтверждения кодом (ConfirmedAt) вход его не требует
+type TOTPStruct struct {
+	UserID          uint       `gorm:"primaryKey;autoIncrement:false"`
+	SecretEncrypted string     `gorm:"not null"`
+	ConfirmedAt     *time.Time // nil - подключение начато, но не подтверждено
+	LastUsedStep    *uint64    // шаг последнего принятого кода - тот же код второй раз не пройдет
+	CreatedAt       time.Time
+}
+
+func (TOTPStruct) TableName() string {
+	return "user_totp" // как в миграции
+}
+
+// одноразовый код восстановления (вход, если телефон с приложением потерян)
+type RecoveryCodeStruct struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `gorm:"not null"`
+	CodeHash  string     `gorm:"not null"` // sha256 кода (hex)
+	UsedAt    *time.Time // nil - код еще не использован
+	CreatedAt time.Time
+}
+
+func (RecoveryCodeStruct) TableName() string {
+	return "recovery_codes" // как в миграции
+}
+
+// вход, который ждет второй фактор: пароль уже проверен, осталось ввести код
+type MFAChallengeStruct struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	TokenHash  string `gorm:"not null"` // sha256 токена (hex)
+	UserID     uint   `gorm:"not null"`
+	DeviceName string
+	UserAgent  string
+	Attempts   int       `gorm:"not null;default:0"` // неверные коды - после лимита вход нужно начинать заново
+	ExpiresAt  time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+func (MFAChallengeStruct) TableName() string {
+	return "mfa_challenges" // как в миграции
+}
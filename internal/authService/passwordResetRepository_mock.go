@@ -0,0 +1,31 @@
+package authService
+
+import "github.com/stretchr/testify/mock"
+
+type MockPasswordResetRepo struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepo) Create(token *PasswordResetTokenStruct) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepo) GetValid(tokenHash string) (PasswordResetTokenStruct, error) {
+	args := m.Called(tokenHash)
+	var token PasswordResetTokenStruct
+	if res := args.Get(0); res != nil {
+		token = res.(PasswordResetTokenStruct)
+	}
+	return token, args.Error(1)
+}
+
+func (m *MockPasswordResetRepo) Consume(id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetRepo) DeleteForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
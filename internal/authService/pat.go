@@ -0,0 +1,158 @@
+package authService
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+)
+
+// персональные токены (для скриптов и CI, где нельзя войти интерактивно):
+//   • у токена есть имя, скоупы (tasks:read, tasks:write...) и необязательный срок действия
+//   • сам токен показывается один раз при создании, в бд хранится только его sha256
+//   • передается так же, как access-токен: Authorization: Bearer wpat_...
+//   • время последнего использования обновляется не чаще раза в минуту (чтобы не писать в бд на каждый запрос)
+
+const (
+	personalTokenPrefix       = "wpat_" // по нему Authenticate отличает токен от JWT
+	personalTokenPrefixLength = 12      // сколько первых символов показываем в списке
+	maxPersonalTokenName      = 100
+	lastUsedResolution        = time.Minute
+)
+
+// структура параметров метода CreatePersonalToken
+type CreatePersonalTokenParams struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time // nil - бессрочный
+}
+
+// бизнес-модель персонального токена (без секрета)
+type PersonalToken struct {
+	ID         uint
+	Name       string
+	Prefix     string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// CreatedPersonalToken - только что созданный токен вместе с секретом (отдается клиенту один раз)
+type CreatedPersonalToken struct {
+	PersonalToken
+	Token string
+}
+
+// toPersonalToken - маппит бд-модель в бизнес-модель
+func toPersonalToken(t *PersonalTokenStruct) PersonalToken {
+	return PersonalToken{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.TokenPrefix,
+		Scopes:     strings.Fields(t.Scopes),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+// CreatePersonalToken - создает токен пользователя
+func (s *AuthService) CreatePersonalToken(userID uint, params CreatePersonalTokenParams) (*CreatedPersonalToken, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return nil, errors.New("token name is empty")
+	}
+	if len([]rune(name)) > maxPersonalTokenName {
+		return nil, errors.New("token name is too long")
+	}
+
+	if len(params.Scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	scopes, err := rbac.ParseScopes(params.Scopes)
+	if err != nil {
+		return nil, errors.New("invalid scope: " + err.Error())
+	}
+
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(s.now()) {
+		return nil, errors.New("token expiry must be in the future")
+	}
+
+	secret, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	token := personalTokenPrefix + secret
+
+	record := &PersonalTokenStruct{
+		UserID:      userID,
+		Name:        name,
+		TokenPrefix: token[:personalTokenPrefixLength],
+		TokenHash:   hashToken(token),
+		Scopes:      strings.Join(scopes, " "),
+		ExpiresAt:   params.ExpiresAt,
+		CreatedAt:   s.now(),
+	}
+	if err := s.tokens.Create(record); err != nil {
+		return nil, err
+	}
+
+	return &CreatedPersonalToken{PersonalToken: toPersonalToken(record), Token: token}, nil
+}
+
+// ListPersonalTokens - токены пользователя (новые сверху)
+func (s *AuthService) ListPersonalTokens(userID uint) ([]PersonalToken, error) {
+	records, err := s.tokens.ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]PersonalToken, 0, len(records))
+	for i := range records {
+		tokens = append(tokens, toPersonalToken(&records[i]))
+	}
+	return tokens, nil
+}
+
+// RevokePersonalToken - отзывает токен пользователя (действует сразу)
+func (s *AuthService) RevokePersonalToken(userID, id uint) error {
+	ok, err := s.tokens.Revoke(userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// authenticatePersonalToken - проверка персонального токена (вызывается из Authenticate)
+func (s *AuthService) authenticatePersonalToken(token string) (*Principal, error) {
+	record, err := s.tokens.GetActiveByHash(hashToken(token))
+	if err != nil {
+		return nil, errors.New("invalid or expired personal access token")
+	}
+
+	user, err := s.activeAccount(record.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= lastUsedResolution {
+		// не получилось записать время - не повод отклонять запрос
+		if err := s.tokens.TouchLastUsed(record.ID, now); err != nil {
+			log.Printf("Failed to update last use of personal access token %d: %v", record.ID, err)
+		}
+	}
+
+	return &Principal{
+		UserID:  record.UserID,
+		TokenID: record.ID,
+		Scopes:  strings.Fields(record.Scopes),
+		Role:    user.Role,
+	}, nil
+}
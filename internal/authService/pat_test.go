@@ -0,0 +1,155 @@
+package authService
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newTokenService - сервис с фиксированным временем и репозиторием персональных токенов
+func newTokenService(repo *MockPersonalTokenRepo) *AuthService {
+	users := &fakeUsers{byEmail: map[string]*userService.User{
+		"user@example.com": {ID: 7, Email: "user@example.com", Role: rbac.RoleUser},
+		"off@example.com":  {ID: 9, Email: "off@example.com", Role: rbac.RoleUser, DisabledAt: &testNow},
+	}}
+	service := NewAuthService(users, new(MockPasswordResetRepo), new(MockEmailVerificationRepo), new(MockSessionRepo), repo, &fakeMailer{})
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestCreatePersonalToken(t *testing.T) {
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		var stored *PersonalTokenStruct
+		repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*PersonalTokenStruct)
+			stored.ID = 3
+		}).Return(nil)
+
+		expires := testNow.Add(24 * time.Hour)
+		created, err := newTokenService(repo).CreatePersonalToken(7, CreatePersonalTokenParams{
+			Name:      "  CI  ",
+			Scopes:    []string{"tasks:write", "tasks:read"},
+			ExpiresAt: &expires,
+		})
+		assert.NoError(t, err)
+
+		// секрет отдается один раз, в бд - только хэш и начало токена
+		assert.True(t, strings.HasPrefix(created.Token, personalTokenPrefix))
+		assert.Equal(t, hashToken(created.Token), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, created.Token)
+		assert.Equal(t, created.Token[:personalTokenPrefixLength], created.Prefix)
+
+		assert.Equal(t, uint(3), created.ID)
+		assert.Equal(t, "CI", created.Name)
+		assert.Equal(t, []string{"tasks:read", "tasks:write"}, created.Scopes)
+		assert.Equal(t, "tasks:read tasks:write", stored.Scopes)
+		assert.Equal(t, uint(7), stored.UserID)
+	})
+
+	past := testNow.Add(-time.Minute)
+	tests := []struct {
+		name    string
+		params  CreatePersonalTokenParams
+		wantErr string
+	}{
+		{name: "пустое имя", params: CreatePersonalTokenParams{Name: " ", Scopes: []string{"tasks:read"}}, wantErr: "token name is empty"},
+		{name: "длинное имя", params: CreatePersonalTokenParams{Name: strings.Repeat("x", 101), Scopes: []string{"tasks:read"}}, wantErr: "token name is too long"},
+		{name: "без скоупов", params: CreatePersonalTokenParams{Name: "CI"}, wantErr: "at least one scope is required"},
+		{name: "неизвестный скоуп", params: CreatePersonalTokenParams{Name: "CI", Scopes: []string{"admin"}}, wantErr: "invalid scope"},
+		{name: "срок в прошлом", params: CreatePersonalTokenParams{Name: "CI", Scopes: []string{"tasks:read"}, ExpiresAt: &past}, wantErr: "token expiry must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPersonalTokenRepo)
+
+			_, err := newTokenService(repo).CreatePersonalToken(7, tt.params)
+			assert.ErrorContains(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestAuthenticatePersonalToken(t *testing.T) {
+	const token = "wpat_secret"
+	recent := testNow.Add(-10 * time.Second)
+
+	t.Run("верный токен", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		repo.On("GetActiveByHash", hashToken(token)).Return(PersonalTokenStruct{ID: 3, UserID: 7, Scopes: "tasks:read"}, nil)
+		repo.On("TouchLastUsed", uint(3), testNow).Return(nil)
+
+		principal, err := newTokenService(repo).Authenticate(token)
+		assert.NoError(t, err)
+		assert.Equal(t, &Principal{UserID: 7, TokenID: 3, Scopes: []string{"tasks:read"}, Role: rbac.RoleUser}, principal)
+		assert.True(t, principal.IsPersonalToken())
+		assert.True(t, principal.HasScope(rbac.ScopeTasksRead))
+		assert.False(t, principal.HasScope(rbac.ScopeTasksWrite))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("недавно использованный токен - время не переписываем", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		repo.On("GetActiveByHash", hashToken(token)).Return(PersonalTokenStruct{ID: 3, UserID: 7, Scopes: "tasks:read", LastUsedAt: &recent}, nil)
+
+		_, err := newTokenService(repo).Authenticate(token)
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка записи времени не мешает запросу", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		repo.On("GetActiveByHash", hashToken(token)).Return(PersonalTokenStruct{ID: 3, UserID: 7, Scopes: "tasks:read"}, nil)
+		repo.On("TouchLastUsed", uint(3), testNow).Return(errors.New("db error"))
+
+		_, err := newTokenService(repo).Authenticate(token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("отозванный, истекший или неизвестный токен", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		repo.On("GetActiveByHash", hashToken(token)).Return(PersonalTokenStruct{}, gorm.ErrRecordNotFound)
+
+		_, err := newTokenService(repo).Authenticate(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("аккаунт отключен", func(t *testing.T) {
+		repo := new(MockPersonalTokenRepo)
+		repo.On("GetActiveByHash", hashToken(token)).Return(PersonalTokenStruct{ID: 4, UserID: 9, Scopes: "tasks:read"}, nil)
+
+		_, err := newTokenService(repo).Authenticate(token)
+		assert.EqualError(t, err, "account is disabled")
+		repo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("сессия скоупами не ограничена", func(t *testing.T) {
+		assert.True(t, Principal{UserID: 7, SessionID: "family-1"}.HasScope(rbac.ScopeUsersWrite))
+	})
+}
+
+func TestListAndRevokePersonalTokens(t *testing.T) {
+	repo := new(MockPersonalTokenRepo)
+	repo.On("ListForUser", uint(7)).Return([]PersonalTokenStruct{
+		{ID: 2, Name: "deploy", TokenPrefix: "wpat_abcdefg", Scopes: "tasks:read tasks:write"},
+	}, nil)
+	repo.On("Revoke", uint(7), uint(2)).Return(true, nil)
+	repo.On("Revoke", uint(7), uint(5)).Return(false, nil)
+	service := newTokenService(repo)
+
+	tokens, err := service.ListPersonalTokens(7)
+	assert.NoError(t, err)
+	assert.Equal(t, []PersonalToken{{ID: 2, Name: "deploy", Prefix: "wpat_abcdefg", Scopes: []string{"tasks:read", "tasks:write"}}}, tokens)
+
+	assert.NoError(t, service.RevokePersonalToken(7, 2))
+	// чужой или уже отозванный токен
+	assert.EqualError(t, service.RevokePersonalToken(7, 5), "token not found")
+}
@@ -0,0 +1,44 @@
+package authService
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPersonalTokenRepo struct {
+	mock.Mock
+}
+
+func (m *MockPersonalTokenRepo) Create(token *PersonalTokenStruct) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPersonalTokenRepo) GetActiveByHash(tokenHash string) (PersonalTokenStruct, error) {
+	args := m.Called(tokenHash)
+	var token PersonalTokenStruct
+	if res := args.Get(0); res != nil {
+		token = res.(PersonalTokenStruct)
+	}
+	return token, args.Error(1)
+}
+
+func (m *MockPersonalTokenRepo) ListForUser(userID uint) ([]PersonalTokenStruct, error) {
+	args := m.Called(userID)
+	var tokens []PersonalTokenStruct
+	if res := args.Get(0); res != nil {
+		tokens = res.([]PersonalTokenStruct)
+	}
+	return tokens, args.Error(1)
+}
+
+func (m *MockPersonalTokenRepo) Revoke(userID, id uint) (bool, error) {
+	args := m.Called(userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepo) TouchLastUsed(id uint, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
@@ -0,0 +1,429 @@
+package authService
+
+import (
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repo-слой для токенов сброса пароля (только запросы к бд)
+
+type PasswordResetRepoInterface interface {
+	Create(token *PasswordResetTokenStruct) error
+	GetValid(tokenHash string) (PasswordResetTokenStruct, error)
+	Consume(id uint) (bool, error)
+	DeleteForUser(userID uint) error
+}
+
+type PasswordResetRepo struct{}
+
+func (r *PasswordResetRepo) Create(token *PasswordResetTokenStruct) error {
+	return db.DB.Create(token).Error
+}
+
+// GetValid - возвращает неиспользованный и не истекший токен (gorm.ErrRecordNotFound, если такого нет)
+func (r *PasswordResetRepo) GetValid(tokenHash string) (PasswordResetTokenStruct, error) {
+	var token PasswordResetTokenStruct
+	err := db.DB.
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return PasswordResetTokenStruct{}, err
+	}
+	return token, nil
+}
+
+// Consume - помечает токен использованным
+// условие в WHERE делает это атомарно: из двух одновременных запросов с одним токеном пройдет только один
+func (r *PasswordResetRepo) Consume(id uint) (bool, error) {
+	now := time.Now()
+	res := db.DB.Model(&PasswordResetTokenStruct{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// DeleteForUser - удаляет прежние токены пользователя (действует только последняя ссылка из письма)
+// заодно чистим все истекшие токены, чтобы таблица не росла
+func (r *PasswordResetRepo) DeleteForUser(userID uint) error {
+	return db.DB.
+		Where("user_id = ? OR expires_at <= ?", userID, time.Now()).
+		Delete(&PasswordResetTokenStruct{}).Error
+}
+
+// repo-слой для токенов подтверждения email
+
+type EmailVerificationRepoInterface interface {
+	Create(token *EmailVerificationTokenStruct) error
+	Take(tokenHash string) (EmailVerificationTokenStruct, error)
+	DeleteForUser(userID uint) error
+}
+
+type EmailVerificationRepo struct{}
+
+func (r *EmailVerificationRepo) Create(token *EmailVerificationTokenStruct) error {
+	return db.DB.Create(token).Error
+}
+
+// Take - забирает (удаляет и возвращает) не истекший токен: так он срабатывает ровно один раз
+// (gorm.ErrRecordNotFound, если такого токена нет)
+func (r *EmailVerificationRepo) Take(tokenHash string) (EmailVerificationTokenStruct, error) {
+	var token EmailVerificationTokenStruct
+	res := db.DB.
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND expires_at > ?", tokenHash, time.Now()).
+		Delete(&token)
+	if res.Error != nil {
+		return EmailVerificationTokenStruct{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return EmailVerificationTokenStruct{}, gorm.ErrRecordNotFound
+	}
+	return token, nil
+}
+
+// DeleteForUser - удаляет прежние токены пользователя и все истекшие
+func (r *EmailVerificationRepo) DeleteForUser(userID uint) error {
+	return db.DB.
+		Where("user_id = ? OR expires_at <= ?", userID, time.Now()).
+		Delete(&EmailVerificationTokenStruct{}).Error
+}
+
+// repo-слой для сессий (refresh-токенов)
+
+type SessionRepoInterface interface {
+	Create(session *SessionStruct) error
+	GetByTokenHash(tokenHash string) (SessionStruct, error)
+	Rotate(id uint) (bool, error)
+	GetActive(familyID string) (SessionStruct, error)
+	ListActive(userID uint) ([]SessionStruct, error)
+	RevokeFamily(userID uint, familyID string) (bool, error)
+	RevokeForUser(userID uint) error
+}
+
+type SessionRepo struct{}
+
+func (r *SessionRepo) Create(session *SessionStruct) error {
+	return db.DB.Create(session).Error
+}
+
+// GetByTokenHash - строка по хэшу refresh-токена, в любом состоянии (нужно, чтобы заметить повторное использование)
+func (r *SessionRepo) GetByTokenHash(tokenHash string) (SessionStruct, error) {
+	var session SessionStruct
+	err := db.DB.Where("refresh_token_hash = ?", tokenHash).First(&session).Error
+	if err != nil {
+		return SessionStruct{}, err
+	}
+	return session, nil
+}
+
+// Rotate - помечает токен обменянным; false - его уже обменял (или отозвал) другой запрос
+func (r *SessionRepo) Rotate(id uint) (bool, error) {
+	res := db.DB.Model(&SessionStruct{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// activeScope - условие "последний, не отозванный и не истекший токен семейства"
+func activeScope(tx *gorm.DB) *gorm.DB {
+	return tx.Where("rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", time.Now())
+}
+
+// GetActive - активная строка сессии (gorm.ErrRecordNotFound, если сессия отозвана или истекла)
+func (r *SessionRepo) GetActive(familyID string) (SessionStruct, error) {
+	var session SessionStruct
+	err := db.DB.Scopes(activeScope).Where("family_id = ?", familyID).First(&session).Error
+	if err != nil {
+		return SessionStruct{}, err
+	}
+	return session, nil
+}
+
+// ListActive - активные сессии пользователя (новые сверху)
+func (r *SessionRepo) ListActive(userID uint) ([]SessionStruct, error) {
+	var sessions []SessionStruct
+	err := db.DB.Scopes(activeScope).
+		Where("user_id = ?", userID).
+		Order("started_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeFamily - отзывает все токены одной сессии пользователя; false - такой активной сессии нет
+func (r *SessionRepo) RevokeFamily(userID uint, familyID string) (bool, error) {
+	res := db.DB.Model(&SessionStruct{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// RevokeForUser - отзывает все сессии пользователя
+func (r *SessionRepo) RevokeForUser(userID uint) error {
+	return db.DB.Model(&SessionStruct{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+type PersonalTokenRepoInterface interface {
+	Create(token *PersonalTokenStruct) error
+	GetActiveByHash(tokenHash string) (PersonalTokenStruct, error)
+	ListForUser(userID uint) ([]PersonalTokenStruct, error)
+	Revoke(userID, id uint) (bool, error)
+	TouchLastUsed(id uint, at time.Time) error
+}
+
+type PersonalTokenRepo struct{}
+
+func (r *PersonalTokenRepo) Create(token *PersonalTokenStruct) error {
+	return db.DB.Create(token).Error
+}
+
+// GetActiveByHash - не отозванный и не истекший токен (gorm.ErrRecordNotFound - такого нет)
+func (r *PersonalTokenRepo) GetActiveByHash(tokenHash string) (PersonalTokenStruct, error) {
+	var token PersonalTokenStruct
+	err := db.DB.
+		Where("token_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return PersonalTokenStruct{}, err
+	}
+	return token, nil
+}
+
+// ListForUser - не отозванные токены пользователя (истекшие тоже - чтобы было видно, что их пора заменить)
+func (r *PersonalTokenRepo) ListForUser(userID uint) ([]PersonalTokenStruct, error) {
+	var tokens []PersonalTokenStruct
+	err := db.DB.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke - отзывает токен пользователя; false - такого токена нет (или он уже отозван)
+func (r *PersonalTokenRepo) Revoke(userID, id uint) (bool, error) {
+	res := db.DB.Model(&PersonalTokenStruct{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// TouchLastUsed - запоминает время последнего использования
+func (r *PersonalTokenRepo) TouchLastUsed(id uint, at time.Time) error {
+	return db.DB.Model(&PersonalTokenStruct{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+// repo-слой для входа через OIDC
+
+type OIDCStateRepoInterface interface {
+	Create(state *OIDCLoginStateStruct) error
+	Take(stateHash string) (OIDCLoginStateStruct, error)
+}
+
+type OIDCStateRepo struct{}
+
+// Create - сохраняет state и заодно удаляет истекшие (брошенные на странице провайдера входы)
+func (r *OIDCStateRepo) Create(state *OIDCLoginStateStruct) error {
+	err := db.DB.Where("expires_at <= ?", time.Now()).Delete(&OIDCLoginStateStruct{}).Error
+	if err != nil {
+		return err
+	}
+	return db.DB.Create(state).Error
+}
+
+// Take - забирает (удаляет и возвращает) не истекший state: callback с ним срабатывает один раз
+// (gorm.ErrRecordNotFound, если такого state нет)
+func (r *OIDCStateRepo) Take(stateHash string) (OIDCLoginStateStruct, error) {
+	var state OIDCLoginStateStruct
+	res := db.DB.
+		Clauses(clause.Returning{}).
+		Where("state_hash = ? AND expires_at > ?", stateHash, time.Now()).
+		Delete(&state)
+	if res.Error != nil {
+		return OIDCLoginStateStruct{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return OIDCLoginStateStruct{}, gorm.ErrRecordNotFound
+	}
+	return state, nil
+}
+
+type IdentityRepoInterface interface {
+	Get(issuer, subject string) (UserIdentityStruct, error)
+	Create(identity *UserIdentityStruct) error
+}
+
+type IdentityRepo struct{}
+
+// Get - внешний аккаунт по issuer и subject (gorm.ErrRecordNotFound - еще не привязан)
+func (r *IdentityRepo) Get(issuer, subject string) (UserIdentityStruct, error) {
+	var identity UserIdentityStruct
+	err := db.DB.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		return UserIdentityStruct{}, err
+	}
+	return identity, nil
+}
+
+func (r *IdentityRepo) Create(identity *UserIdentityStruct) error {
+	return db.DB.Create(identity).Error
+}
+
+// repo-слой для двухфакторной аутентификации
+
+type TwoFactorRepoInterface interface {
+	Get(userID uint) (TOTPStruct, error)
+	Save(totp *TOTPStruct) error
+	Confirm(userID uint, at time.Time) error
+	UseStep(userID uint, step uint64) (bool, error)
+	Delete(userID uint) error
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uint) (int, error)
+}
+
+type TwoFactorRepo struct{}
+
+// Get - TOTP пользователя (gorm.ErrRecordNotFound - двухфакторная аутентификация не подключалась)
+func (r *TwoFactorRepo) Get(userID uint) (TOTPStruct, error) {
+	var totp TOTPStruct
+	err := db.DB.Where("user_id = ?", userID).First(&totp).Error
+	if err != nil {
+		return TOTPStruct{}, err
+	}
+	return totp, nil
+}
+
+// Save - создает или заменяет неподтвержденный секрет (подтвержденный не трогаем)
+func (r *TwoFactorRepo) Save(totp *TOTPStruct) error {
+	return db.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret_encrypted", "created_at", "last_used_step"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_totp.confirmed_at IS NULL"}}},
+	}).Create(totp).Error
+}
+
+func (r *TwoFactorRepo) Confirm(userID uint, at time.Time) error {
+	return db.DB.Model(&TOTPStruct{}).Where("user_id = ?", userID).Update("confirmed_at", at).Error
+}
+
+// UseStep - запоминает шаг принятого кода; false - этот (или более поздний) шаг уже использован
+func (r *TwoFactorRepo) UseStep(userID uint, step uint64) (bool, error) {
+	res := db.DB.Model(&TOTPStruct{}).
+		Where("user_id = ? AND (last_used_step IS NULL OR last_used_step < ?)", userID, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Delete - отключает двухфакторную аутентификацию (вместе с кодами восстановления)
+func (r *TwoFactorRepo) Delete(userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeStruct{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&TOTPStruct{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes - новый набор кодов, старые перестают работать
+func (r *TwoFactorRepo) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeStruct{}).Error; err != nil {
+			return err
+		}
+		codes := make([]RecoveryCodeStruct, 0, len(codeHashes))
+		for _, h := range codeHashes {
+			codes = append(codes, RecoveryCodeStruct{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode - гасит код атомарно; false - такого кода нет или он уже использован
+func (r *TwoFactorRepo) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	res := db.DB.Model(&RecoveryCodeStruct{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// CountRecoveryCodes - сколько неиспользованных кодов осталось
+func (r *TwoFactorRepo) CountRecoveryCodes(userID uint) (int, error) {
+	var count int64
+	err := db.DB.Model(&RecoveryCodeStruct{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return int(count), err
+}
+
+type MFAChallengeRepoInterface interface {
+	Create(challenge *MFAChallengeStruct) error
+	GetValid(tokenHash string) (MFAChallengeStruct, error)
+	RegisterAttempt(id uint) (int, error)
+	Delete(id uint) error
+}
+
+type MFAChallengeRepo struct{}
+
+// Create - сохраняет вход и заодно удаляет истекшие
+func (r *MFAChallengeRepo) Create(challenge *MFAChallengeStruct) error {
+	err := db.DB.Where("expires_at <= ?", time.Now()).Delete(&MFAChallengeStruct{}).Error
+	if err != nil {
+		return err
+	}
+	return db.DB.Create(challenge).Error
+}
+
+// GetValid - не истекший вход по хэшу токена (gorm.ErrRecordNotFound - такого нет)
+func (r *MFAChallengeRepo) GetValid(tokenHash string) (MFAChallengeStruct, error) {
+	var challenge MFAChallengeStruct
+	err := db.DB.Where("token_hash = ? AND expires_at > ?", tokenHash, time.Now()).First(&challenge).Error
+	if err != nil {
+		return MFAChallengeStruct{}, err
+	}
+	return challenge, nil
+}
+
+// RegisterAttempt - атомарно считает попытку ввода кода и возвращает их число
+func (r *MFAChallengeRepo) RegisterAttempt(id uint) (int, error) {
+	var challenge MFAChallengeStruct
+	res := db.DB.Model(&challenge).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return challenge.Attempts, nil
+}
+
+func (r *MFAChallengeRepo) Delete(id uint) error {
+	return db.DB.Delete(&MFAChallengeStruct{}, id).Error
+}
@@ -0,0 +1,280 @@
+package authService
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/encryption"
+	"github.com/AntonRadchenko/WebPet1/internal/mailer"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+)
+
+// 3. service-слой для аутентификации
+// вход и сессии (access- и refresh-токены) - в session.go, вход через внешний OIDC-провайдер - в oidc.go,
+// двухфакторная аутентификация (TOTP и коды восстановления) - в twofactor.go
+// сброс пароля по одноразовому токену из письма:
+//   1) POST /auth/password-reset - создаем токен и отправляем ссылку на почту
+//   2) POST /auth/password-reset/confirm - по токену из ссылки задаем новый пароль
+// подтверждение email:
+//   1) после регистрации или смены email userService просит отправить письмо (SendEmailVerification)
+//   2) GET /auth/verify?token=... - отмечаем адрес подтвержденным
+// токены случайные (32 байта), в бд лежит только их хэш, они одноразовые и живут ограниченное время
+
+// Users - то, что нужно от userService (интерфейс, чтобы в тестах подставлять заглушку)
+type Users interface {
+	GetUserByEmail(email string) (*userService.User, error)
+	ValidateNewPassword(id uint, password string) error
+	SetPassword(id uint, password string) (*userService.User, error)
+	MarkEmailVerified(id uint, email string) (*userService.User, error)
+	CheckCredentials(attempt userService.LoginAttempt) (*userService.User, error)
+	GetAccount(id uint) (*userService.User, error)
+	CheckLockout(id uint) error
+	CreateExternalUser(email string) (*userService.User, error)
+	RecordAuthEvent(userID uint, eventType, reason string, attempt userService.LoginAttempt)
+}
+
+// настройки по умолчанию
+const (
+	DefaultPasswordResetTTL = time.Hour
+	DefaultPasswordResetURL = "http://localhost:9092/reset-password"
+	DefaultVerifyEmailTTL   = 24 * time.Hour
+	DefaultVerifyEmailURL   = "http://localhost:9092/auth/verify"
+)
+
+// размер токена в байтах (до кодирования в base64)
+const tokenBytes = 32
+
+type AuthService struct {
+	users         Users
+	resets        PasswordResetRepoInterface
+	verifications EmailVerificationRepoInterface
+	sessions      SessionRepoInterface
+	tokens        PersonalTokenRepoInterface
+	mailer        mailer.Mailer
+	oidc          IdentityProvider // nil - вход через OIDC выключен
+	oidcStates    OIDCStateRepoInterface
+	identities    IdentityRepoInterface
+	twoFactor     TwoFactorRepoInterface // nil - двухфакторная аутентификация выключена
+	mfaChallenges MFAChallengeRepoInterface
+	totpCipher    *encryption.Cipher // nil - подключать TOTP нельзя (ключ шифрования не задан)
+	totpIssuer    string
+	resetURL      string        // страница фронтенда, к ней добавляется ?token=...
+	resetTTL      time.Duration // сколько живет токен
+	verifyURL     string        // эндпоинт GET /auth/verify (снаружи), к нему добавляется ?token=...
+	verifyTTL     time.Duration
+	jwtSecret     []byte // секрет подписи access-токенов
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+
+	now func() time.Time // подменяется в тестах
+}
+
+func NewAuthService(users Users, resets PasswordResetRepoInterface, verifications EmailVerificationRepoInterface,
+	sessions SessionRepoInterface, tokens PersonalTokenRepoInterface, m mailer.Mailer) *AuthService {
+	// случайный секрет по умолчанию: токены действуют только до перезапуска (в проде секрет задается в конфиге)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+
+	return &AuthService{
+		users:         users,
+		resets:        resets,
+		verifications: verifications,
+		sessions:      sessions,
+		tokens:        tokens,
+		mailer:        m,
+		resetURL:      DefaultPasswordResetURL,
+		resetTTL:      DefaultPasswordResetTTL,
+		verifyURL:     DefaultVerifyEmailURL,
+		verifyTTL:     DefaultVerifyEmailTTL,
+		totpIssuer:    DefaultTOTPIssuer,
+		jwtSecret:     secret,
+		accessTTL:     DefaultAccessTokenTTL,
+		refreshTTL:    DefaultRefreshTokenTTL,
+		now:           time.Now,
+	}
+}
+
+// WithPasswordReset - задает адрес страницы сброса пароля и время жизни токена (из конфига)
+func (s *AuthService) WithPasswordReset(resetURL string, ttl time.Duration) *AuthService {
+	s.resetURL = resetURL
+	s.resetTTL = ttl
+	return s
+}
+
+// RequestPasswordReset - отправляет письмо со ссылкой для сброса пароля
+// если такого пользователя нет - молча ничего не делаем: ответ не должен выдавать, зарегистрирован ли email
+func (s *AuthService) RequestPasswordReset(email string) error {
+	user, err := s.users.GetUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	// старые ссылки перестают работать - действует только последняя
+	if err := s.resets.DeleteForUser(user.ID); err != nil {
+		return err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	record := &PasswordResetTokenStruct{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: s.now().Add(s.resetTTL),
+	}
+	if err := s.resets.Create(record); err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.resetURL, token)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf("Someone asked to reset the password for your account.\n\n"+
+			"Open this link to choose a new password (valid for %s):\n%s\n\n"+
+			"If it wasn't you, just ignore this email - your password stays the same.",
+			s.resetTTL, link),
+	})
+}
+
+// ConfirmPasswordReset - задает новый пароль по токену из письма
+func (s *AuthService) ConfirmPasswordReset(token, newPassword string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("invalid or expired token")
+	}
+
+	record, err := s.resets.GetValid(hashToken(token))
+	if err != nil || record.ID == 0 {
+		return errors.New("invalid or expired token")
+	}
+
+	// сначала проверяем пароль по политике, чтобы неудачная попытка не сжигала токен
+	if err := s.users.ValidateNewPassword(record.UserID, newPassword); err != nil {
+		return err
+	}
+
+	// гасим токен (атомарно) - второй запрос с тем же токеном сюда уже не пройдет
+	ok, err := s.resets.Consume(record.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid or expired token")
+	}
+
+	// меняем пароль (userService заодно отзывает сессии пользователя)
+	_, err = s.users.SetPassword(record.UserID, newPassword)
+	return err
+}
+
+// WithEmailVerification - задает внешний адрес GET /auth/verify и время жизни ссылки (из конфига)
+func (s *AuthService) WithEmailVerification(verifyURL string, ttl time.Duration) *AuthService {
+	s.verifyURL = verifyURL
+	s.verifyTTL = ttl
+	return s
+}
+
+// SendEmailVerification - отправляет ссылку для подтверждения текущего email пользователя
+// (реализует userService.EmailVerifier)
+func (s *AuthService) SendEmailVerification(user userService.User) error {
+	// действует только последняя ссылка
+	if err := s.verifications.DeleteForUser(user.ID); err != nil {
+		return err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	record := &EmailVerificationTokenStruct{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: s.now().Add(s.verifyTTL),
+	}
+	if err := s.verifications.Create(record); err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.verifyURL, token)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf("Please confirm your email address by opening this link (valid for %s):\n%s\n\n"+
+			"If you didn't create an account, just ignore this email.",
+			s.verifyTTL, link),
+	})
+}
+
+// VerifyEmail - подтверждает email по токену из письма
+func (s *AuthService) VerifyEmail(token string) (*userService.User, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	// токен забирается из бд сразу - второй раз по той же ссылке не сработает
+	record, err := s.verifications.Take(hashToken(token))
+	if err != nil || record.ID == 0 {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	user, err := s.users.MarkEmailVerified(record.UserID, record.Email)
+	if err != nil {
+		// пользователь удален или уже сменил email - ссылка больше не действует
+		if strings.Contains(err.Error(), "user not found") || strings.Contains(err.Error(), "email has changed") {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// tokenLink - ссылка с токеном в query (?token=...)
+func tokenLink(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid link url %q: %w", base, err)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// newToken - случайный токен для ссылки (base64url без паддинга)
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken - в бд храним sha256 токена (утечка таблицы не дает рабочих ссылок)
+// соль не нужна: токен сам по себе случайный и длинный
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
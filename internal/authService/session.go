@@ -0,0 +1,311 @@
+package authService
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/google/uuid"
+)
+
+// сессии:
+//   • вход (email + пароль) выдает пару: короткий access-токен (JWT) и долгий refresh-токен
+//   • refresh-токен одноразовый: при обновлении он обменивается на новый (ротация)
+//   • если уже обменянный refresh-токен приходит снова - значит его украли (или украли новый),
+//     поэтому отзываем всю сессию (семейство токенов) целиком
+//   • access-токен проверяется вместе с сессией, поэтому отзыв сессии действует сразу
+
+// настройки по умолчанию
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// ограничения длины того, что клиент сообщает о себе
+const (
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+)
+
+// структура параметров метода Login
+type LoginParams struct {
+	Email      string
+	Password   string
+	DeviceName string // необязательное имя устройства ("iPhone Антона")
+	UserAgent  string
+	IP         string // для журнала входов
+}
+
+// выданная пара токенов
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+	SessionID             string
+}
+
+// бизнес-модель сессии
+type Session struct {
+	ID          string
+	DeviceName  string
+	UserAgent   string
+	StartedAt   time.Time
+	RefreshedAt time.Time
+	ExpiresAt   time.Time
+	Current     bool // сессия, из которой пришел запрос
+}
+
+// Principal - кто выполняет запрос (результат проверки access-токена или персонального токена)
+type Principal struct {
+	UserID    uint
+	SessionID string    // пусто для персонального токена
+	TokenID   uint      // персональный токен (0 - вход через сессию)
+	Scopes    []string  // скоупы персонального токена
+	Role      rbac.Role // берется из бд при каждом запросе, поэтому смена роли действует сразу
+}
+
+// IsPersonalToken - запрос пришел с персональным токеном, а не из сессии
+func (p Principal) IsPersonalToken() bool {
+	return p.TokenID != 0
+}
+
+// HasScope - разрешает ли токен действие (сессии скоупы не ограничивают)
+func (p Principal) HasScope(scope string) bool {
+	if !p.IsPersonalToken() {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor - от чьего имени сервисы выполняют действие
+func (p Principal) Actor() rbac.Actor {
+	return rbac.Actor{UserID: p.UserID, Role: p.Role}
+}
+
+// WithTokens - задает секрет подписи access-токенов и время жизни токенов (из конфига)
+// пустой секрет - остается случайный из конструктора
+func (s *AuthService) WithTokens(secret []byte, accessTTL, refreshTTL time.Duration) *AuthService {
+	if len(secret) > 0 {
+		s.jwtSecret = secret
+	}
+	s.accessTTL = accessTTL
+	s.refreshTTL = refreshTTL
+	return s
+}
+
+// Login - проверяет email и пароль и начинает новую сессию (или требует второй фактор, если он включен)
+func (s *AuthService) Login(params LoginParams) (*TokenPair, error) {
+	user, err := s.users.CheckCredentials(userService.LoginAttempt{
+		Email:     params.Email,
+		Password:  params.Password,
+		IP:        params.IP,
+		UserAgent: params.UserAgent,
+	})
+	if err != nil {
+		// об отключенном аккаунте userService говорит только после верного пароля - ее можно отдать как есть,
+		// блокировку после серии неудач - тоже (в ней срок, когда можно пробовать снова)
+		var locked *userService.LockedError
+		if strings.Contains(err.Error(), "account is disabled") || errors.As(err, &locked) {
+			return nil, err
+		}
+		return nil, errors.New("invalid credentials")
+	}
+
+	// включен второй фактор - вместо токенов отдаем mfa-токен (*SecondFactorRequiredError)
+	if err := s.requireSecondFactor(user.ID, params.DeviceName, params.UserAgent); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user.ID, params.DeviceName, params.UserAgent)
+}
+
+// startSession - начинает новую сессию (новое семейство refresh-токенов)
+func (s *AuthService) startSession(userID uint, deviceName, userAgent string) (*TokenPair, error) {
+	session := &SessionStruct{
+		FamilyID:   uuid.NewString(),
+		UserID:     userID,
+		DeviceName: truncate(deviceName, maxDeviceNameLength),
+		UserAgent:  truncate(userAgent, maxUserAgentLength),
+		StartedAt:  s.now(),
+	}
+	return s.issueTokens(session)
+}
+
+// Refresh - обменивает refresh-токен на новую пару токенов
+func (s *AuthService) Refresh(refreshToken string) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	current, err := s.sessions.GetByTokenHash(hashToken(refreshToken))
+	if err != nil || current.ID == 0 {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if current.RevokedAt != nil || !s.now().Before(current.ExpiresAt) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// токен уже обменивали - это повторное использование
+	if current.RotatedAt != nil {
+		return nil, s.revokeReusedFamily(current)
+	}
+
+	// гасим текущий токен атомарно: если параллельный запрос успел раньше - это тоже повтор
+	ok, err := s.sessions.Rotate(current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.revokeReusedFamily(current)
+	}
+
+	// новая строка того же семейства
+	next := &SessionStruct{
+		FamilyID:   current.FamilyID,
+		UserID:     current.UserID,
+		DeviceName: current.DeviceName,
+		UserAgent:  current.UserAgent,
+		StartedAt:  current.StartedAt,
+	}
+	return s.issueTokens(next)
+}
+
+// revokeReusedFamily - отзывает сессию, в которой повторно использовали refresh-токен
+func (s *AuthService) revokeReusedFamily(session SessionStruct) error {
+	log.Printf("Refresh token reuse detected for user %d, session %s - revoking session", session.UserID, session.FamilyID)
+	if _, err := s.sessions.RevokeFamily(session.UserID, session.FamilyID); err != nil {
+		return err
+	}
+	return errors.New("refresh token reuse detected")
+}
+
+// issueTokens - сохраняет новый refresh-токен сессии и выдает пару токенов
+func (s *AuthService) issueTokens(session *SessionStruct) (*TokenPair, error) {
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session.RefreshTokenHash = hashToken(refreshToken)
+	session.CreatedAt = s.now()
+	session.ExpiresAt = session.CreatedAt.Add(s.refreshTTL)
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+
+	accessToken, accessExpiresAt, err := s.issueAccessToken(session.UserID, session.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+		SessionID:             session.FamilyID,
+	}, nil
+}
+
+// Authenticate - проверяет access-токен, то, что его сессия еще не отозвана,
+// и что аккаунт существует и не отключен; персональные токены узнаются по префиксу
+func (s *AuthService) Authenticate(accessToken string) (*Principal, error) {
+	if strings.HasPrefix(accessToken, personalTokenPrefix) {
+		return s.authenticatePersonalToken(accessToken)
+	}
+
+	userID, sessionID, err := s.parseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessions.GetActive(sessionID)
+	if err != nil || session.UserID != userID {
+		return nil, errors.New("session is revoked or expired")
+	}
+
+	user, err := s.activeAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{UserID: userID, SessionID: sessionID, Role: user.Role}, nil
+}
+
+// activeAccount - пользователь существует и его аккаунт не отключен
+func (s *AuthService) activeAccount(userID uint) (*userService.User, error) {
+	user, err := s.users.GetAccount(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.DisabledAt != nil {
+		return nil, errors.New("account is disabled")
+	}
+	return user, nil
+}
+
+// ListSessions - активные сессии пользователя (currentSessionID помечается как текущая)
+func (s *AuthService) ListSessions(userID uint, currentSessionID string) ([]Session, error) {
+	dbSessions, err := s.sessions.ListActive(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	sessions := make([]Session, 0, len(dbSessions))
+	for _, dbSession := range dbSessions {
+		sessions = append(sessions, Session{
+			ID:          dbSession.FamilyID,
+			DeviceName:  dbSession.DeviceName,
+			UserAgent:   dbSession.UserAgent,
+			StartedAt:   dbSession.StartedAt,
+			RefreshedAt: dbSession.CreatedAt,
+			ExpiresAt:   dbSession.ExpiresAt,
+			Current:     dbSession.FamilyID == currentSessionID,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession - отзывает одну сессию пользователя (выход на конкретном устройстве)
+func (s *AuthService) RevokeSession(userID uint, sessionID string) error {
+	ok, err := s.sessions.RevokeFamily(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// RevokeUserSessions - отзывает все сессии пользователя
+// (выход везде; также реализует userService.SessionRevoker - смена пароля и удаление пользователя)
+func (s *AuthService) RevokeUserSessions(userID uint) error {
+	return s.sessions.RevokeForUser(userID)
+}
+
+// проверка на этапе компиляции: AuthService подходит userService
+var (
+	_ userService.SessionRevoker = (*AuthService)(nil)
+	_ userService.EmailVerifier  = (*AuthService)(nil)
+)
+
+// truncate - обрезает строку до max символов
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
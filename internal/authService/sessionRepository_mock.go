@@ -0,0 +1,54 @@
+package authService
+
+import "github.com/stretchr/testify/mock"
+
+type MockSessionRepo struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepo) Create(session *SessionStruct) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) GetByTokenHash(tokenHash string) (SessionStruct, error) {
+	args := m.Called(tokenHash)
+	var session SessionStruct
+	if res := args.Get(0); res != nil {
+		session = res.(SessionStruct)
+	}
+	return session, args.Error(1)
+}
+
+func (m *MockSessionRepo) Rotate(id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepo) GetActive(familyID string) (SessionStruct, error) {
+	args := m.Called(familyID)
+	var session SessionStruct
+	if res := args.Get(0); res != nil {
+		session = res.(SessionStruct)
+	}
+	return session, args.Error(1)
+}
+
+func (m *MockSessionRepo) ListActive(userID uint) ([]SessionStruct, error) {
+	args := m.Called(userID)
+	var sessions []SessionStruct
+	if res := args.Get(0); res != nil {
+		sessions = res.([]SessionStruct)
+	}
+	return sessions, args.Error(1)
+}
+
+func (m *MockSessionRepo) RevokeFamily(userID uint, familyID string) (bool, error) {
+	args := m.Called(userID, familyID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepo) RevokeForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
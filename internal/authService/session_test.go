@@ -0,0 +1,278 @@
+package authService
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newSessionService - сервис с фиксированным временем и секретом
+func newSessionService(repo *MockSessionRepo) *AuthService {
+	users := &fakeUsers{byEmail: map[string]*userService.User{
+		"user@example.com": {ID: 7, Email: "user@example.com", Role: rbac.RoleAdmin},
+		"off@example.com":  {ID: 9, Email: "off@example.com", Role: rbac.RoleUser, DisabledAt: &testNow},
+	}}
+	service := NewAuthService(users, new(MockPasswordResetRepo), new(MockEmailVerificationRepo), repo, new(MockPersonalTokenRepo), &fakeMailer{}).
+		WithTokens([]byte("test-secret"), 15*time.Minute, 24*time.Hour)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("успешный вход", func(t *testing.T) {
+		repo := new(MockSessionRepo)
+		service := newSessionService(repo)
+
+		var stored *SessionStruct
+		repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*SessionStruct)
+		}).Return(nil)
+
+		pair, err := service.Login(LoginParams{
+			Email:      "user@example.com",
+			Password:   "Str0ng-Passw0rd",
+			DeviceName: "laptop",
+			UserAgent:  "curl/8.0",
+			IP:         "203.0.113.7",
+		})
+		assert.NoError(t, err)
+
+		// IP и User-Agent уходят в userService для журнала входов
+		assert.Equal(t, []userService.LoginAttempt{{
+			Email: "user@example.com", Password: "Str0ng-Passw0rd", IP: "203.0.113.7", UserAgent: "curl/8.0",
+		}}, service.users.(*fakeUsers).attempts)
+
+		// в бд - хэш refresh-токена и данные устройства
+		assert.Equal(t, uint(7), stored.UserID)
+		assert.Equal(t, hashToken(pair.RefreshToken), stored.RefreshTokenHash)
+		assert.Equal(t, "laptop", stored.DeviceName)
+		assert.Equal(t, "curl/8.0", stored.UserAgent)
+		assert.Equal(t, testNow.Add(24*time.Hour), pair.RefreshTokenExpiresAt)
+		assert.Equal(t, testNow.Add(15*time.Minute), pair.AccessTokenExpiresAt)
+		assert.Equal(t, stored.FamilyID, pair.SessionID)
+
+		// access-токен проходит проверку, пока сессия активна
+		repo.On("GetActive", pair.SessionID).Return(*stored, nil)
+		principal, err := service.Authenticate(pair.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, &Principal{UserID: 7, SessionID: pair.SessionID, Role: rbac.RoleAdmin}, principal)
+	})
+
+	t.Run("неверный пароль", func(t *testing.T) {
+		repo := new(MockSessionRepo)
+		service := newSessionService(repo)
+
+		_, err := service.Login(LoginParams{Email: "user@example.com", Password: "wrong"})
+		assert.EqualError(t, err, "invalid credentials")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("аккаунт отключен", func(t *testing.T) {
+		repo := new(MockSessionRepo)
+		service := newSessionService(repo)
+
+		_, err := service.Login(LoginParams{Email: "off@example.com", Password: "Str0ng-Passw0rd"})
+		assert.EqualError(t, err, "account is disabled")
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("вход заблокирован после серии неудач", func(t *testing.T) {
+		repo := new(MockSessionRepo)
+		service := newSessionService(repo)
+		until := testNow.Add(time.Minute)
+		service.users.(*fakeUsers).lockedUntil = &until
+
+		_, err := service.Login(LoginParams{Email: "user@example.com", Password: "Str0ng-Passw0rd"})
+		var locked *userService.LockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, until, locked.Until)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	const token = "refresh-token"
+	rotatedAt := testNow.Add(-time.Minute)
+	revokedAt := testNow.Add(-time.Minute)
+
+	current := SessionStruct{
+		ID:         1,
+		FamilyID:   "family-1",
+		UserID:     7,
+		DeviceName: "laptop",
+		StartedAt:  testNow.Add(-time.Hour),
+		ExpiresAt:  testNow.Add(time.Hour),
+	}
+	with := func(change func(s *SessionStruct)) SessionStruct {
+		s := current
+		change(&s)
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		mockSetup func(m *MockSessionRepo)
+		wantErr   string
+	}{
+		{
+			name: "ротация: старый токен гасится, новый в том же семействе",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(current, nil)
+				m.On("Rotate", uint(1)).Return(true, nil)
+				m.On("Create", mock.MatchedBy(func(s *SessionStruct) bool {
+					return s.FamilyID == "family-1" && s.UserID == 7 && s.DeviceName == "laptop" &&
+						s.StartedAt.Equal(current.StartedAt) && s.RefreshTokenHash != hashToken(token)
+				})).Return(nil)
+			},
+		},
+		{
+			name: "повторное использование обменянного токена отзывает всю сессию",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(with(func(s *SessionStruct) { s.RotatedAt = &rotatedAt }), nil)
+				m.On("RevokeFamily", uint(7), "family-1").Return(true, nil)
+			},
+			wantErr: "refresh token reuse detected",
+		},
+		{
+			name: "параллельный обмен того же токена - тоже повтор",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(current, nil)
+				m.On("Rotate", uint(1)).Return(false, nil)
+				m.On("RevokeFamily", uint(7), "family-1").Return(true, nil)
+			},
+			wantErr: "refresh token reuse detected",
+		},
+		{
+			name: "отозванная сессия",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(with(func(s *SessionStruct) { s.RevokedAt = &revokedAt }), nil)
+			},
+			wantErr: "invalid refresh token",
+		},
+		{
+			name: "истекший токен",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(with(func(s *SessionStruct) { s.ExpiresAt = testNow }), nil)
+			},
+			wantErr: "invalid refresh token",
+		},
+		{
+			name: "неизвестный токен",
+			mockSetup: func(m *MockSessionRepo) {
+				m.On("GetByTokenHash", hashToken(token)).Return(SessionStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: "invalid refresh token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSessionRepo)
+			tt.mockSetup(repo)
+			service := newSessionService(repo)
+
+			pair, err := service.Refresh(token)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, pair)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "family-1", pair.SessionID)
+				assert.NotEqual(t, token, pair.RefreshToken)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo := new(MockSessionRepo)
+	service := newSessionService(repo)
+	active := SessionStruct{ID: 1, FamilyID: "family-1", UserID: 7}
+	repo.On("GetActive", "family-1").Return(active, nil)
+	repo.On("GetActive", "family-2").Return(SessionStruct{}, gorm.ErrRecordNotFound)
+
+	token, _, err := service.issueAccessToken(7, "family-1")
+	assert.NoError(t, err)
+
+	principal, err := service.Authenticate(token)
+	assert.NoError(t, err)
+	assert.Equal(t, rbac.Actor{UserID: 7, Role: rbac.RoleAdmin}, principal.Actor())
+
+	// аккаунт отключен - токен больше не действует, даже если сессия еще активна
+	repo.On("GetActive", "family-3").Return(SessionStruct{ID: 3, FamilyID: "family-3", UserID: 9}, nil)
+	disabled, _, _ := service.issueAccessToken(9, "family-3")
+	_, err = service.Authenticate(disabled)
+	assert.EqualError(t, err, "account is disabled")
+
+	// сессия отозвана
+	revoked, _, _ := service.issueAccessToken(7, "family-2")
+	_, err = service.Authenticate(revoked)
+	assert.Error(t, err)
+
+	// сессия чужая
+	foreign, _, _ := service.issueAccessToken(8, "family-1")
+	_, err = service.Authenticate(foreign)
+	assert.Error(t, err)
+
+	// срок действия вышел
+	service.now = func() time.Time { return testNow.Add(16 * time.Minute) }
+	_, err = service.Authenticate(token)
+	assert.Error(t, err)
+	service.now = func() time.Time { return testNow }
+
+	// подпись другим секретом
+	other := newSessionService(repo).WithTokens([]byte("other-secret"), time.Minute, time.Hour)
+	forged, _, _ := other.issueAccessToken(7, "family-1")
+	_, err = service.Authenticate(forged)
+	assert.Error(t, err)
+
+	// alg=none не принимается
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, accessClaims{
+		SessionID: "family-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   "7",
+			ExpiresAt: jwt.NewNumericDate(testNow.Add(time.Minute)),
+		},
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	_, err = service.Authenticate(unsigned)
+	assert.Error(t, err)
+}
+
+func TestListAndRevokeSessions(t *testing.T) {
+	repo := new(MockSessionRepo)
+	service := newSessionService(repo)
+
+	repo.On("ListActive", uint(7)).Return([]SessionStruct{
+		{FamilyID: "family-1", DeviceName: "laptop", StartedAt: testNow, CreatedAt: testNow},
+		{FamilyID: "family-2", DeviceName: "phone", StartedAt: testNow, CreatedAt: testNow},
+	}, nil)
+	repo.On("RevokeFamily", uint(7), "family-2").Return(true, nil)
+	repo.On("RevokeFamily", uint(7), "missing").Return(false, nil)
+	repo.On("RevokeForUser", uint(7)).Return(nil)
+
+	sessions, err := service.ListSessions(7, "family-2")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+	assert.Equal(t, "phone", sessions[1].DeviceName)
+
+	assert.NoError(t, service.RevokeSession(7, "family-2"))
+	assert.EqualError(t, service.RevokeSession(7, "missing"), "session not found")
+	assert.NoError(t, service.RevokeUserSessions(7))
+
+	repo.AssertExpectations(t)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "при", truncate("привет", 3))
+}
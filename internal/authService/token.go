@@ -0,0 +1,63 @@
+package authService
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// access-токен - короткоживущий JWT (HS256):
+//   sub - id пользователя, sid - id сессии (чтобы отзыв сессии сразу отключал и ее access-токены)
+
+const tokenIssuer = "webpet1"
+
+type accessClaims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// issueAccessToken - подписывает access-токен для сессии
+func (s *AuthService) issueAccessToken(userID uint, sessionID string) (string, time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.accessTTL)
+
+	claims := accessClaims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// parseAccessToken - проверяет подпись, алгоритм, издателя и срок действия
+func (s *AuthService) parseAccessToken(token string) (uint, string, error) {
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(token, claims,
+		func(*jwt.Token) (interface{}, error) { return s.jwtSecret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), // без этого можно подсунуть alg=none
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid access token: %w", err)
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || userID == 0 || claims.SessionID == "" {
+		return 0, "", errors.New("invalid access token: bad claims")
+	}
+	return uint(userID), claims.SessionID, nil
+}
@@ -0,0 +1,55 @@
+package authService
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorRepo struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepo) Get(userID uint) (TOTPStruct, error) {
+	args := m.Called(userID)
+	var totp TOTPStruct
+	if res := args.Get(0); res != nil {
+		totp = res.(TOTPStruct)
+	}
+	return totp, args.Error(1)
+}
+
+func (m *MockTwoFactorRepo) Save(totp *TOTPStruct) error {
+	args := m.Called(totp)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) Confirm(userID uint, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) UseStep(userID uint, step uint64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepo) Delete(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	args := m.Called(userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepo) CountRecoveryCodes(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
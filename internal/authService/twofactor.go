@@ -0,0 +1,406 @@
+package authService
+
+import (
+	"crypto/rand"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/encryption"
+	"github.com/AntonRadchenko/WebPet1/internal/totp"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"gorm.io/gorm"
+)
+
+// двухфакторная аутентификация (TOTP, RFC 6238):
+//   1) POST /auth/2fa/totp - создаем секрет и отдаем его вместе с otpauth:// ссылкой (для QR-кода)
+//   2) POST /auth/2fa/totp/confirm - пользователь вводит код из приложения: так мы знаем, что секрет сохранен,
+//      и только теперь включаем второй фактор и выдаем одноразовые коды восстановления (показываются один раз)
+//   3) вход по паролю для такого пользователя не выдает токены сразу, а возвращает mfa-токен,
+//      который вместе с кодом из приложения (или кодом восстановления) меняется на сессию в POST /auth/login/2fa
+// секрет в бд зашифрован ключом из конфига, коды восстановления - только хэши
+// принятый код запоминается (номер шага), поэтому подсмотренный код второй раз не сработает
+
+// настройки по умолчанию
+const (
+	DefaultTOTPIssuer = "WebPet1"
+	mfaChallengeTTL   = 5 * time.Minute
+	maxMFAAttempts    = 5 // неверных кодов на один вход - дальше вход по паролю нужно начинать заново
+	totpSkew          = 1 // допуск в шагах в обе стороны (часы телефона могут расходиться с нашими)
+	recoveryCodeCount = 10
+)
+
+// SecondFactorRequiredError - пароль верный, но нужен второй фактор
+// (Token - одноразовый mfa-токен для POST /auth/login/2fa)
+type SecondFactorRequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	return "second factor required"
+}
+
+// начатое подключение TOTP: секрет (base32 для ручного ввода) и ссылка для QR-кода
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// состояние двухфакторной аутентификации пользователя
+type TwoFactorStatus struct {
+	Enabled           bool
+	EnabledAt         *time.Time
+	RecoveryCodesLeft int
+}
+
+// структура параметров метода CompleteSecondFactor
+type SecondFactorParams struct {
+	Token string // mfa-токен из ответа на вход по паролю
+	Code  string // код из приложения или код восстановления
+	IP    string // для журнала входов
+}
+
+// WithTwoFactor - включает двухфакторную аутентификацию
+// cipher == nil - новые подключения TOTP недоступны, но уже подключенные пользователи
+// все равно проходят второй шаг (кодом восстановления)
+func (s *AuthService) WithTwoFactor(cipher *encryption.Cipher, issuer string, repo TwoFactorRepoInterface, challenges MFAChallengeRepoInterface) *AuthService {
+	s.totpCipher = cipher
+	if issuer != "" {
+		s.totpIssuer = issuer
+	}
+	s.twoFactor = repo
+	s.mfaChallenges = challenges
+	return s
+}
+
+// StartTOTPEnrollment - новый секрет для пользователя (заменяет неподтвержденный)
+func (s *AuthService) StartTOTPEnrollment(userID uint) (*TOTPEnrollment, error) {
+	if s.twoFactor == nil || s.totpCipher == nil {
+		return nil, errors.New("two-factor authentication is not configured")
+	}
+
+	current, err := s.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.ConfirmedAt != nil {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	user, err := s.activeAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.totpCipher.Encrypt(secret, totpAAD(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	record := &TOTPStruct{UserID: userID, SecretEncrypted: encrypted, CreatedAt: s.now()}
+	if err := s.twoFactor.Save(record); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(s.totpIssuer, user.Email, secret, totp.DefaultOptions),
+	}, nil
+}
+
+// ConfirmTOTP - включает второй фактор по первому коду из приложения и выдает коды восстановления
+func (s *AuthService) ConfirmTOTP(userID uint, code string) ([]string, error) {
+	if s.twoFactor == nil || s.totpCipher == nil {
+		return nil, errors.New("two-factor authentication is not configured")
+	}
+
+	current, err := s.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, errors.New("two-factor enrollment is not started")
+	}
+	if current.ConfirmedAt != nil {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	ok, err := s.checkTOTP(current, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	if err := s.twoFactor.Confirm(userID, s.now()); err != nil {
+		return nil, err
+	}
+	log.Printf("Two-factor authentication enabled for user %d", userID)
+
+	return s.replaceRecoveryCodes(userID)
+}
+
+// DisableTOTP - выключает второй фактор (нужен действующий код или код восстановления)
+func (s *AuthService) DisableTOTP(userID uint, code string) error {
+	current, err := s.enabledTOTP(userID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.checkSecondFactor(current, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid code")
+	}
+
+	if err := s.twoFactor.Delete(userID); err != nil {
+		return err
+	}
+	log.Printf("Two-factor authentication disabled for user %d", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes - новый набор кодов восстановления, старые перестают работать
+func (s *AuthService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	current, err := s.enabledTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.checkSecondFactor(current, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	return s.replaceRecoveryCodes(userID)
+}
+
+// GetTwoFactorStatus - включен ли второй фактор и сколько кодов восстановления осталось
+func (s *AuthService) GetTwoFactorStatus(userID uint) (*TwoFactorStatus, error) {
+	status := &TwoFactorStatus{}
+	if s.twoFactor == nil {
+		return status, nil
+	}
+
+	current, err := s.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.ConfirmedAt == nil {
+		return status, nil
+	}
+
+	left, err := s.twoFactor.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.EnabledAt = current.ConfirmedAt
+	status.RecoveryCodesLeft = left
+	return status, nil
+}
+
+// requireSecondFactor - если у пользователя включен второй фактор, сохраняем вход и возвращаем
+// *SecondFactorRequiredError с mfa-токеном; nil - второй фактор не нужен
+func (s *AuthService) requireSecondFactor(userID uint, deviceName, userAgent string) error {
+	if s.twoFactor == nil {
+		return nil
+	}
+
+	current, err := s.getTOTP(userID)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.ConfirmedAt == nil {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	challenge := &MFAChallengeStruct{
+		TokenHash:  hashToken(token),
+		UserID:     userID,
+		DeviceName: truncate(deviceName, maxDeviceNameLength),
+		UserAgent:  truncate(userAgent, maxUserAgentLength),
+		ExpiresAt:  s.now().Add(mfaChallengeTTL),
+	}
+	if err := s.mfaChallenges.Create(challenge); err != nil {
+		return err
+	}
+
+	return &SecondFactorRequiredError{Token: token, ExpiresAt: challenge.ExpiresAt}
+}
+
+// CompleteSecondFactor - второй шаг входа: mfa-токен + код меняются на сессию
+func (s *AuthService) CompleteSecondFactor(params SecondFactorParams) (*TokenPair, error) {
+	if s.twoFactor == nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	token := strings.TrimSpace(params.Token)
+	if token == "" {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	challenge, err := s.mfaChallenges.GetValid(hashToken(token))
+	if err != nil || challenge.ID == 0 {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	// попытку считаем до проверки кода: параллельные запросы не дадут перебрать больше лимита
+	attempts, err := s.mfaChallenges.RegisterAttempt(challenge.ID)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	attempt := userService.LoginAttempt{IP: params.IP, UserAgent: challenge.UserAgent}
+	if attempts > maxMFAAttempts {
+		if err := s.mfaChallenges.Delete(challenge.ID); err != nil {
+			return nil, err
+		}
+		s.users.RecordAuthEvent(challenge.UserID, userService.AuthEventMFAFailed, userService.LoginFailureTooManyAttempts, attempt)
+		return nil, errors.New("too many attempts, log in again")
+	}
+
+	current, err := s.enabledTOTP(challenge.UserID)
+	if err != nil {
+		// второй фактор успели выключить - вход начинаем заново
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	ok, err := s.checkSecondFactor(current, params.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.users.RecordAuthEvent(challenge.UserID, userService.AuthEventMFAFailed, userService.LoginFailureInvalidCode, attempt)
+		return nil, errors.New("invalid code")
+	}
+
+	// mfa-токен одноразовый
+	if err := s.mfaChallenges.Delete(challenge.ID); err != nil {
+		return nil, err
+	}
+	// пока вводили код, аккаунт могли отключить
+	user, err := s.activeAccount(challenge.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "account is disabled") {
+			s.users.RecordAuthEvent(challenge.UserID, userService.AuthEventMFAFailed, userService.LoginFailureDisabled, attempt)
+		}
+		return nil, err
+	}
+	attempt.Email = user.Email
+	s.users.RecordAuthEvent(user.ID, userService.AuthEventMFASucceeded, "", attempt)
+
+	return s.startSession(challenge.UserID, challenge.DeviceName, challenge.UserAgent)
+}
+
+// getTOTP - запись TOTP пользователя (nil - двухфакторная аутентификация не подключалась)
+func (s *AuthService) getTOTP(userID uint) (*TOTPStruct, error) {
+	record, err := s.twoFactor.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// enabledTOTP - подтвержденная запись TOTP пользователя
+func (s *AuthService) enabledTOTP(userID uint) (*TOTPStruct, error) {
+	if s.twoFactor == nil {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	current, err := s.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.ConfirmedAt == nil {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	return current, nil
+}
+
+// checkSecondFactor - код из приложения (только цифры) или код восстановления
+func (s *AuthService) checkSecondFactor(record *TOTPStruct, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, nil
+	}
+	if _, err := strconv.ParseUint(code, 10, 64); err == nil {
+		return s.checkTOTP(record, code)
+	}
+	return s.twoFactor.UseRecoveryCode(record.UserID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// checkTOTP - проверяет код из приложения и запоминает его шаг (повтор того же кода не пройдет)
+func (s *AuthService) checkTOTP(record *TOTPStruct, code string) (bool, error) {
+	// без ключа секрет не расшифровать - остаются только коды восстановления
+	if s.totpCipher == nil {
+		return false, nil
+	}
+	secret, err := s.totpCipher.Decrypt(record.SecretEncrypted, totpAAD(record.UserID))
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := totp.Validate(code, secret, s.now(), totp.DefaultOptions, totpSkew)
+	if !ok {
+		return false, nil
+	}
+	if record.LastUsedStep != nil && step <= *record.LastUsedStep {
+		return false, nil
+	}
+	return s.twoFactor.UseStep(record.UserID, step)
+}
+
+// replaceRecoveryCodes - создает новый набор кодов восстановления (в бд - только хэши)
+func (s *AuthService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code := newRecoveryCode()
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.twoFactor.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode - код вида "abcde-fghij" (10 символов base32, 50 бит)
+func newRecoveryCode() string {
+	text := strings.ToLower(rand.Text())
+	return text[:5] + "-" + text[5:10]
+}
+
+// normalizeRecoveryCode - регистр, дефисы и пробелы при вводе не важны
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// totpAAD - привязывает шифротекст секрета к пользователю
+func totpAAD(userID uint) []byte {
+	return []byte("user_totp:" + strconv.FormatUint(uint64(userID), 10))
+}
@@ -0,0 +1,307 @@
+package authService
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/encryption"
+	"github.com/AntonRadchenko/WebPet1/internal/totp"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var testTOTPSecret = []byte("12345678901234567890")
+
+type twoFactorFixture struct {
+	service    *AuthService
+	users      *fakeUsers
+	cipher     *encryption.Cipher
+	repo       *MockTwoFactorRepo
+	challenges *MockMFAChallengeRepo
+	sessions   *MockSessionRepo
+}
+
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	cipher, err := encryption.NewCipher([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	f := &twoFactorFixture{
+		cipher:     cipher,
+		repo:       new(MockTwoFactorRepo),
+		challenges: new(MockMFAChallengeRepo),
+		sessions:   new(MockSessionRepo),
+	}
+	f.service = newSessionService(f.sessions).WithTwoFactor(cipher, "WebPet1", f.repo, f.challenges)
+	f.users = f.service.users.(*fakeUsers)
+	f.sessions.On("Create", mock.Anything).Return(nil)
+	return f
+}
+
+// enabled - подтвержденная запись TOTP пользователя с тестовым секретом
+func (f *twoFactorFixture) enabled(t *testing.T, userID uint) TOTPStruct {
+	encrypted, err := f.cipher.Encrypt(testTOTPSecret, totpAAD(userID))
+	require.NoError(t, err)
+	confirmedAt := testNow.Add(-time.Hour)
+	return TOTPStruct{UserID: userID, SecretEncrypted: encrypted, ConfirmedAt: &confirmedAt}
+}
+
+func currentCode(t *testing.T) string {
+	code, err := totp.Generate(testTOTPSecret, testNow, totp.DefaultOptions)
+	require.NoError(t, err)
+	return code
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	f.repo.On("Get", uint(7)).Return(nil, gorm.ErrRecordNotFound).Once()
+	var saved *TOTPStruct
+	f.repo.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*TOTPStruct)
+	}).Return(nil)
+
+	enrollment, err := f.service.StartTOTPEnrollment(7)
+	require.NoError(t, err)
+
+	// в бд секрет зашифрован и привязан к пользователю
+	require.NotNil(t, saved)
+	assert.NotContains(t, saved.SecretEncrypted, enrollment.Secret)
+	secret, err := f.cipher.Decrypt(saved.SecretEncrypted, totpAAD(7))
+	require.NoError(t, err)
+	assert.Equal(t, totp.EncodeSecret(secret), enrollment.Secret)
+	_, err = f.cipher.Decrypt(saved.SecretEncrypted, totpAAD(8))
+	assert.Error(t, err)
+
+	u, err := url.Parse(enrollment.URI)
+	require.NoError(t, err)
+	assert.Equal(t, "/WebPet1:user@example.com", u.Path)
+	assert.Equal(t, enrollment.Secret, u.Query().Get("secret"))
+
+	// подтверждение первым кодом включает второй фактор и выдает коды восстановления
+	f.repo.On("Get", uint(7)).Return(*saved, nil)
+	code, err := totp.Generate(secret, testNow, totp.DefaultOptions)
+	require.NoError(t, err)
+	step := totp.Step(testNow, totp.DefaultOptions.Period)
+	f.repo.On("UseStep", uint(7), step).Return(true, nil)
+	f.repo.On("Confirm", uint(7), testNow).Return(nil)
+	var hashes []string
+	f.repo.On("ReplaceRecoveryCodes", uint(7), mock.Anything).Run(func(args mock.Arguments) {
+		hashes = args.Get(1).([]string)
+	}).Return(nil)
+
+	_, err = f.service.ConfirmTOTP(7, "000000")
+	assert.EqualError(t, err, "invalid code")
+	f.repo.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything)
+
+	codes, err := f.service.ConfirmTOTP(7, code)
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+	for i, c := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, c)
+		// в бд - только хэш (регистр и дефис при вводе не важны)
+		assert.Equal(t, hashToken(normalizeRecoveryCode(strings.ToUpper(c))), hashes[i])
+	}
+}
+
+func TestTOTPEnrollmentErrors(t *testing.T) {
+	t.Run("без ключа шифрования подключить нельзя", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.service.WithTwoFactor(nil, "", f.repo, f.challenges)
+
+		_, err := f.service.StartTOTPEnrollment(7)
+		assert.EqualError(t, err, "two-factor authentication is not configured")
+		_, err = f.service.ConfirmTOTP(7, "123456")
+		assert.EqualError(t, err, "two-factor authentication is not configured")
+	})
+
+	t.Run("уже подключено", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(f.enabled(t, 7), nil)
+
+		_, err := f.service.StartTOTPEnrollment(7)
+		assert.EqualError(t, err, "two-factor authentication is already enabled")
+		_, err = f.service.ConfirmTOTP(7, currentCode(t))
+		assert.EqualError(t, err, "two-factor authentication is already enabled")
+		f.repo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("подтверждение без начатого подключения", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.service.ConfirmTOTP(7, "123456")
+		assert.EqualError(t, err, "two-factor enrollment is not started")
+	})
+}
+
+func TestLoginWithSecondFactor(t *testing.T) {
+	login := func(f *twoFactorFixture) error {
+		_, err := f.service.Login(LoginParams{Email: "user@example.com", Password: "Str0ng-Passw0rd", DeviceName: "phone", UserAgent: "app/1.0"})
+		return err
+	}
+
+	t.Run("без второго фактора - обычный вход", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+		pair, err := f.service.Login(LoginParams{Email: "user@example.com", Password: "Str0ng-Passw0rd"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, pair.AccessToken)
+	})
+
+	t.Run("пароль верный - вместо токенов mfa-токен", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(f.enabled(t, 7), nil)
+		var challenge *MFAChallengeStruct
+		f.challenges.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			challenge = args.Get(0).(*MFAChallengeStruct)
+		}).Return(nil)
+
+		err := login(f)
+		var mfa *SecondFactorRequiredError
+		require.ErrorAs(t, err, &mfa)
+		assert.Equal(t, testNow.Add(mfaChallengeTTL), mfa.ExpiresAt)
+		assert.Equal(t, &MFAChallengeStruct{
+			TokenHash:  hashToken(mfa.Token),
+			UserID:     7,
+			DeviceName: "phone",
+			UserAgent:  "app/1.0",
+			ExpiresAt:  testNow.Add(mfaChallengeTTL),
+		}, challenge)
+		f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	challenge := MFAChallengeStruct{ID: 3, TokenHash: hashToken("mfa-token"), UserID: 7, DeviceName: "phone", UserAgent: "app/1.0"}
+	step := totp.Step(testNow, totp.DefaultOptions.Period)
+
+	t.Run("код из приложения начинает сессию", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(f.enabled(t, 7), nil)
+		f.repo.On("UseStep", uint(7), step).Return(true, nil)
+		f.challenges.On("GetValid", hashToken("mfa-token")).Return(challenge, nil)
+		f.challenges.On("RegisterAttempt", uint(3)).Return(1, nil)
+		f.challenges.On("Delete", uint(3)).Return(nil)
+
+		pair, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: currentCode(t), IP: "10.0.0.1"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, pair.RefreshToken)
+		assert.Equal(t, []authEvent{{UserID: 7, Type: userService.AuthEventMFASucceeded, IP: "10.0.0.1"}}, f.users.events)
+		f.challenges.AssertCalled(t, "Delete", uint(3))
+		f.sessions.AssertCalled(t, "Create", mock.MatchedBy(func(s *SessionStruct) bool {
+			return s.UserID == 7 && s.DeviceName == "phone" && s.UserAgent == "app/1.0"
+		}))
+	})
+
+	t.Run("тот же код второй раз не проходит", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		record := f.enabled(t, 7)
+		record.LastUsedStep = &step
+		f.repo.On("Get", uint(7)).Return(record, nil)
+		f.challenges.On("GetValid", hashToken("mfa-token")).Return(challenge, nil)
+		f.challenges.On("RegisterAttempt", uint(3)).Return(2, nil)
+
+		_, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: currentCode(t)})
+		assert.EqualError(t, err, "invalid code")
+		f.repo.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything)
+		assert.Equal(t, []authEvent{{UserID: 7, Type: userService.AuthEventMFAFailed, Reason: userService.LoginFailureInvalidCode}}, f.users.events)
+		f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("код восстановления", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.repo.On("Get", uint(7)).Return(f.enabled(t, 7), nil)
+		f.repo.On("UseRecoveryCode", uint(7), hashToken("abcde23456")).Return(true, nil).Once()
+		f.repo.On("UseRecoveryCode", uint(7), hashToken("abcde23456")).Return(false, nil)
+		f.challenges.On("GetValid", hashToken("mfa-token")).Return(challenge, nil)
+		f.challenges.On("RegisterAttempt", uint(3)).Return(1, nil)
+		f.challenges.On("Delete", uint(3)).Return(nil)
+
+		_, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: " ABCDE-23456 "})
+		require.NoError(t, err)
+
+		// код одноразовый
+		_, err = f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: "abcde-23456"})
+		assert.EqualError(t, err, "invalid code")
+	})
+
+	t.Run("слишком много неверных кодов", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.challenges.On("GetValid", hashToken("mfa-token")).Return(challenge, nil)
+		f.challenges.On("RegisterAttempt", uint(3)).Return(maxMFAAttempts+1, nil)
+		f.challenges.On("Delete", uint(3)).Return(nil)
+
+		_, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: currentCode(t)})
+		assert.EqualError(t, err, "too many attempts, log in again")
+		f.challenges.AssertCalled(t, "Delete", uint(3))
+		assert.Equal(t, []authEvent{{UserID: 7, Type: userService.AuthEventMFAFailed, Reason: userService.LoginFailureTooManyAttempts}}, f.users.events)
+		f.repo.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("неизвестный mfa-токен", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		f.challenges.On("GetValid", hashToken("forged")).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "forged", Code: "123456"})
+		assert.EqualError(t, err, "invalid or expired mfa token")
+		_, err = f.service.CompleteSecondFactor(SecondFactorParams{Token: " ", Code: "123456"})
+		assert.EqualError(t, err, "invalid or expired mfa token")
+		assert.Empty(t, f.users.events)
+	})
+
+	t.Run("аккаунт отключили, пока вводили код", func(t *testing.T) {
+		f := newTwoFactorFixture(t)
+		off := challenge
+		off.UserID = 9
+		f.repo.On("Get", uint(9)).Return(f.enabled(t, 9), nil)
+		f.repo.On("UseStep", uint(9), step).Return(true, nil)
+		f.challenges.On("GetValid", hashToken("mfa-token")).Return(off, nil)
+		f.challenges.On("RegisterAttempt", uint(3)).Return(1, nil)
+		f.challenges.On("Delete", uint(3)).Return(nil)
+
+		_, err := f.service.CompleteSecondFactor(SecondFactorParams{Token: "mfa-token", Code: currentCode(t)})
+		assert.EqualError(t, err, "account is disabled")
+		f.sessions.AssertNotCalled(t, "Create", mock.Anything)
+		assert.Equal(t, []authEvent{{UserID: 9, Type: userService.AuthEventMFAFailed, Reason: userService.LoginFailureDisabled}}, f.users.events)
+	})
+}
+
+func TestDisableTOTPAndRecoveryCodes(t *testing.T) {
+	step := totp.Step(testNow, totp.DefaultOptions.Period)
+
+	f := newTwoFactorFixture(t)
+	f.repo.On("Get", uint(7)).Return(f.enabled(t, 7), nil)
+	f.repo.On("UseStep", uint(7), step).Return(true, nil)
+	f.repo.On("UseRecoveryCode", uint(7), mock.Anything).Return(false, nil)
+	f.repo.On("ReplaceRecoveryCodes", uint(7), mock.Anything).Return(nil)
+	f.repo.On("CountRecoveryCodes", uint(7)).Return(4, nil)
+	f.repo.On("Delete", uint(7)).Return(nil)
+
+	status, err := f.service.GetTwoFactorStatus(7)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 4, status.RecoveryCodesLeft)
+
+	_, err = f.service.RegenerateRecoveryCodes(7, "wrong-code")
+	assert.EqualError(t, err, "invalid code")
+	codes, err := f.service.RegenerateRecoveryCodes(7, currentCode(t))
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+
+	assert.EqualError(t, f.service.DisableTOTP(7, ""), "invalid code")
+	f.repo.AssertNotCalled(t, "Delete", mock.Anything)
+	assert.NoError(t, f.service.DisableTOTP(7, currentCode(t)))
+	f.repo.AssertCalled(t, "Delete", uint(7))
+
+	// не подключено
+	other := newTwoFactorFixture(t)
+	other.repo.On("Get", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+	assert.EqualError(t, other.service.DisableTOTP(7, "123456"), "two-factor authentication is not enabled")
+	status, err = other.service.GetTwoFactorStatus(7)
+	require.NoError(t, err)
+	assert.Equal(t, &TwoFactorStatus{}, status)
+}
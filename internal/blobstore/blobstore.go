@@ -0,0 +1,52 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// хранилище содержимого вложений (сами байты; метаданные лежат в Postgres)
+// реализации:
+//   • LocalStore - каталог на диске (разработка, один инстанс)
+//   • S3Store    - S3-совместимое хранилище (AWS S3, MinIO и т.п.); для тестов есть s3test
+// ключ - путь вида "tasks/12/<uuid>": только безопасные сегменты, без ".." и ведущего "/"
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+type BlobStore interface {
+	// Put - сохраняет содержимое r под ключом key (читает r до конца, не держа его целиком в памяти)
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get - читает length байт начиная с offset; length < 0 - до конца
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete - удаляет содержимое; отсутствие ключа - не ошибка
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix - удаляет все ключи, начинающиеся с prefix (например, "tasks/12/" - все файлы задачи)
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// validKey - ключ не должен выходить за пределы хранилища
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// validPrefix - префикс - это ключ "каталога": безопасные сегменты и "/" в конце
+func validPrefix(prefix string) error {
+	dir, ok := strings.CutSuffix(prefix, "/")
+	if !ok {
+		return ErrInvalidKey
+	}
+	return validKey(dir)
+}
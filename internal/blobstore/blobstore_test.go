@@ -0,0 +1,199 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore/s3test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestS3 - S3Store поверх s3test; partSize уменьшен, чтобы multipart проверялся на маленьких данных
+func newTestS3(t *testing.T, secret string) (*S3Store, *s3test.Server) {
+	server := s3test.NewServer("attachments", "test-key", "test-secret")
+	server.MinPartSize = 16
+	server.MaxKeys = 2
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        server.URL(),
+		Region:          server.Region,
+		Bucket:          "attachments",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: secret,
+	})
+	require.NoError(t, err)
+	store.partSize = 16
+	return store, server
+}
+
+// одни и те же проверки для всех реализаций
+func TestBlobStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) BlobStore{
+		"local": func(t *testing.T) BlobStore {
+			store, err := NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+		"s3": func(t *testing.T) BlobStore {
+			store, _ := newTestS3(t, "test-secret")
+			return store
+		},
+	}
+
+	const content = "0123456789abcdefghijklmnopqrstuvwxyz" // больше двух частей по 16 байт
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{name: "весь файл", offset: 0, length: -1, want: content},
+		{name: "диапазон из середины", offset: 10, length: 6, want: "abcdef"},
+		{name: "с позиции до конца", offset: 30, length: -1, want: "uvwxyz"},
+		{name: "диапазон длиннее файла обрезается", offset: 34, length: 10, want: "yz"},
+		{name: "пустой диапазон", offset: 5, length: 0, want: ""},
+	}
+
+	for storeName, newStore := range stores {
+		t.Run(storeName, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			require.NoError(t, store.Put(ctx, "tasks/1/blob", strings.NewReader(content), "text/plain"))
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					rc, err := store.Get(ctx, "tasks/1/blob", tt.offset, tt.length)
+					require.NoError(t, err)
+					defer rc.Close()
+
+					got, err := io.ReadAll(rc)
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, string(got))
+				})
+			}
+
+			t.Run("перезапись ключа", func(t *testing.T) {
+				require.NoError(t, store.Put(ctx, "tasks/1/other", strings.NewReader("old"), ""))
+				require.NoError(t, store.Put(ctx, "tasks/1/other", strings.NewReader("new"), ""))
+
+				rc, err := store.Get(ctx, "tasks/1/other", 0, -1)
+				require.NoError(t, err)
+				defer rc.Close()
+				got, _ := io.ReadAll(rc)
+				assert.Equal(t, "new", string(got))
+			})
+
+			t.Run("удаление и повторное удаление", func(t *testing.T) {
+				require.NoError(t, store.Delete(ctx, "tasks/1/blob"))
+				require.NoError(t, store.Delete(ctx, "tasks/1/blob"))
+
+				_, err := store.Get(ctx, "tasks/1/blob", 0, -1)
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+
+			t.Run("удаление по префиксу", func(t *testing.T) {
+				for _, key := range []string{"tasks/5/a", "tasks/5/b", "tasks/5/c", "tasks/50/a"} {
+					require.NoError(t, store.Put(ctx, key, strings.NewReader(key), ""))
+				}
+
+				require.NoError(t, store.DeletePrefix(ctx, "tasks/5/"))
+				for _, key := range []string{"tasks/5/a", "tasks/5/b", "tasks/5/c"} {
+					_, err := store.Get(ctx, key, 0, -1)
+					assert.ErrorIs(t, err, ErrNotFound, key)
+				}
+
+				// похожий префикс другой задачи не задет
+				rc, err := store.Get(ctx, "tasks/50/a", 0, -1)
+				require.NoError(t, err)
+				rc.Close()
+
+				// пустой префикс - тоже не ошибка; префикс без "/" на конце - ошибка
+				assert.NoError(t, store.DeletePrefix(ctx, "tasks/404/"))
+				assert.ErrorIs(t, store.DeletePrefix(ctx, "tasks/5"), ErrInvalidKey)
+				assert.ErrorIs(t, store.DeletePrefix(ctx, "../"), ErrInvalidKey)
+			})
+
+			t.Run("недопустимые ключи", func(t *testing.T) {
+				for _, key := range []string{"", "/etc/passwd", "tasks/../../etc", "tasks//1", `tasks\1`} {
+					assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x"), ""), ErrInvalidKey, key)
+					_, err := store.Get(ctx, key, 0, -1)
+					assert.ErrorIs(t, err, ErrInvalidKey, key)
+				}
+			})
+		})
+	}
+}
+
+// failingReader - отдает данные, а потом ломается (клиент оборвал загрузку)
+type failingReader struct {
+	r io.Reader
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("маленький файл - один PUT, большой - multipart", func(t *testing.T) {
+		store, server := newTestS3(t, "test-secret")
+
+		require.NoError(t, store.Put(ctx, "small", strings.NewReader("hello"), "text/plain"))
+		data, ok := server.Object("small")
+		require.True(t, ok)
+		assert.Equal(t, "hello", string(data))
+
+		big := bytes.Repeat([]byte("0123456789"), 10) // 100 байт = 7 частей по 16
+		require.NoError(t, store.Put(ctx, "big", bytes.NewReader(big), "application/octet-stream"))
+		data, ok = server.Object("big")
+		require.True(t, ok)
+		assert.Equal(t, big, data)
+		assert.Zero(t, server.PendingUploads())
+	})
+
+	t.Run("ровно одна полная часть", func(t *testing.T) {
+		store, server := newTestS3(t, "test-secret")
+
+		require.NoError(t, store.Put(ctx, "exact", strings.NewReader("0123456789abcdef"), ""))
+		data, _ := server.Object("exact")
+		assert.Equal(t, "0123456789abcdef", string(data))
+	})
+
+	t.Run("обрыв посреди multipart - загрузка отменяется", func(t *testing.T) {
+		store, server := newTestS3(t, "test-secret")
+
+		err := store.Put(ctx, "broken", &failingReader{r: strings.NewReader(strings.Repeat("x", 40))}, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset")
+
+		_, ok := server.Object("broken")
+		assert.False(t, ok)
+		assert.Zero(t, server.PendingUploads())
+	})
+
+	t.Run("неверный секрет", func(t *testing.T) {
+		store, _ := newTestS3(t, "wrong-secret")
+
+		err := store.Put(ctx, "small", strings.NewReader("hello"), "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SignatureDoesNotMatch")
+	})
+
+	t.Run("недопустимый endpoint", func(t *testing.T) {
+		_, err := NewS3Store(S3Config{Endpoint: "localhost:9000", Bucket: "b"})
+		assert.Error(t, err)
+	})
+}
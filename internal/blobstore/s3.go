@@ -0,0 +1,382 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore/sigv4"
+)
+
+// S3Store - S3-совместимое хранилище (path-style адреса: {endpoint}/{bucket}/{key}, подпись SigV4)
+//   • Put читает поток частями по partSize: если все уместилось в одну часть - обычный PUT,
+//     иначе multipart upload (в памяти одновременно не больше одной части)
+//   • Get ходит с заголовком Range, поэтому диапазоны не тянут весь объект
+//   • при ошибке посреди multipart upload недокачанная загрузка отменяется (abort)
+
+// минимальный размер части multipart upload в S3 (кроме последней)
+const MinPartSize = 5 << 20
+
+type S3Config struct {
+	Endpoint        string // например, http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+type S3Store struct {
+	endpoint *url.URL
+	region   string
+	bucket   string
+	creds    sigv4.Credentials
+	client   *http.Client
+	partSize int
+	now      func() time.Time
+}
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3: bucket is required")
+	}
+
+	return &S3Store{
+		endpoint: endpoint,
+		region:   cfg.Region,
+		bucket:   cfg.Bucket,
+		creds:    sigv4.Credentials{AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey},
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		partSize: MinPartSize,
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	buf := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// весь файл уместился в одну часть
+		return s.putObject(ctx, key, buf[:n], contentType)
+	}
+	if err != nil {
+		return err
+	}
+	return s.putMultipart(ctx, key, r, buf, contentType)
+}
+
+func (s *S3Store) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	header := http.Header{}
+	switch {
+	case length > 0:
+		header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	case offset > 0:
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		return nil, s.responseError(http.MethodGet, key, resp)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if err := s.responseError(http.MethodDelete, key, resp); !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix - ListObjectsV2 по префиксу страницами и удаление каждого ключа
+func (s *S3Store) DeletePrefix(ctx context.Context, prefix string) error {
+	if err := validPrefix(prefix); err != nil {
+		return err
+	}
+
+	token := ""
+	for {
+		page, err := s.listObjects(ctx, prefix, token)
+		if err != nil {
+			return err
+		}
+		for _, object := range page.Contents {
+			if err := s.Delete(ctx, object.Key); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *S3Store) putObject(ctx context.Context, key string, body []byte, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(http.MethodPut, key, resp)
+	}
+	return nil
+}
+
+// XML-тела multipart upload (только нужные поля)
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// страница ListObjectsV2 (только нужные поля)
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Store) listObjects(ctx context.Context, prefix, token string) (*listBucketResult, error) {
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, s.responseError(http.MethodGet, prefix, resp)
+	}
+	var page listBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("s3: list %s: malformed response", prefix)
+	}
+	return &page, nil
+}
+
+// putMultipart - загрузка частями; first - уже прочитанная первая (полная) часть
+func (s *S3Store) putMultipart(ctx context.Context, key string, r io.Reader, first []byte, contentType string) error {
+	uploadID, err := s.initiateMultipart(ctx, key, contentType)
+	if err != nil {
+		return err
+	}
+
+	parts, err := s.uploadParts(ctx, key, uploadID, r, first)
+	if err == nil {
+		err = s.completeMultipart(ctx, key, uploadID, parts)
+	}
+	if err != nil {
+		// отменяем загрузку отдельным контекстом: исходный мог быть уже отменен
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if resp, abortErr := s.do(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil); abortErr == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *S3Store) initiateMultipart(ctx context.Context, key, contentType string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, header, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", s.responseError(http.MethodPost, key, resp)
+	}
+	var result initiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("s3: initiate multipart upload %s: malformed response", key)
+	}
+	return result.UploadID, nil
+}
+
+func (s *S3Store) uploadParts(ctx context.Context, key, uploadID string, r io.Reader, first []byte) ([]completedPart, error) {
+	var parts []completedPart
+	buf := first
+	for number := 1; ; number++ {
+		etag, err := s.uploadPart(ctx, key, uploadID, number, buf)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+
+		n, err := io.ReadFull(r, buf[:cap(buf)])
+		if errors.Is(err, io.EOF) {
+			return parts, nil
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		buf = buf[:n]
+	}
+}
+
+func (s *S3Store) uploadPart(ctx context.Context, key, uploadID string, number int, body []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	resp, err := s.do(ctx, http.MethodPut, key, query, nil, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", s.responseError(http.MethodPut, key, resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+func (s *S3Store) completeMultipart(ctx context.Context, key, uploadID string, parts []completedPart) error {
+	body, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(http.MethodPost, key, resp)
+	}
+
+	// S3 может ответить 200 и все равно вернуть ошибку в теле
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if e := parseError(data); e != nil {
+		return fmt.Errorf("s3: complete multipart upload %s: %s: %s", key, e.Code, e.Message)
+	}
+	return nil
+}
+
+// do - подписанный запрос к объекту key (пустой key - запрос к самому бакету)
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	payloadHash := sigv4.EmptySHA256
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	sigv4.Sign(req, s.creds, s.region, "s3", payloadHash, s.now())
+
+	return s.client.Do(req)
+}
+
+// s3Error - XML-ошибка S3
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func parseError(data []byte) *s3Error {
+	var e s3Error
+	if err := xml.Unmarshal(data, &e); err != nil || e.Code == "" {
+		return nil
+	}
+	return &e
+}
+
+// responseError - ошибка по неуспешному ответу; отсутствующий объект - ErrNotFound
+func (s *S3Store) responseError(method, key string, resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	e := parseError(data)
+
+	if resp.StatusCode == http.StatusNotFound && (e == nil || e.Code == "NoSuchKey") {
+		return ErrNotFound
+	}
+	if e != nil {
+		return fmt.Errorf("s3: %s %s: %s: %s", method, key, e.Code, e.Message)
+	}
+	return fmt.Errorf("s3: %s %s: unexpected status %d", method, key, resp.StatusCode)
+}
@@ -0,0 +1,362 @@
+package s3test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/blobstore/sigv4"
+)
+
+// Server - локальное S3-совместимое хранилище для тестов (как MinIO, только внутри процесса и в памяти)
+// умеет ровно то, что нужно blobstore.S3Store, и проверяет запросы так же строго, как настоящий S3:
+//   • подпись SigV4 и хэш тела (X-Amz-Content-Sha256)
+//   • PUT / GET (с Range) / HEAD / DELETE объекта
+//   • GET ?list-type=2 - список ключей бакета по префиксу (ListObjectsV2, страницами по MaxKeys)
+//   • multipart upload: POST ?uploads, PUT ?partNumber&uploadId, POST ?uploadId, DELETE ?uploadId
+//     (все части, кроме последней, не меньше MinPartSize)
+// ошибки - XML <Error><Code>...</Code></Error> с кодами S3 (NoSuchKey, SignatureDoesNotMatch, ...)
+
+type object struct {
+	data        []byte
+	contentType string
+	etag        string
+	modified    time.Time
+}
+
+type upload struct {
+	key         string
+	contentType string
+	parts       map[int][]byte
+}
+
+type Server struct {
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// минимальный размер части multipart upload (кроме последней); в тестах можно уменьшить
+	MinPartSize int
+	// сколько ключей в одной странице списка (в S3 - 1000); в тестах можно уменьшить
+	MaxKeys int
+
+	server *httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]object
+	uploads map[string]*upload
+}
+
+// NewServer - запускает сервер с одним бакетом; после теста его нужно закрыть (Close)
+func NewServer(bucket, accessKeyID, secretAccessKey string) *Server {
+	s := &Server{
+		Region:          "us-east-1",
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		MinPartSize:     5 << 20,
+		MaxKeys:         1000,
+		objects:         make(map[string]object),
+		uploads:         make(map[string]*upload),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL - адрес сервера (endpoint для клиента)
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Object - содержимое объекта (для проверок в тестах)
+func (s *Server) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[key]
+	return o.data, ok
+}
+
+// PendingUploads - сколько multipart upload начато и не завершено / не отменено
+func (s *Server) PendingUploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	creds := sigv4.Credentials{AccessKeyID: s.AccessKeyID, SecretAccessKey: s.SecretAccessKey}
+	if err := sigv4.Verify(r, creds, s.Region, "s3", time.Now()); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != r.Header.Get(sigv4.HeaderContentSHA256) {
+		writeError(w, http.StatusBadRequest, "XAmzContentSHA256Mismatch", "payload hash does not match")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.Bucket {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+		return
+	}
+	if key == "" {
+		if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+			s.listObjects(w, r.URL.Query().Get("prefix"), r.URL.Query().Get("continuation-token"))
+			return
+		}
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "bucket operations are not supported")
+		return
+	}
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.initiateUpload(w, r, key)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		s.uploadPart(w, q.Get("uploadId"), q.Get("partNumber"), body)
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		s.completeUpload(w, key, q.Get("uploadId"), body)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		s.abortUpload(w, q.Get("uploadId"))
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, key, body)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		s.getObject(w, r, key)
+	case r.Method == http.MethodDelete:
+		s.deleteObject(w, key)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+	}
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	o := newObject(body, r.Header.Get("Content-Type"))
+
+	s.mu.Lock()
+	s.objects[key] = o
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", o.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, key string) {
+	s.mu.Lock()
+	o, ok := s.objects[key]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+
+	w.Header().Set("ETag", o.etag)
+	w.Header().Set("Content-Type", o.contentType)
+	// Range, If-Range и 416 - как у настоящего S3
+	http.ServeContent(w, r, "", o.modified, bytes.NewReader(o.data))
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, key string) {
+	s.mu.Lock()
+	delete(s.objects, key)
+	s.mu.Unlock()
+
+	// S3 отвечает 204 и на удаление отсутствующего ключа
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listObjects - ключи по алфавиту; continuation-token - последний ключ предыдущей страницы
+func (s *Server) listObjects(w http.ResponseWriter, prefix, token string) {
+	s.mu.Lock()
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > token {
+			keys = append(keys, key)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(keys)
+
+	type content struct {
+		Key string `xml:"Key"`
+	}
+	result := struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		Name                  string    `xml:"Name"`
+		Prefix                string    `xml:"Prefix"`
+		KeyCount              int       `xml:"KeyCount"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		Contents              []content `xml:"Contents"`
+	}{Name: s.Bucket, Prefix: prefix}
+
+	if len(keys) > s.MaxKeys {
+		keys = keys[:s.MaxKeys]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{Key: key})
+	}
+	result.KeyCount = len(keys)
+	writeXML(w, result)
+}
+
+func (s *Server) initiateUpload(w http.ResponseWriter, r *http.Request, key string) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	id := hex.EncodeToString(idBytes)
+
+	s.mu.Lock()
+	s.uploads[id] = &upload{key: key, contentType: r.Header.Get("Content-Type"), parts: make(map[int][]byte)}
+	s.mu.Unlock()
+
+	writeXML(w, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}{Bucket: s.Bucket, Key: key, UploadID: id})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, uploadID, partNumber string, body []byte) {
+	number, err := strconv.Atoi(partNumber)
+	if err != nil || number < 1 || number > 10000 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "part number must be between 1 and 10000")
+		return
+	}
+
+	s.mu.Lock()
+	u, ok := s.uploads[uploadID]
+	if ok {
+		u.parts[number] = body
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+		return
+	}
+
+	w.Header().Set("ETag", etag(body))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) completeUpload(w http.ResponseWriter, key, uploadID string, body []byte) {
+	var req struct {
+		Parts []struct {
+			PartNumber int    `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}
+	if err := xml.Unmarshal(body, &req); err != nil || len(req.Parts) == 0 {
+		writeError(w, http.StatusBadRequest, "MalformedXML", "the XML you provided was not well-formed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.uploads[uploadID]
+	if !ok || u.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+		return
+	}
+	if !sort.SliceIsSorted(req.Parts, func(i, j int) bool { return req.Parts[i].PartNumber < req.Parts[j].PartNumber }) {
+		writeError(w, http.StatusBadRequest, "InvalidPartOrder", "parts must be in ascending order")
+		return
+	}
+
+	var data []byte
+	for i, p := range req.Parts {
+		part, ok := u.parts[p.PartNumber]
+		if !ok || etag(part) != p.ETag {
+			writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d not found", p.PartNumber))
+			return
+		}
+		if i < len(req.Parts)-1 && len(part) < s.MinPartSize {
+			writeError(w, http.StatusBadRequest, "EntityTooSmall", "proposed upload is smaller than the minimum allowed size")
+			return
+		}
+		data = append(data, part...)
+	}
+
+	o := newObject(data, u.contentType)
+	s.objects[key] = o
+	delete(s.uploads, uploadID)
+
+	writeXML(w, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}{Bucket: s.Bucket, Key: key, ETag: o.etag})
+}
+
+func (s *Server) abortUpload(w http.ResponseWriter, uploadID string) {
+	s.mu.Lock()
+	_, ok := s.uploads[uploadID]
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newObject(data []byte, contentType string) object {
+	if contentType == "" {
+		contentType = "binary/octet-stream" // как у S3
+	}
+	return object{data: data, contentType: contentType, etag: etag(data), modified: time.Now().UTC()}
+}
+
+// etag - как у S3 для обычных объектов: md5 в кавычках
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
@@ -0,0 +1,54 @@
+package sigv4
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+	now := time.Date(2025, 12, 18, 9, 0, 0, 0, time.UTC)
+
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/tasks/1/a%20b?partNumber=2&uploadId=xyz", nil)
+		Sign(req, creds, "us-east-1", "s3", EmptySHA256, now)
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(req *http.Request)
+		creds   Credentials
+		now     time.Time
+		wantErr string
+	}{
+		{name: "подпись сходится", creds: creds, now: now},
+		{name: "часы расходятся в пределах допуска", creds: creds, now: now.Add(10 * time.Minute)},
+		{name: "другой секрет", creds: Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "other"}, now: now, wantErr: "signature does not match"},
+		{name: "другой ключ", creds: Credentials{AccessKeyID: "OTHER", SecretAccessKey: "secret"}, now: now, wantErr: "unknown access key"},
+		{name: "подмена пути", tamper: func(r *http.Request) { r.URL.Path = "/bucket/tasks/2/a b" }, creds: creds, now: now, wantErr: "signature does not match"},
+		{name: "подмена query", tamper: func(r *http.Request) { r.URL.RawQuery = "partNumber=3&uploadId=xyz" }, creds: creds, now: now, wantErr: "signature does not match"},
+		{name: "подмена хэша тела", tamper: func(r *http.Request) { r.Header.Set(HeaderContentSHA256, "00") }, creds: creds, now: now, wantErr: "signature does not match"},
+		{name: "устаревший запрос", creds: creds, now: now.Add(time.Hour), wantErr: "request time too skewed"},
+		{name: "без подписи", tamper: func(r *http.Request) { r.Header.Del("Authorization") }, creds: creds, now: now, wantErr: "unsupported authorization"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest()
+			if tt.tamper != nil {
+				tt.tamper(req)
+			}
+
+			err := Verify(req, tt.creds, "us-east-1", "s3", tt.now)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package commentService
+
+import "github.com/stretchr/testify/mock"
+
+type MockCommentRepo struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepo) Create(comment *CommentStruct) (*CommentStruct, error) {
+	args := m.Called(comment)
+	var c *CommentStruct
+	if res := args.Get(0); res != nil {
+		c = res.(*CommentStruct)
+	}
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepo) GetByID(id uint) (CommentStruct, error) {
+	args := m.Called(id)
+	var c CommentStruct
+	if res := args.Get(0); res != nil {
+		c = res.(CommentStruct)
+	}
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepo) GetByTask(taskID uint) ([]CommentStruct, error) {
+	args := m.Called(taskID)
+	var comments []CommentStruct
+	if res := args.Get(0); res != nil {
+		comments = res.([]CommentStruct)
+	}
+	return comments, args.Error(1)
+}
+
+func (m *MockCommentRepo) Update(comment *CommentStruct, fields []string) (*CommentStruct, error) {
+	args := m.Called(comment, fields)
+	var c *CommentStruct
+	if res := args.Get(0); res != nil {
+		c = res.(*CommentStruct)
+	}
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepo) Delete(comment *CommentStruct) (*CommentStruct, error) {
+	args := m.Called(comment)
+	var c *CommentStruct
+	if res := args.Get(0); res != nil {
+		c = res.(*CommentStruct)
+	}
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepo) CountByTasks(taskIDs []uint) (map[uint]int64, error) {
+	args := m.Called(taskIDs)
+	var counts map[uint]int64
+	if res := args.Get(0); res != nil {
+		counts = res.(map[uint]int64)
+	}
+	return counts, args.Error(1)
+}
@@ -0,0 +1,239 @@
+package commentService
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var (
+	owner    = rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	stranger = rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	admin    = rbac.Actor{UserID: 3, Role: rbac.RoleAdmin}
+	guest    = rbac.Actor{UserID: 4, Role: rbac.RoleUser} // гость пространства задачи
+)
+
+// fakeTasks - задача 7 из пространства принадлежит owner, ее видят owner, админ и гость guest (только смотрит)
+type fakeTasks struct{}
+
+func (f fakeTasks) GetTask(actor rbac.Actor, id uint) (*taskService.Task, error) {
+	task, _, err := f.GetTaskPermissions(actor, id)
+	return task, err
+}
+
+func (fakeTasks) GetTaskPermissions(actor rbac.Actor, id uint) (*taskService.Task, taskService.TaskPermissions, error) {
+	var perms taskService.TaskPermissions
+	switch {
+	case id != 7:
+		return nil, perms, errors.New("task not found")
+	case actor.CanAccessUser(1):
+		perms = taskService.TaskPermissions{Contribute: true, Manage: true}
+	case actor.UserID == guest.UserID:
+	default:
+		return nil, perms, errors.New("task not found")
+	}
+	workspaceID := uint(2)
+	return &taskService.Task{ID: 7, UserId: 1, WorkspaceID: &workspaceID}, perms, nil
+}
+
+func TestGetComments(t *testing.T) {
+	deletedAt := time.Now()
+	mockRepo := new(MockCommentRepo)
+	mockRepo.On("GetByTask", uint(7)).Return([]CommentStruct{
+		{ID: 1, TaskID: 7, AuthorID: 1, Body: "Первый", Version: 1},
+		{ID: 2, TaskID: 7, AuthorID: 3, Body: "", Version: 2, DeletedAt: &deletedAt},
+	}, nil)
+	service := NewCommentService(mockRepo, fakeTasks{})
+
+	comments, err := service.GetComments(owner, 7)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "Первый", comments[0].Body)
+	assert.True(t, comments[1].Deleted) // надгробие остается в ленте
+
+	_, err = service.GetComments(stranger, 7)
+	assert.EqualError(t, err, "task not found")
+
+	// гость пространства ленту читает
+	comments, err = service.GetComments(guest, 7)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+}
+
+func TestCreateComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		taskID  uint
+		body    string
+		wantErr string
+	}{
+		{name: "владелец задачи", actor: owner, taskID: 7, body: "  Готово  "},
+		{name: "админ к чужой задаче", actor: admin, taskID: 7, body: "Проверил"},
+		{name: "чужая задача", actor: stranger, taskID: 7, body: "Привет", wantErr: "task not found"},
+		{name: "гость пространства только смотрит", actor: guest, taskID: 7, body: "Привет", wantErr: "forbidden"},
+		{name: "пустой комментарий", actor: owner, taskID: 7, body: "   ", wantErr: "comment is empty"},
+		{name: "слишком длинный", actor: owner, taskID: 7, body: strings.Repeat("я", maxBodyLength+1), wantErr: "comment is too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCommentRepo)
+			if tt.wantErr == "" {
+				mockRepo.On("Create", mock.MatchedBy(func(c *CommentStruct) bool {
+					return c.TaskID == tt.taskID && c.AuthorID == tt.actor.UserID && c.Body == strings.TrimSpace(tt.body)
+				})).Return(&CommentStruct{ID: 10, TaskID: tt.taskID, AuthorID: tt.actor.UserID, Body: strings.TrimSpace(tt.body), Version: 1}, nil).Once()
+			}
+
+			comment, err := NewCommentService(mockRepo, fakeTasks{}).CreateComment(tt.actor, tt.taskID, CreateCommentParams{Body: tt.body})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(10), comment.ID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+	uintPtr := func(u uint) *uint { return &u }
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		actor      rbac.Actor
+		existing   CommentStruct
+		getErr     error
+		version    *uint
+		params     UpdateCommentParams
+		wantErr    string
+		wantUpdate bool
+	}{
+		{
+			name:       "автор правит свой комментарий",
+			actor:      owner,
+			existing:   CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: "Старый", Version: 1},
+			version:    uintPtr(1),
+			params:     UpdateCommentParams{Body: stringPtr("Новый")},
+			wantUpdate: true,
+		},
+		{
+			name:     "даже админ не правит чужой комментарий",
+			actor:    admin,
+			existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: "Старый", Version: 1},
+			params:   UpdateCommentParams{Body: stringPtr("Новый")},
+			wantErr:  "forbidden",
+		},
+		{
+			name:     "комментарий к чужой задаче выглядит как несуществующий",
+			actor:    stranger,
+			existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 2, Body: "Старый", Version: 1},
+			params:   UpdateCommentParams{Body: stringPtr("Новый")},
+			wantErr:  "comment not found",
+		},
+		{
+			name:    "нет такого комментария",
+			actor:   owner,
+			getErr:  gorm.ErrRecordNotFound,
+			params:  UpdateCommentParams{Body: stringPtr("Новый")},
+			wantErr: "comment not found",
+		},
+		{
+			name:     "надгробие не правится",
+			actor:    owner,
+			existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Version: 2, DeletedAt: &deletedAt},
+			params:   UpdateCommentParams{Body: stringPtr("Новый")},
+			wantErr:  "comment is deleted",
+		},
+		{
+			name:     "устаревшая версия",
+			actor:    owner,
+			existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: "Старый", Version: 2},
+			version:  uintPtr(1),
+			params:   UpdateCommentParams{Body: stringPtr("Новый")},
+			wantErr:  "version mismatch",
+		},
+		{
+			name:     "нечего обновлять",
+			actor:    owner,
+			existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: "Старый", Version: 1},
+			wantErr:  "no fields to update",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCommentRepo)
+			mockRepo.On("GetByID", uint(1)).Return(tt.existing, tt.getErr)
+			if tt.wantUpdate {
+				mockRepo.On("Update", mock.MatchedBy(func(c *CommentStruct) bool { return c.Body == *tt.params.Body }), []string{CommentFieldBody}).
+					Return(&CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: *tt.params.Body, Version: 2}, nil).Once()
+			}
+
+			comment, err := NewCommentService(mockRepo, fakeTasks{}).UpdateComment(tt.actor, 1, tt.version, tt.params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(2), comment.Version)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	deletedAt := time.Now()
+	ownComment := CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Body: "Текст", Version: 1}
+
+	tests := []struct {
+		name       string
+		actor      rbac.Actor
+		existing   CommentStruct
+		wantErr    string
+		wantDelete bool
+	}{
+		{name: "автор удаляет свой комментарий", actor: owner, existing: ownComment, wantDelete: true},
+		{name: "админ удаляет чужой комментарий", actor: admin, existing: ownComment, wantDelete: true},
+		{name: "владелец задачи не удаляет чужой комментарий", actor: owner, existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 3, Body: "Текст", Version: 1}, wantErr: "forbidden"},
+		{name: "повторное удаление", actor: owner, existing: CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Version: 2, DeletedAt: &deletedAt}, wantErr: "comment is deleted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCommentRepo)
+			mockRepo.On("GetByID", uint(1)).Return(tt.existing, nil)
+			if tt.wantDelete {
+				mockRepo.On("Delete", mock.Anything).Return(&CommentStruct{ID: 1, TaskID: 7, AuthorID: 1, Version: 2, DeletedAt: &deletedAt}, nil).Once()
+			}
+
+			err := NewCommentService(mockRepo, fakeTasks{}).DeleteComment(tt.actor, 1, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestToCommentTombstone(t *testing.T) {
+	deletedAt := time.Now()
+	comment := toComment(&CommentStruct{ID: 1, Body: "остался по ошибке", DeletedAt: &deletedAt})
+	assert.True(t, comment.Deleted)
+	assert.Empty(t, comment.Body)
+}
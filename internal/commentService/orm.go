@@ -0,0 +1,26 @@
+package commentService
+
+import "time"
+
+// модель базы данных: комментарий к задаче
+// удаленный комментарий остается строкой-надгробием (DeletedAt != nil, текст стерт),
+// чтобы в ленте было видно, что здесь что-то было
+type CommentStruct struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	TaskID    uint `gorm:"not null;index"`
+	AuthorID  uint `gorm:"not null"`
+	Body      string
+	Version   uint `gorm:"not null;default:1"` // версия строки (для If-Match / ETag)
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (CommentStruct) TableName() string {
+	return "task_comments" // как в миграции
+}
+
+// имена колонок, которые можно частично обновлять (маска для CommentRepo.Update)
+const (
+	CommentFieldBody = "body"
+)
@@ -0,0 +1,121 @@
+package commentService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"gorm.io/gorm/clause"
+)
+
+// 2. repo-слой комментариев (как в taskService: только работа с бд)
+
+type CommentRepoInterface interface {
+	Create(comment *CommentStruct) (*CommentStruct, error)
+	GetByID(id uint) (CommentStruct, error)
+	GetByTask(taskID uint) ([]CommentStruct, error)
+	Update(comment *CommentStruct, fields []string) (*CommentStruct, error)
+	Delete(comment *CommentStruct) (*CommentStruct, error)
+	CountByTasks(taskIDs []uint) (map[uint]int64, error)
+}
+
+type CommentRepo struct{}
+
+// Create - добавляет комментарий
+func (r *CommentRepo) Create(comment *CommentStruct) (*CommentStruct, error) {
+	if comment.Version == 0 {
+		comment.Version = 1 // новая строка всегда начинается с первой версии
+	}
+	if err := db.DB.Create(comment).Error; err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetByID - комментарий по ID (в том числе удаленный)
+func (r *CommentRepo) GetByID(id uint) (CommentStruct, error) {
+	var comment CommentStruct
+	err := db.DB.First(&comment, "id = ?", id).Error
+	return comment, err
+}
+
+// GetByTask - лента комментариев задачи (сначала старые, надгробия на своих местах)
+func (r *CommentRepo) GetByTask(taskID uint) ([]CommentStruct, error) {
+	var comments []CommentStruct
+	err := db.DB.Where("task_id = ?", taskID).Order("created_at, id").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Update - обновляет только колонки из fields при совпадении версии (как TaskRepo.Update)
+func (r *CommentRepo) Update(comment *CommentStruct, fields []string) (*CommentStruct, error) {
+	updated := *comment
+	updated.UpdatedAt = time.Now()
+	updated.Version = comment.Version + 1
+
+	columns := append([]string{"updated_at", "version"}, fields...)
+
+	res := db.DB.Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("version = ? AND deleted_at IS NULL", comment.Version).
+		Select(columns).
+		Updates(&updated)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, errors.New("version mismatch")
+	}
+	return &updated, nil
+}
+
+// Delete - превращает комментарий в надгробие: текст стираем, строку оставляем
+func (r *CommentRepo) Delete(comment *CommentStruct) (*CommentStruct, error) {
+	now := time.Now()
+	deleted := *comment
+	deleted.Body = ""
+	deleted.DeletedAt = &now
+	deleted.UpdatedAt = now
+	deleted.Version = comment.Version + 1
+
+	res := db.DB.Model(&deleted).
+		Clauses(clause.Returning{}).
+		Where("version = ? AND deleted_at IS NULL", comment.Version).
+		Select("body", "deleted_at", "updated_at", "version").
+		Updates(&deleted)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, errors.New("version mismatch")
+	}
+	return &deleted, nil
+}
+
+// CountByTasks - число неудаленных комментариев у каждой из задач одним запросом
+// (задач без комментариев в ответе нет - для них 0)
+func (r *CommentRepo) CountByTasks(taskIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TaskID uint
+		Count  int64
+	}
+	err := db.DB.Model(&CommentStruct{}).
+		Select("task_id, COUNT(*) AS count").
+		Where("task_id IN ? AND deleted_at IS NULL", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TaskID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,203 @@
+package commentService
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+)
+
+// 3. service-слой комментариев
+// права доступа:
+//   • читать комментарии может тот, кому видна задача, писать - тоже, кроме гостя пространства задачи
+//     (он только смотрит - "forbidden"); комментарии к чужой задаче выглядят как несуществующие
+//     ("task not found" / "comment not found")
+//   • менять текст может только автор (даже админ не может - "forbidden")
+//   • удалить может автор или админ; удаленный комментарий остается в ленте надгробием без текста
+
+// максимальная длина комментария (в символах)
+const maxBodyLength = 5000
+
+// структура параметров метода CreateComment
+type CreateCommentParams struct {
+	Body string
+}
+
+// структура параметров метода UpdateComment (nil - поле не меняется)
+type UpdateCommentParams struct {
+	Body *string
+}
+
+// бизнес-модель комментария (у надгробия Deleted == true и пустой Body)
+type Comment struct {
+	ID        uint
+	TaskID    uint
+	AuthorID  uint
+	Body      string
+	Version   uint
+	Deleted   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TaskGetter - задача, если actor может ее видеть, и его права на нее (реализует taskService.TaskService)
+type TaskGetter interface {
+	GetTask(actor rbac.Actor, id uint) (*taskService.Task, error)
+	GetTaskPermissions(actor rbac.Actor, id uint) (*taskService.Task, taskService.TaskPermissions, error)
+}
+
+type CommentService struct {
+	repo  CommentRepoInterface
+	tasks TaskGetter
+}
+
+func NewCommentService(r CommentRepoInterface, tasks TaskGetter) *CommentService {
+	return &CommentService{repo: r, tasks: tasks}
+}
+
+// GetComments - лента комментариев задачи (сначала старые)
+func (s *CommentService) GetComments(actor rbac.Actor, taskID uint) ([]Comment, error) {
+	if _, err := s.tasks.GetTask(actor, taskID); err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	dbComments, err := s.repo.GetByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	comments := make([]Comment, 0, len(dbComments))
+	for i := range dbComments {
+		comments = append(comments, *toComment(&dbComments[i]))
+	}
+	return comments, nil
+}
+
+func (s *CommentService) CreateComment(actor rbac.Actor, taskID uint, params CreateCommentParams) (*Comment, error) {
+	_, perms, err := s.tasks.GetTaskPermissions(actor, taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	if !perms.Contribute {
+		return nil, errors.New("forbidden")
+	}
+
+	body, err := validateBody(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(&CommentStruct{
+		TaskID:   taskID,
+		AuthorID: actor.UserID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toComment(created), nil
+}
+
+// UpdateComment - правка текста автором
+// version - версия, которую видел клиент (из If-Match); nil - обновляем любую текущую версию
+func (s *CommentService) UpdateComment(actor rbac.Actor, id uint, version *uint, params UpdateCommentParams) (*Comment, error) {
+	dbComment, err := s.getVisible(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if dbComment.DeletedAt != nil {
+		return nil, errors.New("comment is deleted")
+	}
+	if dbComment.AuthorID != actor.UserID {
+		return nil, errors.New("forbidden")
+	}
+	if version != nil && *version != dbComment.Version {
+		return nil, errors.New("version mismatch")
+	}
+
+	// маска изменённых колонок
+	var fields []string
+	if params.Body != nil {
+		body, err := validateBody(*params.Body)
+		if err != nil {
+			return nil, err
+		}
+		dbComment.Body = body
+		fields = append(fields, CommentFieldBody)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+
+	updated, err := s.repo.Update(&dbComment, fields)
+	if err != nil {
+		return nil, err
+	}
+	return toComment(updated), nil
+}
+
+// DeleteComment - превращает комментарий в надгробие (автор или админ; version - как в UpdateComment)
+func (s *CommentService) DeleteComment(actor rbac.Actor, id uint, version *uint) error {
+	dbComment, err := s.getVisible(actor, id)
+	if err != nil {
+		return err
+	}
+	if dbComment.DeletedAt != nil {
+		return errors.New("comment is deleted")
+	}
+	if dbComment.AuthorID != actor.UserID && !actor.IsAdmin() {
+		return errors.New("forbidden")
+	}
+	if version != nil && *version != dbComment.Version {
+		return errors.New("version mismatch")
+	}
+
+	_, err = s.repo.Delete(&dbComment)
+	return err
+}
+
+// getVisible - комментарий, если actor видит его задачу
+func (s *CommentService) getVisible(actor rbac.Actor, id uint) (CommentStruct, error) {
+	dbComment, err := s.repo.GetByID(id)
+	if err != nil || dbComment.ID == 0 {
+		return CommentStruct{}, errors.New("comment not found")
+	}
+	if _, err := s.tasks.GetTask(actor, dbComment.TaskID); err != nil {
+		return CommentStruct{}, errors.New("comment not found")
+	}
+	return dbComment, nil
+}
+
+// validateBody - обрезает пробелы по краям и проверяет длину
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment is empty")
+	}
+	if utf8.RuneCountInString(body) > maxBodyLength {
+		return "", errors.New("comment is too long")
+	}
+	return body, nil
+}
+
+// toComment - маппим бд-модель в бизнес-модель (у надгробия текста нет)
+func toComment(c *CommentStruct) *Comment {
+	comment := &Comment{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		Version:   c.Version,
+		Deleted:   c.DeletedAt != nil,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if comment.Deleted {
+		comment.Body = ""
+	}
+	return comment
+}
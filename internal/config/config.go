@@ -0,0 +1,593 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// конфигурация приложения
+// все настройки читаются из переменных окружения, у каждой есть значение по умолчанию,
+// поэтому приложение запускается и без них
+
+type Config struct {
+	RateLimit   RateLimitConfig
+	Password    PasswordConfig
+	Mailer      MailerConfig
+	Auth        AuthConfig
+	OIDC        OIDCConfig
+	Lockout     LockoutConfig
+	TwoFactor   TwoFactorConfig
+	Attachments AttachmentsConfig
+	Workspaces  WorkspacesConfig
+	Events      EventsConfig
+	Webhooks    WebhooksConfig
+}
+
+// лимит token bucket: Requests запросов за Per (это же и размер "ведра")
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	// доверять ли X-Forwarded-For (только если приложение стоит за своим прокси; берется адрес, который дописал он)
+	TrustProxy bool
+	// лимит для всех маршрутов, у которых нет своего
+	Default Limit
+	// лимиты по маршрутам: ключ - шаблон маршрута как в ServeMux ("POST /users", "PATCH /tasks/{id}")
+	Routes map[string]Limit
+	// неудачных аутентификаций (ответов 401) с одного IP - считаются до проверки токена
+	AuthFailures Limit
+}
+
+// настройки политики паролей (максимальная длина не настраивается - это ограничение bcrypt)
+type PasswordConfig struct {
+	MinLength  int
+	MinClasses int
+	// проверять ли пароль по встроенному списку распространенных (100 тысяч паролей, см. userService)
+	CheckCommon bool
+}
+
+// способы отправки писем
+const (
+	MailerLog  = "log"  // письмо печатается в лог (по умолчанию, для локальной разработки)
+	MailerFile = "file" // письма дописываются в файл MAILER_FILE
+	MailerSMTP = "smtp" // настоящая отправка через SMTP
+)
+
+type MailerConfig struct {
+	Driver   string
+	From     string
+	FilePath string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+type AuthConfig struct {
+	// страница фронтенда для ввода нового пароля (к ней добавляется ?token=...)
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+	// внешний адрес эндпоинта GET /auth/verify (к нему добавляется ?token=...)
+	VerifyEmailURL string
+	VerifyEmailTTL time.Duration
+	// запрещать создавать задачи, пока email не подтвержден
+	RequireVerifiedEmail bool
+	// секрет подписи access-токенов (пустой - случайный при каждом запуске, все входы слетают после рестарта)
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// блокировка входа после серии неудачных попыток
+// после MaxFailures неудач подряд вход закрыт на BaseDuration, дальше срок удваивается до MaxDuration
+type LockoutConfig struct {
+	MaxFailures  int // 0 - блокировка выключена
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+}
+
+// двухфакторная аутентификация (TOTP)
+// без EncryptionKey подключить TOTP нельзя (секреты в бд хранятся только зашифрованными)
+type TwoFactorConfig struct {
+	EncryptionKey []byte // 32 байта (AES-256), в окружении - base64
+	Issuer        string // название сервиса в приложении-аутентификаторе
+}
+
+// вход через внешний OIDC-провайдер: включен, если задан OIDC_ISSUER
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// внешний адрес эндпоинта GET /auth/oidc/callback (должен быть зарегистрирован у провайдера)
+	RedirectURL string
+	Scopes      []string
+}
+
+// Enabled - настроен ли вход через OIDC
+func (c OIDCConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// где хранится содержимое вложений
+const (
+	AttachmentsLocal = "local" // каталог на диске ATTACHMENTS_DIR (по умолчанию)
+	AttachmentsS3    = "s3"    // S3-совместимое хранилище (AWS S3, MinIO и т.п.)
+)
+
+type AttachmentsConfig struct {
+	Store   string
+	Dir     string
+	MaxSize int64 // максимальный размер одного файла в байтах
+
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+// как события об изменении задач доходят до потоков GET /events
+const (
+	EventsMemory   = "memory"   // только внутри процесса (по умолчанию, один экземпляр приложения)
+	EventsPostgres = "postgres" // через Postgres LISTEN/NOTIFY - для нескольких экземпляров
+)
+
+type EventsConfig struct {
+	Broker string
+	// сколько последних событий помнить для переподключения с Last-Event-ID
+	ReplaySize int
+}
+
+// исходящие вебхуки
+// без EncryptionKey создавать вебхуки нельзя (секреты подписи в бд хранятся только зашифрованными)
+type WebhooksConfig struct {
+	EncryptionKey []byte        // 32 байта (AES-256), в окружении - base64
+	MaxAttempts   int           // попыток доставки одного события
+	Timeout       time.Duration // сколько ждать ответа получателя
+	PollInterval  time.Duration // как часто проверять очередь доставок
+}
+
+// приглашения в рабочие пространства
+type WorkspacesConfig struct {
+	// страница фронтенда для принятия приглашения (к ней добавляется ?token=...)
+	InviteURL string
+	InviteTTL time.Duration
+}
+
+// лимиты по умолчанию: создание пользователей, смена и сброс пароля и вход ограничены жестче,
+// чтобы их нельзя было перебирать
+const (
+	defaultRateLimit       = "100/m"
+	defaultRateLimitRoutes = "POST /users=5/m,POST /users/{id}/password=5/m," +
+		"POST /auth/password-reset=5/m,POST /auth/password-reset/confirm=10/m," +
+		"POST /auth/login=10/m,POST /auth/refresh=30/m," +
+		"POST /auth/login/2fa=10/m,POST /auth/2fa/totp/disable=10/m,POST /auth/2fa/recovery-codes=10/m"
+	defaultAuthFailures = "20/m"
+)
+
+// Load - читает конфигурацию из окружения
+func Load() (*Config, error) {
+	def, err := ParseLimit(getEnv("RATE_LIMIT_DEFAULT", defaultRateLimit))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	routes, err := ParseRouteLimits(getEnv("RATE_LIMIT_ROUTES", defaultRateLimitRoutes))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+	}
+
+	authFailures, err := ParseLimit(getEnv("RATE_LIMIT_AUTH_FAILURES", defaultAuthFailures))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_AUTH_FAILURES: %w", err)
+	}
+
+	enabled, err := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ENABLED: %w", err)
+	}
+
+	trustProxy, err := strconv.ParseBool(getEnv("RATE_LIMIT_TRUST_PROXY", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_TRUST_PROXY: %w", err)
+	}
+
+	password, err := loadPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	mailer, err := loadMailer()
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := loadAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	oidc, err := loadOIDC()
+	if err != nil {
+		return nil, err
+	}
+
+	lockout, err := loadLockout()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := loadTwoFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := loadAttachments()
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := loadWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := loadEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := loadWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		RateLimit: RateLimitConfig{
+			Enabled:      enabled,
+			TrustProxy:   trustProxy,
+			Default:      def,
+			Routes:       routes,
+			AuthFailures: authFailures,
+		},
+		Password:    password,
+		Mailer:      mailer,
+		Auth:        auth,
+		OIDC:        oidc,
+		Lockout:     lockout,
+		TwoFactor:   twoFactor,
+		Attachments: attachments,
+		Workspaces:  workspaces,
+		Events:      events,
+		Webhooks:    webhooks,
+	}, nil
+}
+
+// loadMailer - читает настройки отправки писем
+func loadMailer() (MailerConfig, error) {
+	cfg := MailerConfig{
+		Driver:       getEnv("MAILER", MailerLog),
+		From:         getEnv("MAIL_FROM", "noreply@localhost"),
+		FilePath:     getEnv("MAILER_FILE", "mail.log"),
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+	}
+
+	switch cfg.Driver {
+	case MailerLog, MailerFile, MailerSMTP:
+	default:
+		return MailerConfig{}, fmt.Errorf("MAILER: unknown mailer %q, want log, file or smtp", cfg.Driver)
+	}
+
+	port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil || port < 1 || port > 65535 {
+		return MailerConfig{}, fmt.Errorf("SMTP_PORT: invalid port")
+	}
+	cfg.SMTPPort = port
+
+	return cfg, nil
+}
+
+// loadAttachments - читает настройки хранилища вложений
+func loadAttachments() (AttachmentsConfig, error) {
+	cfg := AttachmentsConfig{
+		Store:             getEnv("ATTACHMENTS_STORE", AttachmentsLocal),
+		Dir:               getEnv("ATTACHMENTS_DIR", "./data/attachments"),
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+	}
+
+	maxSize, err := strconv.ParseInt(getEnv("ATTACHMENTS_MAX_SIZE", "10485760"), 10, 64)
+	if err != nil || maxSize < 1 {
+		return AttachmentsConfig{}, fmt.Errorf("ATTACHMENTS_MAX_SIZE: must be a positive number of bytes")
+	}
+	cfg.MaxSize = maxSize
+
+	switch cfg.Store {
+	case AttachmentsLocal:
+		if strings.TrimSpace(cfg.Dir) == "" {
+			return AttachmentsConfig{}, fmt.Errorf("ATTACHMENTS_DIR: required for the local store")
+		}
+	case AttachmentsS3:
+		endpoint, err := url.Parse(cfg.S3Endpoint)
+		if err != nil || !endpoint.IsAbs() || endpoint.Host == "" {
+			return AttachmentsConfig{}, fmt.Errorf("S3_ENDPOINT: must be an absolute URL")
+		}
+		if cfg.S3Bucket == "" {
+			return AttachmentsConfig{}, fmt.Errorf("S3_BUCKET: required when ATTACHMENTS_STORE is s3")
+		}
+		if cfg.S3AccessKeyID == "" || cfg.S3SecretAccessKey == "" {
+			return AttachmentsConfig{}, fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY: required when ATTACHMENTS_STORE is s3")
+		}
+	default:
+		return AttachmentsConfig{}, fmt.Errorf("ATTACHMENTS_STORE: unknown store %q, want local or s3", cfg.Store)
+	}
+
+	return cfg, nil
+}
+
+// loadWorkspaces - читает настройки приглашений в рабочие пространства
+func loadWorkspaces() (WorkspacesConfig, error) {
+	ttl, err := time.ParseDuration(getEnv("WORKSPACE_INVITE_TTL", "168h"))
+	if err != nil || ttl <= 0 {
+		return WorkspacesConfig{}, fmt.Errorf("WORKSPACE_INVITE_TTL: must be a positive duration like 24h or 168h")
+	}
+
+	return WorkspacesConfig{
+		InviteURL: getEnv("WORKSPACE_INVITE_URL", "http://localhost:9092/join-workspace"),
+		InviteTTL: ttl,
+	}, nil
+}
+
+// loadEvents - читает настройки событий об изменении задач
+func loadEvents() (EventsConfig, error) {
+	broker := getEnv("EVENTS_BROKER", EventsMemory)
+	if broker != EventsMemory && broker != EventsPostgres {
+		return EventsConfig{}, fmt.Errorf("EVENTS_BROKER: unknown broker %q, want memory or postgres", broker)
+	}
+
+	replaySize, err := strconv.Atoi(getEnv("EVENTS_REPLAY_SIZE", "1000"))
+	if err != nil || replaySize < 1 {
+		return EventsConfig{}, fmt.Errorf("EVENTS_REPLAY_SIZE: must be a positive number of events")
+	}
+
+	return EventsConfig{Broker: broker, ReplaySize: replaySize}, nil
+}
+
+// loadWebhooks - читает настройки вебхуков
+func loadWebhooks() (WebhooksConfig, error) {
+	maxAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil || maxAttempts < 1 {
+		return WebhooksConfig{}, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: must be a positive number")
+	}
+
+	timeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil || timeout <= 0 {
+		return WebhooksConfig{}, fmt.Errorf("WEBHOOK_TIMEOUT: must be a positive duration like 10s")
+	}
+
+	pollInterval, err := time.ParseDuration(getEnv("WEBHOOK_POLL_INTERVAL", "5s"))
+	if err != nil || pollInterval <= 0 {
+		return WebhooksConfig{}, fmt.Errorf("WEBHOOK_POLL_INTERVAL: must be a positive duration like 5s")
+	}
+
+	cfg := WebhooksConfig{MaxAttempts: maxAttempts, Timeout: timeout, PollInterval: pollInterval}
+	raw := strings.TrimSpace(getEnv("WEBHOOK_ENCRYPTION_KEY", ""))
+	if raw == "" {
+		return cfg, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return WebhooksConfig{}, fmt.Errorf("WEBHOOK_ENCRYPTION_KEY: must be 32 bytes encoded in base64 (openssl rand -base64 32)")
+	}
+	cfg.EncryptionKey = key
+
+	return cfg, nil
+}
+
+// loadAuth - читает настройки аутентификации
+func loadAuth() (AuthConfig, error) {
+	ttl, err := time.ParseDuration(getEnv("PASSWORD_RESET_TTL", "1h"))
+	if err != nil || ttl <= 0 {
+		return AuthConfig{}, fmt.Errorf("PASSWORD_RESET_TTL: must be a positive duration like 30m or 1h")
+	}
+
+	verifyTTL, err := time.ParseDuration(getEnv("VERIFY_EMAIL_TTL", "24h"))
+	if err != nil || verifyTTL <= 0 {
+		return AuthConfig{}, fmt.Errorf("VERIFY_EMAIL_TTL: must be a positive duration like 30m or 24h")
+	}
+
+	requireVerified, err := strconv.ParseBool(getEnv("REQUIRE_VERIFIED_EMAIL", "false"))
+	if err != nil {
+		return AuthConfig{}, fmt.Errorf("REQUIRE_VERIFIED_EMAIL: %w", err)
+	}
+
+	accessTTL, err := time.ParseDuration(getEnv("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil || accessTTL <= 0 {
+		return AuthConfig{}, fmt.Errorf("ACCESS_TOKEN_TTL: must be a positive duration like 15m")
+	}
+
+	refreshTTL, err := time.ParseDuration(getEnv("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil || refreshTTL <= 0 {
+		return AuthConfig{}, fmt.Errorf("REFRESH_TOKEN_TTL: must be a positive duration like 720h")
+	}
+
+	// короткий секрет для HS256 легко подобрать
+	secret := getEnv("JWT_SECRET", "")
+	if secret != "" && len(secret) < 32 {
+		return AuthConfig{}, fmt.Errorf("JWT_SECRET: must be at least 32 bytes")
+	}
+
+	return AuthConfig{
+		PasswordResetURL:     getEnv("PASSWORD_RESET_URL", "http://localhost:9092/reset-password"),
+		PasswordResetTTL:     ttl,
+		VerifyEmailURL:       getEnv("VERIFY_EMAIL_URL", "http://localhost:9092/auth/verify"),
+		VerifyEmailTTL:       verifyTTL,
+		RequireVerifiedEmail: requireVerified,
+		JWTSecret:            secret,
+		AccessTokenTTL:       accessTTL,
+		RefreshTokenTTL:      refreshTTL,
+	}, nil
+}
+
+// loadLockout - читает настройки блокировки входа
+func loadLockout() (LockoutConfig, error) {
+	maxFailures, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_THRESHOLD", "5"))
+	if err != nil || maxFailures < 0 {
+		return LockoutConfig{}, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD: must be a number, 0 disables the lockout")
+	}
+
+	base, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_BASE", "1m"))
+	if err != nil || base <= 0 {
+		return LockoutConfig{}, fmt.Errorf("LOGIN_LOCKOUT_BASE: must be a positive duration like 1m")
+	}
+
+	maxDuration, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_MAX", "1h"))
+	if err != nil || maxDuration < base {
+		return LockoutConfig{}, fmt.Errorf("LOGIN_LOCKOUT_MAX: must be a duration not shorter than LOGIN_LOCKOUT_BASE")
+	}
+
+	return LockoutConfig{MaxFailures: maxFailures, BaseDuration: base, MaxDuration: maxDuration}, nil
+}
+
+// loadTwoFactor - читает настройки двухфакторной аутентификации
+func loadTwoFactor() (TwoFactorConfig, error) {
+	cfg := TwoFactorConfig{Issuer: getEnv("TOTP_ISSUER", "WebPet1")}
+	if strings.Contains(cfg.Issuer, ":") || strings.TrimSpace(cfg.Issuer) == "" {
+		return TwoFactorConfig{}, fmt.Errorf("TOTP_ISSUER: must be a non-empty name without ':'")
+	}
+
+	raw := strings.TrimSpace(getEnv("TOTP_ENCRYPTION_KEY", ""))
+	if raw == "" {
+		return cfg, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return TwoFactorConfig{}, fmt.Errorf("TOTP_ENCRYPTION_KEY: must be 32 bytes encoded in base64 (openssl rand -base64 32)")
+	}
+	cfg.EncryptionKey = key
+
+	return cfg, nil
+}
+
+// loadOIDC - читает настройки входа через OIDC (без OIDC_ISSUER вход выключен)
+func loadOIDC() (OIDCConfig, error) {
+	cfg := OIDCConfig{
+		Issuer:       strings.TrimSpace(getEnv("OIDC_ISSUER", "")),
+		ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:9092/auth/oidc/callback"),
+		Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+	}
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+
+	if cfg.ClientID == "" {
+		return OIDCConfig{}, fmt.Errorf("OIDC_CLIENT_ID: required when OIDC_ISSUER is set")
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		return OIDCConfig{}, fmt.Errorf("OIDC_SCOPES: must include openid")
+	}
+	redirect, err := url.Parse(cfg.RedirectURL)
+	if err != nil || !redirect.IsAbs() {
+		return OIDCConfig{}, fmt.Errorf("OIDC_REDIRECT_URL: must be an absolute URL")
+	}
+
+	return cfg, nil
+}
+
+// loadPassword - читает настройки политики паролей
+func loadPassword() (PasswordConfig, error) {
+	minLength, err := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	if err != nil || minLength < 1 {
+		return PasswordConfig{}, fmt.Errorf("PASSWORD_MIN_LENGTH: must be a positive number")
+	}
+
+	minClasses, err := strconv.Atoi(getEnv("PASSWORD_MIN_CLASSES", "2"))
+	if err != nil || minClasses < 1 || minClasses > 4 {
+		return PasswordConfig{}, fmt.Errorf("PASSWORD_MIN_CLASSES: must be between 1 and 4")
+	}
+
+	checkCommon, err := strconv.ParseBool(getEnv("PASSWORD_CHECK_COMMON", "true"))
+	if err != nil {
+		return PasswordConfig{}, fmt.Errorf("PASSWORD_CHECK_COMMON: %w", err)
+	}
+
+	return PasswordConfig{
+		MinLength:   minLength,
+		MinClasses:  minClasses,
+		CheckCommon: checkCommon,
+	}, nil
+}
+
+// ParseLimit - разбирает лимит вида "N/s", "N/m" или "N/h"
+func ParseLimit(s string) (Limit, error) {
+	count, unit, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, want N/s, N/m or N/h", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("invalid request count in limit %q", s)
+	}
+
+	var per time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid period in limit %q, want s, m or h", s)
+	}
+
+	return Limit{Requests: n, Per: per}, nil
+}
+
+// ParseRouteLimits - разбирает список "МЕТОД /путь=N/m" через запятую
+func ParseRouteLimits(s string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route limit %q, want \"METHOD /path=N/m\"", item)
+		}
+
+		l, err := ParseLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+		routes[strings.TrimSpace(route)] = l
+	}
+	return routes, nil
+}
+
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,284 @@
+package config
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{name: "в секунду", in: "10/s", want: Limit{Requests: 10, Per: time.Second}},
+		{name: "в минуту с пробелами", in: " 5 / m ", want: Limit{Requests: 5, Per: time.Minute}},
+		{name: "в час", in: "1000/h", want: Limit{Requests: 1000, Per: time.Hour}},
+		{name: "нет периода", in: "10", wantErr: true},
+		{name: "неизвестный период", in: "10/d", wantErr: true},
+		{name: "ноль запросов", in: "0/m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	got, err := ParseRouteLimits("POST /users=5/m, PATCH /tasks/{id}=30/m,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"POST /users":       {Requests: 5, Per: time.Minute},
+		"PATCH /tasks/{id}": {Requests: 30, Per: time.Minute},
+	}, got)
+
+	_, err = ParseRouteLimits("POST /users")
+	assert.Error(t, err)
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("RATE_LIMIT_DEFAULT", "50/m")
+	t.Setenv("RATE_LIMIT_ROUTES", "")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.True(t, cfg.RateLimit.Enabled)
+	assert.False(t, cfg.RateLimit.TrustProxy)
+	assert.Equal(t, Limit{Requests: 50, Per: time.Minute}, cfg.RateLimit.Default)
+	assert.Empty(t, cfg.RateLimit.Routes)
+	assert.Equal(t, Limit{Requests: 20, Per: time.Minute}, cfg.RateLimit.AuthFailures)
+	assert.Equal(t, PasswordConfig{MinLength: 8, MinClasses: 2, CheckCommon: true}, cfg.Password)
+}
+
+func TestLoadPassword(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_MIN_CLASSES", "3")
+	t.Setenv("PASSWORD_CHECK_COMMON", "false")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, PasswordConfig{MinLength: 12, MinClasses: 3, CheckCommon: false}, cfg.Password)
+
+	t.Setenv("PASSWORD_MIN_CLASSES", "5")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoadMailerAndAuth(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, MailerLog, cfg.Mailer.Driver)
+	assert.Equal(t, 587, cfg.Mailer.SMTPPort)
+	assert.Equal(t, time.Hour, cfg.Auth.PasswordResetTTL)
+	assert.Equal(t, 24*time.Hour, cfg.Auth.VerifyEmailTTL)
+	assert.False(t, cfg.Auth.RequireVerifiedEmail)
+	assert.Empty(t, cfg.Auth.JWTSecret)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 30*24*time.Hour, cfg.Auth.RefreshTokenTTL)
+
+	t.Setenv("MAILER", "smtp")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("PASSWORD_RESET_TTL", "15m")
+	t.Setenv("REQUIRE_VERIFIED_EMAIL", "true")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, MailerSMTP, cfg.Mailer.Driver)
+	assert.Equal(t, 2525, cfg.Mailer.SMTPPort)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.PasswordResetTTL)
+	assert.True(t, cfg.Auth.RequireVerifiedEmail)
+
+	t.Setenv("MAILER", "pigeon")
+	_, err = Load()
+	assert.Error(t, err)
+	t.Setenv("MAILER", "log")
+
+	t.Setenv("JWT_SECRET", "too-short")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoadOIDC(t *testing.T) {
+	// без OIDC_ISSUER вход через OIDC выключен
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.False(t, cfg.OIDC.Enabled())
+
+	t.Setenv("OIDC_ISSUER", "https://accounts.example.com")
+	_, err = Load()
+	assert.ErrorContains(t, err, "OIDC_CLIENT_ID")
+
+	t.Setenv("OIDC_CLIENT_ID", "webpet")
+	t.Setenv("OIDC_CLIENT_SECRET", "secret")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.True(t, cfg.OIDC.Enabled())
+	assert.Equal(t, OIDCConfig{
+		Issuer:       "https://accounts.example.com",
+		ClientID:     "webpet",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:9092/auth/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}, cfg.OIDC)
+
+	t.Setenv("OIDC_SCOPES", "email profile")
+	_, err = Load()
+	assert.ErrorContains(t, err, "OIDC_SCOPES")
+	t.Setenv("OIDC_SCOPES", "openid email")
+
+	t.Setenv("OIDC_REDIRECT_URL", "/auth/oidc/callback")
+	_, err = Load()
+	assert.ErrorContains(t, err, "OIDC_REDIRECT_URL")
+}
+
+func TestLoadLockout(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, LockoutConfig{MaxFailures: 5, BaseDuration: time.Minute, MaxDuration: time.Hour}, cfg.Lockout)
+
+	t.Setenv("LOGIN_LOCKOUT_THRESHOLD", "0")
+	t.Setenv("LOGIN_LOCKOUT_BASE", "30s")
+	t.Setenv("LOGIN_LOCKOUT_MAX", "15m")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, LockoutConfig{MaxFailures: 0, BaseDuration: 30 * time.Second, MaxDuration: 15 * time.Minute}, cfg.Lockout)
+
+	t.Setenv("LOGIN_LOCKOUT_MAX", "10s")
+	_, err = Load()
+	assert.ErrorContains(t, err, "LOGIN_LOCKOUT_MAX")
+}
+
+func TestLoadTwoFactor(t *testing.T) {
+	// без ключа подключить TOTP нельзя, но приложение запускается
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, TwoFactorConfig{Issuer: "WebPet1"}, cfg.TwoFactor)
+
+	t.Setenv("TOTP_ENCRYPTION_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	t.Setenv("TOTP_ISSUER", "WebPet Staging")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, TwoFactorConfig{EncryptionKey: []byte("0123456789abcdef0123456789abcdef"), Issuer: "WebPet Staging"}, cfg.TwoFactor)
+
+	t.Setenv("TOTP_ENCRYPTION_KEY", "c2hvcnQ=")
+	_, err = Load()
+	assert.ErrorContains(t, err, "TOTP_ENCRYPTION_KEY")
+	t.Setenv("TOTP_ENCRYPTION_KEY", "")
+
+	t.Setenv("TOTP_ISSUER", "Web:Pet")
+	_, err = Load()
+	assert.ErrorContains(t, err, "TOTP_ISSUER")
+}
+
+func TestLoadAttachments(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, AttachmentsConfig{Store: AttachmentsLocal, Dir: "./data/attachments", MaxSize: 10 << 20, S3Region: "us-east-1"}, cfg.Attachments)
+
+	t.Setenv("ATTACHMENTS_STORE", "s3")
+	t.Setenv("ATTACHMENTS_MAX_SIZE", "1048576")
+	t.Setenv("S3_ENDPOINT", "http://localhost:9000")
+	t.Setenv("S3_BUCKET", "attachments")
+	t.Setenv("S3_ACCESS_KEY_ID", "minio")
+	t.Setenv("S3_SECRET_ACCESS_KEY", "minio-secret")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<20), cfg.Attachments.MaxSize)
+	assert.Equal(t, "http://localhost:9000", cfg.Attachments.S3Endpoint)
+
+	t.Setenv("S3_SECRET_ACCESS_KEY", "")
+	_, err = Load()
+	assert.ErrorContains(t, err, "S3_SECRET_ACCESS_KEY")
+
+	t.Setenv("S3_ENDPOINT", "localhost:9000")
+	_, err = Load()
+	assert.ErrorContains(t, err, "S3_ENDPOINT")
+
+	t.Setenv("ATTACHMENTS_STORE", "ftp")
+	_, err = Load()
+	assert.ErrorContains(t, err, "ATTACHMENTS_STORE")
+	t.Setenv("ATTACHMENTS_STORE", "local")
+
+	t.Setenv("ATTACHMENTS_MAX_SIZE", "0")
+	_, err = Load()
+	assert.ErrorContains(t, err, "ATTACHMENTS_MAX_SIZE")
+}
+
+func TestLoadWorkspaces(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, WorkspacesConfig{InviteURL: "http://localhost:9092/join-workspace", InviteTTL: 7 * 24 * time.Hour}, cfg.Workspaces)
+
+	t.Setenv("WORKSPACE_INVITE_URL", "https://app.example.com/join")
+	t.Setenv("WORKSPACE_INVITE_TTL", "48h")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/join", cfg.Workspaces.InviteURL)
+	assert.Equal(t, 48*time.Hour, cfg.Workspaces.InviteTTL)
+
+	t.Setenv("WORKSPACE_INVITE_TTL", "-1h")
+	_, err = Load()
+	assert.ErrorContains(t, err, "WORKSPACE_INVITE_TTL")
+}
+
+func TestLoadEvents(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, EventsConfig{Broker: EventsMemory, ReplaySize: 1000}, cfg.Events)
+
+	t.Setenv("EVENTS_BROKER", "postgres")
+	t.Setenv("EVENTS_REPLAY_SIZE", "50")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, EventsConfig{Broker: EventsPostgres, ReplaySize: 50}, cfg.Events)
+
+	t.Setenv("EVENTS_REPLAY_SIZE", "0")
+	_, err = Load()
+	assert.ErrorContains(t, err, "EVENTS_REPLAY_SIZE")
+	t.Setenv("EVENTS_REPLAY_SIZE", "50")
+
+	t.Setenv("EVENTS_BROKER", "kafka")
+	_, err = Load()
+	assert.ErrorContains(t, err, "EVENTS_BROKER")
+}
+
+func TestLoadWebhooks(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, WebhooksConfig{MaxAttempts: 10, Timeout: 10 * time.Second, PollInterval: 5 * time.Second}, cfg.Webhooks)
+
+	key := strings.Repeat("k", 32)
+	t.Setenv("WEBHOOK_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte(key)))
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	t.Setenv("WEBHOOK_TIMEOUT", "2s")
+	t.Setenv("WEBHOOK_POLL_INTERVAL", "1s")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, WebhooksConfig{EncryptionKey: []byte(key), MaxAttempts: 3, Timeout: 2 * time.Second, PollInterval: time.Second}, cfg.Webhooks)
+
+	t.Setenv("WEBHOOK_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+	_, err = Load()
+	assert.ErrorContains(t, err, "WEBHOOK_ENCRYPTION_KEY")
+	t.Setenv("WEBHOOK_ENCRYPTION_KEY", "")
+
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+	_, err = Load()
+	assert.ErrorContains(t, err, "WEBHOOK_MAX_ATTEMPTS")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+
+	t.Setenv("WEBHOOK_TIMEOUT", "soon")
+	_, err = Load()
+	assert.ErrorContains(t, err, "WEBHOOK_TIMEOUT")
+}
@@ -0,0 +1,68 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// шифрование секретов в бд (AES-256-GCM)
+//   • результат: "v1:" + base64(nonce || ciphertext+tag) - префикс версии оставляет место для смены ключа
+//   • aad (дополнительные данные) привязывает шифротекст к записи: например, секрет одного пользователя
+//     нельзя переложить в строку другого - расшифровка не пройдет
+
+// длина ключа AES-256
+const KeySize = 32
+
+const versionPrefix = "v1:"
+
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt - шифрует plaintext (nonce случайный для каждого вызова)
+func (c *Cipher) Encrypt(plaintext, aad []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, aad)
+	return versionPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt - расшифровывает и проверяет целостность (неверный ключ, aad или испорченные данные - ошибка)
+func (c *Cipher) Decrypt(ciphertext string, aad []byte) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, versionPrefix)
+	if !ok {
+		return nil, errors.New("unsupported ciphertext version")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, errors.New("malformed ciphertext")
+	}
+
+	nonce, data := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, data, aad)
+	if err != nil {
+		return nil, errors.New("cannot decrypt: wrong key or corrupted data")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,60 @@
+package encryption
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, KeySize)
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+
+	secret := []byte("totp-secret")
+	sealed, err := c.Encrypt(secret, []byte("user:1"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, "v1:"))
+	assert.NotContains(t, sealed, "totp-secret")
+
+	// одинаковый текст шифруется каждый раз по-разному (случайный nonce)
+	again, err := c.Encrypt(secret, []byte("user:1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	plain, err := c.Decrypt(sealed, []byte("user:1"))
+	require.NoError(t, err)
+	assert.Equal(t, secret, plain)
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		ciphertext string
+		aad        string
+	}{
+		{name: "чужая запись (другой aad)", cipher: c, ciphertext: sealed, aad: "user:2"},
+		{name: "другой ключ", cipher: mustCipher(t, bytes.Repeat([]byte{2}, KeySize)), ciphertext: sealed, aad: "user:1"},
+		{name: "испорченные данные", cipher: c, ciphertext: sealed[:len(sealed)-4] + "AAAA", aad: "user:1"},
+		{name: "неизвестная версия", cipher: c, ciphertext: "v2:" + strings.TrimPrefix(sealed, "v1:"), aad: "user:1"},
+		{name: "не base64", cipher: c, ciphertext: "v1:%%%", aad: "user:1"},
+		{name: "слишком короткий", cipher: c, ciphertext: "v1:AAAA", aad: "user:1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cipher.Decrypt(tt.ciphertext, []byte(tt.aad))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = NewCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func mustCipher(t *testing.T, key []byte) *Cipher {
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+	return c
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// шина событий об изменении задач внутри процесса
+//   • TaskService публикует событие после того, как изменение записано в бд
+//   • подписчики (SSE-потоки) получают события через канал; кто не успевает читать -
+//     отключается, чтобы не тормозить остальных (клиент переподключится и догонит по Last-Event-ID)
+//   • последние события лежат в кольцевом буфере: по id последнего полученного события
+//     переподключившийся клиент получает все, что пропустил, если это еще есть в буфере
+//   • несколько экземпляров приложения синхронизирует PGNotifier (Postgres LISTEN/NOTIFY)
+
+// типы событий
+const (
+	TaskCreated = "task.created"
+	TaskUpdated = "task.updated"
+	TaskDeleted = "task.deleted"
+	// задача стала выполненной - только для вебхуков (в потоки идет обычный task.updated)
+	TaskCompleted = "task.completed"
+	// события могли потеряться (буфер не помнит Last-Event-ID, обрыв LISTEN) - клиенту пора перечитать задачи
+	Reset = "reset"
+)
+
+// размеры по умолчанию
+const (
+	DefaultReplaySize = 1000
+	// сколько событий может ждать в канале подписчика, прежде чем его отключат
+	subscriberBuffer = 64
+)
+
+// Event - событие об изменении задачи
+type Event struct {
+	ID     string    `json:"id"` // если не задан - назначает шина (или PGNotifier - одинаковый на всех экземплярах)
+	Type   string    `json:"type"`
+	TaskID uint      `json:"task_id"`
+	At     time.Time `json:"at"`
+
+	// кому задача видна (состояние после изменения, для удаления - до него); клиенту не отдается
+	OwnerID     uint   `json:"owner_id"`
+	AssigneeIDs []uint `json:"assignee_ids,omitempty"`
+	WorkspaceID *uint  `json:"workspace_id,omitempty"`
+
+	// тело события для клиента (задача в виде апи-модели)
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Publisher - куда TaskService отправляет события (реализуют Bus и PGNotifier)
+type Publisher interface {
+	Publish(ev Event)
+}
+
+type Bus struct {
+	mu     sync.Mutex
+	replay []Event // кольцевой буфер
+	next   int     // куда запишется следующее событие
+	full   bool
+	subs   map[*Subscription]struct{}
+}
+
+func NewBus(replaySize int) *Bus {
+	if replaySize < 1 {
+		replaySize = DefaultReplaySize
+	}
+	return &Bus{replay: make([]Event, replaySize), subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription - подписка на события; C закрывается, когда подписку закрыли или отключили за отставание
+type Subscription struct {
+	C <-chan Event
+
+	c   chan Event
+	bus *Bus
+}
+
+// Close - отписывает (повторный вызов ничего не делает)
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+// Publish - сохраняет событие в буфер и рассылает подписчикам
+func (b *Bus) Publish(ev Event) {
+	if ev.ID == "" {
+		ev.ID = uuid.NewString()
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.replay[b.next] = ev
+	b.next = (b.next + 1) % len(b.replay)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.c <- ev:
+		default:
+			// подписчик не успевает - отключаем, пропущенное он догонит из буфера
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe - подписка на новые события
+// lastEventID - id последнего события, которое клиент уже получил ("" - только новые события);
+// replay - события после него из буфера; complete = false - такого id в буфере нет,
+// и что клиент пропустил, уже не восстановить
+func (b *Bus) Subscribe(lastEventID string) (sub *Subscription, replay []Event, complete bool) {
+	c := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: c, c: c, bus: b}
+
+	// буфер копируем под той же блокировкой, под которой подписываемся: между ними ничего не потеряется
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	buffered := b.buffered()
+	for i := range buffered {
+		if buffered[i].ID == lastEventID {
+			return sub, buffered[i+1:], true
+		}
+	}
+	return sub, nil, false
+}
+
+// buffered - копия буфера, от старых событий к новым (вызывается под блокировкой)
+func (b *Bus) buffered() []Event {
+	if !b.full {
+		return append([]Event(nil), b.replay[:b.next]...)
+	}
+	events := make([]Event, 0, len(b.replay))
+	events = append(events, b.replay[b.next:]...)
+	return append(events, b.replay[:b.next]...)
+}
+
+// remove - убирает подписчика и закрывает его канал (вызывается под блокировкой)
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.c)
+}
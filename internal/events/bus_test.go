@@ -0,0 +1,121 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ids - id событий по порядку
+func ids(events []Event) []string {
+	result := make([]string, 0, len(events))
+	for _, ev := range events {
+		result = append(result, ev.ID)
+	}
+	return result
+}
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus(10)
+	sub, replay, complete := bus.Subscribe("")
+	defer sub.Close()
+	assert.Empty(t, replay)
+	assert.True(t, complete)
+
+	bus.Publish(Event{Type: TaskCreated, TaskID: 1})
+	bus.Publish(Event{ID: "from-notify", Type: TaskUpdated, TaskID: 1})
+
+	first := <-sub.C
+	assert.Equal(t, TaskCreated, first.Type)
+	assert.NotEmpty(t, first.ID)
+	assert.False(t, first.At.IsZero())
+	// id, назначенный до шины (PGNotifier), сохраняется
+	assert.Equal(t, "from-notify", (<-sub.C).ID)
+}
+
+func TestBusReplay(t *testing.T) {
+	bus := NewBus(3)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		bus.Publish(Event{ID: id, Type: TaskUpdated})
+	}
+
+	tests := []struct {
+		name         string
+		lastEventID  string
+		wantReplay   []string
+		wantComplete bool
+	}{
+		{name: "без Last-Event-ID - только новые", lastEventID: "", wantReplay: []string{}, wantComplete: true},
+		{name: "догоняем пропущенное", lastEventID: "2", wantReplay: []string{"3", "4"}, wantComplete: true},
+		{name: "уже все получил", lastEventID: "4", wantReplay: []string{}, wantComplete: true},
+		{name: "вытеснено из буфера", lastEventID: "1", wantReplay: []string{}, wantComplete: false},
+		{name: "неизвестный id", lastEventID: "nope", wantReplay: []string{}, wantComplete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, replay, complete := bus.Subscribe(tt.lastEventID)
+			defer sub.Close()
+			assert.Equal(t, tt.wantReplay, ids(replay))
+			assert.Equal(t, tt.wantComplete, complete)
+		})
+	}
+}
+
+func TestBusSlowSubscriber(t *testing.T) {
+	bus := NewBus(10)
+	slow, _, _ := bus.Subscribe("")
+	fast, _, _ := bus.Subscribe("")
+	defer fast.Close()
+
+	received := 0
+	for i := 0; i < subscriberBuffer+1; i++ {
+		bus.Publish(Event{Type: TaskUpdated})
+		<-fast.C
+		received++
+	}
+	assert.Equal(t, subscriberBuffer+1, received)
+
+	// медленный подписчик получил то, что влезло в его канал, и был отключен
+	count := 0
+	for range slow.C {
+		count++
+	}
+	assert.Equal(t, subscriberBuffer, count)
+
+	// повторное закрытие не паникует
+	slow.Close()
+	slow.Close()
+}
+
+func TestPayload(t *testing.T) {
+	ws := uint(3)
+	ev := Event{ID: "e1", Type: TaskUpdated, TaskID: 7, OwnerID: 2, AssigneeIDs: []uint{4}, WorkspaceID: &ws,
+		Data: json.RawMessage(`{"id":7}`)}
+
+	payload, err := encodePayload(ev)
+	require.NoError(t, err)
+	decoded, err := decodePayload(payload)
+	require.NoError(t, err)
+	assert.Equal(t, ev.TaskID, decoded.TaskID)
+	assert.Equal(t, ev.AssigneeIDs, decoded.AssigneeIDs)
+	assert.Equal(t, ws, *decoded.WorkspaceID)
+	assert.JSONEq(t, `{"id":7}`, string(decoded.Data))
+
+	// большое тело не влезает в NOTIFY - событие уходит без него
+	ev.Data = json.RawMessage(`{"task":"` + strings.Repeat("a", maxPayload) + `"}`)
+	payload, err = encodePayload(ev)
+	require.NoError(t, err)
+	decoded, err = decodePayload(payload)
+	require.NoError(t, err)
+	assert.Empty(t, decoded.Data)
+	assert.Equal(t, uint(7), decoded.TaskID)
+
+	_, err = decodePayload(`{"type":"task.updated"}`)
+	assert.Error(t, err)
+	_, err = decodePayload(`not json`)
+	assert.Error(t, err)
+}
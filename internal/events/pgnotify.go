@@ -0,0 +1,165 @@
+package events
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// синхронизация нескольких экземпляров приложения через Postgres LISTEN/NOTIFY
+//   • Publish не кладет событие в свою шину напрямую, а отправляет NOTIFY; событие возвращается через LISTEN
+//     всем экземплярам, включая отправителя, - порядок событий в буферах везде одинаковый,
+//     и Last-Event-ID работает, на какой бы экземпляр клиент ни переподключился
+//   • NOTIFY не больше ~8000 байт: событие, которое не влезает, уходит без тела (клиент перечитает задачу)
+//   • пока соединение LISTEN оборвано, события проходят мимо - после переподключения в шину уходит Reset
+
+// Channel - канал NOTIFY
+const Channel = "task_events"
+
+// предел полезной нагрузки NOTIFY (в Postgres - 8000 байт, оставляем запас)
+const maxPayload = 7900
+
+// пауза перед переподключением LISTEN: растет вдвое до maxListenDelay
+const (
+	minListenDelay = time.Second
+	maxListenDelay = time.Minute
+)
+
+type PGNotifier struct {
+	db  *gorm.DB
+	bus *Bus
+}
+
+func NewPGNotifier(db *gorm.DB, bus *Bus) *PGNotifier {
+	return &PGNotifier{db: db, bus: bus}
+}
+
+// Publish - отправляет событие всем экземплярам
+func (n *PGNotifier) Publish(ev Event) {
+	if ev.ID == "" {
+		ev.ID = uuid.NewString()
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+
+	payload, err := encodePayload(ev)
+	if err == nil {
+		err = n.db.Exec("SELECT pg_notify(?, ?)", Channel, payload).Error
+	}
+	if err != nil {
+		// до других экземпляров не дошло - пусть узнают хотя бы клиенты этого
+		log.Printf("Failed to notify task event %s: %v", ev.ID, err)
+		n.bus.Publish(ev)
+	}
+}
+
+// Listen - принимает события из NOTIFY и кладет их в шину, пока не отменен ctx (запускать в горутине)
+func (n *PGNotifier) Listen(ctx context.Context) {
+	delay := minListenDelay
+	reconnect := false
+	for {
+		listening, err := n.listen(ctx, reconnect)
+		if ctx.Err() != nil {
+			return
+		}
+		if listening {
+			delay = minListenDelay
+			reconnect = true
+		}
+		log.Printf("Task events listener failed: %v (retrying in %s)", err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxListenDelay)
+	}
+}
+
+// listen - одно соединение LISTEN; listening - успели ли начать слушать
+func (n *PGNotifier) listen(ctx context.Context, reconnect bool) (listening bool, err error) {
+	sqlDB, err := n.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	rawErr := conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			err = errors.New("LISTEN needs a pgx connection")
+			return nil
+		}
+		pgConn := stdConn.Conn()
+
+		if _, err = pgConn.Exec(ctx, "LISTEN "+Channel); err != nil {
+			return driver.ErrBadConn
+		}
+		listening = true
+		if reconnect {
+			n.bus.Publish(Event{Type: Reset})
+		}
+
+		for {
+			notification, waitErr := pgConn.WaitForNotification(ctx)
+			if waitErr != nil {
+				err = waitErr
+				// соединение с подпиской в пул не возвращаем
+				return driver.ErrBadConn
+			}
+			ev, decodeErr := decodePayload(notification.Payload)
+			if decodeErr != nil {
+				log.Printf("Skipping malformed task event: %v", decodeErr)
+				continue
+			}
+			n.bus.Publish(ev)
+		}
+	})
+	if err == nil && !errors.Is(rawErr, driver.ErrBadConn) {
+		err = rawErr
+	}
+	return listening, err
+}
+
+// encodePayload - событие для NOTIFY (без тела, если целиком не влезает)
+func encodePayload(ev Event) (string, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) > maxPayload {
+		ev.Data = nil
+		if payload, err = json.Marshal(ev); err != nil {
+			return "", err
+		}
+		if len(payload) > maxPayload {
+			return "", errors.New("task event is too large for NOTIFY")
+		}
+	}
+	return string(payload), nil
+}
+
+// decodePayload - событие из NOTIFY
+func decodePayload(payload string) (Event, error) {
+	var ev Event
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		return Event{}, err
+	}
+	if ev.ID == "" || ev.Type == "" {
+		return Event{}, errors.New("task event without id or type")
+	}
+	return ev, nil
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyRepo - поддельный репозиторий (для тестирования middleware)
+type MockIdempotencyRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepo) Get(key, scope string) (IdempotencyKeyStruct, error) {
+	args := m.Called(key, scope)
+	var record IdempotencyKeyStruct
+	if res := args.Get(0); res != nil {
+		record = res.(IdempotencyKeyStruct)
+	}
+	return record, args.Error(1)
+}
+
+func (m *MockIdempotencyRepo) Reserve(record *IdempotencyKeyStruct) (bool, error) {
+	args := m.Called(record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepo) Complete(record *IdempotencyKeyStruct) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepo) Delete(key, scope string) error {
+	args := m.Called(key, scope)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
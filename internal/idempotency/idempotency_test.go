@@ -0,0 +1,323 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMiddleware(t *testing.T) {
+	const body = `{"task":"buy milk","user_id":1}`
+	const scope = "POST /tasks"
+
+	tests := []struct {
+		name        string
+		method      string
+		key         string
+		body        string
+		handlerCode int
+		mockSetup   func(m *MockIdempotencyRepo)
+		wantCode    int
+		wantBody    string
+		wantCalled  bool // был ли вызван настоящий хендлер
+		wantReplay  bool
+	}{
+		{
+			name:        "без ключа - обычный запрос",
+			method:      http.MethodPost,
+			body:        body,
+			handlerCode: http.StatusCreated,
+			mockSetup:   func(m *MockIdempotencyRepo) {},
+			wantCode:    http.StatusCreated,
+			wantBody:    `{"id":1}`,
+			wantCalled:  true,
+		},
+		{
+			name:        "GET с ключом - ключ игнорируется",
+			method:      http.MethodGet,
+			key:         "abc",
+			handlerCode: http.StatusOK,
+			mockSetup:   func(m *MockIdempotencyRepo) {},
+			wantCode:    http.StatusOK,
+			wantBody:    `{"id":1}`,
+			wantCalled:  true,
+		},
+		{
+			name:        "первый запрос - ответ сохраняется",
+			method:      http.MethodPost,
+			key:         "abc",
+			body:        body,
+			handlerCode: http.StatusCreated,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.MatchedBy(func(r *IdempotencyKeyStruct) bool {
+					return r.IdempotencyKey == "abc" && r.Scope == scope && r.RequestHash == hashBody([]byte(body))
+				})).Return(true, nil)
+				m.On("Complete", mock.MatchedBy(func(r *IdempotencyKeyStruct) bool {
+					return r.StatusCode == http.StatusCreated &&
+						string(r.ResponseBody) == `{"id":1}` &&
+						strings.Contains(r.ResponseHeaders, `"ETag":"\"1\""`)
+				})).Return(nil)
+			},
+			wantCode:   http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalled: true,
+		},
+		{
+			name:   "повтор с тем же телом - сохраненный ответ",
+			method: http.MethodPost,
+			key:    "abc",
+			body:   body,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(false, nil)
+				m.On("Get", "abc", scope).Return(IdempotencyKeyStruct{
+					IdempotencyKey:  "abc",
+					Scope:           scope,
+					RequestHash:     hashBody([]byte(body)),
+					StatusCode:      http.StatusCreated,
+					ResponseHeaders: `{"Content-Type":"application/json"}`,
+					ResponseBody:    []byte(`{"id":1}`),
+				}, nil)
+			},
+			wantCode:   http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalled: false,
+			wantReplay: true,
+		},
+		{
+			name:   "тот же ключ с другим телом - 422",
+			method: http.MethodPost,
+			key:    "abc",
+			body:   `{"task":"other","user_id":1}`,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(false, nil)
+				m.On("Get", "abc", scope).Return(IdempotencyKeyStruct{
+					RequestHash: hashBody([]byte(body)),
+					StatusCode:  http.StatusCreated,
+				}, nil)
+			},
+			wantCode:   http.StatusUnprocessableEntity,
+			wantCalled: false,
+		},
+		{
+			name:   "первый запрос еще выполняется - 409",
+			method: http.MethodPost,
+			key:    "abc",
+			body:   body,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(false, nil)
+				m.On("Get", "abc", scope).Return(IdempotencyKeyStruct{
+					RequestHash: hashBody([]byte(body)),
+					StatusCode:  0,
+				}, nil)
+			},
+			wantCode:   http.StatusConflict,
+			wantCalled: false,
+		},
+		{
+			name:        "ошибка сервера - ключ освобождается",
+			method:      http.MethodPost,
+			key:         "abc",
+			body:        body,
+			handlerCode: http.StatusInternalServerError,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(true, nil)
+				m.On("Delete", "abc", scope).Return(nil)
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantBody:   `{"id":1}`,
+			wantCalled: true,
+		},
+		{
+			name:   "ошибка бд при резервировании ключа",
+			method: http.MethodPost,
+			key:    "abc",
+			body:   body,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(false, errors.New("db error"))
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantCalled: false,
+		},
+		{
+			name:        "ответ не сохранился - ключ освобождается",
+			method:      http.MethodPost,
+			key:         "abc",
+			body:        body,
+			handlerCode: http.StatusCreated,
+			mockSetup: func(m *MockIdempotencyRepo) {
+				m.On("Reserve", mock.Anything).Return(true, nil)
+				m.On("Complete", mock.Anything).Return(errors.New("db error"))
+				m.On("Delete", "abc", scope).Return(nil)
+			},
+			wantCode:   http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalled: true,
+		},
+		{
+			name:       "слишком большое тело - 413",
+			method:     http.MethodPost,
+			key:        "abc",
+			body:       strings.Repeat("x", maxBodySize+1),
+			mockSetup:  func(m *MockIdempotencyRepo) {},
+			wantCode:   http.StatusRequestEntityTooLarge,
+			wantCalled: false,
+		},
+		{
+			name:       "слишком длинный ключ - 400",
+			method:     http.MethodPost,
+			key:        strings.Repeat("k", maxKeyLength+1),
+			body:       body,
+			mockSetup:  func(m *MockIdempotencyRepo) {},
+			wantCode:   http.StatusBadRequest,
+			wantCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockIdempotencyRepo)
+			tt.mockSetup(mockRepo)
+
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", `"1"`)
+				w.WriteHeader(tt.handlerCode)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			})
+
+			m := NewMiddleware(mockRepo, time.Hour)
+			req := httptest.NewRequest(tt.method, "/tasks", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(HeaderKey, tt.key)
+			}
+			rec := httptest.NewRecorder()
+
+			m.Handler(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantCalled, called)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+			if tt.wantReplay {
+				assert.Equal(t, "true", rec.Header().Get(HeaderReplayed))
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMiddlewareKeepsBodyForHandler(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	mockRepo.On("Reserve", mock.Anything).Return(true, nil)
+	mockRepo.On("Complete", mock.Anything).Return(nil)
+
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"email":"a@b.c"}`))
+	req.Header.Set(HeaderKey, "key-1")
+	NewMiddleware(mockRepo, 0).Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	// хендлер должен прочитать тело целиком, несмотря на то что middleware уже посчитала его хеш
+	assert.Equal(t, `{"email":"a@b.c"}`, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMiddlewareScopesKeyByUser(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	mockRepo.On("Reserve", mock.MatchedBy(func(r *IdempotencyKeyStruct) bool {
+		return r.Scope == "user:7 POST /tasks"
+	})).Return(true, nil)
+	mockRepo.On("Complete", mock.Anything).Return(nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	user := func(r *http.Request) (uint, bool) { return 7, true }
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{}`))
+	req.Header.Set(HeaderKey, "key-1")
+	NewMiddleware(mockRepo, 0).WithUser(user).Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	// один и тот же ключ у разных пользователей - разные записи
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMiddlewareWithoutStorage(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo) // ни Reserve, ни Complete не ожидаются
+
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"secret":"whsec_x"}`))
+	})
+	m := NewMiddleware(mockRepo, 0).WithoutStorage("POST /webhooks")
+
+	// r.Pattern заполняет ServeMux - как в main.go
+	mux := http.NewServeMux()
+	mux.Handle("POST /webhooks", m.Handler(next))
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{}`))
+		req.Header.Set(HeaderKey, "key-1")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Empty(t, rr.Header().Get(HeaderReplayed))
+	}
+
+	// секрет не попал в бд, каждый запрос выполнен хендлером
+	assert.Equal(t, 2, calls)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	mockRepo.On("Reserve", mock.Anything).Return(true, nil)
+	mockRepo.On("Delete", "key-1", "POST /tasks").Return(nil).Once()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{}`))
+	req.Header.Set(HeaderKey, "key-1")
+	// паника идет дальше (ее ловит net/http), но ключ уже свободен - повтор не получит 409
+	assert.PanicsWithValue(t, "boom", func() {
+		NewMiddleware(mockRepo, 0).Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+	})
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunPurge(t *testing.T) {
+	now := time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockRepo := new(MockIdempotencyRepo)
+	mockRepo.On("DeleteExpired", now).Run(func(mock.Arguments) { cancel() }).Return(int64(3), nil).Once()
+
+	m := NewMiddleware(mockRepo, 0)
+	m.now = func() time.Time { return now }
+	m.RunPurge(ctx, time.Hour) // первая чистка - сразу, потом выход по отмене ctx
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,262 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// middleware для заголовка Idempotency-Key
+//   • оборачивает сгенерированные strict-хендлеры (подключается через StdHTTPServerOptions.Middlewares)
+//   • первый запрос с ключом выполняется как обычно, его ответ сохраняется в бд
+//   • повтор с тем же ключом и тем же телом получает сохраненный ответ (хендлер не вызывается)
+//   • тот же ключ с другим телом - 422
+//   • тот же ключ, пока первый запрос еще выполняется - 409
+//   • ответы 5xx не сохраняются: ключ освобождается и запрос можно повторить (так же - если хендлер упал с паникой)
+//   • тело запроса с ключом читается в память, поэтому оно не больше maxBodySize (иначе 413)
+//   • истекшие ключи удаляет RunPurge (запускать в горутине)
+//   • с WithUser ключи разных пользователей не пересекаются (по чужому ключу не получить чужой ответ)
+//   • маршруты из WithoutStorage отвечают секретом, который нельзя хранить в бд открытым текстом -
+//     для них ключ игнорируется и ничего не сохраняется
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	DefaultTTL           = 24 * time.Hour
+	DefaultPurgeInterval = time.Hour
+	maxKeyLength         = 255
+	maxBodySize          = 1 << 20 // 1 MiB
+)
+
+// заголовки ответа, которые сохраняем вместе с телом
+var storedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// UserFunc - достает ID аутентифицированного пользователя из запроса
+type UserFunc func(r *http.Request) (uint, bool)
+
+type Middleware struct {
+	repo     IdempotencyRepoInterface
+	ttl      time.Duration
+	now      func() time.Time
+	userFunc UserFunc
+	noStore  map[string]bool // шаблоны маршрутов ServeMux (как в r.Pattern)
+}
+
+// конструктор NewMiddleware - ttl задает, сколько хранится ответ (0 - DefaultTTL)
+func NewMiddleware(r IdempotencyRepoInterface, ttl time.Duration) *Middleware {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Middleware{repo: r, ttl: ttl, now: time.Now}
+}
+
+// WithUser - добавляет пользователя в область действия ключа
+func (m *Middleware) WithUser(fn UserFunc) *Middleware {
+	m.userFunc = fn
+	return m
+}
+
+// WithoutStorage - маршруты, ответы которых не сохраняются (например, "POST /webhooks" - в ответе секрет подписи)
+func (m *Middleware) WithoutStorage(routes ...string) *Middleware {
+	if m.noStore == nil {
+		m.noStore = make(map[string]bool)
+	}
+	for _, route := range routes {
+		m.noStore[route] = true
+	}
+	return m
+}
+
+// Handler - сама middleware (подходит под тип MiddlewareFunc из api.gen.go)
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		// ключ имеет смысл только для неидемпотентных POST-запросов
+		// r.Pattern заполняет ServeMux - это шаблон маршрута, например "POST /webhooks"
+		if r.Method != http.MethodPost || key == "" || m.noStore[r.Pattern] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "can't read request body", http.StatusBadRequest)
+			return
+		}
+		// возвращаем тело обратно, чтобы его смог прочитать strict-хендлер
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := r.Method + " " + r.URL.Path
+		if m.userFunc != nil {
+			if userID, ok := m.userFunc(r); ok {
+				scope = "user:" + strconv.FormatUint(uint64(userID), 10) + " " + scope
+			}
+		}
+		hash := hashBody(body)
+
+		record := &IdempotencyKeyStruct{
+			IdempotencyKey:  key,
+			Scope:           scope,
+			RequestHash:     hash,
+			ResponseHeaders: "{}",
+			ExpiresAt:       m.now().Add(m.ttl),
+		}
+
+		reserved, err := m.repo.Reserve(record)
+		if err != nil {
+			log.Printf("[IDEMPOTENCY] reserve key failed: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		if !reserved {
+			// ключ уже использовался - отдаем сохраненный ответ
+			m.replay(w, key, scope, hash)
+			return
+		}
+
+		// ответ не сохранен (5xx, паника в хендлере, ошибка бд) - освобождаем ключ,
+		// иначе повторы до конца ttl получали бы 409 "still in progress"
+		stored := false
+		defer func() {
+			if !stored {
+				if err := m.repo.Delete(key, scope); err != nil {
+					log.Printf("[IDEMPOTENCY] release key failed: %v", err)
+				}
+			}
+		}()
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			// ошибку сервера не запоминаем, иначе клиент навсегда получит 500 на этот ключ
+			return
+		}
+
+		record.StatusCode = rec.status
+		record.ResponseHeaders = encodeHeaders(rec.Header())
+		record.ResponseBody = rec.body.Bytes()
+		if err := m.repo.Complete(record); err != nil {
+			log.Printf("[IDEMPOTENCY] store response failed: %v", err)
+			return
+		}
+		stored = true
+	})
+}
+
+// RunPurge - раз в interval удаляет истекшие ключи, пока не отменен ctx (запускать в горутине)
+func (m *Middleware) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if deleted, err := m.repo.DeleteExpired(m.now()); err != nil {
+			log.Printf("[IDEMPOTENCY] purge expired keys failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("[IDEMPOTENCY] purged %d expired keys", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replay - отвечает на повторный запрос сохраненным ответом
+func (m *Middleware) replay(w http.ResponseWriter, key, scope, hash string) {
+	stored, err := m.repo.Get(key, scope)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// запись успела истечь между Reserve и Get - просим клиента повторить
+			http.Error(w, "idempotency key expired, retry the request", http.StatusConflict)
+			return
+		}
+		log.Printf("[IDEMPOTENCY] load key failed: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if stored.RequestHash != hash {
+		http.Error(w, "Idempotency-Key was already used with a different request body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		http.Error(w, "a request with this Idempotency-Key is still in progress", http.StatusConflict)
+		return
+	}
+
+	for name, value := range decodeHeaders(stored.ResponseHeaders) {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.ResponseBody)
+}
+
+// recorder - ResponseWriter, который параллельно с отправкой клиенту запоминает ответ
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeHeaders(h http.Header) string {
+	headers := make(map[string]string)
+	for _, name := range storedHeaders {
+		if v := h.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+	encoded, _ := json.Marshal(headers)
+	return string(encoded)
+}
+
+func decodeHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	_ = json.Unmarshal([]byte(s), &headers)
+	return headers
+}
@@ -0,0 +1,20 @@
+package idempotency
+
+import "time"
+
+// модель базы данных
+type IdempotencyKeyStruct struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	IdempotencyKey  string `gorm:"not null"`
+	Scope           string `gorm:"not null"` // метод + путь запроса
+	RequestHash     string `gorm:"not null"` // sha256 тела запроса (hex)
+	StatusCode      int    // 0 - запрос еще выполняется
+	ResponseHeaders string // сохраненные заголовки ответа (JSON)
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+func (IdempotencyKeyStruct) TableName() string {
+	return "idempotency_keys" // как в миграции
+}
@@ -0,0 +1,87 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repo-слой для ключей идемпотентности (как TaskRepo / UserRepo - только запросы к бд)
+
+type IdempotencyRepoInterface interface {
+	Get(key, scope string) (IdempotencyKeyStruct, error)
+	Reserve(record *IdempotencyKeyStruct) (bool, error)
+	Complete(record *IdempotencyKeyStruct) error
+	Delete(key, scope string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type IdempotencyRepo struct{}
+
+// Get - возвращает еще не истекшую запись по ключу (gorm.ErrRecordNotFound, если ее нет)
+func (r *IdempotencyRepo) Get(key, scope string) (IdempotencyKeyStruct, error) {
+	var record IdempotencyKeyStruct
+	err := db.DB.
+		Where("idempotency_key = ? AND scope = ? AND expires_at > ?", key, scope, time.Now()).
+		First(&record).Error
+	if err != nil {
+		return IdempotencyKeyStruct{}, err
+	}
+	return record, nil
+}
+
+// Reserve - занимает ключ под выполняющийся запрос
+// возвращает false, если ключ уже занят (другим запросом с тем же ключом)
+func (r *IdempotencyRepo) Reserve(record *IdempotencyKeyStruct) (bool, error) {
+	// истекшая запись с тем же ключом больше не действует - освобождаем место
+	err := db.DB.
+		Where("idempotency_key = ? AND scope = ? AND expires_at <= ?", record.IdempotencyKey, record.Scope, time.Now()).
+		Delete(&IdempotencyKeyStruct{}).Error
+	if err != nil {
+		return false, err
+	}
+
+	res := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Complete - сохраняет ответ для занятого ключа
+func (r *IdempotencyRepo) Complete(record *IdempotencyKeyStruct) error {
+	res := db.DB.Model(&IdempotencyKeyStruct{}).
+		Where("idempotency_key = ? AND scope = ?", record.IdempotencyKey, record.Scope).
+		Updates(map[string]interface{}{
+			"status_code":      record.StatusCode,
+			"response_headers": record.ResponseHeaders,
+			"response_body":    record.ResponseBody,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("idempotency key not found")
+	}
+	return nil
+}
+
+// Delete - освобождает ключ (например, если запрос упал и его можно повторить)
+func (r *IdempotencyRepo) Delete(key, scope string) error {
+	err := db.DB.
+		Where("idempotency_key = ? AND scope = ?", key, scope).
+		Delete(&IdempotencyKeyStruct{}).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired - удаляет истекшие ключи (чистка по расписанию, см. Middleware.RunPurge)
+func (r *IdempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	res := db.DB.Where("expires_at <= ?", now).Delete(&IdempotencyKeyStruct{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer - никуда не отправляет, а печатает письмо в лог приложения
+// (удобно локально: ссылку для сброса пароля видно прямо в консоли)
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	log.Printf("[MAIL] from=%s to=%s subject=%q\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer - дописывает письма в файл (формат как у настоящего письма, между письмами - пустая строка)
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+func NewFileMailer(path, from string) *FileMailer {
+	return &FileMailer{path: path, from: from, now: time.Now}
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(build(m.from, msg, m.now())); err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, "\r\n\r\n")
+	return err
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// отправка писем (сброс пароля, подтверждение email и т.д.)
+// сервисы зависят только от интерфейса Mailer, а реализация выбирается в main по конфигу:
+//   • SMTPMailer - настоящая отправка через SMTP-сервер
+//   • LogMailer / FileMailer - письмо пишется в лог или в файл (локальная разработка и тесты)
+
+// Message - одно текстовое письмо
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// validate - адрес и тема попадают в заголовки письма, поэтому переводы строк в них запрещены
+// (иначе через них можно подставить свои заголовки)
+func (m Message) validate() error {
+	if strings.TrimSpace(m.To) == "" {
+		return errors.New("mail recipient is empty")
+	}
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.New("mail headers must not contain line breaks")
+	}
+	return nil
+}
+
+// build - собирает письмо в формате RFC 5322 (заголовки + пустая строка + тело, строки через CRLF)
+func build(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	// не-ASCII тема кодируется по RFC 2047
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
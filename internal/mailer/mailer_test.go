@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDate = time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+
+func TestBuild(t *testing.T) {
+	raw := string(build("noreply@example.com", Message{
+		To:      "user@example.com",
+		Subject: "Сброс пароля",
+		Body:    "строка 1\nстрока 2",
+	}, testDate))
+
+	headers, body, ok := strings.Cut(raw, "\r\n\r\n")
+	assert.True(t, ok)
+	assert.Contains(t, headers, "From: noreply@example.com\r\n")
+	assert.Contains(t, headers, "To: user@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?utf-8?q?")
+	assert.Contains(t, headers, "Date: Sun, 14 Dec 2025 10:00:00 +0000")
+	assert.Equal(t, "строка 1\r\nстрока 2", body)
+}
+
+func TestMessageValidate(t *testing.T) {
+	assert.NoError(t, Message{To: "a@example.com", Subject: "hi"}.validate())
+	assert.Error(t, Message{Subject: "hi"}.validate())
+	// попытка подставить свой заголовок
+	assert.Error(t, Message{To: "a@example.com\r\nBcc: evil@example.com"}.validate())
+	assert.Error(t, Message{To: "a@example.com", Subject: "hi\nBcc: evil@example.com"}.validate())
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", 587, "user", "secret", "noreply@example.com")
+	m.now = func() time.Time { return testDate }
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotAuth smtp.Auth
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotFrom, gotTo = addr, a, from, to
+		return nil
+	}
+
+	err := m.Send(Message{To: "user@example.com", Subject: "hi", Body: "text"})
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.NotNil(t, gotAuth)
+	assert.Equal(t, "noreply@example.com", gotFrom)
+	assert.Equal(t, []string{"user@example.com"}, gotTo)
+
+	// без логина - без авторизации
+	assert.Nil(t, NewSMTPMailer("localhost", 25, "", "", "noreply@example.com").auth)
+}
+
+func TestFileMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := NewFileMailer(path, "noreply@example.com")
+
+	assert.NoError(t, m.Send(Message{To: "a@example.com", Subject: "first", Body: "1"}))
+	assert.NoError(t, m.Send(Message{To: "b@example.com", Subject: "second", Body: "2"}))
+	assert.Error(t, m.Send(Message{To: ""}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "From: noreply@example.com"))
+	assert.Contains(t, string(data), "To: b@example.com")
+}
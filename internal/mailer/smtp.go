@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPMailer - отправка через SMTP-сервер
+// smtp.SendMail сам включает STARTTLS, если сервер его поддерживает
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth // nil - сервер без авторизации
+
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error // подменяется в тестах
+	now  func() time.Time
+}
+
+// NewSMTPMailer - username пустой, если сервер не требует авторизации
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+		send: smtp.SendMail,
+		now:  time.Now,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	return m.send(m.addr, m.auth, m.from, []string{msg.To}, build(m.from, msg, m.now()))
+}
@@ -0,0 +1,148 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// клиент внешнего провайдера OpenID Connect (Google, Keycloak, GitLab...)
+// поток authorization code + PKCE:
+//   1) AuthCodeURL - адрес, на который отправляем браузер (с state, nonce и code_challenge)
+//   2) провайдер возвращает браузер на RedirectURL с ?code=...&state=...
+//   3) Exchange - меняем code на токены (с code_verifier) и проверяем id_token
+// адреса эндпоинтов берутся из discovery (/.well-known/openid-configuration) при первом обращении,
+// поэтому приложение запускается, даже если провайдер временно недоступен
+
+// scopes по умолчанию (openid обязателен, email нужен, чтобы связать вход с пользователем)
+var DefaultScopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+
+type Config struct {
+	Issuer       string // адрес провайдера (как в поле iss токенов)
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // наш callback (GET /auth/oidc/callback снаружи)
+	Scopes       []string
+}
+
+// Claims - то, что нам нужно из проверенного id_token
+type Claims struct {
+	Issuer        string
+	Subject       string // постоянный id пользователя у провайдера (email может меняться)
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func NewClient(cfg Config) *Client {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultScopes
+	}
+	return &Client{cfg: cfg, httpClient: http.DefaultClient}
+}
+
+// WithHTTPClient - свой http-клиент для запросов к провайдеру (таймауты, прокси)
+func (c *Client) WithHTTPClient(hc *http.Client) *Client {
+	c.httpClient = hc
+	return c
+}
+
+// Issuer - адрес провайдера (вместе с subject однозначно определяет внешний аккаунт)
+func (c *Client) Issuer() string {
+	return c.cfg.Issuer
+}
+
+// discover - читает настройки провайдера один раз; неудачная попытка не запоминается
+func (c *Client) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.oauth != nil {
+		return c.oauth, c.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, c.httpClient), c.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	c.oauth = &oauth2.Config{
+		ClientID:     c.cfg.ClientID,
+		ClientSecret: c.cfg.ClientSecret,
+		RedirectURL:  c.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       c.cfg.Scopes,
+	}
+	// ключи провайдера (JWKS) подтягиваются и обновляются самим verifier'ом
+	c.verifier = provider.Verifier(&gooidc.Config{ClientID: c.cfg.ClientID})
+	return c.oauth, c.verifier, nil
+}
+
+// AuthCodeURL - адрес страницы входа у провайдера
+// verifier - секрет PKCE, провайдеру уходит только его sha256 (code_challenge)
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	conf, _, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return conf.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange - меняет code на токены и проверяет id_token: подпись, iss, aud, срок и nonce
+func (c *Client) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	conf, idVerifier, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = gooidc.ClientContext(ctx, c.httpClient)
+	token, err := conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc code exchange: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("oidc: no id_token in token response")
+	}
+
+	idToken, err := idVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id_token: %w", err)
+	}
+	// nonce защищает от подстановки чужого id_token (replay)
+	if idToken.Nonce != nonce {
+		return nil, errors.New("oidc: id_token nonce mismatch")
+	}
+
+	var extra struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&extra); err != nil {
+		return nil, fmt.Errorf("oidc: id_token claims: %w", err)
+	}
+
+	return &Claims{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         extra.Email,
+		EmailVerified: extra.EmailVerified,
+		Name:          extra.Name,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRedirectURL = "http://localhost:9092/auth/oidc/callback"
+
+func newTestClient(p *oidctest.Provider, secret string) *Client {
+	return NewClient(Config{
+		Issuer:       p.Issuer(),
+		ClientID:     p.ClientID,
+		ClientSecret: secret,
+		RedirectURL:  testRedirectURL,
+	})
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	p := oidctest.NewProvider("webpet", "secret")
+	defer p.Close()
+
+	authURL, err := newTestClient(p, "secret").AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, p.Issuer()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "webpet", q.Get("client_id"))
+	assert.Equal(t, testRedirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, "nonce-1", q.Get("nonce"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	// провайдеру уходит хэш, а не сам verifier
+	assert.NotEmpty(t, q.Get("code_challenge"))
+	assert.NotContains(t, authURL, "verifier-verifier")
+}
+
+func TestExchange(t *testing.T) {
+	const verifier = "verifier-verifier-verifier-verifier-verifier"
+
+	tests := []struct {
+		name          string
+		secret        string // секрет клиента
+		verifier      string // verifier при обмене code
+		nonce         string // ожидаемый nonce при обмене
+		wantErr       bool
+		wantErrSubstr string
+	}{
+		{
+			name:     "успешный вход",
+			secret:   "secret",
+			verifier: verifier,
+			nonce:    "nonce-1",
+		},
+		{
+			name:          "неверный code_verifier (PKCE)",
+			secret:        "secret",
+			verifier:      "another-verifier-another-verifier-another",
+			nonce:         "nonce-1",
+			wantErr:       true,
+			wantErrSubstr: "invalid_grant",
+		},
+		{
+			name:          "неверный секрет клиента",
+			secret:        "wrong",
+			verifier:      verifier,
+			nonce:         "nonce-1",
+			wantErr:       true,
+			wantErrSubstr: "invalid_client",
+		},
+		{
+			name:          "nonce не совпадает",
+			secret:        "secret",
+			verifier:      verifier,
+			nonce:         "nonce-2",
+			wantErr:       true,
+			wantErrSubstr: "nonce mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := oidctest.NewProvider("webpet", "secret")
+			defer p.Close()
+			p.SetUser(oidctest.User{Subject: "sub-42", Email: "anton@example.com", EmailVerified: true, Name: "Anton"})
+
+			client := newTestClient(p, tt.secret)
+			authURL, err := client.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+			require.NoError(t, err)
+
+			code, state, err := p.Authorize(authURL)
+			require.NoError(t, err)
+			assert.Equal(t, "state-1", state)
+
+			claims, err := client.Exchange(context.Background(), code, tt.verifier, tt.nonce)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrSubstr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, &Claims{
+				Issuer:        p.Issuer(),
+				Subject:       "sub-42",
+				Email:         "anton@example.com",
+				EmailVerified: true,
+				Name:          "Anton",
+			}, claims)
+
+			// code одноразовый
+			_, err = client.Exchange(context.Background(), code, tt.verifier, tt.nonce)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDiscoveryRetriesAfterFailure(t *testing.T) {
+	p := oidctest.NewProvider("webpet", "secret")
+	issuer := p.Issuer()
+	p.Close()
+
+	// провайдер недоступен - ошибка, но она не запоминается
+	client := NewClient(Config{Issuer: issuer, ClientID: "webpet", RedirectURL: testRedirectURL})
+	_, err := client.AuthCodeURL(context.Background(), "s", "n", "v")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oidc discovery")
+	assert.Nil(t, client.oauth)
+}
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/config"
+)
+
+// middleware ограничения частоты запросов (token bucket)
+//   • у каждого маршрута свой лимит из конфига (или лимит по умолчанию)
+//   • лимит считается отдельно для IP клиента и для аутентифицированного пользователя
+//   • на каждый ответ ставятся заголовки RateLimit-Limit / RateLimit-Remaining / RateLimit-Reset
+//   • при превышении - 429 и Retry-After
+//   • если хранилище недоступно, запрос пропускается (лучше пропустить, чем положить API)
+//   • AuthFailures стоит снаружи проверки токена: ответы 401 (битый/чужой токен, неверный пароль) считаются по IP,
+//     и после cfg.AuthFailures неудач IP получает 429 еще до проверки - так токены не перебрать
+
+// UserFunc - достает ID аутентифицированного пользователя из запроса
+type UserFunc func(r *http.Request) (uint, bool)
+
+type Middleware struct {
+	store    Store
+	cfg      config.RateLimitConfig
+	userFunc UserFunc
+}
+
+func NewMiddleware(s Store, cfg config.RateLimitConfig) *Middleware {
+	return &Middleware{store: s, cfg: cfg}
+}
+
+// WithUser - включает отдельный лимит на пользователя
+func (m *Middleware) WithUser(fn UserFunc) *Middleware {
+	m.userFunc = fn
+	return m
+}
+
+// Handler - сама middleware (подходит под тип MiddlewareFunc из api.gen.go)
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// IP клиента нужен и дальше (журнал входов) - кладем его в контекст даже при выключенном лимите
+		ip := m.clientIP(r)
+		r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, ip))
+
+		if !m.cfg.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// r.Pattern заполняет ServeMux - это шаблон маршрута, например "PATCH /tasks/{id}"
+		route := r.Pattern
+		limit, ok := m.cfg.Routes[route]
+		if !ok {
+			limit = m.cfg.Default
+		}
+
+		keys := []string{"ip:" + ip + ":" + route}
+		if m.userFunc != nil {
+			if userID, ok := m.userFunc(r); ok {
+				keys = append(keys, "user:"+strconv.FormatUint(uint64(userID), 10)+":"+route)
+			}
+		}
+
+		// из всех ведер берем самое "строгое" - его и показываем клиенту
+		var result *Result
+		for _, key := range keys {
+			res, err := m.store.Take(key, limit)
+			if err != nil {
+				log.Printf("[RATELIMIT] store error: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if result == nil || !res.Allowed || (result.Allowed && res.Remaining < result.Remaining) {
+				result = &res
+			}
+			if !res.Allowed {
+				break
+			}
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset.Seconds())))
+		h.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(ceilSeconds(limit.Per.Seconds())))
+
+		if !result.Allowed {
+			h.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter.Seconds())))
+			log.Printf("[RATELIMIT] %s %s: too many requests", r.Method, route)
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthFailures - лимит неудачных аутентификаций с одного IP (ставить снаружи authn-middleware)
+// обычные запросы токены не тратят: ведро расходуют только ответы 401
+func (m *Middleware) AuthFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.cfg.Enabled || m.cfg.AuthFailures.Requests <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := "authfail:" + m.clientIP(r)
+		res, err := m.store.Peek(key, m.cfg.AuthFailures)
+		if err != nil {
+			log.Printf("[RATELIMIT] store error: %v", err)
+		} else if !res.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter.Seconds())))
+			log.Printf("[RATELIMIT] %s %s: too many failed authentications", r.Method, r.URL.Path)
+			http.Error(w, "too many failed authentication attempts", http.StatusTooManyRequests)
+			return
+		}
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		if sw.status == http.StatusUnauthorized {
+			if _, err := m.store.Take(key, m.cfg.AuthFailures); err != nil {
+				log.Printf("[RATELIMIT] store error: %v", err)
+			}
+		}
+	})
+}
+
+// statusWriter - запоминает код ответа; Hijack и Unwrap нужны потокам (WebSocket, SSE) за этой middleware
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type clientIPKey struct{}
+
+// ClientIP - IP клиента, который определила middleware (пусто, если запрос прошел мимо нее)
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// clientIP - IP клиента (X-Forwarded-For учитываем только если доверяем прокси)
+// берем последний адрес цепочки - его дописал наш прокси; все, что левее, мог прислать сам клиент
+func (m *Middleware) clientIP(r *http.Request) string {
+	if m.cfg.TrustProxy {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			fwd := values[len(values)-1]
+			if i := strings.LastIndex(fwd, ","); i >= 0 {
+				fwd = fwd[i+1:]
+			}
+			if ip := strings.TrimSpace(fwd); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds - округляет вверх до целых секунд (не меньше 1 для ненулевых значений)
+func ceilSeconds(s float64) int {
+	return int(math.Ceil(s))
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"github.com/AntonRadchenko/WebPet1/internal/config"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStore - поддельное хранилище (для проверки поведения middleware при ошибках хранилища)
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Take(key string, limit config.Limit) (Result, error) {
+	args := m.Called(key, limit)
+	var res Result
+	if r := args.Get(0); r != nil {
+		res = r.(Result)
+	}
+	return res, args.Error(1)
+}
+
+func (m *MockStore) Peek(key string, limit config.Limit) (Result, error) {
+	args := m.Called(key, limit)
+	var res Result
+	if r := args.Get(0); r != nil {
+		res = r.(Result)
+	}
+	return res, args.Error(1)
+}
@@ -0,0 +1,305 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeClock - управляемое время для MemoryStore
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	s, clock := newTestStore()
+	limit := config.Limit{Requests: 3, Per: time.Minute} // 1 токен каждые 20 секунд
+
+	// полное ведро - три запроса подряд проходят
+	for i := 2; i >= 0; i-- {
+		res, err := s.Take("k", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+		assert.Equal(t, 3, res.Limit)
+	}
+
+	// четвертый - нет, следующий токен через 20 секунд
+	res, _ := s.Take("k", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 20*time.Second, res.RetryAfter)
+	assert.Equal(t, time.Minute, res.Reset)
+
+	// через 20 секунд токен появился
+	clock.advance(20 * time.Second)
+	res, _ = s.Take("k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// у другого ключа свое ведро
+	res, _ = s.Take("other", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+
+	// за долгое время ведро наполняется, но не больше своего размера
+	clock.advance(time.Hour)
+	res, _ = s.Take("k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, clock := newTestStore()
+	limit := config.Limit{Requests: 1, Per: time.Second}
+
+	_, _ = s.Take("old", limit)
+	clock.advance(2 * time.Minute)
+	_, _ = s.Take("new", limit)
+
+	// давно наполнившееся ведро удалено, новое осталось
+	assert.Len(t, s.buckets, 1)
+	assert.Contains(t, s.buckets, "new")
+}
+
+func TestMemoryStoreSweepKeepsLongWindows(t *testing.T) {
+	s, clock := newTestStore()
+	login := config.Limit{Requests: 10, Per: time.Hour}
+	cheap := config.Limit{Requests: 100, Per: time.Second}
+
+	// ведро входа почти опустошено
+	for range 10 {
+		_, _ = s.Take("login", login)
+	}
+
+	// через пару минут запрос к "дешевому" маршруту запускает чистку
+	clock.advance(2 * time.Minute)
+	_, _ = s.Take("cheap", cheap)
+
+	// ведро входа не наполнилось - его нельзя удалять, иначе лимит сбросился бы
+	assert.Contains(t, s.buckets, "login")
+	res, _ := s.Take("login", login)
+	assert.False(t, res.Allowed)
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled: true,
+		Default: config.Limit{Requests: 100, Per: time.Minute},
+		Routes: map[string]config.Limit{
+			"POST /users": {Requests: 2, Per: time.Minute},
+		},
+	}
+
+	newServer := func(m *Middleware) *http.ServeMux {
+		mux := http.NewServeMux()
+		ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+		mux.Handle("POST /users", m.Handler(ok))
+		mux.Handle("GET /users", m.Handler(ok))
+		return mux
+	}
+
+	do := func(mux *http.ServeMux, method, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users", nil)
+		req.RemoteAddr = ip + ":12345"
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("лимит маршрута из конфига и 429 с Retry-After", func(t *testing.T) {
+		store, _ := newTestStore()
+		mux := newServer(NewMiddleware(store, cfg))
+
+		rec := do(mux, http.MethodPost, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+
+		assert.Equal(t, http.StatusOK, do(mux, http.MethodPost, "10.0.0.1").Code)
+
+		rec = do(mux, http.MethodPost, "10.0.0.1")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+		// другой IP и другой маршрут не затронуты
+		assert.Equal(t, http.StatusOK, do(mux, http.MethodPost, "10.0.0.2").Code)
+		rec = do(mux, http.MethodGet, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("лимит на пользователя не зависит от IP", func(t *testing.T) {
+		store, _ := newTestStore()
+		m := NewMiddleware(store, cfg).WithUser(func(r *http.Request) (uint, bool) { return 7, true })
+		mux := newServer(m)
+
+		assert.Equal(t, http.StatusOK, do(mux, http.MethodPost, "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, do(mux, http.MethodPost, "10.0.0.2").Code)
+		// тот же пользователь с третьего IP - лимит пользователя исчерпан
+		assert.Equal(t, http.StatusTooManyRequests, do(mux, http.MethodPost, "10.0.0.3").Code)
+	})
+
+	t.Run("X-Forwarded-For учитывается только при TrustProxy", func(t *testing.T) {
+		store, _ := newTestStore()
+		m := NewMiddleware(store, cfg)
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.RemoteAddr = "192.168.0.1:1000"
+		req.Header.Set("X-Forwarded-For", "203.0.113.5")
+		assert.Equal(t, "192.168.0.1", m.clientIP(req))
+
+		m.cfg.TrustProxy = true
+		assert.Equal(t, "203.0.113.5", m.clientIP(req))
+	})
+
+	t.Run("подделанный X-Forwarded-For не меняет IP", func(t *testing.T) {
+		store, _ := newTestStore()
+		m := NewMiddleware(store, config.RateLimitConfig{TrustProxy: true})
+
+		// клиент прислал свой заголовок, прокси дописал к нему настоящий адрес
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.RemoteAddr = "192.168.0.1:1000"
+		req.Header.Set("X-Forwarded-For", "1.2.3.4, 5.6.7.8, 203.0.113.5")
+		assert.Equal(t, "203.0.113.5", m.clientIP(req))
+
+		// прокси добавил свой заголовок отдельной строкой
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		req.Header.Add("X-Forwarded-For", "203.0.113.5")
+		assert.Equal(t, "203.0.113.5", m.clientIP(req))
+
+		// пустой хвост - берем адрес соединения
+		req.Header.Set("X-Forwarded-For", "1.2.3.4, ")
+		assert.Equal(t, "192.168.0.1", m.clientIP(req))
+	})
+
+	t.Run("выключенный лимитер пропускает все", func(t *testing.T) {
+		store := new(MockStore)
+		mux := newServer(NewMiddleware(store, config.RateLimitConfig{Enabled: false}))
+
+		rec := do(mux, http.MethodPost, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+		store.AssertNotCalled(t, "Take", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка хранилища - запрос пропускается", func(t *testing.T) {
+		store := new(MockStore)
+		store.On("Take", mock.Anything, mock.Anything).Return(nil, errors.New("redis is down"))
+		mux := newServer(NewMiddleware(store, cfg))
+
+		assert.Equal(t, http.StatusOK, do(mux, http.MethodPost, "10.0.0.1").Code)
+		store.AssertExpectations(t)
+	})
+}
+
+func TestMiddlewareAuthFailures(t *testing.T) {
+	cfg := config.RateLimitConfig{Enabled: true, AuthFailures: config.Limit{Requests: 2, Per: time.Minute}}
+
+	// имитация authn: без верного токена - 401, хендлер дальше не вызывается
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	do := func(h http.Handler, token, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	store, clock := newTestStore()
+	h := NewMiddleware(store, cfg).AuthFailures(handler)
+
+	// удачные запросы лимит не тратят
+	for range 5 {
+		assert.Equal(t, http.StatusOK, do(h, "good", "10.0.0.1").Code)
+	}
+
+	// две неудачи - дальше 429 еще до проверки токена
+	assert.Equal(t, http.StatusUnauthorized, do(h, "guess-1", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(h, "guess-2", "10.0.0.1").Code)
+	calls = 0
+	rec := do(h, "guess-3", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, 0, calls)
+
+	// другой IP не затронут; через полминуты у первого появилась одна попытка
+	assert.Equal(t, http.StatusUnauthorized, do(h, "guess-1", "10.0.0.2").Code)
+	clock.advance(30 * time.Second)
+	assert.Equal(t, http.StatusUnauthorized, do(h, "guess-4", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do(h, "guess-5", "10.0.0.1").Code)
+}
+
+func TestStatusWriterHijack(t *testing.T) {
+	// WebSocket за middleware должен получить соединение (gorilla/websocket требует http.Hijacker)
+	hijacked := make(chan bool, 1)
+	h := NewMiddleware(NewMemoryStore(), config.RateLimitConfig{Enabled: true, AuthFailures: config.Limit{Requests: 1, Per: time.Minute}}).
+		AuthFailures(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				hijacked <- false
+				return
+			}
+			conn, _, err := hj.Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			hijacked <- err == nil
+		}))
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	assert.True(t, <-hijacked)
+}
+
+func TestMiddlewareClientIP(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIP(r.Context())
+	})
+
+	// IP попадает в контекст и при выключенном лимите
+	m := NewMiddleware(NewMemoryStore(), config.RateLimitConfig{Enabled: false})
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	m.Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "203.0.113.7", got)
+
+	// X-Forwarded-For - только если доверяем прокси
+	m = NewMiddleware(NewMemoryStore(), config.RateLimitConfig{TrustProxy: true})
+	m.Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "198.51.100.1", got)
+
+	assert.Empty(t, ClientIP(req.Context()))
+}
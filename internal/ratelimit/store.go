@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/config"
+)
+
+// хранилище "ведер" token bucket
+// интерфейс нужен, чтобы при нескольких инстансах приложения можно было подключить общее хранилище
+// (например Redis), а для одного инстанса и тестов хватает MemoryStore
+
+type Store interface {
+	// Take - пытается взять один токен из ведра key с лимитом limit
+	Take(key string, limit config.Limit) (Result, error)
+	// Peek - состояние ведра без расхода токена (Allowed - есть ли в нем хотя бы один токен)
+	Peek(key string, limit config.Limit) (Result, error)
+}
+
+// результат попытки взять токен
+type Result struct {
+	Allowed    bool
+	Limit      int           // размер ведра
+	Remaining  int           // сколько токенов осталось после запроса
+	RetryAfter time.Duration // через сколько появится следующий токен (если Allowed == false)
+	Reset      time.Duration // через сколько ведро наполнится полностью
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // когда ведро наполнится полностью (у каждого маршрута свое окно)
+}
+
+// MemoryStore - хранилище в памяти процесса
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// через сколько проверять и удалять давно не используемые ведра
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(key string, limit config.Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Per.Seconds() // токенов в секунду
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+
+	// доливаем токены за прошедшее время (но не больше размера ведра)
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.last = now
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((capacity - b.tokens) / rate)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (s *MemoryStore) Peek(key string, limit config.Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Per.Seconds()
+
+	tokens := capacity // ведра нет - оно полное
+	if b, ok := s.buckets[key]; ok {
+		tokens = min(capacity, b.tokens+s.now().Sub(b.last).Seconds()*rate)
+	}
+
+	res := Result{Limit: limit.Requests, Allowed: tokens >= 1, Remaining: int(tokens)}
+	if !res.Allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	res.Reset = seconds((capacity - tokens) / rate)
+	return res, nil
+}
+
+// sweep - удаляет ведра, которые уже успели наполниться (они ничем не отличаются от новых)
+// время наполнения у каждого ведра свое: лимит текущего запроса тут ни при чем, иначе запрос к маршруту
+// с коротким окном обнулял бы полупустые ведра маршрутов с длинным (например, входа)
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(f float64) time.Duration {
+	return time.Duration(f * float64(time.Second))
+}
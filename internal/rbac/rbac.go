@@ -0,0 +1,43 @@
+package rbac
+
+import "fmt"
+
+// роли и проверка прав
+// роль хранится у пользователя в бд; сервисы получают Actor (кто выполняет действие)
+// и сами решают, можно ли ему трогать чужие данные
+
+type Role string
+
+const (
+	RoleUser  Role = "user"  // обычный пользователь - только свои данные
+	RoleAdmin Role = "admin" // администратор - любые данные и управление пользователями
+)
+
+// ParseRole - проверяет, что роль существует
+func ParseRole(s string) (Role, error) {
+	switch Role(s) {
+	case RoleUser, RoleAdmin:
+		return Role(s), nil
+	default:
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+}
+
+// Actor - кто выполняет действие (пустой Actor - аноним, ему ничего нельзя)
+type Actor struct {
+	UserID    uint
+	Role      Role
+	RequestID string // запрос, в рамках которого выполняется действие (для журнала аудита; в правах не участвует)
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.UserID != 0 && a.Role == RoleAdmin
+}
+
+// CanAccessUser - можно ли работать с данными пользователя userID (свои данные или админ)
+func (a Actor) CanAccessUser(userID uint) bool {
+	if a.UserID == 0 {
+		return false
+	}
+	return a.IsAdmin() || a.UserID == userID
+}
@@ -0,0 +1,60 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	role, err := ParseRole("admin")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, role)
+
+	_, err = ParseRole("root")
+	assert.Error(t, err)
+	_, err = ParseRole("")
+	assert.Error(t, err)
+}
+
+func TestActor(t *testing.T) {
+	user := Actor{UserID: 1, Role: RoleUser}
+	admin := Actor{UserID: 2, Role: RoleAdmin}
+	anonymous := Actor{}
+
+	assert.True(t, user.CanAccessUser(1))
+	assert.False(t, user.CanAccessUser(3))
+	assert.False(t, user.IsAdmin())
+
+	assert.True(t, admin.CanAccessUser(3))
+	assert.True(t, admin.IsAdmin())
+
+	// аноним без id ничего не может, даже с ролью
+	assert.False(t, anonymous.CanAccessUser(0))
+	assert.False(t, Actor{Role: RoleAdmin}.IsAdmin())
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{"tasks:write", "tasks:read", "tasks:write"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tasks:read", "tasks:write"}, scopes)
+
+	_, err = ParseScopes([]string{"tasks:read", "admin"})
+	assert.Error(t, err)
+}
+
+func TestWorkspaceRole(t *testing.T) {
+	role, err := ParseWorkspaceRole("guest")
+	assert.NoError(t, err)
+	assert.Equal(t, WorkspaceGuest, role)
+
+	_, err = ParseWorkspaceRole("viewer")
+	assert.Error(t, err)
+
+	assert.True(t, WorkspaceAdmin.CanManageMembers())
+	assert.False(t, WorkspaceMember.CanManageMembers())
+	assert.True(t, WorkspaceMember.CanWriteTasks())
+	assert.False(t, WorkspaceMember.CanManageTasks())
+	assert.False(t, WorkspaceGuest.CanWriteTasks())
+	assert.False(t, WorkspaceRole("").CanWriteTasks()) // не участник
+}
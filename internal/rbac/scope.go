@@ -0,0 +1,45 @@
+package rbac
+
+import (
+	"fmt"
+	"sort"
+)
+
+// скоупы персональных токенов: токен может только то, что разрешают его скоупы
+// (и не больше, чем может сам пользователь); для обычного входа скоупы не нужны
+
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+// Scopes - все существующие скоупы
+var Scopes = []string{ScopeTasksRead, ScopeTasksWrite, ScopeUsersRead, ScopeUsersWrite}
+
+// ParseScopes - проверяет скоупы, убирает повторы и сортирует
+func ParseScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !isScope(scope) {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func isScope(s string) bool {
+	for _, scope := range Scopes {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package rbac
+
+import "fmt"
+
+// роли участников рабочего пространства (не путать с глобальной ролью пользователя Role):
+//   • owner - все, включая удаление пространства и назначение других владельцев
+//   • admin - приглашает и убирает участников, управляет любыми задачами пространства
+//   • member - создает задачи в пространстве и управляет своими
+//   • guest - только смотрит
+
+type WorkspaceRole string
+
+const (
+	WorkspaceOwner  WorkspaceRole = "owner"
+	WorkspaceAdmin  WorkspaceRole = "admin"
+	WorkspaceMember WorkspaceRole = "member"
+	WorkspaceGuest  WorkspaceRole = "guest"
+)
+
+// ParseWorkspaceRole - проверяет, что роль в пространстве существует
+func ParseWorkspaceRole(s string) (WorkspaceRole, error) {
+	switch WorkspaceRole(s) {
+	case WorkspaceOwner, WorkspaceAdmin, WorkspaceMember, WorkspaceGuest:
+		return WorkspaceRole(s), nil
+	default:
+		return "", fmt.Errorf("unknown workspace role %q", s)
+	}
+}
+
+// CanManageMembers - может приглашать, убирать участников и менять их роли
+func (r WorkspaceRole) CanManageMembers() bool {
+	return r == WorkspaceOwner || r == WorkspaceAdmin
+}
+
+// CanManageTasks - может менять и удалять любые задачи пространства
+func (r WorkspaceRole) CanManageTasks() bool {
+	return r == WorkspaceOwner || r == WorkspaceAdmin
+}
+
+// CanWriteTasks - может создавать задачи в пространстве (все, кроме гостя)
+func (r WorkspaceRole) CanWriteTasks() bool {
+	return r == WorkspaceOwner || r == WorkspaceAdmin || r == WorkspaceMember
+}
@@ -0,0 +1,132 @@
+package taskService
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// события об изменении задач (для GET /events, GET /ws и вебхуков)
+//   • в потоки публикуются после того, как изменение записано в бд (созданная, измененная и удаленная задача,
+//     смена исполнителей - тоже task.updated)
+//   • в вебхуки - из той же транзакции, что меняет задачу (TaskRepo + Outbox): вебхук не должен терять события,
+//     а поток при обрыве все равно присылает Reset; поэтому id события у потока и вебхука разные
+//   • task.completed - только для вебхуков: задача только что стала выполненной
+//   • в событии - кому задача видна в его момент; кто получит событие, решает CanSeeEvent
+//     по тем же правам, что и GetTask (кто потерял доступ к задаче, дальше ее событий не видит)
+
+// eventTask - тело события: задача в том же виде, что в ответах апи (без comment_count)
+type eventTask struct {
+	ID          uint   `json:"id"`
+	Task        string `json:"task"`
+	IsDone      bool   `json:"is_done"`
+	UserId      uint   `json:"user_id"`
+	Version     uint   `json:"version"`
+	Position    int64  `json:"position"`
+	AssigneeIDs []uint `json:"assignee_ids,omitempty"`
+	WorkspaceID *uint  `json:"workspace_id,omitempty"`
+}
+
+// deletedTask - тело события об удалении
+type deletedTask struct {
+	ID uint `json:"id"`
+}
+
+// WithEvents - публиковать события об изменении задач
+func (s *TaskService) WithEvents(p events.Publisher) *TaskService {
+	s.events = p
+	return s
+}
+
+// Outbox - очередь вебхуков, которая пишется транзакцией tx, меняющей задачу (реализует webhookService)
+type Outbox interface {
+	Enqueue(tx *gorm.DB, ev events.Event) error
+}
+
+// publish - отправляет событие об изменении задачи в потоки (без них - ничего не делает)
+func (s *TaskService) publish(eventType string, t *TaskStruct) {
+	if s.events == nil {
+		return
+	}
+	if ev, ok := newEvent(eventType, t); ok {
+		s.events.Publish(ev)
+	}
+}
+
+// webhookEvents - какие события изменения задачи уходят в вебхуки (wasDone - была ли задача выполнена до него)
+func webhookEvents(eventType string, wasDone bool, t *TaskStruct) []string {
+	if eventType == events.TaskUpdated && !wasDone && t.IsDone {
+		return []string{events.TaskUpdated, events.TaskCompleted}
+	}
+	return []string{eventType}
+}
+
+// newEvent - событие о задаче; id назначаем сразу, чтобы событие можно было узнать при повторе
+func newEvent(eventType string, t *TaskStruct) (events.Event, bool) {
+	assignees := AssigneeIDs(t.Assignees)
+	var body any = eventTask{
+		ID:          t.ID,
+		Task:        t.Task,
+		IsDone:      t.IsDone,
+		UserId:      t.UserId,
+		Version:     t.Version,
+		Position:    t.Position,
+		AssigneeIDs: assignees,
+		WorkspaceID: t.WorkspaceID,
+	}
+	if eventType == events.TaskDeleted {
+		body = deletedTask{ID: t.ID}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("Failed to encode %s event for task %d: %v", eventType, t.ID, err)
+		return events.Event{}, false
+	}
+
+	return events.Event{
+		ID:          uuid.NewString(),
+		Type:        eventType,
+		TaskID:      t.ID,
+		At:          time.Now(),
+		OwnerID:     t.UserId,
+		AssigneeIDs: assignees,
+		WorkspaceID: t.WorkspaceID,
+		Data:        data,
+	}, true
+}
+
+// publishAssignees - task.updated после смены исполнителей (если список правда изменился)
+func (s *TaskService) publishAssignees(before TaskStruct) {
+	if s.events == nil {
+		return
+	}
+	after, err := s.repo.GetByID(before.ID)
+	if err != nil || after.ID == 0 {
+		return // задачу успели удалить - о ней будет свое событие
+	}
+	// порядок исполнителей из бд не гарантирован - сравниваем отсортированные списки
+	was, now := AssigneeIDs(before.Assignees), AssigneeIDs(after.Assignees)
+	slices.Sort(was)
+	slices.Sort(now)
+	if slices.Equal(was, now) {
+		return
+	}
+	s.publish(events.TaskUpdated, &after)
+}
+
+// CanSeeEvent - видит ли actor задачу из события (те же права, что у GetTask, но по состоянию из события:
+// удаленной задачи в бд уже нет)
+func (s *TaskService) CanSeeEvent(actor rbac.Actor, ev events.Event) bool {
+	task := TaskStruct{ID: ev.TaskID, UserId: ev.OwnerID, WorkspaceID: ev.WorkspaceID}
+	for _, id := range ev.AssigneeIDs {
+		task.Assignees = append(task.Assignees, TaskAssigneeStruct{TaskID: ev.TaskID, UserID: id})
+	}
+	level, err := s.access(actor, task)
+	return err == nil && level > accessNone
+}
@@ -4,19 +4,70 @@ import "time"
 
 // модель базы данных
 type TaskStruct struct {
-	ID        uint `gorm:"primaryKey;autoIncrement"`
-	UserId    uint `gorm:"not null;index"`
-	Task      string
-	IsDone    bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID          uint  `gorm:"primaryKey;autoIncrement"`
+	UserId      uint  `gorm:"not null;index"`
+	WorkspaceID *uint `gorm:"index"` // рабочее пространство (nil - личная задача)
+	Task        string
+	IsDone      bool
+	Version     uint  `gorm:"not null;default:1"` // версия строки (для If-Match / ETag)
+	Position    int64 `gorm:"not null;default:0"` // место на доске: клиенты упорядочивают задачи по (position, id)
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+
+	// исполнители (загружаются вместе с задачей; владелец - UserId - сюда не входит)
+	Assignees []TaskAssigneeStruct `gorm:"foreignKey:TaskID"`
 }
 
 func (TaskStruct) TableName() string {
     return "task_structs"  // как в миграции
 }
 
+// исполнитель задачи: может ее видеть и менять статус (is_done), но не текст, владельца и не удалять
+type TaskAssigneeStruct struct {
+	TaskID     uint  `gorm:"primaryKey"`
+	UserID     uint  `gorm:"primaryKey"`
+	AssignedBy *uint // кто назначил (nil - система)
+	CreatedAt  time.Time
+}
+
+func (TaskAssigneeStruct) TableName() string {
+	return "task_assignees" // как в миграции
+}
+
+// ревизия задачи - ее состояние после каждого изменения (Revision совпадает с версией задачи)
+// история линейная: откат к старой ревизии не удаляет новые, а создает еще одну
+type TaskRevisionStruct struct {
+	ID           uint `gorm:"primaryKey;autoIncrement"`
+	TaskID       uint `gorm:"not null"`
+	Revision     uint `gorm:"not null"`
+	UserId       uint `gorm:"not null"`
+	Task         string
+	IsDone       bool
+	ActorID      *uint // кто изменил (nil - система)
+	RestoredFrom *uint // ревизия, к которой откатили задачу (nil - обычное изменение)
+	CreatedAt    time.Time
+}
+
+func (TaskRevisionStruct) TableName() string {
+	return "task_revisions" // как в миграции
+}
+
+// имена колонок, которые можно частично обновлять (маска для TaskRepo.Update)
+const (
+	TaskFieldTask     = "task"
+	TaskFieldIsDone   = "is_done"
+	TaskFieldUserId   = "user_id"
+	TaskFieldPosition = "position"
+)
+
+// строка результата полнотекстового поиска (задача + ранг + подсветка совпадений)
+type TaskSearchRow struct {
+	TaskStruct
+	Rank      float64
+	Highlight string
+}
+
 // единый формат ошибок
 // type ErrorStruct struct {
 // 	Error string `json:"error"`
@@ -1,11 +1,19 @@
 package taskService
 
 import (
+	"errors"
+	"html"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
 	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // 2. repo-слой (руки)
@@ -19,29 +27,111 @@ import (
 // То есть TaskRepoInterface описывает контракт,
 // который должен быть реализован любым объектом, претендующим на роль репозитория
 type TaskRepoInterface interface {
-	Create(task *TaskStruct) (*TaskStruct, error) // исправлена пока только сигнатура этого метода
+	Create(task *TaskStruct, actor rbac.Actor) (*TaskStruct, error) // исправлена пока только сигнатура этого метода
 	GetAll() ([]TaskStruct, error)
+	GetByUser(userID uint) ([]TaskStruct, error)
 	GetByID(id uint) (TaskStruct, error)	
-	Update(task *TaskStruct) (*TaskStruct, error)
-	Delete(task *TaskStruct) error
+	Update(task *TaskStruct, fields []string, actor rbac.Actor) (*TaskStruct, error)
+	Delete(task *TaskStruct, actor rbac.Actor) error
+	Restore(task *TaskStruct, revision uint, actor rbac.Actor) (*TaskStruct, error)
+	GetRevisions(taskID uint) ([]TaskRevisionStruct, error)
+	GetRevision(taskID, revision uint) (TaskRevisionStruct, error)
+	Search(userID uint, query string, limit, offset int) ([]TaskSearchRow, int64, error)
+	GetByWorkspace(workspaceID uint) ([]TaskStruct, error)
+	Assign(task *TaskStruct, userID uint, actor rbac.Actor) error
+	Unassign(task *TaskStruct, userID uint, actor rbac.Actor) error
 }
 
-type TaskRepo struct{}
+type TaskRepo struct {
+	outbox Outbox // nil - вебхуков нет, события в очередь не ставятся
+}
+
+// WithOutbox - ставить события об изменении задач в очередь вебхуков той же транзакцией, что и изменение
+func (r *TaskRepo) WithOutbox(o Outbox) *TaskRepo {
+	r.outbox = o
+	return r
+}
+
+// enqueueWebhooks - события задачи в очередь вебхуков (ошибка откатывает все изменение)
+func (r *TaskRepo) enqueueWebhooks(tx *gorm.DB, t *TaskStruct, eventTypes ...string) error {
+	if r.outbox == nil {
+		return nil
+	}
+	for _, eventType := range eventTypes {
+		ev, ok := newEvent(eventType, t)
+		if !ok {
+			continue
+		}
+		if err := r.outbox.Enqueue(tx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 // Create - добавляет новую задачу в таблицу
-func (r *TaskRepo) Create(task *TaskStruct) (*TaskStruct, error) {
-	err := db.DB.Create(task).Error // передаем указатель в ORM
+// (изменения задач пишутся в журнал аудита той же транзакцией - actor и id запроса берутся из actor)
+func (r *TaskRepo) Create(task *TaskStruct, actor rbac.Actor) (*TaskStruct, error) {
+	if task.Version == 0 {
+		task.Version = 1 // новая строка всегда начинается с первой версии
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil { // передаем указатель в ORM
+			return err
+		}
+		if err := writeRevision(tx, task, actor, nil); err != nil {
+			return err
+		}
+		err := audit.Write(tx, audit.Record{
+			EntityType: audit.EntityTask,
+			EntityID:   task.ID,
+			Action:     audit.ActionCreated,
+			Actor:      actor,
+			After:      task.snapshot(),
+		})
+		if err != nil {
+			return err
+		}
+		return r.enqueueWebhooks(tx, task, events.TaskCreated)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return task, nil // передаем объект задачи обратно в сервис
 }
 
+// InMemberWorkspaces - условие "задача личная или пользователь (параметр) состоит в ее пространстве":
+// из пространства, откуда пользователь ушел, его задачи в списки больше не попадают
+// (экспортировано для userService, который выбирает задачи пользователя сам)
+const InMemberWorkspaces = "(workspace_id IS NULL OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?))"
+
+// ownedOrAssigned - условие "задача пользователя (параметр, дважды): своя или он в ней исполнитель"
+const ownedOrAssigned = "(user_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?))"
+
+// searchableBy - условие поиска "задачу видит пользователь (параметр, трижды)": личная - своя или он в ней исполнитель,
+// задача пространства - любая из пространства, где он участник (как в GET /workspaces/{id}/tasks)
+const searchableBy = "((" + ownedOrAssigned + " OR workspace_id IS NOT NULL) AND " + InMemberWorkspaces + ")"
+
+// GetByUser - возвращает задачи, с которыми работает пользователь: свои и те, где он исполнитель
+func (r *TaskRepo) GetByUser(userID uint) ([]TaskStruct, error) {
+	var tasks []TaskStruct
+
+	err := db.DB.Preload("Assignees").
+		Where(ownedOrAssigned, userID, userID).
+		Where(InMemberWorkspaces, userID).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
 // GetAll - возвращает все задачи из таблицы
 func (r *TaskRepo) GetAll() ([]TaskStruct, error) {
 	var tasks []TaskStruct
 
-	err := db.DB.Find(&tasks).Error
+	err := db.DB.Preload("Assignees").Find(&tasks).Error
 	if err != nil  {
 		if strings.Contains(err.Error(), "relation") {
 		// если таблицы нет, то вместо ошибки возвращаем пустой массив []
@@ -53,10 +143,20 @@ func (r *TaskRepo) GetAll() ([]TaskStruct, error) {
 	return tasks, nil
 }
 
+// GetByWorkspace - задачи рабочего пространства
+func (r *TaskRepo) GetByWorkspace(workspaceID uint) ([]TaskStruct, error) {
+	var tasks []TaskStruct
+	err := db.DB.Preload("Assignees").Where("workspace_id = ?", workspaceID).Order("id").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
 // GetByID - возвращает задачу по ID
 func (r *TaskRepo) GetByID(id uint) (TaskStruct, error) {
 	var task TaskStruct
-	err := db.DB.First(&task, "id = ?", id).Error
+	err := db.DB.Preload("Assignees").First(&task, "id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// если задачи нет,
@@ -67,23 +167,414 @@ func (r *TaskRepo) GetByID(id uint) (TaskStruct, error) {
 	return task, nil
 }
 
-// Update - обновляет задачу (текст задачи)
-func (r *TaskRepo) Update(task *TaskStruct) (*TaskStruct, error) {
-	task.UpdatedAt = time.Now()
-	err := db.DB.Save(&task).Error
+// Update - частично обновляет задачу
+// fields - маска колонок (TaskField*), в UPDATE попадают только они + updated_at и version,
+// остальные колонки (например created_at) не трогаются.
+// обновление условное: строка меняется, только если ее версия в бд все еще равна task.Version
+// (то есть с момента чтения ее никто не изменил), иначе - "version mismatch".
+// возвращается свежая строка из бд (UPDATE ... RETURNING *)
+func (r *TaskRepo) Update(task *TaskStruct, fields []string, actor rbac.Actor) (*TaskStruct, error) {
+	return r.update(task, fields, actor, nil)
+}
+
+// Restore - откат к ревизии revision: сервис уже переложил ее поля в task,
+// в бд это обычное обновление всех полей, а новая ревизия помнит, откуда ее восстановили
+func (r *TaskRepo) Restore(task *TaskStruct, revision uint, actor rbac.Actor) (*TaskStruct, error) {
+	fields := []string{TaskFieldTask, TaskFieldIsDone, TaskFieldUserId}
+	return r.update(task, fields, actor, &revision)
+}
+
+// update - общее условное обновление для Update и Restore
+func (r *TaskRepo) update(task *TaskStruct, fields []string, actor rbac.Actor, restoredFrom *uint) (*TaskStruct, error) {
+	updated := *task
+	updated.UpdatedAt = time.Now()
+	updated.Version = task.Version + 1
+
+	columns := append([]string{"updated_at", "version"}, fields...)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// сервис уже поменял поля в task, поэтому состояние "до" для журнала читаем из бд (с блокировкой строки)
+		var before TaskStruct
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, "id = ?", task.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("version mismatch") // задачу успели удалить
+			}
+			return err
+		}
+
+		res := tx.Model(&updated).
+			Clauses(clause.Returning{}).
+			Where("version = ?", task.Version).
+			Select(columns).
+			Updates(&updated)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("version mismatch")
+		}
+
+		// новый владелец больше не исполнитель своей же задачи
+		if slices.Contains(fields, TaskFieldUserId) {
+			err := tx.Where("task_id = ? AND user_id = ?", updated.ID, updated.UserId).Delete(&TaskAssigneeStruct{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		var assignees []TaskAssigneeStruct
+		if err := tx.Where("task_id = ?", updated.ID).Order("created_at, user_id").Find(&assignees).Error; err != nil {
+			return err
+		}
+		updated.Assignees = assignees
+
+		if err := writeRevision(tx, &updated, actor, restoredFrom); err != nil {
+			return err
+		}
+		err = audit.Write(tx, audit.Record{
+			EntityType: audit.EntityTask,
+			EntityID:   updated.ID,
+			Action:     audit.ActionUpdated,
+			Actor:      actor,
+			Before:     before.snapshot(),
+			After:      updated.snapshot(),
+		})
+		if err != nil {
+			return err
+		}
+		return r.enqueueWebhooks(tx, &updated, webhookEvents(events.TaskUpdated, before.IsDone, &updated)...)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return task, nil
+	return &updated, nil
 }
 
-// Delete - удаляет задачу по ID
-func (r *TaskRepo) Delete(task *TaskStruct) error {
-	now := time.Now()
-	task.DeletedAt = &now
-	err := db.DB.Delete(task).Error
-	if err != nil {
+// Delete - удаляет задачу по ID (тоже только при совпадении версии)
+// версия совпала - значит task и есть состояние "до" для журнала
+func (r *TaskRepo) Delete(task *TaskStruct, actor rbac.Actor) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		task.DeletedAt = &now
+		res := tx.Where("version = ?", task.Version).Delete(task)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("version mismatch")
+		}
+
+		err := audit.Write(tx, audit.Record{
+			EntityType: audit.EntityTask,
+			EntityID:   task.ID,
+			Action:     audit.ActionDeleted,
+			Actor:      actor,
+			Before:     task.snapshot(),
+		})
+		if err != nil {
+			return err
+		}
+		return r.enqueueWebhooks(tx, task, events.TaskDeleted)
+	})
+}
+
+// Assign - назначает пользователя исполнителем (повторное назначение ничего не меняет)
+// список исполнителей "до" и "после" пишется в журнал аудита той же транзакцией
+func (r *TaskRepo) Assign(task *TaskStruct, userID uint, actor rbac.Actor) error {
+	return r.changeAssignees(task, actor, func(tx *gorm.DB) (bool, error) {
+		assignee := TaskAssigneeStruct{TaskID: task.ID, UserID: userID}
+		if actor.UserID != 0 {
+			actorID := actor.UserID
+			assignee.AssignedBy = &actorID
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignee)
+		if res.Error != nil {
+			if isForeignKeyViolation(res.Error) {
+				return false, errors.New("user not found")
+			}
+			return false, res.Error
+		}
+		return res.RowsAffected > 0, nil
+	})
+}
+
+// Unassign - снимает исполнителя ("assignee not found", если он не был назначен)
+func (r *TaskRepo) Unassign(task *TaskStruct, userID uint, actor rbac.Actor) error {
+	return r.changeAssignees(task, actor, func(tx *gorm.DB) (bool, error) {
+		res := tx.Where("task_id = ? AND user_id = ?", task.ID, userID).Delete(&TaskAssigneeStruct{})
+		if res.Error != nil {
+			return false, res.Error
+		}
+		if res.RowsAffected == 0 {
+			return false, errors.New("assignee not found")
+		}
+		return true, nil
+	})
+}
+
+// changeAssignees - общая транзакция Assign и Unassign: строка задачи блокируется,
+// чтобы параллельные назначения не перепутали список "до" в журнале
+// change возвращает, изменилось ли что-нибудь (если нет - журнал не пишем)
+func (r *TaskRepo) changeAssignees(task *TaskStruct, actor rbac.Actor, change func(tx *gorm.DB) (bool, error)) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var locked TaskStruct
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", task.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("task not found") // задачу успели удалить
+			}
+			return err
+		}
+
+		before, err := assigneeIDsTx(tx, task.ID)
+		if err != nil {
+			return err
+		}
+		changed, err := change(tx)
+		if err != nil || !changed {
+			return err
+		}
+		after, err := assigneeIDsTx(tx, task.ID)
+		if err != nil {
+			return err
+		}
+
+		err = audit.Write(tx, audit.Record{
+			EntityType: audit.EntityTask,
+			EntityID:   task.ID,
+			Action:     audit.ActionUpdated,
+			Actor:      actor,
+			Before:     audit.Snapshot{"assignee_ids": before},
+			After:      audit.Snapshot{"assignee_ids": after},
+		})
+		if err != nil || r.outbox == nil {
+			return err
+		}
+		// для вебхука - задача с новым списком исполнителей
+		if err := tx.Where("task_id = ?", task.ID).Order("created_at, user_id").Find(&locked.Assignees).Error; err != nil {
+			return err
+		}
+		return r.enqueueWebhooks(tx, &locked, events.TaskUpdated)
+	})
+}
+
+// assigneeIDsTx - id исполнителей задачи по возрастанию
+func assigneeIDsTx(tx *gorm.DB, taskID uint) ([]uint, error) {
+	ids := make([]uint, 0)
+	err := tx.Model(&TaskAssigneeStruct{}).Where("task_id = ?", taskID).Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// loadAssignees - исполнители для найденных задач одним запросом (в Raw-запросах Preload не работает)
+func loadAssignees(rows []TaskSearchRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	var assignees []TaskAssigneeStruct
+	if err := db.DB.Where("task_id IN ?", ids).Order("created_at, user_id").Find(&assignees).Error; err != nil {
 		return err
 	}
+	byTask := make(map[uint][]TaskAssigneeStruct, len(rows))
+	for _, a := range assignees {
+		byTask[a.TaskID] = append(byTask[a.TaskID], a)
+	}
+	for i := range rows {
+		rows[i].Assignees = byTask[rows[i].ID]
+	}
 	return nil
 }
+
+// код ошибки Postgres (SQLSTATE) для нарушения внешнего ключа
+const pgForeignKeyViolation = "23503"
+
+// isForeignKeyViolation - бд отклонила запись, потому что связанной строки нет
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// writeRevision - сохраняет состояние задачи как ревизию с номером ее версии
+func writeRevision(tx *gorm.DB, task *TaskStruct, actor rbac.Actor, restoredFrom *uint) error {
+	rev := TaskRevisionStruct{
+		TaskID:       task.ID,
+		Revision:     task.Version,
+		UserId:       task.UserId,
+		Task:         task.Task,
+		IsDone:       task.IsDone,
+		RestoredFrom: restoredFrom,
+	}
+	if actor.UserID != 0 {
+		actorID := actor.UserID
+		rev.ActorID = &actorID
+	}
+	return tx.Create(&rev).Error
+}
+
+// GetRevisions - все ревизии задачи (сначала новые)
+func (r *TaskRepo) GetRevisions(taskID uint) ([]TaskRevisionStruct, error) {
+	var revisions []TaskRevisionStruct
+	err := db.DB.Where("task_id = ?", taskID).Order("revision DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision - одна ревизия задачи
+func (r *TaskRepo) GetRevision(taskID, revision uint) (TaskRevisionStruct, error) {
+	var rev TaskRevisionStruct
+	err := db.DB.Where("task_id = ? AND revision = ?", taskID, revision).First(&rev).Error
+	return rev, err
+}
+
+// snapshot - задача для журнала аудита (поля как в API)
+func (t *TaskStruct) snapshot() audit.Snapshot {
+	return audit.Snapshot{
+		"id":           t.ID,
+		"user_id":      t.UserId,
+		"workspace_id": t.WorkspaceID,
+		"task":         t.Task,
+		"is_done":      t.IsDone,
+		"version":      t.Version,
+		"position":     t.Position,
+	}
+}
+
+// код ошибки Postgres (SQLSTATE) для несуществующей колонки
+const pgUndefinedColumn = "42703"
+
+// isUndefinedColumn - проверяет, что бд не нашла колонку (например, миграция еще не применена)
+func isUndefinedColumn(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUndefinedColumn
+}
+
+// Search - полнотекстовый поиск по задачам, которые видит пользователь (searchableBy), с ранжированием и подсветкой
+// если колонки search_vector нет (например, в тестовой бд без миграций), то ищем обычным ILIKE;
+// любая другая ошибка - это ошибка, а не повод молча перейти на медленный поиск
+func (r *TaskRepo) Search(userID uint, query string, limit, offset int) ([]TaskSearchRow, int64, error) {
+	rows, total, err := r.searchFullText(userID, query, limit, offset)
+	if err != nil {
+		if !isUndefinedColumn(err) {
+			return nil, 0, err
+		}
+		if rows, total, err = r.searchILike(userID, query, limit, offset); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := loadAssignees(rows); err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// searchFullText - поиск через tsvector + GIN-индекс
+func (r *TaskRepo) searchFullText(userID uint, query string, limit, offset int) ([]TaskSearchRow, int64, error) {
+	var total int64
+	err := db.DB.Model(&TaskStruct{}).
+		Where(searchableBy, userID, userID, userID).
+		Where("search_vector @@ websearch_to_tsquery('simple', ?)", query).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]TaskSearchRow, 0)
+	err = db.DB.Raw(`
+		SELECT id, user_id, workspace_id, task, is_done, version, position, created_at, updated_at, deleted_at,
+			ts_rank(search_vector, q) AS rank,
+			ts_headline('simple', translate(task, ?, ''), q, 'StartSel=`+hlStart+`, StopSel=`+hlStop+`') AS highlight
+		FROM task_structs, websearch_to_tsquery('simple', ?) AS q
+		WHERE `+searchableBy+` AND search_vector @@ q
+		ORDER BY rank DESC, id
+		LIMIT ? OFFSET ?`, hlStart+hlStop, query, userID, userID, userID, limit, offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range rows {
+		rows[i].Highlight = markHighlight(rows[i].Highlight)
+	}
+	return rows, total, nil
+}
+
+// searchILike - простой запасной поиск по подстроке (без ранжирования)
+func (r *TaskRepo) searchILike(userID uint, query string, limit, offset int) ([]TaskSearchRow, int64, error) {
+	pattern := "%" + escapeLike(query) + "%"
+
+	var total int64
+	err := db.DB.Model(&TaskStruct{}).
+		Where(searchableBy, userID, userID, userID).
+		Where("task ILIKE ?", pattern).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []TaskStruct
+	err = db.DB.Where(searchableBy, userID, userID, userID).
+		Where("task ILIKE ?", pattern).
+		Order("id").Limit(limit).Offset(offset).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]TaskSearchRow, 0, len(tasks))
+	for _, t := range tasks {
+		rows = append(rows, TaskSearchRow{
+			TaskStruct: t,
+			Highlight:  highlight(t.Task, query),
+		})
+	}
+	return rows, total, nil
+}
+
+// escapeLike - экранирует спецсимволы LIKE, чтобы запрос искался как обычный текст
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// подсветка - готовый HTML, а текст задачи пишет пользователь: сначала экранируем текст, потом ставим <b>.
+// ts_headline экранировать не умеет, поэтому просим у него метки из области частного использования юникода
+// (из самого текста их убираем заранее) и меняем их на теги уже после экранирования
+const (
+	hlStart = "\uE000"
+	hlStop  = "\uE001"
+)
+
+// markHighlight - экранирует ответ ts_headline и превращает метки в <b>...</b>
+func markHighlight(headline string) string {
+	return strings.NewReplacer(hlStart, "<b>", hlStop, "</b>").Replace(html.EscapeString(headline))
+}
+
+// highlight - оборачивает все вхождения query (без учета регистра) в <b>...</b>, остальной текст экранирует
+func highlight(text, query string) string {
+	if query == "" {
+		return html.EscapeString(text)
+	}
+	lowerText := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+	// если при смене регистра изменилась длина строки (редкие символы юникода), то индексы не совпадут
+	if len(lowerText) != len(text) || len(lowerQuery) != len(query) {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	start := 0
+	for {
+		i := strings.Index(lowerText[start:], lowerQuery)
+		if i < 0 {
+			break
+		}
+		b.WriteString(html.EscapeString(text[start : start+i]))
+		b.WriteString("<b>")
+		b.WriteString(html.EscapeString(text[start+i : start+i+len(query)]))
+		b.WriteString("</b>")
+		start += i + len(query)
+	}
+	b.WriteString(html.EscapeString(text[start:]))
+	return b.String()
+}
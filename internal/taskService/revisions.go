@@ -0,0 +1,138 @@
+package taskService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/textdiff"
+)
+
+// ревизии задач: после каждого изменения repo сохраняет состояние задачи в task_revisions
+//   • номер ревизии = версия задачи (та же, что в ETag)
+//   • откат к ревизии - новое изменение с ее полями: история остается линейной, ничего не удаляется
+//   • права - как у самой задачи: ревизии чужой задачи выглядят как несуществующие
+
+// бизнес-модель ревизии
+type TaskRevision struct {
+	Revision     uint
+	Task         string
+	IsDone       bool
+	UserId       uint
+	ActorID      *uint
+	RestoredFrom *uint
+	CreatedAt    time.Time
+}
+
+// разница текста задачи между двумя ревизиями
+type TaskRevisionDiff struct {
+	From    uint
+	To      uint
+	Changes []textdiff.Op
+}
+
+// GetTaskRevisions - все ревизии задачи (сначала новые)
+func (s *TaskService) GetTaskRevisions(actor rbac.Actor, id uint) ([]TaskRevision, error) {
+	if _, _, err := s.getTask(actor, id); err != nil {
+		return nil, err
+	}
+
+	dbRevisions, err := s.repo.GetRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	revisions := make([]TaskRevision, 0, len(dbRevisions))
+	for _, r := range dbRevisions {
+		revisions = append(revisions, toTaskRevision(r))
+	}
+	return revisions, nil
+}
+
+// DiffTaskRevisions - как менялся текст задачи от ревизии from до ревизии to (пословно)
+func (s *TaskService) DiffTaskRevisions(actor rbac.Actor, id, from, to uint) (*TaskRevisionDiff, error) {
+	if _, _, err := s.getTask(actor, id); err != nil {
+		return nil, err
+	}
+
+	fromRev, err := s.repo.GetRevision(id, from)
+	if err != nil || fromRev.ID == 0 {
+		return nil, errors.New("revision not found")
+	}
+	toRev, err := s.repo.GetRevision(id, to)
+	if err != nil || toRev.ID == 0 {
+		return nil, errors.New("revision not found")
+	}
+
+	return &TaskRevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: textdiff.Diff(fromRev.Task, toRev.Task),
+	}, nil
+}
+
+// RestoreTaskRevision - возвращает задаче состояние ревизии revision (version - как в UpdateTask)
+// если задача уже в этом состоянии, новая ревизия не создается
+func (s *TaskService) RestoreTaskRevision(actor rbac.Actor, id, revision uint, version *uint) (*Task, error) {
+	dbTask, level, err := s.getTask(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	// откат меняет текст и владельца - нужны полные права
+	if level < accessFull {
+		return nil, errors.New("forbidden")
+	}
+
+	if version != nil && *version != dbTask.Version {
+		return nil, errors.New("version mismatch")
+	}
+
+	rev, err := s.repo.GetRevision(id, revision)
+	if err != nil || rev.ID == 0 {
+		return nil, errors.New("revision not found")
+	}
+
+	// откат к ревизии, где задача была у другого пользователя, - это передача задачи: только админ
+	if rev.UserId != dbTask.UserId {
+		if !actor.CanAccessUser(rev.UserId) {
+			return nil, errors.New("forbidden")
+		}
+		// прежний владелец мог уйти из пространства или стать гостем - тогда вернуть ему задачу нельзя
+		if err := s.checkWorkspaceMember(dbTask.WorkspaceID, rev.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	restoredTask := &dbTask
+	if rev.Task != dbTask.Task || rev.IsDone != dbTask.IsDone || rev.UserId != dbTask.UserId {
+		dbTask.Task = rev.Task
+		dbTask.IsDone = rev.IsDone
+		dbTask.UserId = rev.UserId
+
+		restoredTask, err = s.repo.Restore(&dbTask, revision, actor)
+		if err != nil {
+			return nil, err
+		}
+		s.publish(events.TaskUpdated, restoredTask)
+	}
+
+	task := toTask(restoredTask)
+	if err := AttachCommentCounts(s.comments, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func toTaskRevision(r TaskRevisionStruct) TaskRevision {
+	return TaskRevision{
+		Revision:     r.Revision,
+		Task:         r.Task,
+		IsDone:       r.IsDone,
+		UserId:       r.UserId,
+		ActorID:      r.ActorID,
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package taskService
+
+import (
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/textdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestGetTaskRevisions(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	stranger := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	restoredFrom := uint(1)
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Купить хлеб", UserId: 1, Version: 3}, nil)
+	mockRepo.On("GetRevisions", uint(7)).Return([]TaskRevisionStruct{
+		{ID: 3, TaskID: 7, Revision: 3, UserId: 1, Task: "Купить хлеб", RestoredFrom: &restoredFrom},
+		{ID: 2, TaskID: 7, Revision: 2, UserId: 1, Task: "Купить батон"},
+		{ID: 1, TaskID: 7, Revision: 1, UserId: 1, Task: "Купить хлеб"},
+	}, nil)
+	service := NewTaskService(mockRepo)
+
+	revisions, err := service.GetTaskRevisions(owner, 7)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 3)
+	assert.Equal(t, uint(3), revisions[0].Revision)
+	assert.Equal(t, &restoredFrom, revisions[0].RestoredFrom)
+
+	_, err = service.GetTaskRevisions(stranger, 7)
+	assert.EqualError(t, err, "task not found")
+}
+
+func TestDiffTaskRevisions(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Купить батон", UserId: 1, Version: 2}, nil)
+	mockRepo.On("GetRevision", uint(7), uint(1)).Return(TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, Task: "Купить хлеб"}, nil)
+	mockRepo.On("GetRevision", uint(7), uint(2)).Return(TaskRevisionStruct{ID: 2, TaskID: 7, Revision: 2, Task: "Купить батон"}, nil)
+	mockRepo.On("GetRevision", uint(7), uint(9)).Return(TaskRevisionStruct{}, gorm.ErrRecordNotFound)
+	service := NewTaskService(mockRepo)
+
+	diff, err := service.DiffTaskRevisions(owner, 7, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []textdiff.Op{
+		{Type: textdiff.OpEqual, Text: "Купить "},
+		{Type: textdiff.OpDelete, Text: "хлеб"},
+		{Type: textdiff.OpInsert, Text: "батон"},
+	}, diff.Changes)
+
+	_, err = service.DiffTaskRevisions(owner, 7, 1, 9)
+	assert.EqualError(t, err, "revision not found")
+}
+
+func TestRestoreTaskRevision(t *testing.T) {
+	uintPtr := func(u uint) *uint { return &u }
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	current := TaskStruct{ID: 7, Task: "Купить батон", IsDone: true, UserId: 1, Version: 3}
+
+	tests := []struct {
+		name        string
+		actor       rbac.Actor
+		version     *uint
+		revision    TaskRevisionStruct
+		revisionErr error
+		wantErr     string
+		wantRestore bool
+	}{
+		{
+			name:        "откат к старой ревизии - новая версия",
+			actor:       owner,
+			version:     uintPtr(3),
+			revision:    TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, UserId: 1, Task: "Купить хлеб"},
+			wantRestore: true,
+		},
+		{
+			name:     "задача уже в этом состоянии - ничего не пишем",
+			actor:    owner,
+			revision: TaskRevisionStruct{ID: 3, TaskID: 7, Revision: 3, UserId: 1, Task: "Купить батон", IsDone: true},
+		},
+		{
+			name:     "устаревшая версия",
+			actor:    owner,
+			version:  uintPtr(2),
+			revision: TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, UserId: 1, Task: "Купить хлеб"},
+			wantErr:  "version mismatch",
+		},
+		{
+			name:        "нет такой ревизии",
+			actor:       owner,
+			revisionErr: gorm.ErrRecordNotFound,
+			wantErr:     "revision not found",
+		},
+		{
+			name:     "ревизия с другим владельцем - только админ",
+			actor:    owner,
+			revision: TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, UserId: 5, Task: "Купить хлеб"},
+			wantErr:  "forbidden",
+		},
+		{
+			name:        "админ возвращает задачу прежнему владельцу",
+			actor:       testAdmin,
+			revision:    TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, UserId: 5, Task: "Купить хлеб"},
+			wantRestore: true,
+		},
+		{
+			name:    "чужая задача выглядит как несуществующая",
+			actor:   rbac.Actor{UserID: 2, Role: rbac.RoleUser},
+			wantErr: "task not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			mockRepo.On("GetByID", uint(7)).Return(current, nil)
+			mockRepo.On("GetRevision", uint(7), mock.Anything).Return(tt.revision, tt.revisionErr)
+			if tt.wantRestore {
+				mockRepo.On("Restore", mock.MatchedBy(func(task *TaskStruct) bool {
+					return task.Task == tt.revision.Task && task.IsDone == tt.revision.IsDone && task.UserId == tt.revision.UserId
+				}), tt.revision.Revision, tt.actor).
+					Return(&TaskStruct{ID: 7, Task: tt.revision.Task, IsDone: tt.revision.IsDone, UserId: tt.revision.UserId, Version: 4}, nil).Once()
+			}
+
+			task, err := NewTaskService(mockRepo).RestoreTaskRevision(tt.actor, 7, tt.revision.Revision, tt.version)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.revision.Task, task.Task)
+			if tt.wantRestore {
+				assert.Equal(t, uint(4), task.Version)
+			} else {
+				assert.Equal(t, uint(3), task.Version)
+				mockRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("задачу пространства нельзя вернуть тому, кто из него ушел", func(t *testing.T) {
+		wsID := uint(5)
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Купить батон", UserId: 1, WorkspaceID: &wsID, Version: 3}, nil)
+		mockRepo.On("GetRevision", uint(7), uint(1)).Return(TaskRevisionStruct{ID: 1, TaskID: 7, Revision: 1, UserId: 5, Task: "Купить хлеб"}, nil)
+		// 5 был участником, а теперь гость; 6 ушел совсем
+		service := NewTaskService(mockRepo).WithWorkspaces(fakeWorkspaces{5: {1: rbac.WorkspaceMember, 5: rbac.WorkspaceGuest}})
+
+		_, err := service.RestoreTaskRevision(testAdmin, 7, 1, nil)
+		assert.EqualError(t, err, "user is not a workspace member")
+
+		mockRepo.On("GetRevision", uint(7), uint(2)).Return(TaskRevisionStruct{ID: 2, TaskID: 7, Revision: 2, UserId: 6, Task: "Купить хлеб"}, nil)
+		_, err = service.RestoreTaskRevision(testAdmin, 7, 2, nil)
+		assert.EqualError(t, err, "user is not a workspace member")
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
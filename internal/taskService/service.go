@@ -3,6 +3,10 @@ package taskService
 import (
 	"errors"
 	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 )
 
 // 3. service-слой (мозг)
@@ -14,28 +18,102 @@ import (
 
 // структура параметров метода CreateTask
 type CreateTaskParams struct {
-	Task   string
-	IsDone *bool
-	UserId uint
+	Task        string
+	IsDone      *bool
+	UserId      uint
+	WorkspaceID *uint // nil - личная задача
 }
 
 // структура параметров метода UpdateTask
+// это явная маска полей: nil - поле не меняется, не nil - новое значение
+// (в репозиторий уходят только колонки переданных полей)
 type UpdateTaskParams struct {
 	Task   *string
 	IsDone *bool
 	UserId *uint
+	// место на доске (меняет и исполнитель - это как перенос карточки, а не правка задачи)
+	Position *int64
 }
 
 // бизнес-модель, которую возвращает сервис
 type Task struct {
-	ID     uint
-	Task   string
-	IsDone *bool
+	ID           uint
+	Task         string
+	IsDone       *bool
+	UserId       uint
+	WorkspaceID  *uint // nil - личная задача
+	Version      uint
+	Position     int64
+	Assignees    []uint // id исполнителей (nil - нет)
+	CommentCount int64  // заполняется, если сервису передан CommentCounter
+}
+
+// структура параметров метода SearchTasks
+type SearchTasksParams struct {
+	Query  string
 	UserId uint
+	Limit  *int // nil - значение по умолчанию
+	Offset *int // nil - с начала
+}
+
+// один найденный результат поиска
+type TaskSearchResult struct {
+	Task      Task
+	Rank      float64
+	Highlight string
+}
+
+// страница результатов поиска
+type TaskSearchPage struct {
+	Items  []TaskSearchResult
+	Total  int64
+	Limit  int
+	Offset int
+}
+
+// лимиты пагинации поиска
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// EmailVerificationChecker - подтвердил ли пользователь email (реализует userService;
+// сам userService сюда импортировать нельзя - он уже импортирует taskService)
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID uint) (bool, error)
+}
+
+// HistoryReader - история изменений сущности из журнала аудита (реализует audit.AuditService)
+type HistoryReader interface {
+	EntityHistory(entityType string, entityID uint, limit *int) ([]audit.Entry, error)
+}
+
+// CommentCounter - сколько неудаленных комментариев у задач (реализует commentService.CommentRepo)
+// один запрос на весь список задач, а не по запросу на задачу
+type CommentCounter interface {
+	CountByTasks(taskIDs []uint) (map[uint]int64, error)
+}
+
+// WorkspaceAccess - роль пользователя в рабочем пространстве (реализует workspaceService;
+// сам workspaceService сюда импортировать нельзя - через userService он уже зависит от taskService)
+type WorkspaceAccess interface {
+	WorkspaceRole(workspaceID, userID uint) (rbac.WorkspaceRole, error) // "" - не участник
+}
+
+// TaskCleaner - убирает данные задачи, которые лежат вне бд (реализует attachmentService:
+// строки вложений удаляет каскад в бд, а их содержимое в хранилище - он)
+type TaskCleaner interface {
+	CleanupTask(taskID uint)
 }
 
 type TaskService struct {
-	repo TaskRepoInterface // используем интерфейс
+	repo       TaskRepoInterface        // используем интерфейс
+	verified   EmailVerificationChecker // nil - создавать задачи можно без подтверждения email
+	history    HistoryReader            // nil - история задач недоступна
+	comments   CommentCounter           // nil - счетчики комментариев не заполняются
+	cleaners   []TaskCleaner            // вызываются после удаления задачи
+	workspaces WorkspaceAccess          // nil - рабочих пространств нет, все задачи личные
+	events     events.Publisher         // nil - события об изменениях не публикуются
 }
 
 // конструктор NewTaskService - связывает сервис и репозиторий
@@ -43,8 +121,226 @@ func NewTaskService(r TaskRepoInterface) *TaskService {
 	return &TaskService{repo: r}
 }
 
+// WithVerifiedEmailRequired - запрещает создавать задачи пользователям с неподтвержденным email
+func (s *TaskService) WithVerifiedEmailRequired(c EmailVerificationChecker) *TaskService {
+	s.verified = c
+	return s
+}
+
+// WithHistory - подключает чтение истории задач из журнала аудита
+func (s *TaskService) WithHistory(h HistoryReader) *TaskService {
+	s.history = h
+	return s
+}
+
+// WithCommentCounts - заполнять у задач число комментариев
+func (s *TaskService) WithCommentCounts(c CommentCounter) *TaskService {
+	s.comments = c
+	return s
+}
+
+// WithWorkspaces - включает задачи рабочих пространств (доступ к ним - по членству в пространстве)
+func (s *TaskService) WithWorkspaces(w WorkspaceAccess) *TaskService {
+	s.workspaces = w
+	return s
+}
+
+// WithCleanup - что еще убрать после удаления задачи
+func (s *TaskService) WithCleanup(c TaskCleaner) *TaskService {
+	s.cleaners = append(s.cleaners, c)
+	return s
+}
+
+// AttachCommentCounts - проставляет задачам число комментариев одним запросом (c == nil - ничего не делает)
+// экспортирована для userService, который отдает задачи пользователя сам
+func AttachCommentCounts(c CommentCounter, tasks ...*Task) error {
+	if c == nil || len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	counts, err := c.CountByTasks(ids)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		t.CommentCount = counts[t.ID]
+	}
+	return nil
+}
+
+// toTask - маппим бд-модель в бизнес-модель
+func toTask(t *TaskStruct) *Task {
+	return &Task{
+		ID:          t.ID,
+		Task:        t.Task,
+		IsDone:      &t.IsDone,
+		UserId:      t.UserId,
+		WorkspaceID: t.WorkspaceID,
+		Version:     t.Version,
+		Position:    t.Position,
+		Assignees:   AssigneeIDs(t.Assignees),
+	}
+}
+
+// taskPtrs - указатели на элементы списка (для AttachCommentCounts)
+func taskPtrs(tasks []Task) []*Task {
+	ptrs := make([]*Task, len(tasks))
+	for i := range tasks {
+		ptrs[i] = &tasks[i]
+	}
+	return ptrs
+}
+
+// права доступа к задачам:
+//   • личная задача: владелец (создатель) видит и меняет ее, удаляет и назначает исполнителей;
+//     исполнитель видит задачу и меняет только ее статус (is_done), но может сам сняться с задачи
+//   • задача рабочего пространства видна только его участникам (роли - rbac.WorkspaceRole):
+//     owner и admin пространства управляют любыми его задачами, member - своими (и статусом тех,
+//     где он исполнитель), остальные задачи видит и комментирует; guest только смотрит
+//   • комментарии и вложения добавляет любой, кто видит задачу, кроме гостя пространства (GetTaskPermissions)
+//   • админ - любые задачи
+// чужая задача для остальных выглядит как несуществующая ("task not found"),
+// а видимая задача без нужных прав (исполнитель удаляет, user_id в запросе чужой) - "forbidden"
+
+// уровни доступа к задаче (по возрастанию)
+type accessLevel int
+
+const (
+	accessNone    accessLevel = iota // задачи не видно
+	accessRead                       // только смотрит
+	accessComment                    // смотрит, комментирует и прикладывает файлы (участник пространства)
+	accessStatus                     // смотрит и меняет статус (исполнитель)
+	accessFull                       // все: текст, владелец, удаление, исполнители
+)
+
+// access - что actor может делать с задачей
+func (s *TaskService) access(actor rbac.Actor, task TaskStruct) (accessLevel, error) {
+	if task.WorkspaceID == nil || s.workspaces == nil {
+		switch {
+		case actor.CanAccessUser(task.UserId):
+			return accessFull, nil
+		case isAssignee(actor, task):
+			return accessStatus, nil
+		default:
+			return accessNone, nil
+		}
+	}
+
+	if actor.IsAdmin() {
+		return accessFull, nil
+	}
+	if actor.UserID == 0 {
+		return accessNone, nil
+	}
+	role, err := s.workspaces.WorkspaceRole(*task.WorkspaceID, actor.UserID)
+	if err != nil {
+		return accessNone, err
+	}
+	switch {
+	case role == "":
+		return accessNone, nil // ушел из пространства - задач больше не видит, даже своих
+	case role.CanManageTasks():
+		return accessFull, nil
+	case !role.CanWriteTasks():
+		return accessRead, nil
+	case task.UserId == actor.UserID:
+		return accessFull, nil
+	case isAssignee(actor, task):
+		return accessStatus, nil
+	default:
+		return accessComment, nil
+	}
+}
+
+// getTask - задача и уровень доступа к ней ("task not found", если actor ее не видит)
+func (s *TaskService) getTask(actor rbac.Actor, id uint) (TaskStruct, accessLevel, error) {
+	dbTask, err := s.repo.GetByID(id)
+	if err != nil || dbTask.ID == 0 {
+		return TaskStruct{}, accessNone, errors.New("task not found")
+	}
+	level, err := s.access(actor, dbTask)
+	if err != nil {
+		return TaskStruct{}, accessNone, err
+	}
+	if level == accessNone {
+		return TaskStruct{}, accessNone, errors.New("task not found")
+	}
+	return dbTask, level, nil
+}
+
+// TaskPermissions - что actor может делать с видимой задачей (для комментариев и вложений)
+type TaskPermissions struct {
+	Contribute bool // добавляет комментарии и вложения (все, кроме гостя пространства)
+	Manage     bool // управляет задачей целиком: владелец, owner/admin пространства или админ
+}
+
+// GetTaskPermissions - задача и права actor на нее ("task not found", если actor ее не видит)
+func (s *TaskService) GetTaskPermissions(actor rbac.Actor, id uint) (*Task, TaskPermissions, error) {
+	dbTask, level, err := s.getTask(actor, id)
+	if err != nil {
+		return nil, TaskPermissions{}, err
+	}
+	return toTask(&dbTask), TaskPermissions{Contribute: level >= accessComment, Manage: level >= accessFull}, nil
+}
+
+// isAssignee - назначен ли actor исполнителем задачи
+func isAssignee(actor rbac.Actor, task TaskStruct) bool {
+	if actor.UserID == 0 {
+		return false
+	}
+	for _, a := range task.Assignees {
+		if a.UserID == actor.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// managesWorkspaceTasks - управляет ли actor любыми задачами пространства (owner или admin пространства)
+func (s *TaskService) managesWorkspaceTasks(actor rbac.Actor, workspaceID *uint) (bool, error) {
+	if workspaceID == nil || s.workspaces == nil || actor.UserID == 0 {
+		return false, nil
+	}
+	role, err := s.workspaces.WorkspaceRole(*workspaceID, actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	return role.CanManageTasks(), nil
+}
+
+// checkWorkspaceMember - в задачах пространства владельцем и исполнителем может быть
+// только участник, который может работать с задачами (не гость)
+func (s *TaskService) checkWorkspaceMember(workspaceID *uint, userID uint) error {
+	if workspaceID == nil || s.workspaces == nil {
+		return nil
+	}
+	role, err := s.workspaces.WorkspaceRole(*workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanWriteTasks() {
+		return errors.New("user is not a workspace member")
+	}
+	return nil
+}
+
+// AssigneeIDs - id исполнителей для бизнес-модели (экспортирована для userService, как AttachCommentCounts)
+func AssigneeIDs(assignees []TaskAssigneeStruct) []uint {
+	if len(assignees) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(assignees))
+	for _, a := range assignees {
+		ids = append(ids, a.UserID)
+	}
+	return ids
+}
+
 // CreateTask - создает новую задачу (с проверкой что она не пустя)
-func (s *TaskService) CreateTask(params CreateTaskParams) (*Task, error) {
+func (s *TaskService) CreateTask(actor rbac.Actor, params CreateTaskParams) (*Task, error) {
 	// проверка на пустой тип задачи
 	if strings.TrimSpace(params.Task) == "" {
 		return nil, errors.New("task is empty")
@@ -54,6 +350,43 @@ func (s *TaskService) CreateTask(params CreateTaskParams) (*Task, error) {
 		return nil, errors.New("user_id is required")
 	}
 
+	// создавать задачи другим пользователям может только админ
+	if !actor.CanAccessUser(params.UserId) {
+		return nil, errors.New("forbidden")
+	}
+
+	if params.WorkspaceID != nil {
+		if s.workspaces == nil {
+			return nil, errors.New("workspace not found")
+		}
+		// создавать задачи в пространстве могут его участники (кроме гостей) и админ
+		if !actor.IsAdmin() {
+			role, err := s.workspaces.WorkspaceRole(*params.WorkspaceID, actor.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if role == "" {
+				return nil, errors.New("workspace not found")
+			}
+			if !role.CanWriteTasks() {
+				return nil, errors.New("forbidden")
+			}
+		}
+		if err := s.checkWorkspaceMember(params.WorkspaceID, params.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.verified != nil {
+		ok, err := s.verified.IsEmailVerified(params.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("email is not verified")
+		}
+	}
+
 	// если isDone не был передан пользователем, то он будет по умолчанию false
 	isDone := false
 	if params.IsDone != nil { // если done не пустой, то есть был передан в бади
@@ -62,52 +395,115 @@ func (s *TaskService) CreateTask(params CreateTaskParams) (*Task, error) {
 
 	// создаем бд-модель
 	dbTask := &TaskStruct{
-		Task:   params.Task,
-		IsDone: isDone,
-		UserId: params.UserId,
+		Task:        params.Task,
+		IsDone:      isDone,
+		UserId:      params.UserId,
+		WorkspaceID: params.WorkspaceID,
 	}
 
-	createdTask, err := s.repo.Create(dbTask) // передаем данные в репозиторий
+	createdTask, err := s.repo.Create(dbTask, actor) // передаем данные в репозиторий
 	if err != nil {
 		return nil, err
 	}
+	s.publish(events.TaskCreated, createdTask)
 
 	// маппим бд-модель в бизнес-модель
-	return &Task{
-		ID:     createdTask.ID,
-		Task:   createdTask.Task,
-		IsDone: &createdTask.IsDone,
-		UserId: createdTask.UserId,
-	}, nil
+	return toTask(createdTask), nil
 }
 
-// GetTasks - возвращает все задачи
-func (s *TaskService) GetTasks() ([]Task, error) {
-	dbTasks, err := s.repo.GetAll()
+// GetTasks - возвращает все задачи (админу) или только задачи самого пользователя
+func (s *TaskService) GetTasks(actor rbac.Actor) ([]Task, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+
+	var dbTasks []TaskStruct
+	var err error
+	if actor.IsAdmin() {
+		dbTasks, err = s.repo.GetAll()
+	} else {
+		dbTasks, err = s.repo.GetByUser(actor.UserID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// маппим бд-модель в бизнес-модель
 	tasks := make([]Task, 0, len(dbTasks))
-	for _, dbTask := range dbTasks {
-		tasks = append(tasks, Task{
-			ID:     dbTask.ID,
-			Task:   dbTask.Task,
-			IsDone: &dbTask.IsDone,
-			UserId: dbTask.UserId,
-		})
+	for i := range dbTasks {
+		tasks = append(tasks, *toTask(&dbTasks[i]))
+	}
+	if err := AttachCommentCounts(s.comments, taskPtrs(tasks)...); err != nil {
+		return nil, err
 	}
 	return tasks, nil
 }
 
-func (s *TaskService) UpdateTask(id uint, params UpdateTaskParams) (*Task, error) {
-	dbTask, err := s.repo.GetByID(id)
-	if err != nil || dbTask.ID == 0 {
-		return nil, errors.New("task not found")
+// GetWorkspaceTasks - задачи рабочего пространства (его участникам и админу)
+func (s *TaskService) GetWorkspaceTasks(actor rbac.Actor, workspaceID uint) ([]Task, error) {
+	if s.workspaces == nil || actor.UserID == 0 {
+		return nil, errors.New("workspace not found")
+	}
+	if !actor.IsAdmin() {
+		role, err := s.workspaces.WorkspaceRole(workspaceID, actor.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, errors.New("workspace not found")
+		}
+	}
+
+	dbTasks, err := s.repo.GetByWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	tasks := make([]Task, 0, len(dbTasks))
+	for i := range dbTasks {
+		tasks = append(tasks, *toTask(&dbTasks[i]))
+	}
+	if err := AttachCommentCounts(s.comments, taskPtrs(tasks)...); err != nil {
+		return nil, err
 	}
+	return tasks, nil
+}
 
-	updated := false
+// GetTask - возвращает задачу по ID
+func (s *TaskService) GetTask(actor rbac.Actor, id uint) (*Task, error) {
+	dbTask, _, err := s.getTask(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	task := toTask(&dbTask)
+	if err := AttachCommentCounts(s.comments, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// UpdateTask - обновляет задачу
+// version - версия, которую видел клиент (из If-Match); nil - обновляем любую текущую версию
+func (s *TaskService) UpdateTask(actor rbac.Actor, id uint, version *uint, params UpdateTaskParams) (*Task, error) {
+	dbTask, level, err := s.getTask(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// исполнитель меняет только статус и место на доске, а кто только смотрит - ничего
+	if level < accessStatus || level < accessFull && (params.Task != nil || params.UserId != nil) {
+		return nil, errors.New("forbidden")
+	}
+
+	if version != nil && *version != dbTask.Version {
+		return nil, errors.New("version mismatch")
+	}
+
+	// маска изменённых колонок
+	var fields []string
 
 	if params.Task != nil {
 		// проверяем что таска не nil перед ее обновлением
@@ -116,52 +512,224 @@ func (s *TaskService) UpdateTask(id uint, params UpdateTaskParams) (*Task, error
 		}
 		// обновляем
 		dbTask.Task = *params.Task // обновляем таску если она была передана для обновления
-		updated = true
+		fields = append(fields, TaskFieldTask)
 	}
 
 	if params.IsDone != nil {
 		// обновляем
 		dbTask.IsDone = *params.IsDone // обновляем флаг is_done если он был передан для обновления
-		updated = true
+		fields = append(fields, TaskFieldIsDone)
 	}
 
 	if params.UserId != nil {
 		if *params.UserId == 0 {
 			return nil, errors.New("user_id cannot be 0")
 		}
+		// передать задачу другому пользователю может админ, а задачу пространства - и его owner или admin
+		if !actor.CanAccessUser(*params.UserId) {
+			manager, err := s.managesWorkspaceTasks(actor, dbTask.WorkspaceID)
+			if err != nil {
+				return nil, err
+			}
+			if !manager {
+				return nil, errors.New("forbidden")
+			}
+		}
+		if err := s.checkWorkspaceMember(dbTask.WorkspaceID, *params.UserId); err != nil {
+			return nil, err
+		}
 		dbTask.UserId = *params.UserId
-		updated = true
+		fields = append(fields, TaskFieldUserId)
 	}
 
-	if !updated {
+	if params.Position != nil {
+		if *params.Position < 0 {
+			return nil, errors.New("position cannot be negative")
+		}
+		dbTask.Position = *params.Position
+		fields = append(fields, TaskFieldPosition)
+	}
+
+	if len(fields) == 0 {
 		return nil, errors.New("no fields to update")
 	}
 
-	// обновляем задачу
-	updatedTask, err := s.repo.Update(&dbTask)
+	// обновляем только изменённые колонки
+	updatedTask, err := s.repo.Update(&dbTask, fields, actor)
 	if err != nil {
 		return nil, err
 	}
+	s.publish(events.TaskUpdated, updatedTask)
 
 	// маппим бд-модель в бизнес-модель
-	return &Task{
-		ID:     updatedTask.ID,
-		Task:   updatedTask.Task,
-		IsDone: &updatedTask.IsDone,
-		UserId: updatedTask.UserId,
-	}, nil
+	task := toTask(updatedTask)
+	if err := AttachCommentCounts(s.comments, task); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
-func (s *TaskService) DeleteTask(id uint) error {
+// DeleteTask - удаляет задачу (version - как в UpdateTask)
+func (s *TaskService) DeleteTask(actor rbac.Actor, id uint, version *uint) error {
 	// ищем задачу по ID
-	task, err := s.repo.GetByID(id)
-	if err != nil || task.ID == 0 {
-		return errors.New("task not found")
+	task, level, err := s.getTask(actor, id)
+	if err != nil {
+		return err
+	}
+	// удаляет только тот, у кого полные права (не исполнитель)
+	if level < accessFull {
+		return errors.New("forbidden")
+	}
+
+	if version != nil && *version != task.Version {
+		return errors.New("version mismatch")
 	}
 	// удаляем задачу
-	err = s.repo.Delete(&task)
+	err = s.repo.Delete(&task, actor)
+	if err != nil {
+		return err
+	}
+	s.publish(events.TaskDeleted, &task)
+	for _, c := range s.cleaners {
+		c.CleanupTask(task.ID)
+	}
+	return nil
+}
+
+// GetTaskHistory - кто, когда и как менял задачу (сначала новые изменения)
+// удаленной задачи в бд уже нет, поэтому ее историю видит только админ (владельца не проверить)
+func (s *TaskService) GetTaskHistory(actor rbac.Actor, id uint, limit *int) ([]audit.Entry, error) {
+	if s.history == nil {
+		return nil, errors.New("task history is not available")
+	}
+
+	dbTask, err := s.repo.GetByID(id)
+	found := err == nil && dbTask.ID != 0
+	if !found && !actor.IsAdmin() {
+		return nil, errors.New("task not found")
+	}
+	if found {
+		level, err := s.access(actor, dbTask)
+		if err != nil {
+			return nil, err
+		}
+		if level == accessNone {
+			return nil, errors.New("task not found")
+		}
+	}
+
+	entries, err := s.history.EntityHistory(audit.EntityTask, id, limit)
 	if err != nil {
+		return nil, err
+	}
+	if !found && len(entries) == 0 {
+		return nil, errors.New("task not found")
+	}
+	return entries, nil
+}
+
+// AssignTask - назначает пользователя userID исполнителем задачи (только владелец или админ)
+func (s *TaskService) AssignTask(actor rbac.Actor, id, userID uint) error {
+	if userID == 0 {
+		return errors.New("user_id cannot be 0")
+	}
+
+	dbTask, level, err := s.getTask(actor, id)
+	if err != nil {
+		return err
+	}
+	if level < accessFull {
+		return errors.New("forbidden")
+	}
+	if userID == dbTask.UserId {
+		return errors.New("owner cannot be an assignee")
+	}
+	if err := s.checkWorkspaceMember(dbTask.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Assign(&dbTask, userID, actor); err != nil {
 		return err
 	}
+	s.publishAssignees(dbTask)
 	return nil
 }
+
+// UnassignTask - снимает исполнителя с задачи (владелец, админ или сам исполнитель)
+func (s *TaskService) UnassignTask(actor rbac.Actor, id, userID uint) error {
+	dbTask, level, err := s.getTask(actor, id)
+	if err != nil {
+		return err
+	}
+	if level < accessFull && actor.UserID != userID {
+		return errors.New("forbidden")
+	}
+
+	if err := s.repo.Unassign(&dbTask, userID, actor); err != nil {
+		return err
+	}
+	s.publishAssignees(dbTask)
+	return nil
+}
+
+// SearchTasks - полнотекстовый поиск по задачам пользователя и задачам его пространств (с пагинацией)
+func (s *TaskService) SearchTasks(actor rbac.Actor, params SearchTasksParams) (*TaskSearchPage, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return nil, errors.New("search query is empty")
+	}
+
+	if params.UserId == 0 {
+		return nil, errors.New("user_id is required")
+	}
+
+	// искать по чужим задачам может только админ
+	if !actor.CanAccessUser(params.UserId) {
+		return nil, errors.New("forbidden")
+	}
+
+	limit := defaultSearchLimit
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > maxSearchLimit {
+			return nil, errors.New("limit must be between 1 and 100")
+		}
+		limit = *params.Limit
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 {
+			return nil, errors.New("offset cannot be negative")
+		}
+		offset = *params.Offset
+	}
+
+	rows, total, err := s.repo.Search(params.UserId, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	items := make([]TaskSearchResult, 0, len(rows))
+	for i := range rows {
+		items = append(items, TaskSearchResult{
+			Task:      *toTask(&rows[i].TaskStruct),
+			Rank:      rows[i].Rank,
+			Highlight: rows[i].Highlight,
+		})
+	}
+	found := make([]*Task, len(items))
+	for i := range items {
+		found[i] = &items[i].Task
+	}
+	if err := AttachCommentCounts(s.comments, found...); err != nil {
+		return nil, err
+	}
+
+	return &TaskSearchPage{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package taskService
 
 import (
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,8 +10,8 @@ type MockTaskRepo struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepo) Create(task *TaskStruct) (*TaskStruct, error) {
-	args := m.Called(task) // Called() проверяет что метод вызван с правильными параметрами
+func (m *MockTaskRepo) Create(task *TaskStruct, actor rbac.Actor) (*TaskStruct, error) {
+	args := m.Called(task, actor) // Called() проверяет что метод вызван с правильными параметрами
 	var t *TaskStruct
 	if res := args.Get(0); res != nil {
 		t = res.(*TaskStruct)
@@ -27,6 +28,15 @@ func (m *MockTaskRepo) GetAll() ([]TaskStruct, error) {
 	return tasks, args.Error(1)
 }
 
+func (m *MockTaskRepo) GetByUser(userID uint) ([]TaskStruct, error) {
+	args := m.Called(userID)
+	var tasks []TaskStruct
+	if res := args.Get(0); res != nil {
+		tasks = res.([]TaskStruct)
+	}
+	return tasks, args.Error(1)
+}
+
 func (m *MockTaskRepo) GetByID(id uint) (TaskStruct, error) {
     args := m.Called(id) // Проверяем, что метод вызван с правильным параметром
     var task TaskStruct
@@ -36,8 +46,8 @@ func (m *MockTaskRepo) GetByID(id uint) (TaskStruct, error) {
     return task, args.Error(1) 
 }
 
-func (m *MockTaskRepo) Update(task *TaskStruct) (*TaskStruct, error) {
-    args := m.Called(task) // Проверяем, что метод вызван с правильным параметром
+func (m *MockTaskRepo) Update(task *TaskStruct, fields []string, actor rbac.Actor) (*TaskStruct, error) {
+    args := m.Called(task, fields, actor) // Проверяем, что метод вызван с правильными параметрами
     var updatedTask *TaskStruct
     if res := args.Get(0); res != nil {
         updatedTask = res.(*TaskStruct)
@@ -45,7 +55,62 @@ func (m *MockTaskRepo) Update(task *TaskStruct) (*TaskStruct, error) {
     return updatedTask, args.Error(1) 
 }
 
-func (m *MockTaskRepo) Delete(task *TaskStruct) error {
-    args := m.Called(task) // Проверяем, что метод бы9л вызван с правильными параметрами
+func (m *MockTaskRepo) Delete(task *TaskStruct, actor rbac.Actor) error {
+    args := m.Called(task, actor) // Проверяем, что метод бы9л вызван с правильными параметрами
     return args.Error(0)
-}
\ No newline at end of file
+}
+
+func (m *MockTaskRepo) Search(userID uint, query string, limit, offset int) ([]TaskSearchRow, int64, error) {
+	args := m.Called(userID, query, limit, offset)
+	var rows []TaskSearchRow
+	if res := args.Get(0); res != nil {
+		rows = res.([]TaskSearchRow)
+	}
+	return rows, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepo) Restore(task *TaskStruct, revision uint, actor rbac.Actor) (*TaskStruct, error) {
+	args := m.Called(task, revision, actor)
+	var restored *TaskStruct
+	if res := args.Get(0); res != nil {
+		restored = res.(*TaskStruct)
+	}
+	return restored, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetRevisions(taskID uint) ([]TaskRevisionStruct, error) {
+	args := m.Called(taskID)
+	var revisions []TaskRevisionStruct
+	if res := args.Get(0); res != nil {
+		revisions = res.([]TaskRevisionStruct)
+	}
+	return revisions, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetRevision(taskID, revision uint) (TaskRevisionStruct, error) {
+	args := m.Called(taskID, revision)
+	var rev TaskRevisionStruct
+	if res := args.Get(0); res != nil {
+		rev = res.(TaskRevisionStruct)
+	}
+	return rev, args.Error(1)
+}
+
+func (m *MockTaskRepo) Assign(task *TaskStruct, userID uint, actor rbac.Actor) error {
+	args := m.Called(task, userID, actor)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) Unassign(task *TaskStruct, userID uint, actor rbac.Actor) error {
+	args := m.Called(task, userID, actor)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) GetByWorkspace(workspaceID uint) ([]TaskStruct, error) {
+	args := m.Called(workspaceID)
+	var tasks []TaskStruct
+	if res := args.Get(0); res != nil {
+		tasks = res.([]TaskStruct)
+	}
+	return tasks, args.Error(1)
+}
@@ -2,13 +2,24 @@ package taskService
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
+// testAdmin - в тестах бизнес-логики действуем от имени админа, чтобы проверки прав не мешали
+// (сами права проверяются в TestTaskAccess)
+var testAdmin = rbac.Actor{UserID: 100, Role: rbac.RoleAdmin}
+
 func TestCreateTask(t *testing.T) {
 	// вспомогательные функции
 	boolPtr := func(b bool) *bool { return &b }
@@ -40,7 +51,7 @@ func TestCreateTask(t *testing.T) {
 					IsDone: *params.IsDone,
 					UserId: params.UserId,
 				}
-				m.On("Create", dbTask).Return(dbTask, nil)
+				m.On("Create", dbTask, mock.Anything).Return(dbTask, nil)
 			},
 			wantErr: false,
 		},
@@ -59,7 +70,7 @@ func TestCreateTask(t *testing.T) {
                     IsDone: *params.IsDone,
                     UserId: params.UserId,
                 }
-                m.On("Create", dbTask).Return(&TaskStruct{}, errors.New("db error"))
+                m.On("Create", dbTask, mock.Anything).Return(&TaskStruct{}, errors.New("db error"))
             },
         },
 	}
@@ -70,7 +81,7 @@ func TestCreateTask(t *testing.T) {
 			tt.mockSetup(mockRepo, tt.params, tt.want) // настройка мока
 
 			service := NewTaskService(mockRepo)
-			result, err := service.CreateTask(tt.params)
+			result, err := service.CreateTask(testAdmin, tt.params)
 
 			if tt.wantErr { // если ожидается ошибка, то проверяется что ошибка произошла
 				assert.Error(t, err)
@@ -125,7 +136,7 @@ func TestGetTasks(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTaskService(mockRepo)
-			result, err := service.GetTasks()
+			result, err := service.GetTasks(testAdmin)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -157,6 +168,7 @@ func TestUpdateTask(t *testing.T) {
 	tests := []struct {
 		name      string
 		id        uint
+		version   *uint // значение из If-Match (nil - без проверки)
 		params    UpdateTaskParams
 		want      *Task
 		wantErr   bool
@@ -194,7 +206,7 @@ func TestUpdateTask(t *testing.T) {
 					IsDone: *params.IsDone,
 					UserId: *params.UserId,
 				}
-				m.On("Update", mock.Anything).Return(updatedTask, nil)
+				m.On("Update", mock.Anything, []string{TaskFieldTask, TaskFieldIsDone, TaskFieldUserId}, mock.Anything).Return(updatedTask, nil)
 			},
 		},
 		{
@@ -225,7 +237,7 @@ func TestUpdateTask(t *testing.T) {
 					IsDone: false, // не меняли
 					UserId: 1,     // не меняли
 				}
-				m.On("Update", mock.Anything).Return(updatedTask, nil)
+				m.On("Update", mock.Anything, []string{TaskFieldTask}, mock.Anything).Return(updatedTask, nil)
 			},
 		},
 
@@ -258,7 +270,7 @@ func TestUpdateTask(t *testing.T) {
 					IsDone: true,            // обновили
 					UserId: 1,               // не меняли
 				}
-				m.On("Update", mock.Anything).Return(updatedTask, nil)
+				m.On("Update", mock.Anything, []string{TaskFieldIsDone}, mock.Anything).Return(updatedTask, nil)
 			},
 		},
 		{
@@ -289,7 +301,7 @@ func TestUpdateTask(t *testing.T) {
 					IsDone: false,
 					UserId: 3,
 				}
-				m.On("Update", mock.Anything).Return(updatedTask, nil)
+				m.On("Update", mock.Anything, []string{TaskFieldUserId}, mock.Anything).Return(updatedTask, nil)
 			},
 		},
 		{
@@ -358,7 +370,61 @@ func TestUpdateTask(t *testing.T) {
 					UserId: 1,
 				}
 				m.On("GetByID", id).Return(existingTask, nil)
-				m.On("Update", mock.Anything).Return(nil, errors.New("db error"))
+				m.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+		},
+		{
+			name:    "успешное обновление с совпавшей версией",
+			id:      8,
+			version: uintPtr(3),
+			params: UpdateTaskParams{
+				Task: stringPtr("Versioned"),
+			},
+			want: &Task{
+				ID:      8,
+				Task:    "Versioned",
+				IsDone:  boolPtr(false),
+				UserId:  1,
+				Version: 4,
+			},
+			wantErr: false,
+			mockSetup: func(m *MockTaskRepo, id uint, params UpdateTaskParams, want *Task) {
+				existingTask := TaskStruct{ID: id, Task: "Old task", UserId: 1, Version: 3}
+				m.On("GetByID", id).Return(existingTask, nil)
+
+				updatedTask := &TaskStruct{ID: id, Task: "Versioned", UserId: 1, Version: 4}
+				m.On("Update", mock.MatchedBy(func(task *TaskStruct) bool {
+					// в репозиторий уходит версия, которую мы прочитали
+					return task.Version == 3
+				}), []string{TaskFieldTask}, mock.Anything).Return(updatedTask, nil)
+			},
+		},
+		{
+			name:    "ошибка - версия из If-Match устарела",
+			id:      9,
+			version: uintPtr(2),
+			params: UpdateTaskParams{
+				Task: stringPtr("Stale"),
+			},
+			want:    nil,
+			wantErr: true,
+			mockSetup: func(m *MockTaskRepo, id uint, params UpdateTaskParams, want *Task) {
+				existingTask := TaskStruct{ID: id, Task: "Old task", UserId: 1, Version: 3}
+				m.On("GetByID", id).Return(existingTask, nil)
+			},
+		},
+		{
+			name: "ошибка - задачу изменили между чтением и записью",
+			id:   10,
+			params: UpdateTaskParams{
+				IsDone: boolPtr(true),
+			},
+			want:    nil,
+			wantErr: true,
+			mockSetup: func(m *MockTaskRepo, id uint, params UpdateTaskParams, want *Task) {
+				existingTask := TaskStruct{ID: id, Task: "Old task", UserId: 1, Version: 3}
+				m.On("GetByID", id).Return(existingTask, nil)
+				m.On("Update", mock.Anything, []string{TaskFieldIsDone}, mock.Anything).Return(nil, errors.New("version mismatch"))
 			},
 		},
 	}
@@ -368,7 +434,7 @@ func TestUpdateTask(t *testing.T) {
 			tt.mockSetup(mockRepo, tt.id, tt.params, tt.want)
 
 			service := NewTaskService(mockRepo)
-			result, err := service.UpdateTask(tt.id, tt.params)
+			result, err := service.UpdateTask(testAdmin, tt.id, tt.version, tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -379,6 +445,58 @@ func TestUpdateTask(t *testing.T) {
 				assert.Equal(t, tt.want.Task, result.Task)
 				assert.Equal(t, *tt.want.IsDone, *result.IsDone)
 				assert.Equal(t, tt.want.UserId, result.UserId)
+				assert.Equal(t, tt.want.Version, result.Version)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        uint
+		mockSetup func(m *MockTaskRepo, id uint)
+		want      *Task
+		wantErr   bool
+	}{
+		{
+			name: "задача найдена",
+			id:   1,
+			mockSetup: func(m *MockTaskRepo, id uint) {
+				m.On("GetByID", id).Return(TaskStruct{ID: id, Task: "Task 1", IsDone: true, UserId: 2, Version: 5}, nil)
+			},
+			want: &Task{ID: 1, Task: "Task 1", IsDone: &[]bool{true}[0], UserId: 2, Version: 5},
+		},
+		{
+			name: "задача не найдена",
+			id:   999,
+			mockSetup: func(m *MockTaskRepo, id uint) {
+				m.On("GetByID", id).Return(TaskStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			tt.mockSetup(mockRepo, tt.id)
+
+			service := NewTaskService(mockRepo)
+			result, err := service.GetTask(testAdmin, tt.id)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.ID, result.ID)
+				assert.Equal(t, tt.want.Task, result.Task)
+				assert.Equal(t, *tt.want.IsDone, *result.IsDone)
+				assert.Equal(t, tt.want.UserId, result.UserId)
+				assert.Equal(t, tt.want.Version, result.Version)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -387,9 +505,12 @@ func TestUpdateTask(t *testing.T) {
 }
 
 func TestDeleteTask(t *testing.T) {
+	uintPtr := func(u uint) *uint { return &u }
+
 	tasks := []struct {
 		name      string
 		id        uint
+		version   *uint
 		mockSetup func(m *MockTaskRepo, id uint)
 		wantErr   bool
 	}{
@@ -404,7 +525,7 @@ func TestDeleteTask(t *testing.T) {
 					UserId: 1,
 				}
 				m.On("GetByID", id).Return(existingTask, nil)
-				m.On("Delete", &existingTask).Return(nil)
+				m.On("Delete", &existingTask, mock.Anything).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -429,10 +550,31 @@ func TestDeleteTask(t *testing.T) {
 				}
 				m.On("GetByID", id).Return(existingTask, nil)
 				// ошибка возникает при удалении из бд
-				m.On("Delete", &existingTask).Return(errors.New("db error"))
+				m.On("Delete", &existingTask, mock.Anything).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "версия из If-Match устарела",
+			id:      3,
+			version: uintPtr(1),
+			mockSetup: func(m *MockTaskRepo, id uint) {
+				existingTask := TaskStruct{ID: id, Task: "Task 3", UserId: 1, Version: 2}
+				m.On("GetByID", id).Return(existingTask, nil)
 			},
 			wantErr: true,
 		},
+		{
+			name:    "удаление с совпавшей версией",
+			id:      4,
+			version: uintPtr(2),
+			mockSetup: func(m *MockTaskRepo, id uint) {
+				existingTask := TaskStruct{ID: id, Task: "Task 4", UserId: 1, Version: 2}
+				m.On("GetByID", id).Return(existingTask, nil)
+				m.On("Delete", &existingTask, mock.Anything).Return(nil)
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tasks {
@@ -441,7 +583,7 @@ func TestDeleteTask(t *testing.T) {
 			tt.mockSetup(mockRepo, tt.id)
 
 			service := NewTaskService(mockRepo)
-			err := service.DeleteTask(tt.id)
+			err := service.DeleteTask(testAdmin, tt.id, tt.version)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -453,3 +595,880 @@ func TestDeleteTask(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchTasks(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name      string
+		params    SearchTasksParams
+		mockSetup func(m *MockTaskRepo)
+		want      *TaskSearchPage
+		wantErr   bool
+	}{
+		{
+			name:   "успешный поиск с лимитом по умолчанию",
+			params: SearchTasksParams{Query: "  молоко ", UserId: 1},
+			mockSetup: func(m *MockTaskRepo) {
+				m.On("Search", uint(1), "молоко", 20, 0).Return([]TaskSearchRow{
+					{TaskStruct: TaskStruct{ID: 3, Task: "купить молоко", UserId: 1}, Rank: 0.5, Highlight: "купить <b>молоко</b>"},
+				}, int64(1), nil)
+			},
+			want: &TaskSearchPage{
+				Items: []TaskSearchResult{
+					{Task: Task{ID: 3, Task: "купить молоко", UserId: 1}, Rank: 0.5, Highlight: "купить <b>молоко</b>"},
+				},
+				Total:  1,
+				Limit:  20,
+				Offset: 0,
+			},
+		},
+		{
+			name:   "пагинация передается в репозиторий",
+			params: SearchTasksParams{Query: "task", UserId: 2, Limit: intPtr(5), Offset: intPtr(10)},
+			mockSetup: func(m *MockTaskRepo) {
+				m.On("Search", uint(2), "task", 5, 10).Return([]TaskSearchRow{}, int64(12), nil)
+			},
+			want: &TaskSearchPage{Items: []TaskSearchResult{}, Total: 12, Limit: 5, Offset: 10},
+		},
+		{
+			name:      "ошибка - пустой запрос",
+			params:    SearchTasksParams{Query: "   ", UserId: 1},
+			mockSetup: func(m *MockTaskRepo) {},
+			wantErr:   true,
+		},
+		{
+			name:      "ошибка - не передан user_id",
+			params:    SearchTasksParams{Query: "task"},
+			mockSetup: func(m *MockTaskRepo) {},
+			wantErr:   true,
+		},
+		{
+			name:      "ошибка - слишком большой лимит",
+			params:    SearchTasksParams{Query: "task", UserId: 1, Limit: intPtr(101)},
+			mockSetup: func(m *MockTaskRepo) {},
+			wantErr:   true,
+		},
+		{
+			name:      "ошибка - отрицательный offset",
+			params:    SearchTasksParams{Query: "task", UserId: 1, Offset: intPtr(-1)},
+			mockSetup: func(m *MockTaskRepo) {},
+			wantErr:   true,
+		},
+		{
+			name:   "ошибка при поиске в БД",
+			params: SearchTasksParams{Query: "task", UserId: 1},
+			mockSetup: func(m *MockTaskRepo) {
+				m.On("Search", uint(1), "task", 20, 0).Return(nil, int64(0), errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			tt.mockSetup(mockRepo)
+
+			service := NewTaskService(mockRepo)
+			result, err := service.SearchTasks(testAdmin, tt.params)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.Total, result.Total)
+				assert.Equal(t, tt.want.Limit, result.Limit)
+				assert.Equal(t, tt.want.Offset, result.Offset)
+				assert.Equal(t, len(tt.want.Items), len(result.Items))
+				for i := range result.Items {
+					assert.Equal(t, tt.want.Items[i].Task.ID, result.Items[i].Task.ID)
+					assert.Equal(t, tt.want.Items[i].Task.Task, result.Items[i].Task.Task)
+					assert.Equal(t, tt.want.Items[i].Rank, result.Items[i].Rank)
+					assert.Equal(t, tt.want.Items[i].Highlight, result.Items[i].Highlight)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// поиск видит те же задачи пространств, что и их списки: условие строится из InMemberWorkspaces,
+// а число параметров должно совпадать с тем, что передает репозиторий (userID трижды)
+func TestSearchableBy(t *testing.T) {
+	assert.Contains(t, searchableBy, InMemberWorkspaces)
+	assert.Contains(t, searchableBy, "workspace_id IS NOT NULL")
+	assert.Equal(t, 3, strings.Count(searchableBy, "?"))
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{name: "одно вхождение", text: "buy milk", query: "milk", want: "buy <b>milk</b>"},
+		{name: "без учета регистра", text: "Milk and MILK", query: "milk", want: "<b>Milk</b> and <b>MILK</b>"},
+		{name: "кириллица", text: "Купить молоко", query: "купить", want: "<b>Купить</b> молоко"},
+		{name: "нет совпадений", text: "buy bread", query: "milk", want: "buy bread"},
+		{name: "html в тексте экранируется", text: `<img src=x onerror=alert(1)> milk`, query: "milk",
+			want: "&lt;img src=x onerror=alert(1)&gt; <b>milk</b>"},
+		{name: "html в найденном фрагменте", text: "a <script> b", query: "<script>", want: "a <b>&lt;script&gt;</b> b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlight(tt.text, tt.query))
+		})
+	}
+}
+
+func TestIsUndefinedColumn(t *testing.T) {
+	assert.True(t, isUndefinedColumn(&pgconn.PgError{Code: "42703"}))
+	assert.True(t, isUndefinedColumn(fmt.Errorf("search: %w", &pgconn.PgError{Code: "42703"})))
+	// в тексте ошибки есть имя колонки, но это не "колонки нет" - запасной поиск не включается
+	assert.False(t, isUndefinedColumn(&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout", Where: "search_vector"}))
+	assert.False(t, isUndefinedColumn(errors.New(`column "search_vector" does not exist`)))
+}
+
+func TestMarkHighlight(t *testing.T) {
+	// так отвечает ts_headline с метками hlStart/hlStop
+	headline := "<img src=x onerror=alert(1)> " + hlStart + "milk" + hlStop + " & " + hlStart + "bread" + hlStop
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <b>milk</b> &amp; <b>bread</b>", markHighlight(headline))
+}
+
+// fakeVerified - заглушка проверки подтвержденного email (по id пользователя)
+type fakeVerified map[uint]bool
+
+func (f fakeVerified) IsEmailVerified(userID uint) (bool, error) {
+	verified, ok := f[userID]
+	if !ok {
+		return false, errors.New("user not found")
+	}
+	return verified, nil
+}
+
+func TestCreateTaskRequiresVerifiedEmail(t *testing.T) {
+	checker := fakeVerified{1: true, 2: false}
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(&TaskStruct{ID: 10, Task: "Test", UserId: 1, Version: 1}, nil).Once()
+
+	service := NewTaskService(mockRepo).WithVerifiedEmailRequired(checker)
+
+	task, err := service.CreateTask(testAdmin, CreateTaskParams{Task: "Test", UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), task.ID)
+
+	_, err = service.CreateTask(testAdmin, CreateTaskParams{Task: "Test", UserId: 2})
+	assert.ErrorContains(t, err, "email is not verified")
+
+	_, err = service.CreateTask(testAdmin, CreateTaskParams{Task: "Test", UserId: 3})
+	assert.ErrorContains(t, err, "user not found")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskAccess(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	stranger := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	admin := rbac.Actor{UserID: 3, Role: rbac.RoleAdmin}
+	task := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1}
+	newTask := func() TaskStruct { return task }
+
+	t.Run("владелец видит свою задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+
+		result, err := NewTaskService(mockRepo).GetTask(owner, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), result.ID)
+	})
+
+	t.Run("чужая задача выглядит как несуществующая", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		service := NewTaskService(mockRepo)
+
+		_, err := service.GetTask(stranger, 7)
+		assert.EqualError(t, err, "task not found")
+
+		_, err = service.UpdateTask(stranger, 7, nil, UpdateTaskParams{IsDone: &[]bool{true}[0]})
+		assert.EqualError(t, err, "task not found")
+
+		err = service.DeleteTask(stranger, 7, nil)
+		assert.EqualError(t, err, "task not found")
+
+		// до записи в бд дело не доходит
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("аноним не видит ничего", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		service := NewTaskService(mockRepo)
+
+		_, err := service.GetTask(rbac.Actor{}, 7)
+		assert.EqualError(t, err, "task not found")
+
+		_, err = service.GetTasks(rbac.Actor{})
+		assert.EqualError(t, err, "forbidden")
+	})
+
+	t.Run("админ удаляет чужую задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		mockRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		assert.NoError(t, NewTaskService(mockRepo).DeleteTask(admin, 7, nil))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("список задач обычного пользователя - только свои", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByUser", uint(1)).Return([]TaskStruct{task}, nil)
+
+		result, err := NewTaskService(mockRepo).GetTasks(owner)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockRepo.AssertNotCalled(t, "GetAll")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("нельзя создать задачу другому пользователю", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+
+		_, err := NewTaskService(mockRepo).CreateTask(stranger, CreateTaskParams{Task: "Task", UserId: 1})
+		assert.EqualError(t, err, "forbidden")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("нельзя передать свою задачу другому пользователю", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+
+		_, err := NewTaskService(mockRepo).UpdateTask(owner, 7, nil, UpdateTaskParams{UserId: &stranger.UserID})
+		assert.EqualError(t, err, "forbidden")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("поиск по чужим задачам запрещен", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+
+		_, err := NewTaskService(mockRepo).SearchTasks(stranger, SearchTasksParams{Query: "task", UserId: 1})
+		assert.EqualError(t, err, "forbidden")
+		mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// fakeHistory - журнал аудита в памяти
+type fakeHistory map[uint][]audit.Entry
+
+func (f fakeHistory) EntityHistory(entityType string, entityID uint, limit *int) ([]audit.Entry, error) {
+	return f[entityID], nil
+}
+
+func TestGetTaskHistory(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	stranger := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	history := fakeHistory{
+		7: {{ID: 2, EntityType: audit.EntityTask, EntityID: 7, Action: audit.ActionUpdated}, {ID: 1, EntityType: audit.EntityTask, EntityID: 7, Action: audit.ActionCreated}},
+		8: {{ID: 3, EntityType: audit.EntityTask, EntityID: 8, Action: audit.ActionDeleted}},
+	}
+
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		id      uint
+		wantLen int
+		wantErr string
+	}{
+		{name: "владелец видит историю своей задачи", actor: owner, id: 7, wantLen: 2},
+		{name: "админ видит историю чужой задачи", actor: testAdmin, id: 7, wantLen: 2},
+		{name: "чужая задача выглядит как несуществующая", actor: stranger, id: 7, wantErr: "task not found"},
+		{name: "удаленную задачу видит только админ", actor: testAdmin, id: 8, wantLen: 1},
+		{name: "владелец удаленной задачи ее историю не видит", actor: owner, id: 8, wantErr: "task not found"},
+		{name: "задачи не было совсем", actor: testAdmin, id: 9, wantErr: "task not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 2}, nil)
+			mockRepo.On("GetByID", mock.Anything).Return(TaskStruct{}, gorm.ErrRecordNotFound)
+
+			entries, err := NewTaskService(mockRepo).WithHistory(history).GetTaskHistory(tt.actor, tt.id, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, entries, tt.wantLen)
+		})
+	}
+
+	t.Run("без журнала история недоступна", func(t *testing.T) {
+		_, err := NewTaskService(new(MockTaskRepo)).GetTaskHistory(testAdmin, 7, nil)
+		assert.EqualError(t, err, "task history is not available")
+	})
+}
+
+func TestTaskChangesPassActor(t *testing.T) {
+	actor := rbac.Actor{UserID: 1, Role: rbac.RoleUser, RequestID: "req-1"}
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1}, nil)
+	mockRepo.On("Delete", mock.Anything, actor).Return(nil).Once()
+
+	assert.NoError(t, NewTaskService(mockRepo).DeleteTask(actor, 7, nil))
+	mockRepo.AssertExpectations(t)
+}
+
+// fakeCleaner - запоминает, после удаления каких задач его позвали
+type fakeCleaner struct {
+	cleaned []uint
+}
+
+func (f *fakeCleaner) CleanupTask(taskID uint) {
+	f.cleaned = append(f.cleaned, taskID)
+}
+
+func TestDeleteTaskCleanup(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetByID", uint(7)).Return(TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 2}, nil)
+	mockRepo.On("Delete", mock.Anything, owner).Return(nil).Once()
+	cleaner := &fakeCleaner{}
+	service := NewTaskService(mockRepo).WithCleanup(cleaner)
+
+	// версия не совпала - задача не удалена, убирать нечего
+	stale := uint(1)
+	assert.EqualError(t, service.DeleteTask(owner, 7, &stale), "version mismatch")
+	assert.Empty(t, cleaner.cleaned)
+
+	assert.NoError(t, service.DeleteTask(owner, 7, nil))
+	assert.Equal(t, []uint{7}, cleaner.cleaned)
+}
+
+// fakeCounter - счетчики комментариев; calls - сколько раз считали (должно быть по разу на ответ)
+type fakeCounter struct {
+	counts map[uint]int64
+	calls  int
+}
+
+func (f *fakeCounter) CountByTasks(taskIDs []uint) (map[uint]int64, error) {
+	f.calls++
+	return f.counts, nil
+}
+
+func TestCommentCounts(t *testing.T) {
+	counter := &fakeCounter{counts: map[uint]int64{1: 3}}
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetAll").Return([]TaskStruct{{ID: 1, Task: "A", UserId: 1}, {ID: 2, Task: "B", UserId: 1}, {ID: 3, Task: "C", UserId: 2}}, nil)
+	mockRepo.On("GetByID", uint(1)).Return(TaskStruct{ID: 1, Task: "A", UserId: 1}, nil)
+	service := NewTaskService(mockRepo).WithCommentCounts(counter)
+
+	tasks, err := service.GetTasks(testAdmin)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tasks[0].CommentCount)
+	assert.Equal(t, int64(0), tasks[1].CommentCount)
+	assert.Equal(t, 1, counter.calls) // один запрос на весь список, а не на каждую задачу
+
+	task, err := service.GetTask(testAdmin, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), task.CommentCount)
+}
+
+func TestAssigneeAccess(t *testing.T) {
+	assignee := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	stranger := rbac.Actor{UserID: 4, Role: rbac.RoleUser}
+	newTask := func() TaskStruct {
+		return TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1, Assignees: []TaskAssigneeStruct{{TaskID: 7, UserID: 2}}}
+	}
+
+	t.Run("исполнитель видит задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+
+		result, err := NewTaskService(mockRepo).GetTask(assignee, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{2}, result.Assignees)
+	})
+
+	t.Run("исполнитель меняет статус", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		mockRepo.On("Update", mock.MatchedBy(func(task *TaskStruct) bool { return task.IsDone }), []string{TaskFieldIsDone}, assignee).
+			Return(&TaskStruct{ID: 7, Task: "Task", IsDone: true, UserId: 1, Version: 2}, nil).Once()
+
+		done := true
+		result, err := NewTaskService(mockRepo).UpdateTask(assignee, 7, nil, UpdateTaskParams{IsDone: &done})
+		assert.NoError(t, err)
+		assert.True(t, *result.IsDone)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("исполнитель переносит задачу на доске", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		mockRepo.On("Update", mock.MatchedBy(func(task *TaskStruct) bool { return task.Position == 3 }), []string{TaskFieldPosition}, assignee).
+			Return(&TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 2, Position: 3}, nil).Once()
+		service := NewTaskService(mockRepo)
+
+		position := int64(3)
+		result, err := service.UpdateTask(assignee, 7, nil, UpdateTaskParams{Position: &position})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Position)
+
+		negative := int64(-1)
+		_, err = service.UpdateTask(assignee, 7, nil, UpdateTaskParams{Position: &negative})
+		assert.EqualError(t, err, "position cannot be negative")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("исполнитель не меняет текст, владельца и не удаляет", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+		service := NewTaskService(mockRepo)
+
+		text := "Новый текст"
+		_, err := service.UpdateTask(assignee, 7, nil, UpdateTaskParams{Task: &text})
+		assert.EqualError(t, err, "forbidden")
+
+		_, err = service.UpdateTask(assignee, 7, nil, UpdateTaskParams{UserId: &assignee.UserID})
+		assert.EqualError(t, err, "forbidden")
+
+		assert.EqualError(t, service.DeleteTask(assignee, 7, nil), "forbidden")
+		_, err = service.RestoreTaskRevision(assignee, 7, 1, nil)
+		assert.EqualError(t, err, "forbidden")
+
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("посторонний по-прежнему не видит задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("GetByID", uint(7)).Return(newTask(), nil)
+
+		_, err := NewTaskService(mockRepo).GetTask(stranger, 7)
+		assert.EqualError(t, err, "task not found")
+	})
+}
+
+func TestAssignTask(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	assignee := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	stranger := rbac.Actor{UserID: 4, Role: rbac.RoleUser}
+	task := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1, Assignees: []TaskAssigneeStruct{{TaskID: 7, UserID: 2}}}
+
+	tests := []struct {
+		name       string
+		actor      rbac.Actor
+		userID     uint
+		repoErr    error
+		wantErr    string
+		wantAssign bool
+	}{
+		{name: "владелец назначает исполнителя", actor: owner, userID: 3, wantAssign: true},
+		{name: "админ назначает исполнителя", actor: testAdmin, userID: 3, wantAssign: true},
+		{name: "исполнитель не назначает других", actor: assignee, userID: 3, wantErr: "forbidden"},
+		{name: "посторонний не видит задачу", actor: stranger, userID: 3, wantErr: "task not found"},
+		{name: "владелец не может быть исполнителем", actor: owner, userID: 1, wantErr: "owner cannot be an assignee"},
+		{name: "нулевой пользователь", actor: owner, userID: 0, wantErr: "user_id cannot be 0"},
+		{name: "пользователя нет в бд", actor: owner, userID: 99, repoErr: errors.New("user not found"), wantErr: "user not found", wantAssign: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			mockRepo.On("GetByID", uint(7)).Return(task, nil).Maybe()
+			if tt.wantAssign {
+				mockRepo.On("Assign", mock.Anything, tt.userID, tt.actor).Return(tt.repoErr).Once()
+			}
+
+			err := NewTaskService(mockRepo).AssignTask(tt.actor, 7, tt.userID)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tt.wantAssign {
+				mockRepo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnassignTask(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	assignee := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	other := rbac.Actor{UserID: 3, Role: rbac.RoleUser}
+	task := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1, Assignees: []TaskAssigneeStruct{{TaskID: 7, UserID: 2}, {TaskID: 7, UserID: 3}}}
+
+	tests := []struct {
+		name         string
+		actor        rbac.Actor
+		userID       uint
+		wantErr      string
+		wantUnassign bool
+	}{
+		{name: "владелец снимает исполнителя", actor: owner, userID: 2, wantUnassign: true},
+		{name: "исполнитель снимается сам", actor: assignee, userID: 2, wantUnassign: true},
+		{name: "исполнитель не снимает другого исполнителя", actor: other, userID: 2, wantErr: "forbidden"},
+		{name: "посторонний не видит задачу", actor: rbac.Actor{UserID: 4, Role: rbac.RoleUser}, userID: 4, wantErr: "task not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			mockRepo.On("GetByID", uint(7)).Return(task, nil)
+			if tt.wantUnassign {
+				mockRepo.On("Unassign", mock.Anything, tt.userID, tt.actor).Return(nil).Once()
+			}
+
+			err := NewTaskService(mockRepo).UnassignTask(tt.actor, 7, tt.userID)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Unassign", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// fakeWorkspaces - роли в пространствах: workspaceID -> userID -> роль
+type fakeWorkspaces map[uint]map[uint]rbac.WorkspaceRole
+
+func (f fakeWorkspaces) WorkspaceRole(workspaceID, userID uint) (rbac.WorkspaceRole, error) {
+	return f[workspaceID][userID], nil
+}
+
+func TestWorkspaceTaskAccess(t *testing.T) {
+	wsID := uint(5)
+	// в пространстве 5: 1 - владелец пространства, 2 и 3 - участники, 4 - гость; 6 ушел из пространства
+	workspaces := fakeWorkspaces{5: {1: rbac.WorkspaceOwner, 2: rbac.WorkspaceMember, 3: rbac.WorkspaceMember, 4: rbac.WorkspaceGuest}}
+	wsOwner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	author := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	colleague := rbac.Actor{UserID: 3, Role: rbac.RoleUser}
+	guest := rbac.Actor{UserID: 4, Role: rbac.RoleUser}
+	formerMember := rbac.Actor{UserID: 6, Role: rbac.RoleUser}
+	done := true
+	text := "Новый текст"
+
+	// задачу 7 создал участник 2
+	newTask := func() TaskStruct {
+		return TaskStruct{ID: 7, Task: "Task", UserId: 2, WorkspaceID: &wsID, Version: 1}
+	}
+	newService := func(m *MockTaskRepo) *TaskService {
+		m.On("GetByID", uint(7)).Return(newTask(), nil).Maybe()
+		return NewTaskService(m).WithWorkspaces(workspaces)
+	}
+
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		act     func(s *TaskService, actor rbac.Actor) error
+		wantErr string
+	}{
+		{name: "гость видит задачу пространства", actor: guest, act: func(s *TaskService, a rbac.Actor) error { _, err := s.GetTask(a, 7); return err }},
+		{name: "гость не меняет статус", actor: guest, act: func(s *TaskService, a rbac.Actor) error {
+			_, err := s.UpdateTask(a, 7, nil, UpdateTaskParams{IsDone: &done})
+			return err
+		}, wantErr: "forbidden"},
+		{name: "участник не правит чужую задачу", actor: colleague, act: func(s *TaskService, a rbac.Actor) error {
+			_, err := s.UpdateTask(a, 7, nil, UpdateTaskParams{Task: &text})
+			return err
+		}, wantErr: "forbidden"},
+		{name: "участник не удаляет чужую задачу", actor: colleague, act: func(s *TaskService, a rbac.Actor) error { return s.DeleteTask(a, 7, nil) }, wantErr: "forbidden"},
+		{name: "ушедший из пространства не видит даже свою задачу", actor: formerMember, act: func(s *TaskService, a rbac.Actor) error { _, err := s.GetTask(a, 7); return err }, wantErr: "task not found"},
+		{name: "гостя нельзя назначить исполнителем", actor: author, act: func(s *TaskService, a rbac.Actor) error { return s.AssignTask(a, 7, 4) }, wantErr: "user is not a workspace member"},
+		{name: "постороннего нельзя назначить исполнителем", actor: wsOwner, act: func(s *TaskService, a rbac.Actor) error { return s.AssignTask(a, 7, 9) }, wantErr: "user is not a workspace member"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			err := tt.act(newService(mockRepo), tt.actor)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("автор правит свою задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("Update", mock.Anything, []string{TaskFieldTask}, author).
+			Return(&TaskStruct{ID: 7, Task: text, UserId: 2, WorkspaceID: &wsID, Version: 2}, nil).Once()
+
+		result, err := newService(mockRepo).UpdateTask(author, 7, nil, UpdateTaskParams{Task: &text})
+		require.NoError(t, err)
+		assert.Equal(t, &wsID, result.WorkspaceID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("владелец пространства удаляет чужую задачу", func(t *testing.T) {
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("Delete", mock.Anything, wsOwner).Return(nil).Once()
+
+		assert.NoError(t, newService(mockRepo).DeleteTask(wsOwner, 7, nil))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("владелец пространства передает задачу другому участнику", func(t *testing.T) {
+		colleagueID := colleague.UserID
+		mockRepo := new(MockTaskRepo)
+		mockRepo.On("Update", mock.MatchedBy(func(task *TaskStruct) bool { return task.UserId == colleagueID }), []string{TaskFieldUserId}, wsOwner).
+			Return(&TaskStruct{ID: 7, Task: "Task", UserId: colleagueID, WorkspaceID: &wsID, Version: 2}, nil).Once()
+
+		result, err := newService(mockRepo).UpdateTask(wsOwner, 7, nil, UpdateTaskParams{UserId: &colleagueID})
+		require.NoError(t, err)
+		assert.Equal(t, colleagueID, result.UserId)
+		mockRepo.AssertExpectations(t)
+
+		// но не гостю и не постороннему
+		guestID, strangerID := guest.UserID, uint(9)
+		_, err = newService(new(MockTaskRepo)).UpdateTask(wsOwner, 7, nil, UpdateTaskParams{UserId: &guestID})
+		assert.EqualError(t, err, "user is not a workspace member")
+		_, err = newService(new(MockTaskRepo)).UpdateTask(wsOwner, 7, nil, UpdateTaskParams{UserId: &strangerID})
+		assert.EqualError(t, err, "user is not a workspace member")
+	})
+
+	t.Run("автор-участник не передает свою задачу другому", func(t *testing.T) {
+		colleagueID := colleague.UserID
+		mockRepo := new(MockTaskRepo)
+		_, err := newService(mockRepo).UpdateTask(author, 7, nil, UpdateTaskParams{UserId: &colleagueID})
+		assert.EqualError(t, err, "forbidden")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("права на комментарии и вложения", func(t *testing.T) {
+		service := newService(new(MockTaskRepo))
+		want := map[rbac.Actor]TaskPermissions{
+			wsOwner:   {Contribute: true, Manage: true},
+			author:    {Contribute: true, Manage: true},
+			colleague: {Contribute: true},
+			guest:     {}, // гость только смотрит
+		}
+		for actor, perms := range want {
+			task, got, err := service.GetTaskPermissions(actor, 7)
+			require.NoError(t, err)
+			assert.Equal(t, uint(7), task.ID)
+			assert.Equal(t, perms, got, "user %d", actor.UserID)
+		}
+
+		_, _, err := service.GetTaskPermissions(formerMember, 7)
+		assert.EqualError(t, err, "task not found")
+	})
+}
+
+func TestCreateWorkspaceTask(t *testing.T) {
+	wsID := uint(5)
+	workspaces := fakeWorkspaces{5: {2: rbac.WorkspaceMember, 4: rbac.WorkspaceGuest}}
+
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		userID  uint
+		wantErr string
+	}{
+		{name: "участник создает задачу в пространстве", actor: rbac.Actor{UserID: 2, Role: rbac.RoleUser}, userID: 2},
+		{name: "гость не создает задачи", actor: rbac.Actor{UserID: 4, Role: rbac.RoleUser}, userID: 4, wantErr: "forbidden"},
+		{name: "посторонний не видит пространство", actor: rbac.Actor{UserID: 9, Role: rbac.RoleUser}, userID: 9, wantErr: "workspace not found"},
+		{name: "админ не создает задачу для постороннего", actor: testAdmin, userID: 9, wantErr: "user is not a workspace member"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepo)
+			if tt.wantErr == "" {
+				mockRepo.On("Create", mock.MatchedBy(func(task *TaskStruct) bool { return task.WorkspaceID != nil && *task.WorkspaceID == wsID }), tt.actor).
+					Return(&TaskStruct{ID: 1, Task: "Task", UserId: tt.userID, WorkspaceID: &wsID, Version: 1}, nil).Once()
+			}
+
+			task, err := NewTaskService(mockRepo).WithWorkspaces(workspaces).
+				CreateTask(tt.actor, CreateTaskParams{Task: "Task", UserId: tt.userID, WorkspaceID: &wsID})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &wsID, task.WorkspaceID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetWorkspaceTasks(t *testing.T) {
+	wsID := uint(5)
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("GetByWorkspace", wsID).Return([]TaskStruct{{ID: 7, Task: "Task", UserId: 2, WorkspaceID: &wsID}}, nil)
+	service := NewTaskService(mockRepo).WithWorkspaces(fakeWorkspaces{5: {4: rbac.WorkspaceGuest}})
+
+	tasks, err := service.GetWorkspaceTasks(rbac.Actor{UserID: 4, Role: rbac.RoleUser}, wsID)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	_, err = service.GetWorkspaceTasks(rbac.Actor{UserID: 9, Role: rbac.RoleUser}, wsID)
+	assert.EqualError(t, err, "workspace not found")
+}
+
+// fakePublisher - запоминает опубликованные события
+type fakePublisher struct {
+	published []events.Event
+}
+
+func (f *fakePublisher) Publish(ev events.Event) {
+	f.published = append(f.published, ev)
+}
+
+// eventTypes - типы опубликованных событий по порядку
+func (f *fakePublisher) eventTypes() []string {
+	types := make([]string, 0, len(f.published))
+	for _, ev := range f.published {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+func TestTaskEvents(t *testing.T) {
+	owner := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	task := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1, Assignees: []TaskAssigneeStruct{{TaskID: 7, UserID: 2}}}
+	updated := TaskStruct{ID: 7, Task: "Task", IsDone: true, UserId: 1, Version: 2, Assignees: task.Assignees}
+	assigned := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 1,
+		Assignees: []TaskAssigneeStruct{{TaskID: 7, UserID: 3}, {TaskID: 7, UserID: 2}}}
+
+	mockRepo := new(MockTaskRepo)
+	mockRepo.On("Create", mock.Anything, owner).Return(&task, nil).Once()
+	// GetByID по порядку: UpdateTask; повторное назначение исполнителя 2 (до и после - список не изменился,
+	// события нет); назначение исполнителя 3 (до и после); DeleteTask
+	mockRepo.On("GetByID", uint(7)).Return(task, nil).Times(4)
+	mockRepo.On("GetByID", uint(7)).Return(assigned, nil).Once()
+	mockRepo.On("GetByID", uint(7)).Return(task, nil).Once()
+	mockRepo.On("Update", mock.Anything, []string{TaskFieldIsDone}, owner).Return(&updated, nil).Once()
+	mockRepo.On("Assign", mock.Anything, uint(2), owner).Return(nil).Once()
+	mockRepo.On("Assign", mock.Anything, uint(3), owner).Return(nil).Once()
+	mockRepo.On("Delete", mock.Anything, owner).Return(nil).Once()
+	publisher := &fakePublisher{}
+	service := NewTaskService(mockRepo).WithEvents(publisher)
+
+	_, err := service.CreateTask(owner, CreateTaskParams{Task: "Task", UserId: 1})
+	require.NoError(t, err)
+	isDone := true
+	_, err = service.UpdateTask(owner, 7, nil, UpdateTaskParams{IsDone: &isDone})
+	require.NoError(t, err)
+	require.NoError(t, service.AssignTask(owner, 7, 2))
+	require.NoError(t, service.AssignTask(owner, 7, 3))
+	require.NoError(t, service.DeleteTask(owner, 7, nil))
+
+	assert.Equal(t, []string{events.TaskCreated, events.TaskUpdated, events.TaskUpdated, events.TaskDeleted}, publisher.eventTypes())
+
+	// тело - задача в виде апи-модели, в событии - кому она видна
+	ev := publisher.published[1]
+	assert.Equal(t, uint(7), ev.TaskID)
+	assert.Equal(t, uint(1), ev.OwnerID)
+	assert.Equal(t, []uint{2}, ev.AssigneeIDs)
+	assert.JSONEq(t, `{"id":7,"task":"Task","is_done":true,"user_id":1,"version":2,"position":0,"assignee_ids":[2]}`, string(ev.Data))
+	assert.ElementsMatch(t, []uint{2, 3}, publisher.published[2].AssigneeIDs)
+	assert.JSONEq(t, `{"id":7}`, string(publisher.published[3].Data))
+	mockRepo.AssertExpectations(t)
+}
+
+// fakeOutbox - запоминает события, поставленные в очередь вебхуков
+type fakeOutbox struct {
+	queued []events.Event
+	err    error
+}
+
+func (f *fakeOutbox) Enqueue(_ *gorm.DB, ev events.Event) error {
+	f.queued = append(f.queued, ev)
+	return f.err
+}
+
+func TestTaskWebhookEvents(t *testing.T) {
+	done := TaskStruct{ID: 7, Task: "Task", IsDone: true, UserId: 1, Version: 2}
+	open := TaskStruct{ID: 7, Task: "Task", UserId: 1, Version: 2}
+
+	tests := []struct {
+		name      string
+		eventType string
+		wasDone   bool
+		task      TaskStruct
+		want      []string
+	}{
+		{name: "задача стала выполненной - еще и task.completed", eventType: events.TaskUpdated, task: done, want: []string{events.TaskUpdated, events.TaskCompleted}},
+		{name: "уже выполненная задача - task.completed второй раз не приходит", eventType: events.TaskUpdated, wasDone: true, task: done, want: []string{events.TaskUpdated}},
+		{name: "задачу снова открыли", eventType: events.TaskUpdated, wasDone: true, task: open, want: []string{events.TaskUpdated}},
+		{name: "создание", eventType: events.TaskCreated, task: done, want: []string{events.TaskCreated}},
+		{name: "удаление", eventType: events.TaskDeleted, task: done, want: []string{events.TaskDeleted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, webhookEvents(tt.eventType, tt.wasDone, &tt.task))
+		})
+	}
+
+	t.Run("события уходят в очередь транзакции, каждое со своим id", func(t *testing.T) {
+		outbox := &fakeOutbox{}
+		repo := (&TaskRepo{}).WithOutbox(outbox)
+
+		require.NoError(t, repo.enqueueWebhooks(nil, &done, events.TaskUpdated, events.TaskCompleted))
+		require.Len(t, outbox.queued, 2)
+		assert.Equal(t, events.TaskUpdated, outbox.queued[0].Type)
+		assert.Equal(t, events.TaskCompleted, outbox.queued[1].Type)
+		assert.Equal(t, uint(7), outbox.queued[1].TaskID)
+		assert.NotEqual(t, outbox.queued[0].ID, outbox.queued[1].ID)
+	})
+
+	t.Run("ошибка очереди возвращается - транзакция откатится", func(t *testing.T) {
+		repo := (&TaskRepo{}).WithOutbox(&fakeOutbox{err: errors.New("db error")})
+		assert.EqualError(t, repo.enqueueWebhooks(nil, &done, events.TaskDeleted), "db error")
+	})
+
+	t.Run("без вебхуков - ничего не делаем", func(t *testing.T) {
+		assert.NoError(t, (&TaskRepo{}).enqueueWebhooks(nil, &done, events.TaskDeleted))
+	})
+}
+
+func TestCanSeeEvent(t *testing.T) {
+	wsID := uint(5)
+	workspaces := fakeWorkspaces{5: {1: rbac.WorkspaceOwner, 2: rbac.WorkspaceMember, 4: rbac.WorkspaceGuest}}
+	service := NewTaskService(new(MockTaskRepo)).WithWorkspaces(workspaces)
+
+	personal := events.Event{Type: events.TaskUpdated, TaskID: 7, OwnerID: 1, AssigneeIDs: []uint{2}}
+	inWorkspace := events.Event{Type: events.TaskDeleted, TaskID: 8, OwnerID: 2, WorkspaceID: &wsID}
+
+	tests := []struct {
+		name  string
+		actor rbac.Actor
+		event events.Event
+		want  bool
+	}{
+		{name: "владелец", actor: rbac.Actor{UserID: 1, Role: rbac.RoleUser}, event: personal, want: true},
+		{name: "исполнитель", actor: rbac.Actor{UserID: 2, Role: rbac.RoleUser}, event: personal, want: true},
+		{name: "посторонний", actor: rbac.Actor{UserID: 3, Role: rbac.RoleUser}, event: personal, want: false},
+		{name: "админ", actor: testAdmin, event: personal, want: true},
+		{name: "аноним", actor: rbac.Actor{}, event: personal, want: false},
+		{name: "гость пространства", actor: rbac.Actor{UserID: 4, Role: rbac.RoleUser}, event: inWorkspace, want: true},
+		{name: "не участник пространства", actor: rbac.Actor{UserID: 3, Role: rbac.RoleUser}, event: inWorkspace, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, service.CanSeeEvent(tt.actor, tt.event))
+		})
+	}
+}
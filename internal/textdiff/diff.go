@@ -0,0 +1,99 @@
+package textdiff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// пословный diff двух текстов (LCS по словам):
+//   • текст режется на слова и пробельные промежутки, поэтому склейка Equal+Delete дает старый текст,
+//     а Equal+Insert - новый
+//   • соседние операции одного типа склеиваются
+// тексты задач короткие, поэтому квадратичный LCS здесь достаточно
+
+// типы операций
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Op - кусок текста и что с ним произошло
+type Op struct {
+	Type string
+	Text string
+}
+
+// Diff - операции, превращающие from в to
+func Diff(from, to string) []Op {
+	a, b := tokenize(from), tokenize(to)
+
+	// lcs[i][j] - длина общей подпоследовательности a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = appendOp(ops, OpEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = appendOp(ops, OpDelete, a[i])
+			i++
+		default:
+			ops = appendOp(ops, OpInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = appendOp(ops, OpDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		ops = appendOp(ops, OpInsert, b[j])
+	}
+	return ops
+}
+
+// appendOp - добавляет кусок, склеивая его с предыдущим того же типа
+func appendOp(ops []Op, typ, text string) []Op {
+	if n := len(ops); n > 0 && ops[n-1].Type == typ {
+		ops[n-1].Text += text
+		return ops
+	}
+	return append(ops, Op{Type: typ, Text: text})
+}
+
+// tokenize - слова и пробельные промежутки по порядку
+func tokenize(s string) []string {
+	var tokens []string
+	start := 0
+	for start < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[start:])
+		space := unicode.IsSpace(r)
+		end := start
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if unicode.IsSpace(r) != space {
+				break
+			}
+			end += size
+		}
+		tokens = append(tokens, s[start:end])
+		start = end
+	}
+	return tokens
+}
@@ -0,0 +1,71 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []Op
+	}{
+		{
+			name: "тексты совпадают",
+			from: "Купить хлеб",
+			to:   "Купить хлеб",
+			want: []Op{{OpEqual, "Купить хлеб"}},
+		},
+		{
+			name: "замена слова",
+			from: "Купить хлеб и молоко",
+			to:   "Купить батон и молоко",
+			want: []Op{{OpEqual, "Купить "}, {OpDelete, "хлеб"}, {OpInsert, "батон"}, {OpEqual, " и молоко"}},
+		},
+		{
+			name: "добавление в конец",
+			from: "Купить хлеб",
+			to:   "Купить хлеб завтра",
+			want: []Op{{OpEqual, "Купить хлеб"}, {OpInsert, " завтра"}},
+		},
+		{
+			name: "удаление всего",
+			from: "Купить хлеб",
+			to:   "",
+			want: []Op{{OpDelete, "Купить хлеб"}},
+		},
+		{
+			name: "из пустого",
+			from: "",
+			to:   "Новая задача",
+			want: []Op{{OpInsert, "Новая задача"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Diff(tt.from, tt.to))
+		})
+	}
+}
+
+func TestDiffRestoresBothTexts(t *testing.T) {
+	from := "Позвонить  маме\nи купить хлеб"
+	to := "Позвонить папе\nи  купить свежий хлеб"
+
+	var gotFrom, gotTo strings.Builder
+	for _, op := range Diff(from, to) {
+		if op.Type != OpInsert {
+			gotFrom.WriteString(op.Text)
+		}
+		if op.Type != OpDelete {
+			gotTo.WriteString(op.Text)
+		}
+	}
+	assert.Equal(t, from, gotFrom.String())
+	assert.Equal(t, to, gotTo.String())
+}
@@ -0,0 +1,152 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// одноразовые пароли для двухфакторной аутентификации
+//   • HOTP (RFC 4226): код = HMAC(secret, counter), обрезанный до нескольких цифр
+//   • TOTP (RFC 6238): тот же HOTP, где counter - номер временного шага (по умолчанию 30 секунд)
+// по умолчанию - то, что понимают все приложения-аутентификаторы: SHA1, 6 цифр, 30 секунд
+
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+// размер секрета по умолчанию (160 бит - как рекомендует RFC 4226 для SHA1)
+const SecretSize = 20
+
+type Options struct {
+	Digits    int
+	Period    time.Duration
+	Algorithm Algorithm
+}
+
+var DefaultOptions = Options{Digits: 6, Period: 30 * time.Second, Algorithm: SHA1}
+
+// base32 без паддинга - так секрет записывают в otpauth:// и показывают пользователю
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case SHA1, "":
+		return sha1.New, nil
+	case SHA256:
+		return sha256.New, nil
+	case SHA512:
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q", a)
+}
+
+// HOTP - код для счетчика (RFC 4226, раздел 5.3)
+func HOTP(secret []byte, counter uint64, digits int, alg Algorithm) (string, error) {
+	newHash, err := alg.hash()
+	if err != nil {
+		return "", err
+	}
+	if digits < 6 || digits > 10 {
+		return "", errors.New("digits must be between 6 and 10")
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(newHash, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation: 4 байта со смещения из младших битов последнего байта, без старшего бита
+	offset := sum[len(sum)-1] & 0x0f
+	value := uint64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+
+	var mod uint64 = 1
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+// Step - номер временного шага для момента t (T0 = 0, как в RFC 6238)
+func Step(t time.Time, period time.Duration) uint64 {
+	return uint64(t.Unix()) / uint64(period/time.Second)
+}
+
+// Generate - TOTP-код для момента t
+func Generate(secret []byte, t time.Time, opts Options) (string, error) {
+	return HOTP(secret, Step(t, opts.Period), opts.Digits, opts.Algorithm)
+}
+
+// Validate - проверяет код с допуском skew шагов в обе стороны (часы телефона могут спешить или отставать)
+// возвращает шаг, которому соответствует код: по нему вызывающий отсекает повторное использование
+func Validate(code string, secret []byte, t time.Time, opts Options, skew int) (uint64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != opts.Digits {
+		return 0, false
+	}
+
+	current := Step(t, opts.Period)
+	for i := -skew; i <= skew; i++ {
+		if i < 0 && current < uint64(-i) {
+			continue
+		}
+		step := current + uint64(i)
+		want, err := HOTP(secret, step, opts.Digits, opts.Algorithm)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// NewSecret - новый случайный секрет
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeSecret - секрет в base32 (для ручного ввода в приложение)
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
+
+// URI - otpauth:// ссылка для QR-кода (формат Google Authenticator Key Uri)
+func URI(issuer, account string, secret []byte, opts Options) string {
+	q := url.Values{}
+	q.Set("secret", EncodeSecret(secret))
+	q.Set("issuer", issuer)
+	q.Set("algorithm", string(opts.Algorithm))
+	q.Set("digits", strconv.Itoa(opts.Digits))
+	q.Set("period", strconv.Itoa(int(opts.Period/time.Second)))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
@@ -0,0 +1,139 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// секреты из тестовых векторов RFC 6238 (приложение B): для каждого алгоритма - своей длины
+var (
+	seedSHA1   = []byte("12345678901234567890")
+	seedSHA256 = []byte("12345678901234567890123456789012")
+	seedSHA512 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+// RFC 4226, приложение D
+func TestHOTPVectors(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		got, err := HOTP(seedSHA1, uint64(counter), 6, SHA1)
+		require.NoError(t, err)
+		assert.Equal(t, code, got, "counter=%d", counter)
+	}
+}
+
+// RFC 6238, приложение B
+func TestTOTPVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		step uint64
+		sha1 string
+		sha2 string
+		sha5 string
+	}{
+		{unix: 59, step: 0x1, sha1: "94287082", sha2: "46119246", sha5: "90693936"},
+		{unix: 1111111109, step: 0x23523EC, sha1: "07081804", sha2: "68084774", sha5: "25091201"},
+		{unix: 1111111111, step: 0x23523ED, sha1: "14050471", sha2: "67062674", sha5: "99943326"},
+		{unix: 1234567890, step: 0x273EF07, sha1: "89005924", sha2: "91819424", sha5: "93441116"},
+		{unix: 2000000000, step: 0x3F940AA, sha1: "69279037", sha2: "90698825", sha5: "38618901"},
+		{unix: 20000000000, step: 0x27BC86AA, sha1: "65353130", sha2: "77737706", sha5: "47863826"},
+	}
+
+	for _, tt := range tests {
+		at := time.Unix(tt.unix, 0).UTC()
+		assert.Equal(t, tt.step, Step(at, 30*time.Second), "unix=%d", tt.unix)
+
+		for _, c := range []struct {
+			alg    Algorithm
+			secret []byte
+			want   string
+		}{
+			{SHA1, seedSHA1, tt.sha1},
+			{SHA256, seedSHA256, tt.sha2},
+			{SHA512, seedSHA512, tt.sha5},
+		} {
+			got, err := Generate(c.secret, at, Options{Digits: 8, Period: 30 * time.Second, Algorithm: c.alg})
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got, "unix=%d alg=%s", tt.unix, c.alg)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	code, err := Generate(seedSHA1, at, DefaultOptions)
+	require.NoError(t, err)
+	current := Step(at, DefaultOptions.Period)
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		skew     int
+		wantStep uint64
+		wantOK   bool
+	}{
+		{name: "текущий шаг", code: code, at: at, skew: 1, wantStep: current, wantOK: true},
+		{name: "пробелы вокруг кода", code: " " + code + " ", at: at, skew: 1, wantStep: current, wantOK: true},
+		{name: "часы телефона отстают на шаг", code: code, at: at.Add(30 * time.Second), skew: 1, wantStep: current, wantOK: true},
+		{name: "часы телефона спешат на шаг", code: code, at: at.Add(-30 * time.Second), skew: 1, wantStep: current, wantOK: true},
+		{name: "два шага - уже слишком", code: code, at: at.Add(60 * time.Second), skew: 1},
+		{name: "без допуска - только текущий шаг", code: code, at: at.Add(30 * time.Second), skew: 0},
+		{name: "неверный код", code: "000000", at: at, skew: 1},
+		{name: "не та длина", code: code[:5], at: at, skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.code, seedSHA1, tt.at, DefaultOptions, tt.skew)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantStep, step)
+		})
+	}
+
+	// у самого начала эпохи шаг не уходит в минус
+	code0, err := Generate(seedSHA1, time.Unix(0, 0), DefaultOptions)
+	require.NoError(t, err)
+	step, ok := Validate(code0, seedSHA1, time.Unix(5, 0), DefaultOptions, 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), step)
+}
+
+func TestHOTPErrors(t *testing.T) {
+	_, err := HOTP(seedSHA1, 0, 5, SHA1)
+	assert.Error(t, err)
+	_, err = HOTP(seedSHA1, 0, 6, "MD5")
+	assert.Error(t, err)
+}
+
+func TestURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EncodeSecret(secret))
+
+	uri := URI("WebPet1", "anton@example.com", secret, DefaultOptions)
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/WebPet1:anton@example.com", u.Path)
+	assert.Equal(t, url.Values{
+		"secret":    {"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+		"issuer":    {"WebPet1"},
+		"algorithm": {"SHA1"},
+		"digits":    {"6"},
+		"period":    {"30"},
+	}, u.Query())
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+	assert.Len(t, a, SecretSize)
+	assert.NotEqual(t, a, b)
+}
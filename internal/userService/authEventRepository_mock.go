@@ -0,0 +1,21 @@
+package userService
+
+import "github.com/stretchr/testify/mock"
+
+type MockAuthEventRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuthEventRepo) Create(event *AuthEventStruct) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockAuthEventRepo) ListForUser(userID uint, limit int) ([]AuthEventStruct, error) {
+	args := m.Called(userID, limit)
+	var events []AuthEventStruct
+	if res := args.Get(0); res != nil {
+		events = res.([]AuthEventStruct)
+	}
+	return events, args.Error(1)
+}
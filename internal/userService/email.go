@@ -0,0 +1,56 @@
+package userService
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// валидация и нормализация email
+//   • пробелы по краям обрезаются
+//   • домен приводится к нижнему регистру (он регистронезависим по RFC),
+//     локальная часть сохраняется как есть
+//   • уникальность в бд проверяется без учета регистра (индекс по lower(email))
+
+const (
+	maxEmailLength      = 254 // ограничение длины адреса (RFC 5321)
+	maxEmailLocalLength = 64
+)
+
+// normalizeEmail - проверяет адрес и возвращает его нормализованную форму
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", errors.New("email is empty")
+	}
+
+	if len(email) > maxEmailLength {
+		return "", errors.New("invalid email: too long")
+	}
+
+	// ParseAddress принимает и "Имя <addr>", поэтому дополнительно требуем, чтобы адрес был "голым"
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return "", errors.New("invalid email")
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+
+	if len(local) > maxEmailLocalLength {
+		return "", errors.New("invalid email: local part is too long")
+	}
+
+	// домен должен быть похож на настоящий: хотя бы одна точка, без пустых меток
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", errors.New("invalid email: domain must contain a dot")
+	}
+	for _, label := range labels {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", errors.New("invalid email: bad domain")
+		}
+	}
+
+	return local + "@" + strings.ToLower(domain), nil
+}
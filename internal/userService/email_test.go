@@ -0,0 +1,52 @@
+package userService
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "обычный адрес", in: "user@example.com", want: "user@example.com"},
+		{name: "обрезаются пробелы", in: "  user@example.com\t", want: "user@example.com"},
+		{name: "домен в нижний регистр, локальная часть как есть", in: "John.Doe@Example.COM", want: "John.Doe@example.com"},
+		{name: "плюс-адрес", in: "user+tag@mail.example.org", want: "user+tag@mail.example.org"},
+		{name: "пустой", in: "   ", wantErr: true},
+		{name: "без @", in: "user.example.com", wantErr: true},
+		{name: "с именем", in: "User <user@example.com>", wantErr: true},
+		{name: "домен без точки", in: "user@localhost", wantErr: true},
+		{name: "пустая метка домена", in: "user@example..com", wantErr: true},
+		{name: "метка начинается с дефиса", in: "user@-example.com", wantErr: true},
+		{name: "две @", in: "a@b@example.com", wantErr: true},
+		{name: "слишком длинная локальная часть", in: strings.Repeat("a", maxEmailLocalLength+1) + "@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeEmail(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	assert.True(t, isUniqueViolation(&pgconn.PgError{Code: "23505"}))
+	assert.True(t, isUniqueViolation(fmt.Errorf("create user: %w", &pgconn.PgError{Code: "23505"})))
+	assert.False(t, isUniqueViolation(&pgconn.PgError{Code: "23503"})) // foreign key violation
+	assert.False(t, isUniqueViolation(errors.New("ERROR: duplicate key value violates unique constraint")))
+}
@@ -0,0 +1,264 @@
+package userService
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+)
+
+// защита входа от перебора паролей
+//   • каждая попытка входа по паролю пишется в журнал auth_events (кто, с какого IP, чем закончилась)
+//   • после MaxFailures неудачных попыток подряд вход блокируется на BaseDuration,
+//     каждая следующая неудача после блокировки удваивает срок (но не больше MaxDuration)
+//   • удачный вход сбрасывает счетчик, админ может снять блокировку вручную
+//   • пока аккаунт заблокирован, пароль не проверяется совсем - перебор не продвигается
+//   • вход через OIDC и второй шаг входа (TOTP) пишутся в тот же журнал (RecordAuthEvent),
+//     но на счетчик неудач не влияют
+
+// типы событий журнала
+const (
+	AuthEventLoginSucceeded = "login_succeeded"
+	AuthEventLoginFailed    = "login_failed"
+	AuthEventLocked         = "account_locked"
+	AuthEventUnlocked       = "account_unlocked"
+
+	AuthEventOIDCLoginSucceeded    = "oidc_login_succeeded"
+	AuthEventOIDCFirstFactorPassed = "oidc_first_factor_passed" // провайдер пустил, дальше нужен второй фактор
+	AuthEventOIDCLoginFailed       = "oidc_login_failed"
+	AuthEventMFASucceeded          = "mfa_succeeded"
+	AuthEventMFAFailed             = "mfa_failed"
+)
+
+// причины неудачного входа
+const (
+	LoginFailureUnknownEmail    = "unknown_email"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureLocked          = "account_locked"
+	LoginFailureDisabled        = "account_disabled"
+
+	LoginFailureProviderRejected = "provider_rejected"  // OIDC: провайдер отказал или не обменял код
+	LoginFailureEmailUnverified  = "email_not_verified" // OIDC: email не подтвержден у провайдера или у нас
+	LoginFailureInvalidCode      = "invalid_code"       // второй шаг: неверный код
+	LoginFailureTooManyAttempts  = "too_many_attempts"  // второй шаг: попытки кончились
+)
+
+// сколько событий отдаем админу, если лимит не задан (и больше которого не отдаем)
+const (
+	DefaultAuthEventsLimit = 50
+	MaxAuthEventsLimit     = 500
+)
+
+type LockoutPolicy struct {
+	MaxFailures  int // 0 - блокировка выключена
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+}
+
+// DefaultLockoutPolicy - 5 неудач подряд: блокировка на минуту, дальше 2, 4, 8... минут, но не больше часа
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		MaxFailures:  5,
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+	}
+}
+
+// lockDuration - на сколько заблокировать вход после failures неудач подряд (0 - не блокировать)
+func (p LockoutPolicy) lockDuration(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+
+	d := p.BaseDuration
+	for i := p.MaxFailures; i < failures && d < p.MaxDuration; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDuration)
+}
+
+// LockedError - вход временно заблокирован (Until - когда можно пробовать снова)
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return "account is temporarily locked"
+}
+
+// структура параметров метода CheckCredentials (IP и User-Agent - для журнала)
+type LoginAttempt struct {
+	Email     string
+	Password  string
+	IP        string
+	UserAgent string
+}
+
+// бизнес-модель события журнала
+type AuthEvent struct {
+	ID        uint
+	UserID    *uint
+	Email     string
+	Type      string
+	Reason    string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+// состояние входа пользователя для админа: счетчик, блокировка и последние события
+type LoginStatus struct {
+	FailedLogins int
+	LockedUntil  *time.Time // nil - не заблокирован (истекшая блокировка тоже nil)
+	Events       []AuthEvent
+}
+
+// WithLockoutPolicy - заменяет политику блокировки по умолчанию (например, на политику из конфига)
+func (s *UserService) WithLockoutPolicy(p LockoutPolicy) *UserService {
+	s.lockout = p
+	return s
+}
+
+// WithAuthEvents - подключает журнал входов
+func (s *UserService) WithAuthEvents(r AuthEventRepoInterface) *UserService {
+	s.events = r
+	return s
+}
+
+// recordAuthEvent - журнал не должен ломать вход: если записать не вышло, только пишем в лог
+func (s *UserService) recordAuthEvent(userID uint, eventType, reason string, attempt LoginAttempt) {
+	if s.events == nil {
+		return
+	}
+
+	event := &AuthEventStruct{
+		Email:     truncate(attempt.Email, 255),
+		Type:      eventType,
+		Reason:    reason,
+		IP:        truncate(attempt.IP, 64),
+		UserAgent: truncate(attempt.UserAgent, 512),
+	}
+	if userID != 0 {
+		event.UserID = &userID
+	}
+	if err := s.events.Create(event); err != nil {
+		log.Printf("Failed to record auth event %s for %q: %v", eventType, attempt.Email, err)
+	}
+}
+
+// RecordAuthEvent - событие входа, которое проверял не CheckCredentials (OIDC, второй шаг входа)
+// email не задан - берем у пользователя
+func (s *UserService) RecordAuthEvent(userID uint, eventType, reason string, attempt LoginAttempt) {
+	if s.events == nil {
+		return
+	}
+
+	if attempt.Email == "" && userID != 0 {
+		if dbUser, err := s.repo.GetByID(userID); err == nil {
+			attempt.Email = dbUser.Email
+		}
+	}
+	s.recordAuthEvent(userID, eventType, reason, attempt)
+}
+
+// registerFailedLogin - считает неудачу и блокирует вход, если их набралось слишком много
+func (s *UserService) registerFailedLogin(dbUser UserStruct, attempt LoginAttempt) error {
+	failures, err := s.repo.IncrementFailedLogins(dbUser.ID)
+	if err != nil {
+		return err
+	}
+
+	d := s.lockout.lockDuration(failures)
+	if d == 0 {
+		return nil
+	}
+
+	until := time.Now().Add(d)
+	if err := s.repo.SetLockout(dbUser.ID, failures, &until); err != nil {
+		return err
+	}
+	log.Printf("User %d locked out for %s after %d failed logins", dbUser.ID, d, failures)
+	s.recordAuthEvent(dbUser.ID, AuthEventLocked, "", attempt)
+	return nil
+}
+
+// isLocked - действует ли блокировка сейчас
+func isLocked(dbUser UserStruct) bool {
+	return dbUser.LockedUntil != nil && time.Now().Before(*dbUser.LockedUntil)
+}
+
+// GetLoginStatus - счетчик неудач, блокировка и последние события входа пользователя; только для админа
+func (s *UserService) GetLoginStatus(actor rbac.Actor, id uint, limit int) (*LoginStatus, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.New("forbidden")
+	}
+	if limit < 0 || limit > MaxAuthEventsLimit {
+		return nil, errors.New("invalid limit")
+	}
+	if limit == 0 {
+		limit = DefaultAuthEventsLimit
+	}
+
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	status := &LoginStatus{FailedLogins: dbUser.FailedLogins, Events: []AuthEvent{}}
+	if isLocked(dbUser) {
+		status.LockedUntil = dbUser.LockedUntil
+	}
+
+	if s.events == nil {
+		return status, nil
+	}
+	dbEvents, err := s.events.ListForUser(id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	for _, e := range dbEvents {
+		status.Events = append(status.Events, AuthEvent{
+			ID:        e.ID,
+			UserID:    e.UserID,
+			Email:     e.Email,
+			Type:      e.Type,
+			Reason:    e.Reason,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return status, nil
+}
+
+// Unlock - снимает блокировку входа и сбрасывает счетчик неудач; только для админа
+func (s *UserService) Unlock(actor rbac.Actor, id uint) error {
+	if !actor.IsAdmin() {
+		return errors.New("forbidden")
+	}
+
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return errors.New("user not found")
+	}
+
+	if err := s.repo.SetLockout(id, 0, nil); err != nil {
+		return err
+	}
+
+	log.Printf("User %d unlocked by admin %d", id, actor.UserID)
+	s.recordAuthEvent(id, AuthEventUnlocked, "", LoginAttempt{Email: dbUser.Email})
+	return nil
+}
+
+// truncate - обрезает строку до max символов
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
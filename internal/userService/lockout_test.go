@@ -0,0 +1,234 @@
+package userService
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestLockDuration(t *testing.T) {
+	policy := LockoutPolicy{MaxFailures: 3, BaseDuration: time.Minute, MaxDuration: 10 * time.Minute}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 5, want: 4 * time.Minute},
+		{failures: 6, want: 8 * time.Minute},
+		{failures: 7, want: 10 * time.Minute}, // упираемся в максимум
+		{failures: 1000, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, policy.lockDuration(tt.failures), "failures=%d", tt.failures)
+	}
+
+	// MaxFailures = 0 - блокировка выключена
+	assert.Zero(t, LockoutPolicy{}.lockDuration(100))
+}
+
+func TestCheckCredentialsLockout(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Str0ng-Passw0rd"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	attempt := func(password string) LoginAttempt {
+		return LoginAttempt{Email: "user@example.com", Password: password, IP: "203.0.113.7", UserAgent: "curl/8.0"}
+	}
+	// events - типы и причины записанных событий
+	newService := func(m *MockUserRepo) (*UserService, *[]AuthEventStruct) {
+		events := new(MockAuthEventRepo)
+		var recorded []AuthEventStruct
+		events.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			recorded = append(recorded, *args.Get(0).(*AuthEventStruct))
+		}).Return(nil)
+		service := NewUserService(m).
+			WithLockoutPolicy(LockoutPolicy{MaxFailures: 3, BaseDuration: time.Minute, MaxDuration: time.Hour}).
+			WithAuthEvents(events)
+		return service, &recorded
+	}
+	userID := uint(1)
+
+	t.Run("неудача до порога - только счетчик и событие", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByEmail", "user@example.com").Return(UserStruct{ID: 1, Email: "user@example.com", Password: string(hashed)}, nil)
+		mockRepo.On("IncrementFailedLogins", uint(1)).Return(2, nil)
+		service, recorded := newService(mockRepo)
+
+		_, err := service.CheckCredentials(attempt("wrong"))
+		assert.EqualError(t, err, "invalid credentials")
+		mockRepo.AssertNotCalled(t, "SetLockout", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, []AuthEventStruct{{
+			UserID: &userID, Email: "user@example.com", Type: AuthEventLoginFailed, Reason: LoginFailureInvalidPassword,
+			IP: "203.0.113.7", UserAgent: "curl/8.0",
+		}}, *recorded)
+	})
+
+	t.Run("неудача на пороге блокирует вход", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByEmail", "user@example.com").Return(UserStruct{ID: 1, Email: "user@example.com", Password: string(hashed)}, nil)
+		mockRepo.On("IncrementFailedLogins", uint(1)).Return(4, nil)
+		var lockedUntil *time.Time
+		mockRepo.On("SetLockout", uint(1), 4, mock.Anything).Run(func(args mock.Arguments) {
+			lockedUntil = args.Get(2).(*time.Time)
+		}).Return(nil)
+		service, recorded := newService(mockRepo)
+
+		before := time.Now()
+		_, err := service.CheckCredentials(attempt("wrong"))
+		assert.EqualError(t, err, "invalid credentials")
+
+		// четвертая неудача при пороге 3 - вторая блокировка подряд, срок удваивается
+		assert.WithinRange(t, *lockedUntil, before.Add(2*time.Minute), time.Now().Add(2*time.Minute))
+		assert.Len(t, *recorded, 2)
+		assert.Equal(t, AuthEventLocked, (*recorded)[1].Type)
+	})
+
+	t.Run("заблокированный аккаунт - пароль не проверяется", func(t *testing.T) {
+		until := time.Now().Add(time.Minute)
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByEmail", "user@example.com").
+			Return(UserStruct{ID: 1, Email: "user@example.com", Password: string(hashed), FailedLogins: 3, LockedUntil: &until}, nil)
+		service, recorded := newService(mockRepo)
+
+		// даже верный пароль не пускает
+		_, err := service.CheckCredentials(attempt("Str0ng-Passw0rd"))
+		var locked *LockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, until, locked.Until)
+		assert.EqualError(t, err, "account is temporarily locked")
+
+		// и неверный пароль не увеличивает счетчик
+		_, err = service.CheckCredentials(attempt("wrong"))
+		assert.ErrorAs(t, err, &locked)
+		mockRepo.AssertNotCalled(t, "IncrementFailedLogins", mock.Anything)
+		assert.Len(t, *recorded, 2)
+		assert.Equal(t, LoginFailureLocked, (*recorded)[0].Reason)
+	})
+
+	t.Run("удачный вход после истекшей блокировки сбрасывает счетчик", func(t *testing.T) {
+		expired := time.Now().Add(-time.Second)
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByEmail", "user@example.com").
+			Return(UserStruct{ID: 1, Email: "user@example.com", Password: string(hashed), FailedLogins: 3, LockedUntil: &expired}, nil)
+		mockRepo.On("SetLockout", uint(1), 0, (*time.Time)(nil)).Return(nil)
+		service, recorded := newService(mockRepo)
+
+		user, err := service.CheckCredentials(attempt("Str0ng-Passw0rd"))
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		mockRepo.AssertExpectations(t)
+		assert.Equal(t, AuthEventLoginSucceeded, (*recorded)[0].Type)
+	})
+
+	t.Run("несуществующий email - событие без пользователя", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByEmail", "nobody@example.com").Return(UserStruct{}, gorm.ErrRecordNotFound)
+		service, recorded := newService(mockRepo)
+
+		_, err := service.CheckCredentials(LoginAttempt{Email: "nobody@EXAMPLE.com", Password: "x"})
+		assert.EqualError(t, err, "invalid credentials")
+		assert.Equal(t, []AuthEventStruct{{
+			Email: "nobody@example.com", Type: AuthEventLoginFailed, Reason: LoginFailureUnknownEmail,
+		}}, *recorded)
+	})
+}
+
+func TestLoginStatusAndUnlock(t *testing.T) {
+	admin := rbac.Actor{UserID: 1, Role: rbac.RoleAdmin}
+	user := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	until := time.Now().Add(time.Minute)
+	userID := uint(2)
+
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Email: "user@example.com", FailedLogins: 5, LockedUntil: &until}, nil)
+	mockRepo.On("GetByID", uint(3)).Return(UserStruct{}, gorm.ErrRecordNotFound)
+	mockRepo.On("SetLockout", uint(2), 0, (*time.Time)(nil)).Return(nil)
+	events := new(MockAuthEventRepo)
+	events.On("ListForUser", uint(2), DefaultAuthEventsLimit).Return([]AuthEventStruct{
+		{ID: 10, UserID: &userID, Email: "user@example.com", Type: AuthEventLocked},
+	}, nil)
+	events.On("Create", mock.Anything).Return(nil)
+	service := NewUserService(mockRepo).WithAuthEvents(events)
+
+	status, err := service.GetLoginStatus(admin, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, &LoginStatus{
+		FailedLogins: 5,
+		LockedUntil:  &until,
+		Events:       []AuthEvent{{ID: 10, UserID: &userID, Email: "user@example.com", Type: AuthEventLocked}},
+	}, status)
+
+	// только для админа
+	_, err = service.GetLoginStatus(user, 2, 0)
+	assert.EqualError(t, err, "forbidden")
+	assert.EqualError(t, service.Unlock(user, 2), "forbidden")
+
+	_, err = service.GetLoginStatus(admin, 2, MaxAuthEventsLimit+1)
+	assert.EqualError(t, err, "invalid limit")
+	_, err = service.GetLoginStatus(admin, 3, 0)
+	assert.EqualError(t, err, "user not found")
+	assert.EqualError(t, service.Unlock(admin, 3), "user not found")
+
+	assert.NoError(t, service.Unlock(admin, 2))
+	mockRepo.AssertCalled(t, "SetLockout", uint(2), 0, (*time.Time)(nil))
+	events.AssertCalled(t, "Create", mock.MatchedBy(func(e *AuthEventStruct) bool {
+		return e.Type == AuthEventUnlocked && *e.UserID == 2
+	}))
+}
+
+func TestCheckLockout(t *testing.T) {
+	until := time.Now().Add(time.Minute)
+	expired := time.Now().Add(-time.Minute)
+
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, FailedLogins: 5, LockedUntil: &until}, nil)
+	mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, FailedLogins: 5, LockedUntil: &expired}, nil)
+	mockRepo.On("GetByID", uint(3)).Return(UserStruct{}, gorm.ErrRecordNotFound)
+	service := NewUserService(mockRepo)
+
+	err := service.CheckLockout(1)
+	var locked *LockedError
+	if assert.ErrorAs(t, err, &locked) {
+		assert.Equal(t, until, locked.Until)
+	}
+	// истекшая блокировка уже не мешает
+	assert.NoError(t, service.CheckLockout(2))
+	assert.EqualError(t, service.CheckLockout(3), "user not found")
+	// ничего не меняет - ни счетчик, ни журнал
+	mockRepo.AssertNotCalled(t, "SetLockout", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// вход через OIDC и второй шаг входа пишутся в журнал без счетчика неудач
+func TestRecordAuthEvent(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Email: "user@example.com"}, nil)
+	events := new(MockAuthEventRepo)
+	var recorded []AuthEventStruct
+	events.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, *args.Get(0).(*AuthEventStruct))
+	}).Return(nil)
+	service := NewUserService(mockRepo).WithAuthEvents(events)
+	userID := uint(2)
+
+	// email не передали - берем у пользователя
+	service.RecordAuthEvent(2, AuthEventMFAFailed, LoginFailureInvalidCode, LoginAttempt{IP: "203.0.113.7", UserAgent: "app/1.0"})
+	// пользователь неизвестен - событие без user_id
+	service.RecordAuthEvent(0, AuthEventOIDCLoginFailed, LoginFailureEmailUnverified, LoginAttempt{Email: "new@example.com"})
+
+	assert.Equal(t, []AuthEventStruct{
+		{UserID: &userID, Email: "user@example.com", Type: AuthEventMFAFailed, Reason: LoginFailureInvalidCode, IP: "203.0.113.7", UserAgent: "app/1.0"},
+		{Email: "new@example.com", Type: AuthEventOIDCLoginFailed, Reason: LoginFailureEmailUnverified},
+	}, recorded)
+	mockRepo.AssertNotCalled(t, "IncrementFailedLogins", mock.Anything)
+
+	// журнал не подключен - ничего не делаем
+	NewUserService(mockRepo).RecordAuthEvent(2, AuthEventMFASucceeded, "", LoginAttempt{})
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
@@ -12,6 +12,12 @@ type UserStruct struct {
 	Tasks []taskService.TaskStruct `gorm:"foreignkey:UserID"`
 	Email string 
 	Password string 
+	Version uint `gorm:"not null;default:1"` // версия строки (для If-Match / ETag)
+	EmailVerifiedAt *time.Time // nil - адрес еще не подтвержден
+	Role string `gorm:"not null;default:user"` // rbac.Role: user или admin
+	DisabledAt *time.Time // не nil - аккаунт отключен админом, войти нельзя
+	FailedLogins int `gorm:"not null;default:0"` // неудачные входы подряд (сбрасывается после удачного)
+	LockedUntil *time.Time // вход временно заблокирован после серии неудачных попыток
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -19,4 +25,30 @@ type UserStruct struct {
 
 func (UserStruct) TableName() string {
     return "user_structs"  // как в миграции
-}
\ No newline at end of file
+}
+
+// имена колонок, которые можно частично обновлять (маска для UserRepo.Update)
+const (
+	UserFieldEmail           = "email"
+	UserFieldPassword        = "password"
+	UserFieldEmailVerifiedAt = "email_verified_at"
+	UserFieldRole            = "role"
+	UserFieldDisabledAt      = "disabled_at"
+)
+
+// событие входа (журнал попыток для админа)
+// UserID пустой, если вход был на несуществующий email
+type AuthEventStruct struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    *uint
+	Email     string `gorm:"not null"`
+	Type      string `gorm:"not null"` // AuthEvent*
+	Reason    string // причина неудачи (LoginFailure*)
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+func (AuthEventStruct) TableName() string {
+	return "auth_events" // как в миграции
+}
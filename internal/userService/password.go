@@ -0,0 +1,221 @@
+package userService
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// политика паролей
+//   • минимальная длина (в символах)
+//   • максимальная длина в байтах - bcrypt молча обрезает все после 72-го байта,
+//     поэтому более длинные пароли не принимаем, чтобы "хвост" не оказался бесполезным
+//   • сколько разных классов символов нужно (строчные, заглавные, цифры, прочие)
+//   • пароль не должен совпадать с email или содержать его локальную часть
+//   • пароль не должен быть из списка распространенных (проверка офлайн, по встроенному файлу
+//     на 100 тысяч паролей - см. commonPasswordsGz)
+
+// bcrypt использует только первые 72 байта пароля
+const bcryptMaxBytes = 72
+
+type PasswordPolicy struct {
+	MinLength   int
+	MaxBytes    int
+	MinClasses  int
+	ForbidEmail bool
+	CheckCommon bool
+}
+
+// DefaultPasswordPolicy - политика по умолчанию
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:   8,
+		MaxBytes:    bcryptMaxBytes,
+		MinClasses:  2,
+		ForbidEmail: true,
+		CheckCommon: true,
+	}
+}
+
+// коды правил (приходят клиенту в списке нарушений)
+const (
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleCharClasses    = "character_classes"
+	RuleContainsEmail  = "contains_email"
+	RuleCommonPassword = "common_password"
+)
+
+// одно нарушенное правило
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// ValidationError - структурированная ошибка валидации: поле + список всех нарушенных правил
+type ValidationError struct {
+	Field      string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Field, strings.Join(rules, ", "))
+}
+
+// Validate - проверяет пароль по всем правилам сразу и возвращает *ValidationError со списком нарушений
+func (p PasswordPolicy) Validate(password, email string) error {
+	var violations []Violation
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, Violation{
+			Rule:    RuleMinLength,
+			Message: fmt.Sprintf("password must be at least %d characters long", p.MinLength),
+		})
+	}
+
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 || maxBytes > bcryptMaxBytes {
+		maxBytes = bcryptMaxBytes
+	}
+	if len(password) > maxBytes {
+		violations = append(violations, Violation{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("password must be at most %d bytes long", maxBytes),
+		})
+	}
+
+	if classes := charClasses(password); classes < p.MinClasses {
+		violations = append(violations, Violation{
+			Rule:    RuleCharClasses,
+			Message: fmt.Sprintf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.MinClasses),
+		})
+	}
+
+	if p.ForbidEmail && containsEmail(password, email) {
+		violations = append(violations, Violation{
+			Rule:    RuleContainsEmail,
+			Message: "password must not contain the email address",
+		})
+	}
+
+	if p.CheckCommon && isCommonPassword(password) {
+		violations = append(violations, Violation{
+			Rule:    RuleCommonPassword,
+			Message: "password is too common",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Field: "password", Violations: violations}
+	}
+	return nil
+}
+
+// charClasses - сколько классов символов встречается в пароле
+func charClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, has := range []bool{lower, upper, digit, other} {
+		if has {
+			count++
+		}
+	}
+	return count
+}
+
+// минимальная длина локальной части email, начиная с которой проверяем ее вхождение в пароль
+// (иначе адрес вроде a@x.io запрещал бы любой пароль с буквой "a")
+const minEmailPartLength = 3
+
+// containsEmail - совпадает ли пароль с email или содержит его локальную часть
+func containsEmail(password, email string) bool {
+	if email == "" {
+		return false
+	}
+	password = strings.ToLower(password)
+	email = strings.ToLower(email)
+	if strings.Contains(password, email) {
+		return true
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	return len(local) >= minEmailPartLength && strings.Contains(password, local)
+}
+
+// список распространенных паролей (100 тысяч, по одному в строке, в нижнем регистре, gzip, ~240 КБ)
+// собран из словаря паролей zxcvbn (MIT) по порядку частоты: сначала сами 7141 пароль из утечек,
+// затем их варианты с типичными "хвостами" 1, 12, 123, 1234, 12345, !, 1!, 123!, 2, 7, 01, 11, 00, 69, 2024
+// (только для паролей с буквами) - пока список не дорос до 100 тысяч
+//
+//go:embed data/common-passwords.txt.gz
+var commonPasswordsGz []byte
+
+// commonPasswords - список распаковывается один раз, при первом обращении
+var commonPasswords = sync.OnceValues(func() (map[string]struct{}, error) {
+	return loadCommonPasswords(commonPasswordsGz)
+})
+
+// LoadCommonPasswords - распаковать список заранее: битый встроенный файл должен ронять приложение при старте,
+// а не выключать проверку
+func LoadCommonPasswords() error {
+	_, err := commonPasswords()
+	return err
+}
+
+// isCommonPassword - есть ли пароль (без учета регистра) в списке распространенных
+func isCommonPassword(password string) bool {
+	passwords, err := commonPasswords()
+	if err != nil {
+		// молча пропускать любой пароль нельзя; при старте это уже проверил LoadCommonPasswords
+		panic(fmt.Sprintf("common passwords list is broken: %v", err))
+	}
+	_, ok := passwords[strings.ToLower(password)]
+	return ok
+}
+
+func loadCommonPasswords(data []byte) (map[string]struct{}, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[line] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(passwords) == 0 {
+		return nil, errors.New("list is empty")
+	}
+	return passwords, nil
+}
@@ -0,0 +1,115 @@
+package userService
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+
+	tests := []struct {
+		name      string
+		policy    PasswordPolicy
+		password  string
+		email     string
+		wantRules []string // nil - пароль подходит
+	}{
+		{name: "хороший пароль", policy: policy, password: "Correct-Horse-42", email: "user@example.com"},
+		{name: "короткий", policy: policy, password: "aB3", email: "user@example.com", wantRules: []string{RuleMinLength}},
+		{name: "один класс символов", policy: policy, password: "onlyletterslong", email: "user@example.com", wantRules: []string{RuleCharClasses}},
+		{
+			name:      "длиннее 72 байт",
+			policy:    policy,
+			password:  strings.Repeat("aB", 37), // 74 байта
+			email:     "user@example.com",
+			wantRules: []string{RuleMaxLength},
+		},
+		{
+			name:      "многобайтовые символы считаются в байтах",
+			policy:    policy,
+			password:  strings.Repeat("пароль", 6) + "1", // 36 символов, но 73 байта
+			email:     "user@example.com",
+			wantRules: []string{RuleMaxLength},
+		},
+		{name: "содержит локальную часть email", policy: policy, password: "Johnny-2024!", email: "johnny@example.com", wantRules: []string{RuleContainsEmail}},
+		{name: "совпадает с email", policy: policy, password: "Bob@Example.com", email: "bob@example.com", wantRules: []string{RuleContainsEmail}},
+		{name: "короткая локальная часть не мешает", policy: policy, password: "ab-Cd-Ef-12", email: "ab@example.com"},
+		{name: "распространенный пароль", policy: policy, password: "password", email: "user@example.com", wantRules: []string{RuleCharClasses, RuleCommonPassword}},
+		{name: "распространенный без учета регистра", policy: PasswordPolicy{MinLength: 1, CheckCommon: true}, password: "QWERTY", wantRules: []string{RuleCommonPassword}},
+		{
+			name:      "сразу несколько нарушений",
+			policy:    policy,
+			password:  "123456",
+			email:     "user@example.com",
+			wantRules: []string{RuleMinLength, RuleCharClasses, RuleCommonPassword},
+		},
+		{
+			name:     "проверки можно выключить",
+			policy:   PasswordPolicy{MinLength: 4},
+			password: "qwerty",
+			email:    "qwerty@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password, tt.email)
+			if tt.wantRules == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *ValidationError
+			assert.True(t, errors.As(err, &verr))
+			assert.Equal(t, "password", verr.Field)
+
+			rules := make([]string, 0, len(verr.Violations))
+			for _, v := range verr.Violations {
+				rules = append(rules, v.Rule)
+				assert.NotEmpty(t, v.Message)
+			}
+			assert.Equal(t, tt.wantRules, rules)
+		})
+	}
+}
+
+func TestCommonPasswordsListIsBundled(t *testing.T) {
+	// встроенный список должен распаковываться, и в нем ровно столько паролей, сколько заявлено в password.go
+	list, err := loadCommonPasswords(commonPasswordsGz)
+	require.NoError(t, err)
+	assert.Len(t, list, 100000)
+	assert.NoError(t, LoadCommonPasswords())
+	assert.True(t, isCommonPassword("Password123!"))
+	assert.True(t, isCommonPassword("123456"))
+	assert.False(t, isCommonPassword("Correct-Horse-42"))
+}
+
+// битый список не должен молча выключать проверку
+func TestLoadCommonPasswordsBroken(t *testing.T) {
+	var empty bytes.Buffer
+	zw := gzip.NewWriter(&empty)
+	require.NoError(t, zw.Close())
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "не gzip", data: []byte("password\n123456\n")},
+		{name: "обрезанный gzip", data: commonPasswordsGz[:len(commonPasswordsGz)/2]},
+		{name: "пустой список", data: empty.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := loadCommonPasswords(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, list)
+		})
+	}
+}
@@ -2,30 +2,67 @@ package userService
 
 import (
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
 	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// код ошибки Postgres (SQLSTATE) для нарушения уникальности
+const pgUniqueViolation = "23505"
+
+// isUniqueViolation - проверяет, что бд отклонила запись из-за дубликата
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
 type UserRepoInterface interface {
-	Create(user *UserStruct) (*UserStruct, error)
+	Create(user *UserStruct, actor rbac.Actor) (*UserStruct, error)
 	GetAll() ([]UserStruct, error)
 	GetByID(id uint) (UserStruct, error)
-	GetTasksForUser(userID uint) ([]taskService.TaskStruct, error)
-	Update(user *UserStruct) (*UserStruct, error)
-	Delete(user *UserStruct) error
+	GetByEmail(email string) (UserStruct, error)
+	GetTasksForUser(userID uint, role string) ([]taskService.TaskStruct, error)
+	GetOwnedTaskIDs(userID uint) ([]uint, error)
+	Update(user *UserStruct, fields []string, actor rbac.Actor) (*UserStruct, error)
+	IncrementFailedLogins(id uint) (int, error)
+	SetLockout(id uint, failedLogins int, lockedUntil *time.Time) error
+	Delete(user *UserStruct, actor rbac.Actor) error
 }
 
 type UserRepo struct{}
 
-func (r *UserRepo) Create(user *UserStruct) (*UserStruct, error) {
-	err := db.DB.Create(user).Error
+// Create - изменения пользователей пишутся в журнал аудита той же транзакцией
+// actor без пользователя - регистрация: пользователь создает себя сам, он и записывается автором
+func (r *UserRepo) Create(user *UserStruct, actor rbac.Actor) (*UserStruct, error) {
+	if user.Version == 0 {
+		user.Version = 1 // новая строка всегда начинается с первой версии
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if actor.UserID == 0 {
+			actor.UserID = user.ID
+		}
+		return audit.Write(tx, audit.Record{
+			EntityType: audit.EntityUser,
+			EntityID:   user.ID,
+			Action:     audit.ActionCreated,
+			Actor:      actor,
+			After:      user.snapshot(),
+		})
+	})
 	if err != nil {
-		// првоеряем ошибку бд на дупликат бд
-		if strings.Contains(err.Error(), "duplicate key") {
+		// првоеряем ошибку бд на дупликат (уникальный индекс по lower(email))
+		if isUniqueViolation(err) {
 			return nil, errors.New("email already exists")
 		}
 		return nil, err
@@ -60,35 +97,195 @@ func (r *UserRepo) GetByID(id uint) (UserStruct, error) {
 	return user, nil
 }
 
-func (r *UserRepo) GetTasksForUser(userID uint) ([]taskService.TaskStruct, error) {
+// GetByEmail - поиск без учета регистра (совпадает с уникальным индексом по lower(email))
+func (r *UserRepo) GetByEmail(email string) (UserStruct, error) {
+	var user UserStruct
+
+	err := db.DB.First(&user, "lower(email) = lower(?)", email).Error
+	if err != nil {
+		return UserStruct{}, err
+	}
+	return user, nil
+}
+
+// GetTasksForUser - задачи пользователя по его роли в них (TaskRole*, "" - все: и свои, и назначенные)
+// задачи рабочих пространств, из которых пользователь ушел, сюда не попадают
+func (r *UserRepo) GetTasksForUser(userID uint, role string) ([]taskService.TaskStruct, error) {
 	var user UserStruct
 
-	// Загружает пользователя вместе со всеми его задачами одним запросом
-	err := db.DB.Preload("Tasks").First(&user, userID).Error
+	// сначала убеждаемся, что пользователь есть (иначе пустой список не отличить от 404)
+	err := db.DB.First(&user, userID).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("user not found")
 		}
-		return nil, err		
+		return nil, err
 	}
-	return user.Tasks, nil // возвращаем все таски пользователя (Tasks - поле в модели бд (слайс тасок))
+
+	const assigned = "id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)"
+	query := db.DB.Preload("Assignees").Where(taskService.InMemberWorkspaces, userID).Order("id")
+	switch role {
+	case TaskRoleOwner:
+		query = query.Where("user_id = ?", userID)
+	case TaskRoleAssignee:
+		query = query.Where(assigned, userID)
+	default:
+		query = query.Where("user_id = ? OR "+assigned, userID, userID)
+	}
+
+	var tasks []taskService.TaskStruct
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
-func (r *UserRepo) Update(user *UserStruct) (*UserStruct, error) {
-	user.UpdatedAt = time.Now()
-	err := db.DB.Save(&user).Error 
+// Update - частичное обновление: меняются только колонки из fields (UserField*) + updated_at и version,
+// и только если версия в бд все еще равна user.Version; возвращает свежую строку (RETURNING *)
+func (r *UserRepo) Update(user *UserStruct, fields []string, actor rbac.Actor) (*UserStruct, error) {
+	updated := *user
+	updated.UpdatedAt = time.Now()
+	updated.Version = user.Version + 1
+
+	columns := append([]string{"updated_at", "version"}, fields...)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// состояние "до" для журнала - из бд (сервис уже поменял поля в user)
+		var before UserStruct
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, "id = ?", user.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("version mismatch") // пользователя успели удалить
+			}
+			return err
+		}
+
+		res := tx.Model(&updated).
+			Clauses(clause.Returning{}).
+			Where("version = ?", user.Version).
+			Select(columns).
+			Updates(&updated)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("version mismatch")
+		}
+
+		// хэш пароля в журнал не пишем - только отметку, что пароль сменился
+		var redacted []string
+		if slices.Contains(fields, UserFieldPassword) {
+			redacted = append(redacted, UserFieldPassword)
+		}
+		return audit.Write(tx, audit.Record{
+			EntityType: audit.EntityUser,
+			EntityID:   updated.ID,
+			Action:     audit.ActionUpdated,
+			Actor:      actor,
+			Before:     before.snapshot(),
+			After:      updated.snapshot(),
+			Redacted:   redacted,
+		})
+	})
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("email already exists")
+		}
 		return nil, err
 	}
-	return user, nil
+	return &updated, nil
+}
+
+// GetOwnedTaskIDs - id задач, созданных пользователем (при удалении пользователя каскад удалит их вместе с ним)
+func (r *UserRepo) GetOwnedTaskIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := db.DB.Model(&taskService.TaskStruct{}).Where("user_id = ?", userID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *UserRepo) Delete(user *UserStruct, actor rbac.Actor) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		user.DeletedAt = &now
+		res := tx.Where("version = ?", user.Version).Delete(user)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("version mismatch")
+		}
+
+		return audit.Write(tx, audit.Record{
+			EntityType: audit.EntityUser,
+			EntityID:   user.ID,
+			Action:     audit.ActionDeleted,
+			Actor:      actor,
+			Before:     user.snapshot(),
+		})
+	})
+}
+
+// snapshot - пользователь для журнала аудита (поля как в API, без хэша пароля)
+func (u *UserStruct) snapshot() audit.Snapshot {
+	return audit.Snapshot{
+		"id":                u.ID,
+		"email":             u.Email,
+		"role":              u.Role,
+		"email_verified_at": u.EmailVerifiedAt,
+		"disabled_at":       u.DisabledAt,
+		"version":           u.Version,
+	}
+}
+
+// счетчик неудачных входов и блокировка - служебное состояние, а не данные пользователя,
+// поэтому версия строки (и ETag) при их изменении не растет
+
+// IncrementFailedLogins - атомарно увеличивает счетчик неудачных входов и возвращает новое значение
+// (параллельные попытки не теряются)
+func (r *UserRepo) IncrementFailedLogins(id uint) (int, error) {
+	var user UserStruct
+	res := db.DB.Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_logins"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failed_logins", gorm.Expr("failed_logins + 1"))
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, errors.New("user not found")
+	}
+	return user.FailedLogins, nil
+}
+
+// SetLockout - задает счетчик неудачных входов и срок блокировки (0 и nil - сброс)
+func (r *UserRepo) SetLockout(id uint, failedLogins int, lockedUntil *time.Time) error {
+	return db.DB.Model(&UserStruct{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{"failed_logins": failedLogins, "locked_until": lockedUntil}).Error
+}
+
+// repo-слой для журнала входов
+
+type AuthEventRepoInterface interface {
+	Create(event *AuthEventStruct) error
+	ListForUser(userID uint, limit int) ([]AuthEventStruct, error)
 }
 
-func (r *UserRepo) Delete(user *UserStruct) error {
-	now := time.Now()
-	user.DeletedAt = &now
-	err := db.DB.Delete(user).Error
+type AuthEventRepo struct{}
+
+func (r *AuthEventRepo) Create(event *AuthEventStruct) error {
+	return db.DB.Create(event).Error
+}
+
+// ListForUser - последние события пользователя (сначала новые)
+func (r *AuthEventRepo) ListForUser(userID uint, limit int) ([]AuthEventStruct, error) {
+	var events []AuthEventStruct
+	err := db.DB.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&events).Error
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-}
\ No newline at end of file
+	return events, nil
+}
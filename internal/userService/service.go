@@ -2,8 +2,11 @@ package userService
 
 import (
 	"errors"
+	"log"
 	"strings"
+	"time"
 
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -14,26 +17,116 @@ type CreateUserParams struct {
     Password string 
 }
 
-// структура параметров метода UpdateUser
+// структура параметров метода UpdateUser (маска полей: в бд уходят только переданные)
+// пароль здесь не меняется - для этого есть ChangePassword с проверкой текущего пароля
 type UpdateUserParams struct {
     Email    *string  // nil если не обновлять
-    Password *string  // nil если не обновлять
+}
+
+// структура параметров метода ChangePassword
+type ChangePasswordParams struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// SessionRevoker - отзывает все сессии и refresh-токены пользователя
+// (вызывается после смены пароля, чтобы старые входы перестали работать)
+type SessionRevoker interface {
+	RevokeUserSessions(userID uint) error
+}
+
+// EmailVerifier - отправляет письмо со ссылкой для подтверждения email
+// (вызывается после регистрации и после смены email)
+type EmailVerifier interface {
+	SendEmailVerification(user User) error
 }
 
 // бизнес-модель, которую возвращает сервис
 type User struct {
 	ID uint
 	Email string
+	Version uint
+	EmailVerifiedAt *time.Time // nil - email не подтвержден
+	Role rbac.Role
+	DisabledAt *time.Time // не nil - аккаунт отключен
+}
+
+// toUser - маппит бд-модель в бизнес-модель
+func toUser(dbUser *UserStruct) *User {
+	role := rbac.Role(dbUser.Role)
+	if role == "" {
+		role = rbac.RoleUser // строка из бд до миграции с ролями
+	}
+	return &User{
+		ID: dbUser.ID,
+		Email: dbUser.Email,
+		Version: dbUser.Version,
+		EmailVerifiedAt: dbUser.EmailVerifiedAt,
+		Role: role,
+		DisabledAt: dbUser.DisabledAt,
+	}
 }
 
 type UserService struct {
-	repo UserRepoInterface
+	repo     UserRepoInterface
+	policy   PasswordPolicy
+	sessions SessionRevoker // nil - отзывать нечего
+	verifier EmailVerifier  // nil - письма для подтверждения не отправляются
+	lockout  LockoutPolicy
+	events   AuthEventRepoInterface // nil - журнал входов не ведется
+	comments taskService.CommentCounter // nil - у задач пользователя нет счетчиков комментариев
+	cleaners []taskService.TaskCleaner // вызываются для задач, удаленных вместе с пользователем
 }
 
 func NewUserService(r UserRepoInterface) *UserService {
-	return &UserService{repo: r}
+	return &UserService{repo: r, policy: DefaultPasswordPolicy(), lockout: DefaultLockoutPolicy()}
+}
+
+// WithPasswordPolicy - заменяет политику паролей по умолчанию (например, на политику из конфига)
+func (s *UserService) WithPasswordPolicy(p PasswordPolicy) *UserService {
+	s.policy = p
+	return s
+}
+
+// WithSessionRevoker - подключает отзыв сессий при смене пароля
+func (s *UserService) WithSessionRevoker(r SessionRevoker) *UserService {
+	s.sessions = r
+	return s
 }
 
+// WithCommentCounts - заполнять число комментариев у задач пользователя (GetTasksForUser)
+func (s *UserService) WithCommentCounts(c taskService.CommentCounter) *UserService {
+	s.comments = c
+	return s
+}
+
+// WithTaskCleanup - что еще убрать за задачами удаленного пользователя (их строки удаляет каскад в бд)
+func (s *UserService) WithTaskCleanup(c taskService.TaskCleaner) *UserService {
+	s.cleaners = append(s.cleaners, c)
+	return s
+}
+
+// WithEmailVerifier - подключает отправку писем для подтверждения email
+func (s *UserService) WithEmailVerifier(v EmailVerifier) *UserService {
+	s.verifier = v
+	return s
+}
+
+// sendVerification - письмо не должно ломать регистрацию: если отправить не вышло, только пишем в лог
+// (ссылку можно будет запросить еще раз)
+func (s *UserService) sendVerification(user User) {
+	if s.verifier == nil {
+		return
+	}
+	if err := s.verifier.SendEmailVerification(user); err != nil {
+		log.Printf("Failed to send email verification to user %d: %v", user.ID, err)
+	}
+}
+
+// dummyHash - хэш для сравнения, когда пользователя нет: ответ занимает столько же времени,
+// и по нему нельзя понять, зарегистрирован ли email
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
 // функция хеширования пароля
 func hashPass(password string) (string, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -43,15 +136,23 @@ func hashPass(password string) (string, error) {
 	return string(hashed), nil
 }
 
-func (s *UserService) CreateUser(params CreateUserParams) (*User, error) {
-	if strings.TrimSpace(params.Email) == "" {
-		return nil, errors.New("email is empty")
+// actor - кто создает (для журнала аудита); при регистрации он пустой - автором станет сам пользователь
+func (s *UserService) CreateUser(actor rbac.Actor, params CreateUserParams) (*User, error) {
+	// проверяем и нормализуем email (trim + домен в нижнем регистре)
+	email, err := normalizeEmail(params.Email)
+	if err != nil {
+		return nil, err
 	}
 
 	if strings.TrimSpace(params.Password) == "" {
 		return nil, errors.New("password is empty")
 	}
 
+	// проверяем пароль по политике (возвращает все нарушенные правила сразу)
+	if err := s.policy.Validate(params.Password, email); err != nil {
+		return nil, err
+	}
+
 	// хешируем пароль 
 	hashedPassword, err := hashPass(params.Password)
 	if err != nil {
@@ -60,23 +161,57 @@ func (s *UserService) CreateUser(params CreateUserParams) (*User, error) {
 
 	// создаем бд-модель (приватная)
 	dbUser := &UserStruct{
-		Email: params.Email,
+		Email: email,
 		Password: hashedPassword, // передаю в модель бд захешировнный пароль
 	}
 
-	createdUser, err := s.repo.Create(dbUser)
+	createdUser, err := s.repo.Create(dbUser, actor)
 	if err != nil {
 		return nil, err
 	}
 
 	// маппим бд-модель в бизнес-модель 
-	return &User{
-		ID: createdUser.ID,
-		Email: createdUser.Email,
-	}, nil
+	user := toUser(createdUser)
+
+	// новый адрес еще не подтвержден - отправляем ссылку
+	s.sendVerification(*user)
+
+	return user, nil
+}
+
+// CreateExternalUser - создает пользователя, который вошел через внешнего провайдера (OIDC)
+// пароля нет (войти по паролю нельзя, пока его не задать через сброс пароля),
+// email уже подтвержден провайдером, поэтому письмо не отправляем
+func (s *UserService) CreateExternalUser(email string) (*User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dbUser := &UserStruct{
+		Email: email,
+		EmailVerifiedAt: &now,
+	}
+
+	createdUser, err := s.repo.Create(dbUser, rbac.Actor{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(createdUser), nil
 }
 
-func (s *UserService) GetUsers() ([]User, error) {
+// права доступа:
+//   • свой профиль (и свои задачи) пользователь видит и меняет сам, чужие - только админ;
+//     чужой пользователь для обычного пользователя выглядит как несуществующий ("user not found")
+//   • список пользователей, удаление, роли и отключение аккаунтов - только админ ("forbidden")
+
+func (s *UserService) GetUsers(actor rbac.Actor) ([]User, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.New("forbidden")
+	}
+
 	dbUsers, err := s.repo.GetAll()
 	if err != nil {
 		return nil, err
@@ -85,16 +220,27 @@ func (s *UserService) GetUsers() ([]User, error) {
 	// маппим бд-модель в бизнес-модель
 	users := make([]User, 0, len(dbUsers))
 	for _, dbUser := range dbUsers {
-		users = append(users, User{
-			ID: dbUser.ID,
-			Email: dbUser.Email,
-		})
+		users = append(users, *toUser(&dbUser))
 	}
 	return users, nil
 }
 
-func (s *UserService) GetTasksForUser(userID uint) ([]taskService.Task, error) {
-	dbTasks, err := s.repo.GetTasksForUser(userID)
+// роль пользователя в задаче (фильтр GetTasksForUser)
+const (
+	TaskRoleOwner    = "owner"    // создал задачу
+	TaskRoleAssignee = "assignee" // назначен исполнителем
+)
+
+// GetTasksForUser - задачи пользователя: role - TaskRole*, "" - и свои, и назначенные
+func (s *UserService) GetTasksForUser(actor rbac.Actor, userID uint, role string) ([]taskService.Task, error) {
+	if role != "" && role != TaskRoleOwner && role != TaskRoleAssignee {
+		return nil, errors.New("invalid role")
+	}
+	if !actor.CanAccessUser(userID) {
+		return nil, errors.New("user not found")
+	}
+
+	dbTasks, err := s.repo.GetTasksForUser(userID, role)
 	if err != nil {
 		return nil, err
 	}
@@ -107,65 +253,403 @@ func (s *UserService) GetTasksForUser(userID uint) ([]taskService.Task, error) {
 			Task: dbTask.Task,
 			IsDone: &dbTask.IsDone,
 			UserId: dbTask.UserId,
+			WorkspaceID: dbTask.WorkspaceID,
+			Version: dbTask.Version,
+			Position: dbTask.Position,
+			Assignees: taskService.AssigneeIDs(dbTask.Assignees),
 		}
 	}
+	ptrs := make([]*taskService.Task, len(tasks))
+	for i := range tasks {
+		ptrs[i] = &tasks[i]
+	}
+	if err := taskService.AttachCommentCounts(s.comments, ptrs...); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
-func (s *UserService) UpdateUser(id uint, params UpdateUserParams) (*User, error) {
+func (s *UserService) GetUser(actor rbac.Actor, id uint) (*User, error) {
+	if !actor.CanAccessUser(id) {
+		return nil, errors.New("user not found")
+	}
+	return s.GetAccount(id)
+}
+
+// GetAccount - пользователь по id без проверки прав (для внутренних вызовов:
+// authService проверяет по нему роль и отключен ли аккаунт)
+func (s *UserService) GetAccount(id uint) (*User, error) {
 	dbUser, err := s.repo.GetByID(id)
 	if err != nil || dbUser.ID == 0 {
 		return nil, errors.New("user not found")
 	}
 
-	updated := false
+	// маппим бд-модель в бизнес-модель
+	return toUser(&dbUser), nil
+}
 
-	if params.Email != nil {
-		if strings.TrimSpace(*params.Email) == "" {
-			return nil, errors.New("email is empty")
-		}
-		dbUser.Email = *params.Email
-		updated = true
+// version - версия из If-Match (nil - обновляем любую текущую версию)
+func (s *UserService) UpdateUser(actor rbac.Actor, id uint, version *uint, params UpdateUserParams) (*User, error) {
+	if !actor.CanAccessUser(id) {
+		return nil, errors.New("user not found")
 	}
 
-	if params.Password != nil {
-		if strings.TrimSpace(*params.Password) == "" {
-			return nil, errors.New("password is empty")
-		}
-		// хешируем пароль с реквеста
-		hashed, err := hashPass(*params.Password)
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	if version != nil && *version != dbUser.Version {
+		return nil, errors.New("version mismatch")
+	}
+
+	// маска изменённых колонок
+	var fields []string
+	emailChanged := false
+
+	if params.Email != nil {
+		email, err := normalizeEmail(*params.Email)
 		if err != nil {
-			return nil, errors.New("fail to hash password")
+			return nil, err
+		}
+		// тот же адрес в другом регистре - не новый
+		emailChanged = !strings.EqualFold(email, dbUser.Email)
+		dbUser.Email = email
+		fields = append(fields, UserFieldEmail)
+
+		// новый адрес нужно подтвердить заново
+		if emailChanged {
+			dbUser.EmailVerifiedAt = nil
+			fields = append(fields, UserFieldEmailVerifiedAt)
 		}
-		dbUser.Password = hashed
-		updated = true
 	}
 
-	if !updated {
+	if len(fields) == 0 {
 		return nil, errors.New("no fields to update")
 	}
 
-	updatedUser, err := s.repo.Update(&dbUser)
+	updatedUser, err := s.repo.Update(&dbUser, fields, actor)
 	if err != nil {
 		return nil, err
 	}
 
 	// маппим бд-модель в бизнес-модель 
-	return &User{
-		ID: updatedUser.ID,
-		Email: updatedUser.Email,
-	}, nil
+	user := toUser(updatedUser)
+
+	if emailChanged {
+		s.sendVerification(*user)
+	}
+
+	return user, nil
+}
+
+// ChangePassword - меняет пароль, только если передан верный текущий
+// после смены отзываются все сессии пользователя
+func (s *UserService) ChangePassword(actor rbac.Actor, id uint, params ChangePasswordParams) (*User, error) {
+	if !actor.CanAccessUser(id) {
+		return nil, errors.New("user not found")
+	}
+	if params.CurrentPassword == "" {
+		return nil, errors.New("current password is empty")
+	}
+	if strings.TrimSpace(params.NewPassword) == "" {
+		return nil, errors.New("new password is empty")
+	}
+
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	// сверяем текущий пароль с хэшем из бд
+	if bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(params.CurrentPassword)) != nil {
+		return nil, errors.New("current password is incorrect")
+	}
+
+	if params.NewPassword == params.CurrentPassword {
+		return nil, errors.New("new password must differ from the current one")
+	}
+
+	return s.setPassword(actor, dbUser, params.NewPassword)
+}
+
+// GetUserByEmail - ищет пользователя по email (без учета регистра, как уникальный индекс)
+func (s *UserService) GetUserByEmail(email string) (*User, error) {
+	normalized, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	dbUser, err := s.repo.GetByEmail(normalized)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	return toUser(&dbUser), nil
+}
+
+// CheckCredentials - проверяет email и пароль при входе и пишет попытку в журнал
+// на любую ошибку - одинаковый ответ "invalid credentials"; отключенный аккаунт - "account is disabled"
+// (об этом говорим только после верного пароля, чтобы не раскрывать, какие аккаунты отключены);
+// заблокированный после серии неудач - *LockedError (пароль при этом не проверяется)
+func (s *UserService) CheckCredentials(attempt LoginAttempt) (*User, error) {
+	dbUser := UserStruct{}
+	if normalized, err := normalizeEmail(attempt.Email); err == nil {
+		attempt.Email = normalized
+		dbUser, _ = s.repo.GetByEmail(normalized)
+	}
+
+	if dbUser.ID == 0 {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(attempt.Password))
+		s.recordAuthEvent(0, AuthEventLoginFailed, LoginFailureUnknownEmail, attempt)
+		return nil, errors.New("invalid credentials")
+	}
+
+	if isLocked(dbUser) {
+		s.recordAuthEvent(dbUser.ID, AuthEventLoginFailed, LoginFailureLocked, attempt)
+		return nil, &LockedError{Until: *dbUser.LockedUntil}
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(attempt.Password)) != nil {
+		s.recordAuthEvent(dbUser.ID, AuthEventLoginFailed, LoginFailureInvalidPassword, attempt)
+		if err := s.registerFailedLogin(dbUser, attempt); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid credentials")
+	}
+
+	if dbUser.DisabledAt != nil {
+		s.recordAuthEvent(dbUser.ID, AuthEventLoginFailed, LoginFailureDisabled, attempt)
+		return nil, errors.New("account is disabled")
+	}
+
+	// удачный вход сбрасывает счетчик неудач
+	if dbUser.FailedLogins != 0 || dbUser.LockedUntil != nil {
+		if err := s.repo.SetLockout(dbUser.ID, 0, nil); err != nil {
+			return nil, err
+		}
+		dbUser.FailedLogins, dbUser.LockedUntil = 0, nil
+	}
+
+	s.recordAuthEvent(dbUser.ID, AuthEventLoginSucceeded, "", attempt)
+	return toUser(&dbUser), nil
+}
+
+// CheckLockout - действует ли блокировка входа после серии неудач (*LockedError);
+// для входа без пароля (OIDC): блокировка закрывает аккаунт целиком, а не только вход по паролю
+func (s *UserService) CheckLockout(id uint) error {
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return errors.New("user not found")
+	}
+	if isLocked(dbUser) {
+		return &LockedError{Until: *dbUser.LockedUntil}
+	}
+	return nil
+}
+
+// ValidateNewPassword - проверяет пароль по политике для конкретного пользователя, ничего не меняя
+// (нужно сбросу пароля: сначала проверяем пароль, и только потом гасим одноразовый токен)
+func (s *UserService) ValidateNewPassword(id uint, password string) error {
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return errors.New("user not found")
+	}
+	if strings.TrimSpace(password) == "" {
+		return errors.New("new password is empty")
+	}
+	return s.policy.Validate(password, dbUser.Email)
+}
+
+// SetPassword - задает новый пароль без проверки текущего (для сброса пароля по токену)
+func (s *UserService) SetPassword(id uint, password string) (*User, error) {
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+	if strings.TrimSpace(password) == "" {
+		return nil, errors.New("new password is empty")
+	}
+	return s.setPassword(rbac.Actor{UserID: id}, dbUser, password)
+}
+
+// setPassword - проверяет пароль по политике, сохраняет хэш и отзывает сессии пользователя
+func (s *UserService) setPassword(actor rbac.Actor, dbUser UserStruct, password string) (*User, error) {
+	if err := s.policy.Validate(password, dbUser.Email); err != nil {
+		return nil, err
+	}
+
+	hashed, err := hashPass(password)
+	if err != nil {
+		return nil, errors.New("fail to hash password")
+	}
+	dbUser.Password = hashed
+
+	// обновляем только колонку пароля (версия при этом тоже растет)
+	updatedUser, err := s.repo.Update(&dbUser, []string{UserFieldPassword}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.sessions != nil {
+		if err := s.sessions.RevokeUserSessions(dbUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return toUser(updatedUser), nil
 }
 
-func (s *UserService) DeleteUser(id uint) error {
+// MarkEmailVerified - отмечает email подтвержденным
+// email - адрес, на который уходила ссылка: если пользователь успел его сменить, старая ссылка не подходит
+func (s *UserService) MarkEmailVerified(id uint, email string) (*User, error) {
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	if !strings.EqualFold(dbUser.Email, email) {
+		return nil, errors.New("email has changed since the verification was sent")
+	}
+
+	// повторный переход по ссылке - уже подтвержден, ничего не меняем
+	if dbUser.EmailVerifiedAt == nil {
+		now := time.Now()
+		dbUser.EmailVerifiedAt = &now
+
+		updatedUser, err := s.repo.Update(&dbUser, []string{UserFieldEmailVerifiedAt}, rbac.Actor{UserID: id})
+		if err != nil {
+			return nil, err
+		}
+		dbUser = *updatedUser
+	}
+
+	return toUser(&dbUser), nil
+}
+
+// IsEmailVerified - подтвержден ли email пользователя (нужно taskService, если без подтверждения нельзя создавать задачи)
+func (s *UserService) IsEmailVerified(id uint) (bool, error) {
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return false, errors.New("user not found")
+	}
+	return dbUser.EmailVerifiedAt != nil, nil
+}
+
+func (s *UserService) DeleteUser(actor rbac.Actor, id uint, version *uint) error {
+	if !actor.IsAdmin() {
+		return errors.New("forbidden")
+	}
+
 	user, err := s.repo.GetByID(id)
 	if err != nil || user.ID == 0 {
 		return errors.New("user not found")
 	}
 
-	err = s.repo.Delete(&user)
+	if version != nil && *version != user.Version {
+		return errors.New("version mismatch")
+	}
+
+	// задачи пользователя удалит каскад в бд, поэтому их id собираем заранее - после удаления их уже не найти
+	var taskIDs []uint
+	if len(s.cleaners) > 0 {
+		taskIDs, err = s.repo.GetOwnedTaskIDs(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.repo.Delete(&user, actor)
 	if err != nil {
 		return err
 	}
+
+	for _, taskID := range taskIDs {
+		for _, c := range s.cleaners {
+			c.CleanupTask(taskID)
+		}
+	}
+
+	// удаленный пользователь не должен оставаться залогиненным
+	if s.sessions != nil {
+		if err := s.sessions.RevokeUserSessions(id); err != nil {
+			return err
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// SetRole - меняет роль пользователя (повысить до админа / понизить до пользователя); только для админа
+// свою роль админ не меняет - иначе можно случайно остаться без единого админа
+func (s *UserService) SetRole(actor rbac.Actor, id uint, role string) (*User, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.New("forbidden")
+	}
+
+	newRole, err := rbac.ParseRole(role)
+	if err != nil {
+		return nil, errors.New("invalid role")
+	}
+
+	if actor.UserID == id {
+		return nil, errors.New("cannot change your own role")
+	}
+
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	// роль уже такая - ничего не меняем
+	if rbac.Role(dbUser.Role) == newRole {
+		return toUser(&dbUser), nil
+	}
+
+	dbUser.Role = string(newRole)
+	updatedUser, err := s.repo.Update(&dbUser, []string{UserFieldRole}, actor)
+	if err != nil {
+		return nil, err
+	}
+	return toUser(updatedUser), nil
+}
+
+// SetDisabled - отключает (disabled = true) или снова включает аккаунт; только для админа
+// при отключении все сессии пользователя отзываются сразу
+func (s *UserService) SetDisabled(actor rbac.Actor, id uint, disabled bool) (*User, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.New("forbidden")
+	}
+
+	if actor.UserID == id {
+		return nil, errors.New("cannot disable your own account")
+	}
+
+	dbUser, err := s.repo.GetByID(id)
+	if err != nil || dbUser.ID == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	// уже в нужном состоянии - ничего не меняем
+	if (dbUser.DisabledAt != nil) == disabled {
+		return toUser(&dbUser), nil
+	}
+
+	if disabled {
+		now := time.Now()
+		dbUser.DisabledAt = &now
+	} else {
+		dbUser.DisabledAt = nil
+	}
+
+	updatedUser, err := s.repo.Update(&dbUser, []string{UserFieldDisabledAt}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if disabled && s.sessions != nil {
+		if err := s.sessions.RevokeUserSessions(id); err != nil {
+			return nil, err
+		}
+	}
+	return toUser(updatedUser), nil
+}
@@ -1,6 +1,9 @@
 package userService
 
 import (
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,8 +12,8 @@ type MockUserRepo struct {
 	mock.Mock
 }
 
-func (m *MockUserRepo) Create(user *UserStruct) (*UserStruct, error) {
-	args := m.Called(user)
+func (m *MockUserRepo) Create(user *UserStruct, actor rbac.Actor) (*UserStruct, error) {
+	args := m.Called(user, actor)
 	var u *UserStruct
 	if res := args.Get(0); res != nil {
 		u = res.(*UserStruct)
@@ -36,8 +39,17 @@ func (m *MockUserRepo) GetByID(id uint) (UserStruct, error) {
     return user, args.Error(1) 	
 }
 
-func (m *MockUserRepo) GetTasksForUser(userID uint) ([]taskService.TaskStruct, error) {
-    args := m.Called(userID)
+func (m *MockUserRepo) GetByEmail(email string) (UserStruct, error) {
+	args := m.Called(email)
+	var user UserStruct
+	if res := args.Get(0); res != nil {
+		user = res.(UserStruct)
+	}
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepo) GetTasksForUser(userID uint, role string) ([]taskService.TaskStruct, error) {
+    args := m.Called(userID, role)
     var tasks []taskService.TaskStruct
     if res := args.Get(0); res != nil {
         tasks = res.([]taskService.TaskStruct)
@@ -45,8 +57,17 @@ func (m *MockUserRepo) GetTasksForUser(userID uint) ([]taskService.TaskStruct, e
     return tasks, args.Error(1)
 }
 
-func (m *MockUserRepo) Update(user *UserStruct) (*UserStruct, error) {
-    args := m.Called(user) 
+func (m *MockUserRepo) GetOwnedTaskIDs(userID uint) ([]uint, error) {
+    args := m.Called(userID)
+    var ids []uint
+    if res := args.Get(0); res != nil {
+        ids = res.([]uint)
+    }
+    return ids, args.Error(1)
+}
+
+func (m *MockUserRepo) Update(user *UserStruct, fields []string, actor rbac.Actor) (*UserStruct, error) {
+    args := m.Called(user, fields, actor)
     var updatedUser *UserStruct
     if res := args.Get(0); res != nil {
         updatedUser = res.(*UserStruct)
@@ -54,7 +75,17 @@ func (m *MockUserRepo) Update(user *UserStruct) (*UserStruct, error) {
     return updatedUser, args.Error(1) 
 }
 
-func (m *MockUserRepo) Delete(user *UserStruct) error {
-	args := m.Called(user)
+func (m *MockUserRepo) Delete(user *UserStruct, actor rbac.Actor) error {
+	args := m.Called(user, actor)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) IncrementFailedLogins(id uint) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepo) SetLockout(id uint, failedLogins int, lockedUntil *time.Time) error {
+	args := m.Called(id, failedLogins, lockedUntil)
 	return args.Error(0)
 }
@@ -3,13 +3,20 @@ package userService
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// testAdmin - в тестах бизнес-логики действуем от имени админа, чтобы проверки прав не мешали
+// (сами права проверяются в TestUserAccess и TestSetRoleAndDisabled)
+var testAdmin = rbac.Actor{UserID: 100, Role: rbac.RoleAdmin}
+
 func TestCreateUser(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -22,7 +29,7 @@ func TestCreateUser(t *testing.T) {
 			name: "успешное создание пользователя",
 			params: CreateUserParams{
 				Email:    "test@example.com",
-				Password: "123",
+				Password: "Str0ng-Passw0rd",
 			},
 			want: &User{
 				Email: "test@example.com",
@@ -33,7 +40,7 @@ func TestCreateUser(t *testing.T) {
 					Email:    params.Email,
 					Password: "$2a$10$hashed123",
 				}
-				m.On("Create", mock.Anything).Return(dbUser, nil)
+				m.On("Create", mock.Anything, mock.Anything).Return(dbUser, nil)
 			},
 			wantErr: false,
 		},
@@ -41,7 +48,35 @@ func TestCreateUser(t *testing.T) {
 			name: "ошибка - email пустой",
 			params: CreateUserParams{
 				Email:    "",
-				Password: "password123",
+				Password: "Str0ng-Passw0rd",
+			},
+			want: nil,
+			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
+				// Мок не вызывается
+			},
+			wantErr: true,
+		},
+		{
+			name: "email нормализуется перед сохранением",
+			params: CreateUserParams{
+				Email:    "  Test.User@Example.COM ",
+				Password: "Str0ng-Passw0rd",
+			},
+			want: &User{
+				Email: "Test.User@example.com",
+			},
+			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
+				m.On("Create", mock.MatchedBy(func(u *UserStruct) bool {
+					return u.Email == "Test.User@example.com"
+				}), mock.Anything).Return(&UserStruct{ID: 42, Email: "Test.User@example.com"}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ошибка - пароль не проходит политику",
+			params: CreateUserParams{
+				Email:    "test@example.com",
+				Password: "123",
 			},
 			want: nil,
 			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
@@ -49,6 +84,30 @@ func TestCreateUser(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ошибка - некорректный email",
+			params: CreateUserParams{
+				Email:    "not-an-email",
+				Password: "Str0ng-Passw0rd",
+			},
+			want: nil,
+			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
+				// Мок не вызывается
+			},
+			wantErr: true,
+		},
+		{
+			name: "ошибка - email уже занят",
+			params: CreateUserParams{
+				Email:    "taken@example.com",
+				Password: "Str0ng-Passw0rd",
+			},
+			want: nil,
+			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("email already exists"))
+			},
+			wantErr: true,
+		},
 		{
 			name: "ошибка - пароль пустой",
 			params: CreateUserParams{
@@ -65,12 +124,12 @@ func TestCreateUser(t *testing.T) {
 			name: "ошибка при создании в БД",
 			params: CreateUserParams{
 				Email:    "test@example.com",
-				Password: "password123",
+				Password: "Str0ng-Passw0rd",
 			},
 			want:    nil,
 			wantErr: true,
 			mockSetup: func(m *MockUserRepo, params CreateUserParams, want *User) {
-				m.On("Create", mock.Anything).Return(nil, errors.New("db error"))
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 			},
 		},
 	}
@@ -81,7 +140,7 @@ func TestCreateUser(t *testing.T) {
 			tt.mockSetup(mockRepo, tt.params, tt.want)
 
 			service := NewUserService(mockRepo)
-			result, err := service.CreateUser(tt.params)
+			result, err := service.CreateUser(rbac.Actor{}, tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -135,7 +194,7 @@ func TestGetUsers(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewUserService(mockRepo)
-			result, err := service.GetUsers()
+			result, err := service.GetUsers(testAdmin)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -199,7 +258,7 @@ func TestGetTasksForUser(t *testing.T) {
                         UserId: 1,
                     },
                 }
-                m.On("GetTasksForUser", userID).Return(dbTasks, nil)
+                m.On("GetTasksForUser", userID, "").Return(dbTasks, nil)
             },
         },
         {
@@ -208,7 +267,7 @@ func TestGetTasksForUser(t *testing.T) {
             want:   []taskService.Task{},
             wantErr: false,
             mockSetup: func(m *MockUserRepo, userID uint, want []taskService.Task) {
-                m.On("GetTasksForUser", userID).Return([]taskService.TaskStruct{}, nil)
+                m.On("GetTasksForUser", userID, "").Return([]taskService.TaskStruct{}, nil)
             },
         },
         {
@@ -217,7 +276,7 @@ func TestGetTasksForUser(t *testing.T) {
             want:   nil,
             wantErr: true,
             mockSetup: func(m *MockUserRepo, userID uint, want []taskService.Task) {
-                m.On("GetTasksForUser", userID).Return(nil, errors.New("user not found"))
+                m.On("GetTasksForUser", userID, "").Return(nil, errors.New("user not found"))
             },
         },
         {
@@ -226,7 +285,7 @@ func TestGetTasksForUser(t *testing.T) {
             want:   nil,
             wantErr: true,
             mockSetup: func(m *MockUserRepo, userID uint, want []taskService.Task) {
-                m.On("GetTasksForUser", userID).Return(nil, errors.New("db error"))
+                m.On("GetTasksForUser", userID, "").Return(nil, errors.New("db error"))
             },
         },
     }
@@ -237,7 +296,7 @@ func TestGetTasksForUser(t *testing.T) {
             tt.mockSetup(mockRepo, tt.userID, tt.want)
             
             service := NewUserService(mockRepo)
-            result, err := service.GetTasksForUser(tt.userID)
+            result, err := service.GetTasksForUser(testAdmin, tt.userID, "")
             
             if tt.wantErr {
                 assert.Error(t, err)
@@ -267,9 +326,12 @@ func TestGetTasksForUser(t *testing.T) {
 func TestUpdateUser(t *testing.T) {
 	stringPtr := func(s string) *string { return &s }
 
+	uintPtr := func(u uint) *uint { return &u }
+
 	tests := []struct {
 		name    string
 		id      uint
+		version *uint // значение из If-Match (nil - без проверки)
 		params  UpdateUserParams
 		want    *User
 		wantErr bool
@@ -280,7 +342,6 @@ func TestUpdateUser(t *testing.T) {
 			id:   1,
 			params: UpdateUserParams{
 				Email:    stringPtr("newemail@example.com"),
-				Password: stringPtr("newpassword123"),
 			},
 			want: &User{
 				ID:    1,
@@ -302,7 +363,7 @@ func TestUpdateUser(t *testing.T) {
 					Email:    *params.Email,
 					Password: "hashed_new123", 
 				}
-				m.On("Update", mock.Anything).Return(updatedUser, nil)
+				m.On("Update", mock.Anything, []string{UserFieldEmail, UserFieldEmailVerifiedAt}, mock.Anything).Return(updatedUser, nil)
 			},
 		},	
 		{
@@ -329,34 +390,7 @@ func TestUpdateUser(t *testing.T) {
 					Email: *params.Email,
 					Password: "hashed123",
 				}
-				m.On("Update", mock.Anything).Return(updatedUser, nil)
-			},
-		},
-		{
-			name: "обновление только пароля",
-			id:   3,
-			params: UpdateUserParams{
-				Password: stringPtr("newpassword123"),
-			},
-			want: &User{
-				ID:    3,
-				Email: "existing@example.com", // email остается прежним
-			},
-			wantErr: false,
-			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
-				existingUser := UserStruct{
-					ID:       id,
-					Email:    "existing@example.com",
-					Password: "hashed_old123",
-				}
-				m.On("GetByID", id).Return(existingUser, nil)
-
-				updatedUser := &UserStruct{
-					ID:       id,
-					Email:    "existing@example.com", // email не меняется
-					Password: "hashed_new123", // новый хэш
-				}
-				m.On("Update", mock.Anything).Return(updatedUser, nil)
+				m.On("Update", mock.Anything, []string{UserFieldEmail, UserFieldEmailVerifiedAt}, mock.Anything).Return(updatedUser, nil)
 			},
 		},
 		{
@@ -404,10 +438,10 @@ func TestUpdateUser(t *testing.T) {
 			},
 		},
 		{
-			name: "ошибка - пустой пароль",
-			id:   6,
+			name: "ошибка при обновлении в БД",
+			id:   7,
 			params: UpdateUserParams{
-				Password: stringPtr(""), // пустая строка
+				Email: stringPtr("newemail@example.com"),
 			},
 			want:    nil,
 			wantErr: true,
@@ -418,24 +452,109 @@ func TestUpdateUser(t *testing.T) {
 					Password: "hashed123",
 				}
 				m.On("GetByID", id).Return(existingUser, nil)
+				m.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 			},
 		},
 		{
-			name: "ошибка при обновлении в БД",
-			id:   7,
+			name: "ошибка - некорректный новый email",
+			id:   10,
+			params: UpdateUserParams{
+				Email: stringPtr("user@@example.com"),
+			},
+			want:    nil,
+			wantErr: true,
+			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
+				existingUser := UserStruct{ID: id, Email: "existing@example.com", Version: 1}
+				m.On("GetByID", id).Return(existingUser, nil)
+			},
+		},
+		{
+			name: "новый email нормализуется",
+			id:   11,
+			params: UpdateUserParams{
+				Email: stringPtr(" New@EXAMPLE.com"),
+			},
+			want: &User{
+				ID:    11,
+				Email: "New@example.com",
+			},
+			wantErr: false,
+			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
+				existingUser := UserStruct{ID: id, Email: "existing@example.com", Version: 1}
+				m.On("GetByID", id).Return(existingUser, nil)
+				m.On("Update", mock.MatchedBy(func(u *UserStruct) bool {
+					return u.Email == "New@example.com"
+				}), []string{UserFieldEmail, UserFieldEmailVerifiedAt}, mock.Anything).Return(&UserStruct{ID: id, Email: "New@example.com", Version: 2}, nil)
+			},
+		},
+		{
+			name: "смена email сбрасывает подтверждение",
+			id:   12,
+			params: UpdateUserParams{
+				Email: stringPtr("other@example.com"),
+			},
+			want: &User{
+				ID:    12,
+				Email: "other@example.com",
+			},
+			wantErr: false,
+			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
+				verifiedAt := time.Now()
+				existingUser := UserStruct{ID: id, Email: "existing@example.com", EmailVerifiedAt: &verifiedAt}
+				m.On("GetByID", id).Return(existingUser, nil)
+				m.On("Update", mock.MatchedBy(func(u *UserStruct) bool {
+					return u.EmailVerifiedAt == nil
+				}), []string{UserFieldEmail, UserFieldEmailVerifiedAt}, mock.Anything).Return(&UserStruct{ID: id, Email: "other@example.com"}, nil)
+			},
+		},
+		{
+			name: "тот же email в другом регистре - подтверждение сохраняется",
+			id:   13,
+			params: UpdateUserParams{
+				Email: stringPtr("Existing@example.com"),
+			},
+			want: &User{
+				ID:    13,
+				Email: "Existing@example.com",
+			},
+			wantErr: false,
+			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
+				existingUser := UserStruct{ID: id, Email: "existing@example.com"}
+				m.On("GetByID", id).Return(existingUser, nil)
+				m.On("Update", mock.Anything, []string{UserFieldEmail}, mock.Anything).Return(&UserStruct{ID: id, Email: "Existing@example.com"}, nil)
+			},
+		},
+		{
+			name:    "ошибка - версия из If-Match устарела",
+			id:      8,
+			version: uintPtr(1),
 			params: UpdateUserParams{
 				Email: stringPtr("newemail@example.com"),
 			},
 			want:    nil,
 			wantErr: true,
 			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
-				existingUser := UserStruct{
-					ID:       id,
-					Email:    "existing@example.com",
-					Password: "hashed123",
-				}
+				existingUser := UserStruct{ID: id, Email: "existing@example.com", Version: 2}
 				m.On("GetByID", id).Return(existingUser, nil)
-				m.On("Update", mock.Anything).Return(nil, errors.New("db error"))
+			},
+		},
+		{
+			name:    "успешное обновление с совпавшей версией",
+			id:      9,
+			version: uintPtr(2),
+			params: UpdateUserParams{
+				Email: stringPtr("newemail@example.com"),
+			},
+			want: &User{
+				ID:      9,
+				Email:   "newemail@example.com",
+				Version: 3,
+			},
+			wantErr: false,
+			mockSetup: func(m *MockUserRepo, id uint, params UpdateUserParams, want *User) {
+				existingUser := UserStruct{ID: id, Email: "existing@example.com", Version: 2}
+				m.On("GetByID", id).Return(existingUser, nil)
+				m.On("Update", mock.Anything, []string{UserFieldEmail, UserFieldEmailVerifiedAt}, mock.Anything).Return(&UserStruct{ID: id, Email: *params.Email, Version: 3}, nil)
 			},
 		},
 	}
@@ -445,7 +564,7 @@ func TestUpdateUser(t *testing.T) {
 			tt.mockSetup(mockRepo, tt.id, tt.params, tt.want)
 
 			service := NewUserService(mockRepo)
-			result, err := service.UpdateUser(tt.id, tt.params)
+			result, err := service.UpdateUser(testAdmin, tt.id, tt.version, tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -455,6 +574,55 @@ func TestUpdateUser(t *testing.T) {
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.want.ID, result.ID)
 				assert.Equal(t, tt.want.Email, result.Email)
+				if tt.want.Version != 0 {
+					assert.Equal(t, tt.want.Version, result.Version)
+				}
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        uint
+		mockSetup func(m *MockUserRepo, id uint)
+		want      *User
+		wantErr   bool
+	}{
+		{
+			name: "пользователь найден",
+			id:   1,
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(UserStruct{ID: id, Email: "user@example.com", Version: 4}, nil)
+			},
+			want: &User{ID: 1, Email: "user@example.com", Version: 4, Role: rbac.RoleUser},
+		},
+		{
+			name: "пользователь не найден",
+			id:   999,
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(UserStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			tt.mockSetup(mockRepo, tt.id)
+
+			service := NewUserService(mockRepo)
+			result, err := service.GetUser(testAdmin, tt.id)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
 			}
 			mockRepo.AssertExpectations(t)
 		})
@@ -462,9 +630,12 @@ func TestUpdateUser(t *testing.T) {
 }
 
 func TestDeleteUser(t *testing.T) {
+    uintPtr := func(u uint) *uint { return &u }
+
     tests := []struct {
         name      string
         id        uint
+        version   *uint
         mockSetup func(m *MockUserRepo, id uint)
         wantErr   bool
     }{
@@ -478,7 +649,7 @@ func TestDeleteUser(t *testing.T) {
                     Password: "hashed_password",
                 }
                 m.On("GetByID", id).Return(existingUser, nil)
-                m.On("Delete", &existingUser).Return(nil)
+                m.On("Delete", &existingUser, mock.Anything).Return(nil)
             },
             wantErr: false,
         },
@@ -500,7 +671,17 @@ func TestDeleteUser(t *testing.T) {
                     Password: "hashed_password2",
                 }
                 m.On("GetByID", id).Return(existingUser, nil)
-                m.On("Delete", &existingUser).Return(errors.New("db error"))
+                m.On("Delete", &existingUser, mock.Anything).Return(errors.New("db error"))
+            },
+            wantErr: true,
+        },
+        {
+            name:    "версия из If-Match устарела",
+            id:      3,
+            version: uintPtr(1),
+            mockSetup: func(m *MockUserRepo, id uint) {
+                existingUser := UserStruct{ID: id, Email: "user3@example.com", Version: 2}
+                m.On("GetByID", id).Return(existingUser, nil)
             },
             wantErr: true,
         },
@@ -512,7 +693,7 @@ func TestDeleteUser(t *testing.T) {
             tt.mockSetup(mockRepo, tt.id)
 
             service := NewUserService(mockRepo)
-            err := service.DeleteUser(tt.id)
+            err := service.DeleteUser(testAdmin, tt.id, tt.version)
 
             if tt.wantErr {
                 assert.Error(t, err)
@@ -523,4 +704,584 @@ func TestDeleteUser(t *testing.T) {
             mockRepo.AssertExpectations(t)
         })
     }
-}
\ No newline at end of file
+}
+
+// fakeRevoker - запоминает, чьи сессии отозвали
+type fakeRevoker struct {
+	revoked []uint
+	err     error
+}
+
+func (f *fakeRevoker) RevokeUserSessions(userID uint) error {
+	f.revoked = append(f.revoked, userID)
+	return f.err
+}
+
+func TestChangePassword(t *testing.T) {
+	// хэш текущего пароля (MinCost, чтобы тест был быстрым)
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Old-Passw0rd"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	existing := func(id uint) UserStruct {
+		return UserStruct{ID: id, Email: "user@example.com", Password: string(hashed), Version: 3}
+	}
+
+	tests := []struct {
+		name        string
+		id          uint
+		params      ChangePasswordParams
+		revokeErr   error
+		mockSetup   func(m *MockUserRepo, id uint)
+		wantErr     string
+		wantRevoked bool
+	}{
+		{
+			name:   "успешная смена пароля",
+			id:     1,
+			params: ChangePasswordParams{CurrentPassword: "Old-Passw0rd", NewPassword: "New-Passw0rd"},
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(existing(id), nil)
+				m.On("Update", mock.MatchedBy(func(u *UserStruct) bool {
+					// в бд уходит хэш нового пароля
+					return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("New-Passw0rd")) == nil
+				}), []string{UserFieldPassword}, mock.Anything).Return(&UserStruct{ID: id, Email: "user@example.com", Version: 4}, nil)
+			},
+			wantRevoked: true,
+		},
+		{
+			name:   "неверный текущий пароль",
+			id:     2,
+			params: ChangePasswordParams{CurrentPassword: "wrong", NewPassword: "New-Passw0rd"},
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(existing(id), nil)
+			},
+			wantErr: "current password is incorrect",
+		},
+		{
+			name:   "новый пароль совпадает с текущим",
+			id:     3,
+			params: ChangePasswordParams{CurrentPassword: "Old-Passw0rd", NewPassword: "Old-Passw0rd"},
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(existing(id), nil)
+			},
+			wantErr: "must differ",
+		},
+		{
+			name:   "новый пароль не проходит политику",
+			id:     4,
+			params: ChangePasswordParams{CurrentPassword: "Old-Passw0rd", NewPassword: "123"},
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(existing(id), nil)
+			},
+			wantErr: "invalid password",
+		},
+		{
+			name:      "пустой текущий пароль",
+			id:        5,
+			params:    ChangePasswordParams{NewPassword: "New-Passw0rd"},
+			mockSetup: func(m *MockUserRepo, id uint) {},
+			wantErr:   "current password is empty",
+		},
+		{
+			name:   "пользователь не найден",
+			id:     999,
+			params: ChangePasswordParams{CurrentPassword: "Old-Passw0rd", NewPassword: "New-Passw0rd"},
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(UserStruct{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: "user not found",
+		},
+		{
+			name:      "ошибка отзыва сессий",
+			id:        6,
+			params:    ChangePasswordParams{CurrentPassword: "Old-Passw0rd", NewPassword: "New-Passw0rd"},
+			revokeErr: errors.New("db error"),
+			mockSetup: func(m *MockUserRepo, id uint) {
+				m.On("GetByID", id).Return(existing(id), nil)
+				m.On("Update", mock.Anything, []string{UserFieldPassword}, mock.Anything).Return(&UserStruct{ID: id, Version: 4}, nil)
+			},
+			wantErr:     "db error",
+			wantRevoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			tt.mockSetup(mockRepo, tt.id)
+			revoker := &fakeRevoker{err: tt.revokeErr}
+
+			service := NewUserService(mockRepo).WithSessionRevoker(revoker)
+			result, err := service.ChangePassword(testAdmin, tt.id, tt.params)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(4), result.Version)
+			}
+
+			if tt.wantRevoked {
+				assert.Equal(t, []uint{tt.id}, revoker.revoked)
+			} else {
+				assert.Empty(t, revoker.revoked)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	// домен нормализуется до поиска
+	mockRepo.On("GetByEmail", "User@example.com").Return(UserStruct{ID: 1, Email: "User@example.com", Version: 2}, nil)
+	mockRepo.On("GetByEmail", "nobody@example.com").Return(UserStruct{}, gorm.ErrRecordNotFound)
+
+	service := NewUserService(mockRepo)
+
+	user, err := service.GetUserByEmail(" User@EXAMPLE.com")
+	assert.NoError(t, err)
+	assert.Equal(t, &User{ID: 1, Email: "User@example.com", Version: 2, Role: rbac.RoleUser}, user)
+
+	_, err = service.GetUserByEmail("nobody@example.com")
+	assert.ErrorContains(t, err, "user not found")
+
+	_, err = service.GetUserByEmail("not-an-email")
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetPassword(t *testing.T) {
+	t.Run("пароль меняется без текущего, сессии отзываются", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, Email: "user@example.com", Version: 1}, nil)
+		mockRepo.On("Update", mock.Anything, []string{UserFieldPassword}, mock.Anything).Return(&UserStruct{ID: 1, Email: "user@example.com", Version: 2}, nil)
+		revoker := &fakeRevoker{}
+
+		service := NewUserService(mockRepo).WithSessionRevoker(revoker)
+		user, err := service.SetPassword(1, "New-Passw0rd")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), user.Version)
+		assert.Equal(t, []uint{1}, revoker.revoked)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ValidateNewPassword проверяет политику с учетом email", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, Email: "johnny@example.com"}, nil)
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{}, gorm.ErrRecordNotFound)
+
+		service := NewUserService(mockRepo)
+		assert.NoError(t, service.ValidateNewPassword(1, "New-Passw0rd"))
+		assert.ErrorContains(t, service.ValidateNewPassword(1, "Johnny-2024!"), RuleContainsEmail)
+		assert.ErrorContains(t, service.ValidateNewPassword(2, "New-Passw0rd"), "user not found")
+	})
+}
+
+// fakeVerifier - запоминает, кому отправляли письмо для подтверждения
+type fakeVerifier struct {
+	sent []string
+	err  error
+}
+
+func (f *fakeVerifier) SendEmailVerification(user User) error {
+	f.sent = append(f.sent, user.Email)
+	return f.err
+}
+
+func TestEmailVerification(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+
+	t.Run("после регистрации отправляется письмо, ошибка отправки не ломает регистрацию", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&UserStruct{ID: 1, Email: "user@example.com", Version: 1}, nil)
+		verifier := &fakeVerifier{err: errors.New("smtp down")}
+
+		service := NewUserService(mockRepo).WithEmailVerifier(verifier)
+		user, err := service.CreateUser(rbac.Actor{}, CreateUserParams{Email: "user@example.com", Password: "Str0ng-Passw0rd"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, user.EmailVerifiedAt)
+		assert.Equal(t, []string{"user@example.com"}, verifier.sent)
+	})
+
+	t.Run("письмо уходит только при смене адреса", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, Email: "old@example.com"}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(&UserStruct{ID: 1, Email: "new@example.com"}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(&UserStruct{ID: 1, Email: "Old@example.com"}, nil).Once()
+		verifier := &fakeVerifier{}
+
+		service := NewUserService(mockRepo).WithEmailVerifier(verifier)
+		_, err := service.UpdateUser(testAdmin, 1, nil, UpdateUserParams{Email: stringPtr("new@example.com")})
+		assert.NoError(t, err)
+		_, err = service.UpdateUser(testAdmin, 1, nil, UpdateUserParams{Email: stringPtr("Old@example.com")})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"new@example.com"}, verifier.sent)
+	})
+
+	t.Run("MarkEmailVerified", func(t *testing.T) {
+		verifiedAt := time.Now()
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, Email: "user@example.com", Version: 1}, nil)
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Email: "user2@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+		mockRepo.On("Update", mock.MatchedBy(func(u *UserStruct) bool {
+			return u.ID == 1 && u.EmailVerifiedAt != nil
+		}), []string{UserFieldEmailVerifiedAt}, mock.Anything).Return(&UserStruct{ID: 1, Email: "user@example.com", Version: 2, EmailVerifiedAt: &verifiedAt}, nil)
+
+		service := NewUserService(mockRepo)
+
+		user, err := service.MarkEmailVerified(1, "user@example.com")
+		assert.NoError(t, err)
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.Equal(t, uint(2), user.Version)
+
+		// уже подтвержден - повторно не обновляем
+		user, err = service.MarkEmailVerified(2, "user2@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, &verifiedAt, user.EmailVerifiedAt)
+
+		// ссылка была на старый адрес
+		_, err = service.MarkEmailVerified(1, "old@example.com")
+		assert.ErrorContains(t, err, "email has changed")
+
+		verified, err := service.IsEmailVerified(2)
+		assert.NoError(t, err)
+		assert.True(t, verified)
+
+		verified, err = service.IsEmailVerified(1)
+		assert.NoError(t, err)
+		assert.False(t, verified)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCheckCredentials(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Str0ng-Passw0rd"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("GetByEmail", "user@example.com").Return(UserStruct{ID: 1, Email: "user@example.com", Password: string(hashed)}, nil)
+	mockRepo.On("GetByEmail", "nobody@example.com").Return(UserStruct{}, gorm.ErrRecordNotFound)
+
+	mockRepo.On("IncrementFailedLogins", mock.Anything).Return(1, nil)
+
+	service := NewUserService(mockRepo)
+
+	user, err := service.CheckCredentials(LoginAttempt{Email: "user@EXAMPLE.com", Password: "Str0ng-Passw0rd"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+
+	// неверный пароль, неизвестный и некорректный email - одна и та же ошибка
+	for _, tc := range [][2]string{
+		{"user@example.com", "wrong"},
+		{"nobody@example.com", "Str0ng-Passw0rd"},
+		{"not-an-email", "Str0ng-Passw0rd"},
+	} {
+		_, err := service.CheckCredentials(LoginAttempt{Email: tc[0], Password: tc[1]})
+		assert.EqualError(t, err, "invalid credentials")
+	}
+
+	// отключенный аккаунт: с верным паролем - отдельная ошибка, с неверным - как обычно
+	disabledAt := time.Now()
+	mockRepo.On("GetByEmail", "off@example.com").Return(UserStruct{ID: 2, Email: "off@example.com", Password: string(hashed), DisabledAt: &disabledAt}, nil)
+
+	_, err = service.CheckCredentials(LoginAttempt{Email: "off@example.com", Password: "Str0ng-Passw0rd"})
+	assert.EqualError(t, err, "account is disabled")
+	_, err = service.CheckCredentials(LoginAttempt{Email: "off@example.com", Password: "wrong"})
+	assert.EqualError(t, err, "invalid credentials")
+}
+
+func TestDeleteUserRevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	existingUser := UserStruct{ID: 1, Email: "user@example.com", Version: 1}
+	mockRepo.On("GetByID", uint(1)).Return(existingUser, nil)
+	mockRepo.On("Delete", &existingUser, mock.Anything).Return(nil)
+	revoker := &fakeRevoker{}
+
+	service := NewUserService(mockRepo).WithSessionRevoker(revoker)
+	assert.NoError(t, service.DeleteUser(testAdmin, 1, nil))
+	assert.Equal(t, []uint{1}, revoker.revoked)
+
+	// удаление не прошло (версия устарела) - сессии не трогаем
+	stale := uint(5)
+	revoker.revoked = nil
+	assert.Error(t, service.DeleteUser(testAdmin, 1, &stale))
+	assert.Empty(t, revoker.revoked)
+}
+
+// fakeCleaner - запоминает, за какими задачами убирали
+type fakeCleaner struct {
+	cleaned []uint
+}
+
+func (f *fakeCleaner) CleanupTask(taskID uint) {
+	f.cleaned = append(f.cleaned, taskID)
+}
+
+// задачи удаленного пользователя удаляет каскад в бд, а их файлы - cleaner
+func TestDeleteUserCleansUpTasks(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(m *MockUserRepo, user *UserStruct)
+		wantErr     bool
+		wantCleaned []uint
+	}{
+		{
+			name: "после удаления убираем за всеми задачами пользователя",
+			mockSetup: func(m *MockUserRepo, user *UserStruct) {
+				m.On("GetOwnedTaskIDs", user.ID).Return([]uint{10, 11}, nil)
+				m.On("Delete", user, mock.Anything).Return(nil)
+			},
+			wantCleaned: []uint{10, 11},
+		},
+		{
+			name: "удаление не прошло - ничего не убираем",
+			mockSetup: func(m *MockUserRepo, user *UserStruct) {
+				m.On("GetOwnedTaskIDs", user.ID).Return([]uint{10}, nil)
+				m.On("Delete", user, mock.Anything).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "не удалось получить задачи - пользователя не удаляем",
+			mockSetup: func(m *MockUserRepo, user *UserStruct) {
+				m.On("GetOwnedTaskIDs", user.ID).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			user := UserStruct{ID: 1, Email: "user@example.com", Version: 1}
+			mockRepo.On("GetByID", user.ID).Return(user, nil)
+			tt.mockSetup(mockRepo, &user)
+			cleaner := &fakeCleaner{}
+
+			service := NewUserService(mockRepo).WithTaskCleanup(cleaner)
+			err := service.DeleteUser(testAdmin, user.ID, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCleaned, cleaner.cleaned)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// кто изменил пользователя, repo получает вместе с изменением - для журнала аудита
+func TestUserChangesPassActor(t *testing.T) {
+	admin := rbac.Actor{UserID: 1, Role: rbac.RoleAdmin, RequestID: "req-1"}
+
+	t.Run("смена роли - от имени админа", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Email: "user@example.com", Version: 1, Role: "user"}, nil)
+		mockRepo.On("Update", mock.Anything, []string{UserFieldRole}, admin).
+			Return(&UserStruct{ID: 2, Email: "user@example.com", Version: 2, Role: "admin"}, nil).Once()
+
+		_, err := NewUserService(mockRepo).SetRole(admin, 2, "admin")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("сброс пароля по токену - от имени самого пользователя", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Email: "user@example.com", Version: 1}, nil)
+		mockRepo.On("Update", mock.Anything, []string{UserFieldPassword}, rbac.Actor{UserID: 2}).
+			Return(&UserStruct{ID: 2, Email: "user@example.com", Version: 2}, nil).Once()
+
+		_, err := NewUserService(mockRepo).SetPassword(2, "N3w-Str0ng-Passw0rd")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("регистрация - без автора", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("Create", mock.Anything, rbac.Actor{RequestID: "req-2"}).
+			Return(&UserStruct{ID: 3, Email: "new@example.com", Version: 1}, nil).Once()
+
+		_, err := NewUserService(mockRepo).CreateUser(rbac.Actor{RequestID: "req-2"}, CreateUserParams{Email: "new@example.com", Password: "Str0ng-Passw0rd"})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserAccess(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+	user := rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("GetByID", uint(1)).Return(UserStruct{ID: 1, Email: "user@example.com", Version: 1}, nil)
+	service := NewUserService(mockRepo)
+
+	// свой профиль
+	result, err := service.GetUser(user, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, rbac.RoleUser, result.Role)
+
+	// чужой профиль и чужие задачи выглядят как несуществующие
+	_, err = service.GetUser(user, 2)
+	assert.EqualError(t, err, "user not found")
+	_, err = service.GetTasksForUser(user, 2, "")
+	assert.EqualError(t, err, "user not found")
+	_, err = service.UpdateUser(user, 2, nil, UpdateUserParams{Email: stringPtr("x@example.com")})
+	assert.EqualError(t, err, "user not found")
+	_, err = service.ChangePassword(user, 2, ChangePasswordParams{CurrentPassword: "a", NewPassword: "b"})
+	assert.EqualError(t, err, "user not found")
+
+	// список пользователей и удаление - только админ (даже себя)
+	_, err = service.GetUsers(user)
+	assert.EqualError(t, err, "forbidden")
+	assert.EqualError(t, service.DeleteUser(user, 1, nil), "forbidden")
+
+	// аноним
+	_, err = service.GetUser(rbac.Actor{}, 1)
+	assert.EqualError(t, err, "user not found")
+
+	mockRepo.AssertNotCalled(t, "GetAll")
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetTasksForUser", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetRoleAndDisabled(t *testing.T) {
+	admin := rbac.Actor{UserID: 1, Role: rbac.RoleAdmin}
+	user := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+
+	t.Run("повышение до админа", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		existing := UserStruct{ID: 2, Email: "user@example.com", Version: 1, Role: "user"}
+		mockRepo.On("GetByID", uint(2)).Return(existing, nil)
+		mockRepo.On("Update", mock.MatchedBy(func(u *UserStruct) bool { return u.Role == "admin" }), []string{UserFieldRole}, mock.Anything).
+			Return(&UserStruct{ID: 2, Email: "user@example.com", Version: 2, Role: "admin"}, nil)
+
+		result, err := NewUserService(mockRepo).SetRole(admin, 2, "admin")
+		assert.NoError(t, err)
+		assert.Equal(t, rbac.RoleAdmin, result.Role)
+		assert.Equal(t, uint(2), result.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибки смены роли", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(3)).Return(UserStruct{}, gorm.ErrRecordNotFound)
+		service := NewUserService(mockRepo)
+
+		_, err := service.SetRole(user, 3, "admin")
+		assert.EqualError(t, err, "forbidden")
+		_, err = service.SetRole(admin, 3, "root")
+		assert.EqualError(t, err, "invalid role")
+		_, err = service.SetRole(admin, 1, "user")
+		assert.EqualError(t, err, "cannot change your own role")
+		_, err = service.SetRole(admin, 3, "admin")
+		assert.EqualError(t, err, "user not found")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("отключение отзывает сессии, включение - нет", func(t *testing.T) {
+		disabledAt := time.Now()
+		mockRepo := new(MockUserRepo)
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Version: 1}, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(u *UserStruct) bool { return u.DisabledAt != nil }), []string{UserFieldDisabledAt}, mock.Anything).
+			Return(&UserStruct{ID: 2, Version: 2, DisabledAt: &disabledAt}, nil).Once()
+		mockRepo.On("GetByID", uint(2)).Return(UserStruct{ID: 2, Version: 2, DisabledAt: &disabledAt}, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(u *UserStruct) bool { return u.DisabledAt == nil }), []string{UserFieldDisabledAt}, mock.Anything).
+			Return(&UserStruct{ID: 2, Version: 3}, nil).Once()
+		revoker := &fakeRevoker{}
+		service := NewUserService(mockRepo).WithSessionRevoker(revoker)
+
+		result, err := service.SetDisabled(admin, 2, true)
+		assert.NoError(t, err)
+		assert.NotNil(t, result.DisabledAt)
+		assert.Equal(t, []uint{2}, revoker.revoked)
+
+		result, err = service.SetDisabled(admin, 2, false)
+		assert.NoError(t, err)
+		assert.Nil(t, result.DisabledAt)
+		assert.Equal(t, []uint{2}, revoker.revoked)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибки отключения", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		service := NewUserService(mockRepo)
+
+		_, err := service.SetDisabled(user, 3, true)
+		assert.EqualError(t, err, "forbidden")
+		_, err = service.SetDisabled(admin, 1, true)
+		assert.EqualError(t, err, "cannot disable your own account")
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestCreateExternalUser(t *testing.T) {
+	verifiedAt := time.Now()
+	mockRepo := new(MockUserRepo)
+	mockRepo.On("Create", mock.MatchedBy(func(u *UserStruct) bool {
+		// email нормализован, пароля нет, адрес сразу подтвержден
+		return u.Email == "anton@example.com" && u.Password == "" && u.EmailVerifiedAt != nil
+	}), mock.Anything).Return(&UserStruct{ID: 3, Email: "anton@example.com", Version: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	verifier := &fakeVerifier{}
+
+	service := NewUserService(mockRepo).WithEmailVerifier(verifier)
+	user, err := service.CreateExternalUser(" anton@EXAMPLE.com ")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), user.ID)
+	assert.Equal(t, rbac.RoleUser, user.Role)
+	assert.NotNil(t, user.EmailVerifiedAt)
+	// письмо с подтверждением не нужно
+	assert.Empty(t, verifier.sent)
+
+	// войти по паролю такой пользователь не может
+	mockRepo.On("GetByEmail", "anton@example.com").Return(UserStruct{ID: 3, Email: "anton@example.com"}, nil)
+	mockRepo.On("IncrementFailedLogins", uint(3)).Return(1, nil)
+	_, err = service.CheckCredentials(LoginAttempt{Email: "anton@example.com"})
+	assert.EqualError(t, err, "invalid credentials")
+
+	_, err = service.CreateExternalUser("not-an-email")
+	assert.Error(t, err)
+}
+
+func TestGetTasksForUserRole(t *testing.T) {
+	user := rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+
+	tests := []struct {
+		name    string
+		role    string
+		wantErr string
+	}{
+		{name: "без фильтра - свои и назначенные", role: ""},
+		{name: "только свои", role: TaskRoleOwner},
+		{name: "только назначенные", role: TaskRoleAssignee},
+		{name: "неизвестная роль", role: "watcher", wantErr: "invalid role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			if tt.wantErr == "" {
+				mockRepo.On("GetTasksForUser", uint(2), tt.role).Return([]taskService.TaskStruct{
+					{ID: 7, Task: "Чужая задача", UserId: 1, Assignees: []taskService.TaskAssigneeStruct{{TaskID: 7, UserID: 2}}},
+				}, nil).Once()
+			}
+
+			tasks, err := NewUserService(mockRepo).GetTasksForUser(user, 2, tt.role)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "GetTasksForUser", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []uint{2}, tasks[0].Assignees)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
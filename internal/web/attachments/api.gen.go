@@ -0,0 +1,786 @@
+//go:build go1.22
+
+// Package attachments provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Attachment defines model for Attachment.
+type Attachment struct {
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+	Id          uint      `json:"id"`
+	Name        string    `json:"name"`
+
+	// Sha256 Hex SHA-256 of the content (also the ETag of the download)
+	Sha256 string `json:"sha256"`
+
+	// Size Size in bytes
+	Size       int64 `json:"size"`
+	TaskId     uint  `json:"task_id"`
+	UploaderId uint  `json:"uploader_id"`
+}
+
+// PostTasksIdAttachmentsMultipartBody defines parameters for PostTasksIdAttachments.
+type PostTasksIdAttachmentsMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// GetTasksIdAttachmentsAttachmentIdParams defines parameters for GetTasksIdAttachmentsAttachmentId.
+type GetTasksIdAttachmentsAttachmentIdParams struct {
+	Range *string `json:"Range,omitempty"`
+
+	// IfRange ETag of the attachment; if it does not match, Range is ignored
+	IfRange *string `json:"If-Range,omitempty"`
+}
+
+// PostTasksIdAttachmentsMultipartRequestBody defines body for PostTasksIdAttachments for multipart/form-data ContentType.
+type PostTasksIdAttachmentsMultipartRequestBody PostTasksIdAttachmentsMultipartBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Files attached to a task, oldest first
+	// (GET /tasks/{id}/attachments)
+	GetTasksIdAttachments(w http.ResponseWriter, r *http.Request, id uint)
+	// Attach a file to a task
+	// (POST /tasks/{id}/attachments)
+	PostTasksIdAttachments(w http.ResponseWriter, r *http.Request, id uint)
+	// Delete an attachment
+	// (DELETE /tasks/{id}/attachments/{attachmentId})
+	DeleteTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request, id uint, attachmentId uint)
+	// Download an attachment
+	// (GET /tasks/{id}/attachments/{attachmentId})
+	GetTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request, id uint, attachmentId uint, params GetTasksIdAttachmentsAttachmentIdParams)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetTasksIdAttachments operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdAttachments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdAttachments(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTasksIdAttachments operation middleware
+func (siw *ServerInterfaceWrapper) PostTasksIdAttachments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTasksIdAttachments(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteTasksIdAttachmentsAttachmentId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "attachmentId" -------------
+	var attachmentId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "attachmentId", r.PathValue("attachmentId"), &attachmentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "attachmentId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTasksIdAttachmentsAttachmentId(w, r, id, attachmentId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksIdAttachmentsAttachmentId operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "attachmentId" -------------
+	var attachmentId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "attachmentId", r.PathValue("attachmentId"), &attachmentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "attachmentId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksIdAttachmentsAttachmentIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Range" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Range")]; found {
+		var Range string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Range", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Range", valueList[0], &Range, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Range", Err: err})
+			return
+		}
+
+		params.Range = &Range
+
+	}
+
+	// ------------- Optional header parameter "If-Range" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Range")]; found {
+		var IfRange string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Range", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Range", valueList[0], &IfRange, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Range", Err: err})
+			return
+		}
+
+		params.IfRange = &IfRange
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdAttachmentsAttachmentId(w, r, id, attachmentId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/attachments", wrapper.GetTasksIdAttachments)
+	m.HandleFunc("POST "+options.BaseURL+"/tasks/{id}/attachments", wrapper.PostTasksIdAttachments)
+	m.HandleFunc("DELETE "+options.BaseURL+"/tasks/{id}/attachments/{attachmentId}", wrapper.DeleteTasksIdAttachmentsAttachmentId)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/attachments/{attachmentId}", wrapper.GetTasksIdAttachmentsAttachmentId)
+
+	return m
+}
+
+type UnauthorizedResponse struct {
+}
+
+type GetTasksIdAttachmentsRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetTasksIdAttachmentsResponseObject interface {
+	VisitGetTasksIdAttachmentsResponse(w http.ResponseWriter) error
+}
+
+type GetTasksIdAttachments200JSONResponse []Attachment
+
+func (response GetTasksIdAttachments200JSONResponse) VisitGetTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasksIdAttachments401Response = UnauthorizedResponse
+
+func (response GetTasksIdAttachments401Response) VisitGetTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksIdAttachments404Response struct {
+}
+
+func (response GetTasksIdAttachments404Response) VisitGetTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasksIdAttachmentsRequestObject struct {
+	Id   uint `json:"id"`
+	Body *multipart.Reader
+}
+
+type PostTasksIdAttachmentsResponseObject interface {
+	VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error
+}
+
+type PostTasksIdAttachments201JSONResponse Attachment
+
+func (response PostTasksIdAttachments201JSONResponse) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTasksIdAttachments400Response struct {
+}
+
+func (response PostTasksIdAttachments400Response) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostTasksIdAttachments401Response = UnauthorizedResponse
+
+func (response PostTasksIdAttachments401Response) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostTasksIdAttachments403Response struct {
+}
+
+func (response PostTasksIdAttachments403Response) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostTasksIdAttachments404Response struct {
+}
+
+func (response PostTasksIdAttachments404Response) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasksIdAttachments413Response struct {
+}
+
+func (response PostTasksIdAttachments413Response) VisitPostTasksIdAttachmentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(413)
+	return nil
+}
+
+type DeleteTasksIdAttachmentsAttachmentIdRequestObject struct {
+	Id           uint `json:"id"`
+	AttachmentId uint `json:"attachmentId"`
+}
+
+type DeleteTasksIdAttachmentsAttachmentIdResponseObject interface {
+	VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteTasksIdAttachmentsAttachmentId204Response struct {
+}
+
+func (response DeleteTasksIdAttachmentsAttachmentId204Response) VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteTasksIdAttachmentsAttachmentId401Response = UnauthorizedResponse
+
+func (response DeleteTasksIdAttachmentsAttachmentId401Response) VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteTasksIdAttachmentsAttachmentId403Response struct {
+}
+
+func (response DeleteTasksIdAttachmentsAttachmentId403Response) VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteTasksIdAttachmentsAttachmentId404Response struct {
+}
+
+func (response DeleteTasksIdAttachmentsAttachmentId404Response) VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetTasksIdAttachmentsAttachmentIdRequestObject struct {
+	Id           uint `json:"id"`
+	AttachmentId uint `json:"attachmentId"`
+	Params       GetTasksIdAttachmentsAttachmentIdParams
+}
+
+type GetTasksIdAttachmentsAttachmentIdResponseObject interface {
+	VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error
+}
+
+type GetTasksIdAttachmentsAttachmentId200ResponseHeaders struct {
+	AcceptRanges       string
+	ContentDisposition string
+	ETag               string
+}
+
+type GetTasksIdAttachmentsAttachmentId200AsteriskResponse struct {
+	Body          io.Reader
+	Headers       GetTasksIdAttachmentsAttachmentId200ResponseHeaders
+	ContentType   string
+	ContentLength int64
+}
+
+func (response GetTasksIdAttachmentsAttachmentId200AsteriskResponse) VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Accept-Ranges", fmt.Sprint(response.Headers.AcceptRanges))
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetTasksIdAttachmentsAttachmentId206ResponseHeaders struct {
+	AcceptRanges       string
+	ContentDisposition string
+	ContentRange       string
+	ETag               string
+}
+
+type GetTasksIdAttachmentsAttachmentId206AsteriskResponse struct {
+	Body          io.Reader
+	Headers       GetTasksIdAttachmentsAttachmentId206ResponseHeaders
+	ContentType   string
+	ContentLength int64
+}
+
+func (response GetTasksIdAttachmentsAttachmentId206AsteriskResponse) VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Accept-Ranges", fmt.Sprint(response.Headers.AcceptRanges))
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.Header().Set("Content-Range", fmt.Sprint(response.Headers.ContentRange))
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(206)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetTasksIdAttachmentsAttachmentId401Response = UnauthorizedResponse
+
+func (response GetTasksIdAttachmentsAttachmentId401Response) VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksIdAttachmentsAttachmentId404Response struct {
+}
+
+func (response GetTasksIdAttachmentsAttachmentId404Response) VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetTasksIdAttachmentsAttachmentId416ResponseHeaders struct {
+	ContentRange string
+}
+
+type GetTasksIdAttachmentsAttachmentId416Response struct {
+	Headers GetTasksIdAttachmentsAttachmentId416ResponseHeaders
+}
+
+func (response GetTasksIdAttachmentsAttachmentId416Response) VisitGetTasksIdAttachmentsAttachmentIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Range", fmt.Sprint(response.Headers.ContentRange))
+	w.WriteHeader(416)
+	return nil
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Files attached to a task, oldest first
+	// (GET /tasks/{id}/attachments)
+	GetTasksIdAttachments(ctx context.Context, request GetTasksIdAttachmentsRequestObject) (GetTasksIdAttachmentsResponseObject, error)
+	// Attach a file to a task
+	// (POST /tasks/{id}/attachments)
+	PostTasksIdAttachments(ctx context.Context, request PostTasksIdAttachmentsRequestObject) (PostTasksIdAttachmentsResponseObject, error)
+	// Delete an attachment
+	// (DELETE /tasks/{id}/attachments/{attachmentId})
+	DeleteTasksIdAttachmentsAttachmentId(ctx context.Context, request DeleteTasksIdAttachmentsAttachmentIdRequestObject) (DeleteTasksIdAttachmentsAttachmentIdResponseObject, error)
+	// Download an attachment
+	// (GET /tasks/{id}/attachments/{attachmentId})
+	GetTasksIdAttachmentsAttachmentId(ctx context.Context, request GetTasksIdAttachmentsAttachmentIdRequestObject) (GetTasksIdAttachmentsAttachmentIdResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetTasksIdAttachments operation middleware
+func (sh *strictHandler) GetTasksIdAttachments(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetTasksIdAttachmentsRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdAttachments(ctx, request.(GetTasksIdAttachmentsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdAttachments")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdAttachmentsResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdAttachmentsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostTasksIdAttachments operation middleware
+func (sh *strictHandler) PostTasksIdAttachments(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PostTasksIdAttachmentsRequestObject
+
+	request.Id = id
+
+	if reader, err := r.MultipartReader(); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode multipart body: %w", err))
+		return
+	} else {
+		request.Body = reader
+	}
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostTasksIdAttachments(ctx, request.(PostTasksIdAttachmentsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostTasksIdAttachments")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostTasksIdAttachmentsResponseObject); ok {
+		if err := validResponse.VisitPostTasksIdAttachmentsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteTasksIdAttachmentsAttachmentId operation middleware
+func (sh *strictHandler) DeleteTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request, id uint, attachmentId uint) {
+	var request DeleteTasksIdAttachmentsAttachmentIdRequestObject
+
+	request.Id = id
+	request.AttachmentId = attachmentId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteTasksIdAttachmentsAttachmentId(ctx, request.(DeleteTasksIdAttachmentsAttachmentIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteTasksIdAttachmentsAttachmentId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteTasksIdAttachmentsAttachmentIdResponseObject); ok {
+		if err := validResponse.VisitDeleteTasksIdAttachmentsAttachmentIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasksIdAttachmentsAttachmentId operation middleware
+func (sh *strictHandler) GetTasksIdAttachmentsAttachmentId(w http.ResponseWriter, r *http.Request, id uint, attachmentId uint, params GetTasksIdAttachmentsAttachmentIdParams) {
+	var request GetTasksIdAttachmentsAttachmentIdRequestObject
+
+	request.Id = id
+	request.AttachmentId = attachmentId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdAttachmentsAttachmentId(ctx, request.(GetTasksIdAttachmentsAttachmentIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdAttachmentsAttachmentId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdAttachmentsAttachmentIdResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdAttachmentsAttachmentIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
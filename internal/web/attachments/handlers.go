@@ -0,0 +1,183 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mime"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/attachmentService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+)
+
+// handlers вложений (как в tasks: только маппинг HTTP <-> сервис)
+// скачивание отдает поток из хранилища без буферизации; ETag - sha256 содержимого
+
+type AttachmentHandler struct {
+	service *attachmentService.AttachmentService
+}
+
+func NewAttachmentHandler(s *attachmentService.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{service: s}
+}
+
+// toAPIAttachment - маппит бизнес-модель в апи-модель
+func toAPIAttachment(a *attachmentService.Attachment) Attachment {
+	return Attachment{
+		Id:          a.ID,
+		TaskId:      a.TaskID,
+		UploaderId:  a.UploaderID,
+		Name:        a.Name,
+		Size:        a.Size,
+		ContentType: a.ContentType,
+		Sha256:      a.SHA256,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// attachmentETag - строгий тег из хэша содержимого
+func attachmentETag(a *attachmentService.Attachment) string {
+	return `"` + a.SHA256 + `"`
+}
+
+// contentDisposition - всегда attachment: загруженный html не должен открываться в браузере как страница нашего сайта
+// (не-ASCII имена mime кодирует по RFC 2231 в параметр filename*)
+func contentDisposition(name string) string {
+	if value := mime.FormatMediaType("attachment", map[string]string{"filename": name}); value != "" {
+		return value
+	}
+	return "attachment"
+}
+
+func (h *AttachmentHandler) GetTasksIdAttachments(ctx context.Context, req GetTasksIdAttachmentsRequestObject) (GetTasksIdAttachmentsResponseObject, error) {
+	attachments, err := h.service.GetAttachments(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return GetTasksIdAttachments404Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetTasksIdAttachments200JSONResponse, 0, len(attachments))
+	for i := range attachments {
+		response = append(response, toAPIAttachment(&attachments[i]))
+	}
+	return response, nil
+}
+
+func (h *AttachmentHandler) PostTasksIdAttachments(ctx context.Context, req PostTasksIdAttachmentsRequestObject) (PostTasksIdAttachmentsResponseObject, error) {
+	// ищем часть file; остальные поля формы пропускаем
+	var part *multipart.Part
+	for {
+		p, err := req.Body.NextPart()
+		if err != nil {
+			// io.EOF - в форме нет файла; иначе - битое multipart-тело
+			return PostTasksIdAttachments400Response{}, nil
+		}
+		if p.FormName() == "file" {
+			part = p
+			break
+		}
+	}
+
+	attachment, err := h.service.Upload(ctx, authn.Actor(ctx), req.Id, attachmentService.UploadParams{
+		Name:        part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Body:        part,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return PostTasksIdAttachments404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostTasksIdAttachments403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "file is empty") {
+			return PostTasksIdAttachments400Response{}, nil
+		}
+		if strings.Contains(err.Error(), "file is too large") {
+			return PostTasksIdAttachments413Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Attachment %d (%d bytes) added to task %d", attachment.ID, attachment.Size, req.Id)
+
+	return PostTasksIdAttachments201JSONResponse(toAPIAttachment(attachment)), nil
+}
+
+func (h *AttachmentHandler) GetTasksIdAttachmentsAttachmentId(ctx context.Context, req GetTasksIdAttachmentsAttachmentIdRequestObject) (GetTasksIdAttachmentsAttachmentIdResponseObject, error) {
+	attachment, err := h.service.GetAttachment(authn.Actor(ctx), req.Id, req.AttachmentId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return GetTasksIdAttachmentsAttachmentId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	tag := attachmentETag(attachment)
+
+	// If-Range: диапазон только для той же версии файла (сравнение строгое, дату не поддерживаем)
+	var rng *byteRange
+	if req.Params.Range != nil && (req.Params.IfRange == nil || strings.TrimSpace(*req.Params.IfRange) == tag) {
+		rng, err = parseRange(*req.Params.Range, attachment.Size)
+		if errors.Is(err, errRangeNotSatisfiable) {
+			return GetTasksIdAttachmentsAttachmentId416Response{
+				Headers: GetTasksIdAttachmentsAttachmentId416ResponseHeaders{ContentRange: "bytes */" + strconv.FormatInt(attachment.Size, 10)},
+			}, nil
+		}
+	}
+
+	if rng == nil {
+		body, err := h.service.Open(ctx, attachment, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		return GetTasksIdAttachmentsAttachmentId200AsteriskResponse{
+			Body:          body,
+			ContentType:   attachment.ContentType,
+			ContentLength: attachment.Size,
+			Headers: GetTasksIdAttachmentsAttachmentId200ResponseHeaders{
+				AcceptRanges:       "bytes",
+				ContentDisposition: contentDisposition(attachment.Name),
+				ETag:               tag,
+			},
+		}, nil
+	}
+
+	body, err := h.service.Open(ctx, attachment, rng.start, rng.length)
+	if err != nil {
+		return nil, err
+	}
+	return GetTasksIdAttachmentsAttachmentId206AsteriskResponse{
+		Body:          body,
+		ContentType:   attachment.ContentType,
+		ContentLength: rng.length,
+		Headers: GetTasksIdAttachmentsAttachmentId206ResponseHeaders{
+			AcceptRanges:       "bytes",
+			ContentDisposition: contentDisposition(attachment.Name),
+			ContentRange:       rng.contentRange(attachment.Size),
+			ETag:               tag,
+		},
+	}, nil
+}
+
+func (h *AttachmentHandler) DeleteTasksIdAttachmentsAttachmentId(ctx context.Context, req DeleteTasksIdAttachmentsAttachmentIdRequestObject) (DeleteTasksIdAttachmentsAttachmentIdResponseObject, error) {
+	err := h.service.Delete(authn.Actor(ctx), req.Id, req.AttachmentId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return DeleteTasksIdAttachmentsAttachmentId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteTasksIdAttachmentsAttachmentId403Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Attachment %d deleted from task %d", req.AttachmentId, req.Id)
+
+	return DeleteTasksIdAttachmentsAttachmentId204Response{}, nil
+}
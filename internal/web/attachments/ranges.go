@@ -0,0 +1,81 @@
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// разбор заголовка Range (RFC 9110, раздел 14.2) - поддерживается один диапазон байт:
+//   • bytes=0-99  - с 0 по 99 байт включительно
+//   • bytes=100-  - с 100 байта до конца
+//   • bytes=-100  - последние 100 байт
+// несколько диапазонов и битый синтаксис сервер вправе игнорировать - тогда отдаем файл целиком;
+// диапазон, который начинается за концом файла, - 416
+
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// byteRange - length байт начиная со start
+type byteRange struct {
+	start  int64
+	length int64
+}
+
+// contentRange - значение заголовка Content-Range для ответа 206
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// parseRange - диапазон из заголовка для файла размером size
+// nil без ошибки - диапазона нет (или он игнорируется), отдаем весь файл
+func parseRange(header string, size int64) (*byteRange, error) {
+	unit, spec, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok || !strings.EqualFold(strings.TrimSpace(unit), "bytes") || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, nil
+	}
+
+	// суффикс: последние n байт
+	if first == "" {
+		n, ok := parseOffset(last)
+		if !ok {
+			return nil, nil
+		}
+		if n == 0 || size == 0 {
+			return nil, errRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return &byteRange{start: size - n, length: n}, nil
+	}
+
+	start, ok := parseOffset(first)
+	if !ok {
+		return nil, nil
+	}
+	end := size - 1
+	if last != "" {
+		e, ok := parseOffset(last)
+		if !ok || e < start {
+			return nil, nil
+		}
+		end = min(e, size-1)
+	}
+	if start >= size {
+		return nil, errRangeNotSatisfiable
+	}
+	return &byteRange{start: start, length: end - start + 1}, nil
+}
+
+// parseOffset - только цифры (без знака и пробелов), как требует грамматика Range
+func parseOffset(s string) (int64, bool) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
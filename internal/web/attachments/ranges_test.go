@@ -0,0 +1,50 @@
+package attachments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	const size = 1000
+
+	tests := []struct {
+		name      string
+		header    string
+		want      *byteRange
+		wantRange string // Content-Range ответа 206
+		wantErr   bool
+	}{
+		{name: "начало файла", header: "bytes=0-99", want: &byteRange{start: 0, length: 100}, wantRange: "bytes 0-99/1000"},
+		{name: "с позиции до конца", header: "bytes=900-", want: &byteRange{start: 900, length: 100}, wantRange: "bytes 900-999/1000"},
+		{name: "последние байты", header: "bytes=-10", want: &byteRange{start: 990, length: 10}, wantRange: "bytes 990-999/1000"},
+		{name: "конец за пределами файла обрезается", header: "bytes=990-5000", want: &byteRange{start: 990, length: 10}, wantRange: "bytes 990-999/1000"},
+		{name: "суффикс длиннее файла - весь файл", header: "bytes=-5000", want: &byteRange{start: 0, length: 1000}, wantRange: "bytes 0-999/1000"},
+		{name: "один байт", header: "bytes=5-5", want: &byteRange{start: 5, length: 1}, wantRange: "bytes 5-5/1000"},
+		{name: "единица в другом регистре", header: "Bytes=0-0", want: &byteRange{start: 0, length: 1}, wantRange: "bytes 0-0/1000"},
+		{name: "начало за концом файла", header: "bytes=1000-", wantErr: true},
+		{name: "пустой суффикс", header: "bytes=-0", wantErr: true},
+		{name: "несколько диапазонов игнорируются", header: "bytes=0-1,5-6"},
+		{name: "конец раньше начала игнорируется", header: "bytes=10-5"},
+		{name: "другая единица игнорируется", header: "items=0-5"},
+		{name: "битый синтаксис игнорируется", header: "bytes=abc"},
+		{name: "знак в числе игнорируется", header: "bytes=+1-5"},
+		{name: "пустой заголовок", header: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRange(tt.header, size)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errRangeNotSatisfiable)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			if got != nil {
+				assert.Equal(t, tt.wantRange, got.contentRange(size))
+			}
+		})
+	}
+}
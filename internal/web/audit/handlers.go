@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+)
+
+// handlers журнала аудита: только чтение, права (только админ) проверяет сервис
+
+type AuditHandler struct {
+	service *audit.AuditService
+}
+
+func NewAuditHandler(s *audit.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// GetAudit - выборка из журнала по фильтрам с пагинацией
+func (h *AuditHandler) GetAudit(ctx context.Context, req GetAuditRequestObject) (GetAuditResponseObject, error) {
+	params := audit.QueryParams{
+		EntityID:  req.Params.EntityId,
+		ActorID:   req.Params.ActorId,
+		RequestID: optional(req.Params.RequestId),
+		From:      req.Params.From,
+		To:        req.Params.To,
+		Limit:     req.Params.Limit,
+		Offset:    req.Params.Offset,
+	}
+	if req.Params.EntityType != nil {
+		params.EntityType = string(*req.Params.EntityType)
+	}
+	if req.Params.Action != nil {
+		params.Action = string(*req.Params.Action)
+	}
+
+	page, err := h.service.Query(authn.Actor(ctx), params)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetAudit403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "unknown") ||
+			strings.Contains(err.Error(), "must be") ||
+			strings.Contains(err.Error(), "cannot be negative") {
+			return GetAudit400Response{}, nil
+		}
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	items := make([]AuditEntry, 0, len(page.Items))
+	for _, e := range page.Items {
+		items = append(items, toAPIEntry(e))
+	}
+	return GetAudit200JSONResponse{
+		Items:  items,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
+}
+
+func toAPIEntry(e audit.Entry) AuditEntry {
+	entry := AuditEntry{
+		Id:         e.ID,
+		EntityType: AuditEntryEntityType(e.EntityType),
+		EntityId:   e.EntityID,
+		Action:     AuditEntryAction(e.Action),
+		ActorId:    e.ActorID,
+		Changes:    make(map[string]AuditFieldChange, len(e.Changes)),
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.RequestID != "" {
+		entry.RequestId = &e.RequestID
+	}
+	if e.Before != nil {
+		entry.Before = &e.Before
+	}
+	if e.After != nil {
+		entry.After = &e.After
+	}
+	for field, c := range e.Changes {
+		entry.Changes[field] = AuditFieldChange{From: c.From, To: c.To}
+	}
+	return entry
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,2244 @@
+//go:build go1.22
+
+// Package auth provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for TokenScope.
+const (
+	TasksRead  TokenScope = "tasks:read"
+	TasksWrite TokenScope = "tasks:write"
+	UsersRead  TokenScope = "users:read"
+	UsersWrite TokenScope = "users:write"
+)
+
+// CreatePersonalAccessTokenRequest defines model for CreatePersonalAccessTokenRequest.
+type CreatePersonalAccessTokenRequest struct {
+	// ExpiresAt Optional expiry; without it the token lives until revoked
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+	Name      string       `json:"name"`
+	Scopes    []TokenScope `json:"scopes"`
+}
+
+// CreatedPersonalAccessToken defines model for CreatedPersonalAccessToken.
+type CreatedPersonalAccessToken struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Id         uint       `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Name       string     `json:"name"`
+
+	// Prefix First characters of the token, to recognise it
+	Prefix string       `json:"prefix"`
+	Scopes []TokenScope `json:"scopes"`
+
+	// Token The token secret - shown only once
+	Token string `json:"token"`
+}
+
+// EmailVerified defines model for EmailVerified.
+type EmailVerified struct {
+	Email      openapi_types.Email `json:"email"`
+	VerifiedAt time.Time           `json:"verified_at"`
+}
+
+// LoginRequest defines model for LoginRequest.
+type LoginRequest struct {
+	// DeviceName Optional human-readable name of the device, shown in the session list
+	DeviceName *string             `json:"device_name,omitempty"`
+	Email      openapi_types.Email `json:"email"`
+	Password   string              `json:"password"`
+}
+
+// MFAChallenge defines model for MFAChallenge.
+type MFAChallenge struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	MfaRequired bool      `json:"mfa_required"`
+
+	// MfaToken Single-use token for POST /auth/login/2fa
+	MfaToken string `json:"mfa_token"`
+}
+
+// PasswordResetConfirmRequest defines model for PasswordResetConfirmRequest.
+type PasswordResetConfirmRequest struct {
+	NewPassword string `json:"new_password"`
+	Token       string `json:"token"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// PersonalAccessToken defines model for PersonalAccessToken.
+type PersonalAccessToken struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Id         uint       `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Name       string     `json:"name"`
+
+	// Prefix First characters of the token, to recognise it
+	Prefix string       `json:"prefix"`
+	Scopes []TokenScope `json:"scopes"`
+}
+
+// PolicyViolation defines model for PolicyViolation.
+type PolicyViolation struct {
+	Message string `json:"message"`
+	Rule    string `json:"rule"`
+}
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// RefreshRequest defines model for RefreshRequest.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SecondFactorRequest defines model for SecondFactorRequest.
+type SecondFactorRequest struct {
+	// Code Code from the authenticator app or one of the recovery codes
+	Code     string `json:"code"`
+	MfaToken string `json:"mfa_token"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	// Current True for the session the request was made with
+	Current     bool      `json:"current"`
+	DeviceName  *string   `json:"device_name,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Id          string    `json:"id"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	StartedAt   time.Time `json:"started_at"`
+	UserAgent   *string   `json:"user_agent,omitempty"`
+}
+
+// TOTPEnrollment defines model for TOTPEnrollment.
+type TOTPEnrollment struct {
+	// OtpauthUri otpauth://totp/... URI for a QR code
+	OtpauthUri string `json:"otpauth_uri"`
+
+	// Secret Base32 secret for typing into the app by hand
+	Secret string `json:"secret"`
+}
+
+// TokenPair defines model for TokenPair.
+type TokenPair struct {
+	AccessToken string `json:"access_token"`
+
+	// ExpiresIn Access token lifetime in seconds
+	ExpiresIn             int       `json:"expires_in"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+	SessionId             string    `json:"session_id"`
+	TokenType             string    `json:"token_type"`
+}
+
+// TokenScope defines model for TokenScope.
+type TokenScope string
+
+// TwoFactorCodeRequest defines model for TwoFactorCodeRequest.
+type TwoFactorCodeRequest struct {
+	// Code Code from the authenticator app (or a recovery code where allowed)
+	Code string `json:"code"`
+}
+
+// TwoFactorStatus defines model for TwoFactorStatus.
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+// ValidationError defines model for ValidationError.
+type ValidationError struct {
+	Error      string             `json:"error"`
+	Field      *string            `json:"field,omitempty"`
+	Violations *[]PolicyViolation `json:"violations,omitempty"`
+}
+
+// ValidationFailed defines model for ValidationFailed.
+type ValidationFailed = ValidationError
+
+// PostAuthLoginParams defines parameters for PostAuthLogin.
+type PostAuthLoginParams struct {
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
+// GetAuthOidcCallbackParams defines parameters for GetAuthOidcCallback.
+type GetAuthOidcCallbackParams struct {
+	State string  `form:"state" json:"state"`
+	Code  *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// Error Set by the provider when the login was cancelled or refused
+	Error     *string `form:"error,omitempty" json:"error,omitempty"`
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
+// GetAuthVerifyParams defines parameters for GetAuthVerify.
+type GetAuthVerifyParams struct {
+	Token string `form:"token" json:"token"`
+}
+
+// PostAuth2faRecoveryCodesJSONRequestBody defines body for PostAuth2faRecoveryCodes for application/json ContentType.
+type PostAuth2faRecoveryCodesJSONRequestBody = TwoFactorCodeRequest
+
+// PostAuth2faTotpConfirmJSONRequestBody defines body for PostAuth2faTotpConfirm for application/json ContentType.
+type PostAuth2faTotpConfirmJSONRequestBody = TwoFactorCodeRequest
+
+// PostAuth2faTotpDisableJSONRequestBody defines body for PostAuth2faTotpDisable for application/json ContentType.
+type PostAuth2faTotpDisableJSONRequestBody = TwoFactorCodeRequest
+
+// PostAuthLoginJSONRequestBody defines body for PostAuthLogin for application/json ContentType.
+type PostAuthLoginJSONRequestBody = LoginRequest
+
+// PostAuthLogin2faJSONRequestBody defines body for PostAuthLogin2fa for application/json ContentType.
+type PostAuthLogin2faJSONRequestBody = SecondFactorRequest
+
+// PostAuthPasswordResetJSONRequestBody defines body for PostAuthPasswordReset for application/json ContentType.
+type PostAuthPasswordResetJSONRequestBody = PasswordResetRequest
+
+// PostAuthPasswordResetConfirmJSONRequestBody defines body for PostAuthPasswordResetConfirm for application/json ContentType.
+type PostAuthPasswordResetConfirmJSONRequestBody = PasswordResetConfirmRequest
+
+// PostAuthRefreshJSONRequestBody defines body for PostAuthRefresh for application/json ContentType.
+type PostAuthRefreshJSONRequestBody = RefreshRequest
+
+// PostAuthTokensJSONRequestBody defines body for PostAuthTokens for application/json ContentType.
+type PostAuthTokensJSONRequestBody = CreatePersonalAccessTokenRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Show whether two-factor authentication is enabled for me
+	// (GET /auth/2fa)
+	GetAuth2fa(w http.ResponseWriter, r *http.Request)
+	// Replace my recovery codes with a new set
+	// (POST /auth/2fa/recovery-codes)
+	PostAuth2faRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	// Start enrolling an authenticator app (TOTP)
+	// (POST /auth/2fa/totp)
+	PostAuth2faTotp(w http.ResponseWriter, r *http.Request)
+	// Enable two-factor authentication with the first code from the app
+	// (POST /auth/2fa/totp/confirm)
+	PostAuth2faTotpConfirm(w http.ResponseWriter, r *http.Request)
+	// Disable two-factor authentication
+	// (POST /auth/2fa/totp/disable)
+	PostAuth2faTotpDisable(w http.ResponseWriter, r *http.Request)
+	// Log in with email and password and start a new session
+	// (POST /auth/login)
+	PostAuthLogin(w http.ResponseWriter, r *http.Request, params PostAuthLoginParams)
+	// Finish a login with a code from the authenticator app or a recovery code
+	// (POST /auth/login/2fa)
+	PostAuthLogin2fa(w http.ResponseWriter, r *http.Request)
+	// Finish logging in with the external OpenID Connect provider
+	// (GET /auth/oidc/callback)
+	GetAuthOidcCallback(w http.ResponseWriter, r *http.Request, params GetAuthOidcCallbackParams)
+	// Start logging in with the external OpenID Connect provider
+	// (GET /auth/oidc/login)
+	GetAuthOidcLogin(w http.ResponseWriter, r *http.Request)
+	// Request a password reset email
+	// (POST /auth/password-reset)
+	PostAuthPasswordReset(w http.ResponseWriter, r *http.Request)
+	// Set a new password using the token from the reset email
+	// (POST /auth/password-reset/confirm)
+	PostAuthPasswordResetConfirm(w http.ResponseWriter, r *http.Request)
+	// Exchange a refresh token for a new token pair
+	// (POST /auth/refresh)
+	PostAuthRefresh(w http.ResponseWriter, r *http.Request)
+	// Revoke all my sessions (log out everywhere)
+	// (DELETE /auth/sessions)
+	DeleteAuthSessions(w http.ResponseWriter, r *http.Request)
+	// List my active sessions
+	// (GET /auth/sessions)
+	GetAuthSessions(w http.ResponseWriter, r *http.Request)
+	// Revoke one of my sessions
+	// (DELETE /auth/sessions/{id})
+	DeleteAuthSessionsId(w http.ResponseWriter, r *http.Request, id string)
+	// List my personal access tokens
+	// (GET /auth/tokens)
+	GetAuthTokens(w http.ResponseWriter, r *http.Request)
+	// Create a personal access token for scripts and CI
+	// (POST /auth/tokens)
+	PostAuthTokens(w http.ResponseWriter, r *http.Request)
+	// Revoke one of my personal access tokens
+	// (DELETE /auth/tokens/{id})
+	DeleteAuthTokensId(w http.ResponseWriter, r *http.Request, id uint)
+	// Confirm the user's email with the token from the verification email
+	// (GET /auth/verify)
+	GetAuthVerify(w http.ResponseWriter, r *http.Request, params GetAuthVerifyParams)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetAuth2fa operation middleware
+func (siw *ServerInterfaceWrapper) GetAuth2fa(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuth2fa(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuth2faRecoveryCodes operation middleware
+func (siw *ServerInterfaceWrapper) PostAuth2faRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuth2faRecoveryCodes(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuth2faTotp operation middleware
+func (siw *ServerInterfaceWrapper) PostAuth2faTotp(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuth2faTotp(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuth2faTotpConfirm operation middleware
+func (siw *ServerInterfaceWrapper) PostAuth2faTotpConfirm(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuth2faTotpConfirm(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuth2faTotpDisable operation middleware
+func (siw *ServerInterfaceWrapper) PostAuth2faTotpDisable(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuth2faTotpDisable(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthLogin operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthLogin(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAuthLoginParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "User-Agent" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("User-Agent")]; found {
+		var UserAgent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "User-Agent", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "User-Agent", valueList[0], &UserAgent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "User-Agent", Err: err})
+			return
+		}
+
+		params.UserAgent = &UserAgent
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthLogin(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthLogin2fa operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthLogin2fa(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthLogin2fa(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthOidcCallback operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthOidcCallback(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuthOidcCallbackParams
+
+	// ------------- Required query parameter "state" -------------
+
+	if paramValue := r.URL.Query().Get("state"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "state"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "state", r.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "code", r.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", r.URL.Query(), &params.Error)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "error", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "User-Agent" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("User-Agent")]; found {
+		var UserAgent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "User-Agent", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "User-Agent", valueList[0], &UserAgent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "User-Agent", Err: err})
+			return
+		}
+
+		params.UserAgent = &UserAgent
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthOidcCallback(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthOidcLogin operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthOidcLogin(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthOidcLogin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthPasswordReset operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthPasswordReset(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthPasswordReset(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthPasswordResetConfirm operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthPasswordResetConfirm(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthPasswordResetConfirm(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthRefresh operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthRefresh(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthRefresh(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAuthSessions operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAuthSessions(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAuthSessions(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthSessions operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthSessions(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthSessions(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAuthSessionsId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAuthSessionsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAuthSessionsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthTokens operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthTokens operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAuthTokensId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAuthTokensId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAuthTokensId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthVerify operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthVerify(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuthVerifyParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthVerify(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/auth/2fa", wrapper.GetAuth2fa)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/2fa/recovery-codes", wrapper.PostAuth2faRecoveryCodes)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/2fa/totp", wrapper.PostAuth2faTotp)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/2fa/totp/confirm", wrapper.PostAuth2faTotpConfirm)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/2fa/totp/disable", wrapper.PostAuth2faTotpDisable)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/login", wrapper.PostAuthLogin)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/login/2fa", wrapper.PostAuthLogin2fa)
+	m.HandleFunc("GET "+options.BaseURL+"/auth/oidc/callback", wrapper.GetAuthOidcCallback)
+	m.HandleFunc("GET "+options.BaseURL+"/auth/oidc/login", wrapper.GetAuthOidcLogin)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/password-reset", wrapper.PostAuthPasswordReset)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/password-reset/confirm", wrapper.PostAuthPasswordResetConfirm)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/refresh", wrapper.PostAuthRefresh)
+	m.HandleFunc("DELETE "+options.BaseURL+"/auth/sessions", wrapper.DeleteAuthSessions)
+	m.HandleFunc("GET "+options.BaseURL+"/auth/sessions", wrapper.GetAuthSessions)
+	m.HandleFunc("DELETE "+options.BaseURL+"/auth/sessions/{id}", wrapper.DeleteAuthSessionsId)
+	m.HandleFunc("GET "+options.BaseURL+"/auth/tokens", wrapper.GetAuthTokens)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/tokens", wrapper.PostAuthTokens)
+	m.HandleFunc("DELETE "+options.BaseURL+"/auth/tokens/{id}", wrapper.DeleteAuthTokensId)
+	m.HandleFunc("GET "+options.BaseURL+"/auth/verify", wrapper.GetAuthVerify)
+
+	return m
+}
+
+type SessionRequiredResponse struct {
+}
+
+type UnauthorizedResponse struct {
+}
+
+type ValidationFailedJSONResponse ValidationError
+
+type GetAuth2faRequestObject struct {
+}
+
+type GetAuth2faResponseObject interface {
+	VisitGetAuth2faResponse(w http.ResponseWriter) error
+}
+
+type GetAuth2fa200JSONResponse TwoFactorStatus
+
+func (response GetAuth2fa200JSONResponse) VisitGetAuth2faResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuth2fa401Response = UnauthorizedResponse
+
+func (response GetAuth2fa401Response) VisitGetAuth2faResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetAuth2fa403Response = SessionRequiredResponse
+
+func (response GetAuth2fa403Response) VisitGetAuth2faResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuth2faRecoveryCodesRequestObject struct {
+	Body *PostAuth2faRecoveryCodesJSONRequestBody
+}
+
+type PostAuth2faRecoveryCodesResponseObject interface {
+	VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error
+}
+
+type PostAuth2faRecoveryCodes200JSONResponse RecoveryCodes
+
+func (response PostAuth2faRecoveryCodes200JSONResponse) VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faRecoveryCodes400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostAuth2faRecoveryCodes400JSONResponse) VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faRecoveryCodes401Response = UnauthorizedResponse
+
+func (response PostAuth2faRecoveryCodes401Response) VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuth2faRecoveryCodes403Response = SessionRequiredResponse
+
+func (response PostAuth2faRecoveryCodes403Response) VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuth2faRecoveryCodes409Response struct {
+}
+
+func (response PostAuth2faRecoveryCodes409Response) VisitPostAuth2faRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostAuth2faTotpRequestObject struct {
+}
+
+type PostAuth2faTotpResponseObject interface {
+	VisitPostAuth2faTotpResponse(w http.ResponseWriter) error
+}
+
+type PostAuth2faTotp200JSONResponse TOTPEnrollment
+
+func (response PostAuth2faTotp200JSONResponse) VisitPostAuth2faTotpResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faTotp401Response = UnauthorizedResponse
+
+func (response PostAuth2faTotp401Response) VisitPostAuth2faTotpResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuth2faTotp403Response = SessionRequiredResponse
+
+func (response PostAuth2faTotp403Response) VisitPostAuth2faTotpResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuth2faTotp404Response struct {
+}
+
+func (response PostAuth2faTotp404Response) VisitPostAuth2faTotpResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostAuth2faTotp409Response struct {
+}
+
+func (response PostAuth2faTotp409Response) VisitPostAuth2faTotpResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostAuth2faTotpConfirmRequestObject struct {
+	Body *PostAuth2faTotpConfirmJSONRequestBody
+}
+
+type PostAuth2faTotpConfirmResponseObject interface {
+	VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error
+}
+
+type PostAuth2faTotpConfirm200JSONResponse RecoveryCodes
+
+func (response PostAuth2faTotpConfirm200JSONResponse) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faTotpConfirm400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostAuth2faTotpConfirm400JSONResponse) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faTotpConfirm401Response = UnauthorizedResponse
+
+func (response PostAuth2faTotpConfirm401Response) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuth2faTotpConfirm403Response = SessionRequiredResponse
+
+func (response PostAuth2faTotpConfirm403Response) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuth2faTotpConfirm404Response struct {
+}
+
+func (response PostAuth2faTotpConfirm404Response) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostAuth2faTotpConfirm409Response struct {
+}
+
+func (response PostAuth2faTotpConfirm409Response) VisitPostAuth2faTotpConfirmResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostAuth2faTotpDisableRequestObject struct {
+	Body *PostAuth2faTotpDisableJSONRequestBody
+}
+
+type PostAuth2faTotpDisableResponseObject interface {
+	VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error
+}
+
+type PostAuth2faTotpDisable204Response struct {
+}
+
+func (response PostAuth2faTotpDisable204Response) VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type PostAuth2faTotpDisable400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostAuth2faTotpDisable400JSONResponse) VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuth2faTotpDisable401Response = UnauthorizedResponse
+
+func (response PostAuth2faTotpDisable401Response) VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuth2faTotpDisable403Response = SessionRequiredResponse
+
+func (response PostAuth2faTotpDisable403Response) VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuth2faTotpDisable409Response struct {
+}
+
+func (response PostAuth2faTotpDisable409Response) VisitPostAuth2faTotpDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostAuthLoginRequestObject struct {
+	Params PostAuthLoginParams
+	Body   *PostAuthLoginJSONRequestBody
+}
+
+type PostAuthLoginResponseObject interface {
+	VisitPostAuthLoginResponse(w http.ResponseWriter) error
+}
+
+type PostAuthLogin200JSONResponse TokenPair
+
+func (response PostAuthLogin200JSONResponse) VisitPostAuthLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthLogin202JSONResponse MFAChallenge
+
+func (response PostAuthLogin202JSONResponse) VisitPostAuthLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthLogin401Response struct {
+}
+
+func (response PostAuthLogin401Response) VisitPostAuthLoginResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuthLogin403Response struct {
+}
+
+func (response PostAuthLogin403Response) VisitPostAuthLoginResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuthLogin429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type PostAuthLogin429Response struct {
+	Headers PostAuthLogin429ResponseHeaders
+}
+
+func (response PostAuthLogin429Response) VisitPostAuthLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+	return nil
+}
+
+type PostAuthLogin2faRequestObject struct {
+	Body *PostAuthLogin2faJSONRequestBody
+}
+
+type PostAuthLogin2faResponseObject interface {
+	VisitPostAuthLogin2faResponse(w http.ResponseWriter) error
+}
+
+type PostAuthLogin2fa200JSONResponse TokenPair
+
+func (response PostAuthLogin2fa200JSONResponse) VisitPostAuthLogin2faResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthLogin2fa401Response struct {
+}
+
+func (response PostAuthLogin2fa401Response) VisitPostAuthLogin2faResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuthLogin2fa403Response struct {
+}
+
+func (response PostAuthLogin2fa403Response) VisitPostAuthLogin2faResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuthLogin2fa429Response struct {
+}
+
+func (response PostAuthLogin2fa429Response) VisitPostAuthLogin2faResponse(w http.ResponseWriter) error {
+	w.WriteHeader(429)
+	return nil
+}
+
+type GetAuthOidcCallbackRequestObject struct {
+	Params GetAuthOidcCallbackParams
+}
+
+type GetAuthOidcCallbackResponseObject interface {
+	VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error
+}
+
+type GetAuthOidcCallback200JSONResponse TokenPair
+
+func (response GetAuthOidcCallback200JSONResponse) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthOidcCallback202JSONResponse MFAChallenge
+
+func (response GetAuthOidcCallback202JSONResponse) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthOidcCallback400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response GetAuthOidcCallback400JSONResponse) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthOidcCallback401Response struct {
+}
+
+func (response GetAuthOidcCallback401Response) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetAuthOidcCallback403Response struct {
+}
+
+func (response GetAuthOidcCallback403Response) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type GetAuthOidcCallback404Response struct {
+}
+
+func (response GetAuthOidcCallback404Response) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetAuthOidcCallback429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type GetAuthOidcCallback429Response struct {
+	Headers GetAuthOidcCallback429ResponseHeaders
+}
+
+func (response GetAuthOidcCallback429Response) VisitGetAuthOidcCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+	return nil
+}
+
+type GetAuthOidcLoginRequestObject struct {
+}
+
+type GetAuthOidcLoginResponseObject interface {
+	VisitGetAuthOidcLoginResponse(w http.ResponseWriter) error
+}
+
+type GetAuthOidcLogin302ResponseHeaders struct {
+	Location string
+}
+
+type GetAuthOidcLogin302Response struct {
+	Headers GetAuthOidcLogin302ResponseHeaders
+}
+
+func (response GetAuthOidcLogin302Response) VisitGetAuthOidcLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(302)
+	return nil
+}
+
+type GetAuthOidcLogin404Response struct {
+}
+
+func (response GetAuthOidcLogin404Response) VisitGetAuthOidcLoginResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetAuthOidcLogin502Response struct {
+}
+
+func (response GetAuthOidcLogin502Response) VisitGetAuthOidcLoginResponse(w http.ResponseWriter) error {
+	w.WriteHeader(502)
+	return nil
+}
+
+type PostAuthPasswordResetRequestObject struct {
+	Body *PostAuthPasswordResetJSONRequestBody
+}
+
+type PostAuthPasswordResetResponseObject interface {
+	VisitPostAuthPasswordResetResponse(w http.ResponseWriter) error
+}
+
+type PostAuthPasswordReset202Response struct {
+}
+
+func (response PostAuthPasswordReset202Response) VisitPostAuthPasswordResetResponse(w http.ResponseWriter) error {
+	w.WriteHeader(202)
+	return nil
+}
+
+type PostAuthPasswordResetConfirmRequestObject struct {
+	Body *PostAuthPasswordResetConfirmJSONRequestBody
+}
+
+type PostAuthPasswordResetConfirmResponseObject interface {
+	VisitPostAuthPasswordResetConfirmResponse(w http.ResponseWriter) error
+}
+
+type PostAuthPasswordResetConfirm204Response struct {
+}
+
+func (response PostAuthPasswordResetConfirm204Response) VisitPostAuthPasswordResetConfirmResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type PostAuthPasswordResetConfirm400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostAuthPasswordResetConfirm400JSONResponse) VisitPostAuthPasswordResetConfirmResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthRefreshRequestObject struct {
+	Body *PostAuthRefreshJSONRequestBody
+}
+
+type PostAuthRefreshResponseObject interface {
+	VisitPostAuthRefreshResponse(w http.ResponseWriter) error
+}
+
+type PostAuthRefresh200JSONResponse TokenPair
+
+func (response PostAuthRefresh200JSONResponse) VisitPostAuthRefreshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthRefresh401Response struct {
+}
+
+func (response PostAuthRefresh401Response) VisitPostAuthRefreshResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteAuthSessionsRequestObject struct {
+}
+
+type DeleteAuthSessionsResponseObject interface {
+	VisitDeleteAuthSessionsResponse(w http.ResponseWriter) error
+}
+
+type DeleteAuthSessions204Response struct {
+}
+
+func (response DeleteAuthSessions204Response) VisitDeleteAuthSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAuthSessions401Response struct {
+}
+
+func (response DeleteAuthSessions401Response) VisitDeleteAuthSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetAuthSessionsRequestObject struct {
+}
+
+type GetAuthSessionsResponseObject interface {
+	VisitGetAuthSessionsResponse(w http.ResponseWriter) error
+}
+
+type GetAuthSessions200JSONResponse []Session
+
+func (response GetAuthSessions200JSONResponse) VisitGetAuthSessionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthSessions401Response struct {
+}
+
+func (response GetAuthSessions401Response) VisitGetAuthSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteAuthSessionsIdRequestObject struct {
+	Id string `json:"id"`
+}
+
+type DeleteAuthSessionsIdResponseObject interface {
+	VisitDeleteAuthSessionsIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteAuthSessionsId204Response struct {
+}
+
+func (response DeleteAuthSessionsId204Response) VisitDeleteAuthSessionsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAuthSessionsId401Response struct {
+}
+
+func (response DeleteAuthSessionsId401Response) VisitDeleteAuthSessionsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteAuthSessionsId404Response struct {
+}
+
+func (response DeleteAuthSessionsId404Response) VisitDeleteAuthSessionsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetAuthTokensRequestObject struct {
+}
+
+type GetAuthTokensResponseObject interface {
+	VisitGetAuthTokensResponse(w http.ResponseWriter) error
+}
+
+type GetAuthTokens200JSONResponse []PersonalAccessToken
+
+func (response GetAuthTokens200JSONResponse) VisitGetAuthTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthTokens401Response = UnauthorizedResponse
+
+func (response GetAuthTokens401Response) VisitGetAuthTokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetAuthTokens403Response struct {
+}
+
+func (response GetAuthTokens403Response) VisitGetAuthTokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostAuthTokensRequestObject struct {
+	Body *PostAuthTokensJSONRequestBody
+}
+
+type PostAuthTokensResponseObject interface {
+	VisitPostAuthTokensResponse(w http.ResponseWriter) error
+}
+
+type PostAuthTokens201JSONResponse CreatedPersonalAccessToken
+
+func (response PostAuthTokens201JSONResponse) VisitPostAuthTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthTokens400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostAuthTokens400JSONResponse) VisitPostAuthTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAuthTokens401Response = UnauthorizedResponse
+
+func (response PostAuthTokens401Response) VisitPostAuthTokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostAuthTokens403Response struct {
+}
+
+func (response PostAuthTokens403Response) VisitPostAuthTokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteAuthTokensIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type DeleteAuthTokensIdResponseObject interface {
+	VisitDeleteAuthTokensIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteAuthTokensId204Response struct {
+}
+
+func (response DeleteAuthTokensId204Response) VisitDeleteAuthTokensIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAuthTokensId401Response = UnauthorizedResponse
+
+func (response DeleteAuthTokensId401Response) VisitDeleteAuthTokensIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteAuthTokensId403Response struct {
+}
+
+func (response DeleteAuthTokensId403Response) VisitDeleteAuthTokensIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteAuthTokensId404Response struct {
+}
+
+func (response DeleteAuthTokensId404Response) VisitDeleteAuthTokensIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetAuthVerifyRequestObject struct {
+	Params GetAuthVerifyParams
+}
+
+type GetAuthVerifyResponseObject interface {
+	VisitGetAuthVerifyResponse(w http.ResponseWriter) error
+}
+
+type GetAuthVerify200JSONResponse EmailVerified
+
+func (response GetAuthVerify200JSONResponse) VisitGetAuthVerifyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthVerify400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response GetAuthVerify400JSONResponse) VisitGetAuthVerifyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Show whether two-factor authentication is enabled for me
+	// (GET /auth/2fa)
+	GetAuth2fa(ctx context.Context, request GetAuth2faRequestObject) (GetAuth2faResponseObject, error)
+	// Replace my recovery codes with a new set
+	// (POST /auth/2fa/recovery-codes)
+	PostAuth2faRecoveryCodes(ctx context.Context, request PostAuth2faRecoveryCodesRequestObject) (PostAuth2faRecoveryCodesResponseObject, error)
+	// Start enrolling an authenticator app (TOTP)
+	// (POST /auth/2fa/totp)
+	PostAuth2faTotp(ctx context.Context, request PostAuth2faTotpRequestObject) (PostAuth2faTotpResponseObject, error)
+	// Enable two-factor authentication with the first code from the app
+	// (POST /auth/2fa/totp/confirm)
+	PostAuth2faTotpConfirm(ctx context.Context, request PostAuth2faTotpConfirmRequestObject) (PostAuth2faTotpConfirmResponseObject, error)
+	// Disable two-factor authentication
+	// (POST /auth/2fa/totp/disable)
+	PostAuth2faTotpDisable(ctx context.Context, request PostAuth2faTotpDisableRequestObject) (PostAuth2faTotpDisableResponseObject, error)
+	// Log in with email and password and start a new session
+	// (POST /auth/login)
+	PostAuthLogin(ctx context.Context, request PostAuthLoginRequestObject) (PostAuthLoginResponseObject, error)
+	// Finish a login with a code from the authenticator app or a recovery code
+	// (POST /auth/login/2fa)
+	PostAuthLogin2fa(ctx context.Context, request PostAuthLogin2faRequestObject) (PostAuthLogin2faResponseObject, error)
+	// Finish logging in with the external OpenID Connect provider
+	// (GET /auth/oidc/callback)
+	GetAuthOidcCallback(ctx context.Context, request GetAuthOidcCallbackRequestObject) (GetAuthOidcCallbackResponseObject, error)
+	// Start logging in with the external OpenID Connect provider
+	// (GET /auth/oidc/login)
+	GetAuthOidcLogin(ctx context.Context, request GetAuthOidcLoginRequestObject) (GetAuthOidcLoginResponseObject, error)
+	// Request a password reset email
+	// (POST /auth/password-reset)
+	PostAuthPasswordReset(ctx context.Context, request PostAuthPasswordResetRequestObject) (PostAuthPasswordResetResponseObject, error)
+	// Set a new password using the token from the reset email
+	// (POST /auth/password-reset/confirm)
+	PostAuthPasswordResetConfirm(ctx context.Context, request PostAuthPasswordResetConfirmRequestObject) (PostAuthPasswordResetConfirmResponseObject, error)
+	// Exchange a refresh token for a new token pair
+	// (POST /auth/refresh)
+	PostAuthRefresh(ctx context.Context, request PostAuthRefreshRequestObject) (PostAuthRefreshResponseObject, error)
+	// Revoke all my sessions (log out everywhere)
+	// (DELETE /auth/sessions)
+	DeleteAuthSessions(ctx context.Context, request DeleteAuthSessionsRequestObject) (DeleteAuthSessionsResponseObject, error)
+	// List my active sessions
+	// (GET /auth/sessions)
+	GetAuthSessions(ctx context.Context, request GetAuthSessionsRequestObject) (GetAuthSessionsResponseObject, error)
+	// Revoke one of my sessions
+	// (DELETE /auth/sessions/{id})
+	DeleteAuthSessionsId(ctx context.Context, request DeleteAuthSessionsIdRequestObject) (DeleteAuthSessionsIdResponseObject, error)
+	// List my personal access tokens
+	// (GET /auth/tokens)
+	GetAuthTokens(ctx context.Context, request GetAuthTokensRequestObject) (GetAuthTokensResponseObject, error)
+	// Create a personal access token for scripts and CI
+	// (POST /auth/tokens)
+	PostAuthTokens(ctx context.Context, request PostAuthTokensRequestObject) (PostAuthTokensResponseObject, error)
+	// Revoke one of my personal access tokens
+	// (DELETE /auth/tokens/{id})
+	DeleteAuthTokensId(ctx context.Context, request DeleteAuthTokensIdRequestObject) (DeleteAuthTokensIdResponseObject, error)
+	// Confirm the user's email with the token from the verification email
+	// (GET /auth/verify)
+	GetAuthVerify(ctx context.Context, request GetAuthVerifyRequestObject) (GetAuthVerifyResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetAuth2fa operation middleware
+func (sh *strictHandler) GetAuth2fa(w http.ResponseWriter, r *http.Request) {
+	var request GetAuth2faRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuth2fa(ctx, request.(GetAuth2faRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuth2fa")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuth2faResponseObject); ok {
+		if err := validResponse.VisitGetAuth2faResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuth2faRecoveryCodes operation middleware
+func (sh *strictHandler) PostAuth2faRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var request PostAuth2faRecoveryCodesRequestObject
+
+	var body PostAuth2faRecoveryCodesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuth2faRecoveryCodes(ctx, request.(PostAuth2faRecoveryCodesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuth2faRecoveryCodes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuth2faRecoveryCodesResponseObject); ok {
+		if err := validResponse.VisitPostAuth2faRecoveryCodesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuth2faTotp operation middleware
+func (sh *strictHandler) PostAuth2faTotp(w http.ResponseWriter, r *http.Request) {
+	var request PostAuth2faTotpRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuth2faTotp(ctx, request.(PostAuth2faTotpRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuth2faTotp")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuth2faTotpResponseObject); ok {
+		if err := validResponse.VisitPostAuth2faTotpResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuth2faTotpConfirm operation middleware
+func (sh *strictHandler) PostAuth2faTotpConfirm(w http.ResponseWriter, r *http.Request) {
+	var request PostAuth2faTotpConfirmRequestObject
+
+	var body PostAuth2faTotpConfirmJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuth2faTotpConfirm(ctx, request.(PostAuth2faTotpConfirmRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuth2faTotpConfirm")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuth2faTotpConfirmResponseObject); ok {
+		if err := validResponse.VisitPostAuth2faTotpConfirmResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuth2faTotpDisable operation middleware
+func (sh *strictHandler) PostAuth2faTotpDisable(w http.ResponseWriter, r *http.Request) {
+	var request PostAuth2faTotpDisableRequestObject
+
+	var body PostAuth2faTotpDisableJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuth2faTotpDisable(ctx, request.(PostAuth2faTotpDisableRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuth2faTotpDisable")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuth2faTotpDisableResponseObject); ok {
+		if err := validResponse.VisitPostAuth2faTotpDisableResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthLogin operation middleware
+func (sh *strictHandler) PostAuthLogin(w http.ResponseWriter, r *http.Request, params PostAuthLoginParams) {
+	var request PostAuthLoginRequestObject
+
+	request.Params = params
+
+	var body PostAuthLoginJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthLogin(ctx, request.(PostAuthLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthLoginResponseObject); ok {
+		if err := validResponse.VisitPostAuthLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthLogin2fa operation middleware
+func (sh *strictHandler) PostAuthLogin2fa(w http.ResponseWriter, r *http.Request) {
+	var request PostAuthLogin2faRequestObject
+
+	var body PostAuthLogin2faJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthLogin2fa(ctx, request.(PostAuthLogin2faRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthLogin2fa")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthLogin2faResponseObject); ok {
+		if err := validResponse.VisitPostAuthLogin2faResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAuthOidcCallback operation middleware
+func (sh *strictHandler) GetAuthOidcCallback(w http.ResponseWriter, r *http.Request, params GetAuthOidcCallbackParams) {
+	var request GetAuthOidcCallbackRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuthOidcCallback(ctx, request.(GetAuthOidcCallbackRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuthOidcCallback")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuthOidcCallbackResponseObject); ok {
+		if err := validResponse.VisitGetAuthOidcCallbackResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAuthOidcLogin operation middleware
+func (sh *strictHandler) GetAuthOidcLogin(w http.ResponseWriter, r *http.Request) {
+	var request GetAuthOidcLoginRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuthOidcLogin(ctx, request.(GetAuthOidcLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuthOidcLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuthOidcLoginResponseObject); ok {
+		if err := validResponse.VisitGetAuthOidcLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthPasswordReset operation middleware
+func (sh *strictHandler) PostAuthPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var request PostAuthPasswordResetRequestObject
+
+	var body PostAuthPasswordResetJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthPasswordReset(ctx, request.(PostAuthPasswordResetRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthPasswordReset")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthPasswordResetResponseObject); ok {
+		if err := validResponse.VisitPostAuthPasswordResetResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthPasswordResetConfirm operation middleware
+func (sh *strictHandler) PostAuthPasswordResetConfirm(w http.ResponseWriter, r *http.Request) {
+	var request PostAuthPasswordResetConfirmRequestObject
+
+	var body PostAuthPasswordResetConfirmJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthPasswordResetConfirm(ctx, request.(PostAuthPasswordResetConfirmRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthPasswordResetConfirm")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthPasswordResetConfirmResponseObject); ok {
+		if err := validResponse.VisitPostAuthPasswordResetConfirmResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthRefresh operation middleware
+func (sh *strictHandler) PostAuthRefresh(w http.ResponseWriter, r *http.Request) {
+	var request PostAuthRefreshRequestObject
+
+	var body PostAuthRefreshJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthRefresh(ctx, request.(PostAuthRefreshRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthRefresh")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthRefreshResponseObject); ok {
+		if err := validResponse.VisitPostAuthRefreshResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAuthSessions operation middleware
+func (sh *strictHandler) DeleteAuthSessions(w http.ResponseWriter, r *http.Request) {
+	var request DeleteAuthSessionsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAuthSessions(ctx, request.(DeleteAuthSessionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAuthSessions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAuthSessionsResponseObject); ok {
+		if err := validResponse.VisitDeleteAuthSessionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAuthSessions operation middleware
+func (sh *strictHandler) GetAuthSessions(w http.ResponseWriter, r *http.Request) {
+	var request GetAuthSessionsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuthSessions(ctx, request.(GetAuthSessionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuthSessions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuthSessionsResponseObject); ok {
+		if err := validResponse.VisitGetAuthSessionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAuthSessionsId operation middleware
+func (sh *strictHandler) DeleteAuthSessionsId(w http.ResponseWriter, r *http.Request, id string) {
+	var request DeleteAuthSessionsIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAuthSessionsId(ctx, request.(DeleteAuthSessionsIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAuthSessionsId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAuthSessionsIdResponseObject); ok {
+		if err := validResponse.VisitDeleteAuthSessionsIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAuthTokens operation middleware
+func (sh *strictHandler) GetAuthTokens(w http.ResponseWriter, r *http.Request) {
+	var request GetAuthTokensRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuthTokens(ctx, request.(GetAuthTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuthTokens")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuthTokensResponseObject); ok {
+		if err := validResponse.VisitGetAuthTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAuthTokens operation middleware
+func (sh *strictHandler) PostAuthTokens(w http.ResponseWriter, r *http.Request) {
+	var request PostAuthTokensRequestObject
+
+	var body PostAuthTokensJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAuthTokens(ctx, request.(PostAuthTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAuthTokens")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAuthTokensResponseObject); ok {
+		if err := validResponse.VisitPostAuthTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAuthTokensId operation middleware
+func (sh *strictHandler) DeleteAuthTokensId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request DeleteAuthTokensIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAuthTokensId(ctx, request.(DeleteAuthTokensIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAuthTokensId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAuthTokensIdResponseObject); ok {
+		if err := validResponse.VisitDeleteAuthTokensIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAuthVerify operation middleware
+func (sh *strictHandler) GetAuthVerify(w http.ResponseWriter, r *http.Request, params GetAuthVerifyParams) {
+	var request GetAuthVerifyRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAuthVerify(ctx, request.(GetAuthVerifyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAuthVerify")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAuthVerifyResponseObject); ok {
+		if err := validResponse.VisitGetAuthVerifyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -0,0 +1,506 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/ratelimit"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type AuthHandler struct {
+	service *authService.AuthService
+}
+
+func NewAuthHandler(s *authService.AuthService) *AuthHandler {
+	return &AuthHandler{service: s}
+}
+
+// toValidationError - тело ответа 400 (для нарушений политики паролей - со списком правил)
+func toValidationError(err error) ValidationFailedJSONResponse {
+	var verr *userService.ValidationError
+	if !errors.As(err, &verr) {
+		return ValidationFailedJSONResponse{Error: err.Error()}
+	}
+
+	violations := make([]PolicyViolation, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		violations = append(violations, PolicyViolation{Rule: v.Rule, Message: v.Message})
+	}
+	return ValidationFailedJSONResponse{
+		Error:      verr.Error(),
+		Field:      &verr.Field,
+		Violations: &violations,
+	}
+}
+
+func (h *AuthHandler) PostAuthPasswordReset(_ context.Context, request PostAuthPasswordResetRequestObject) (PostAuthPasswordResetResponseObject, error) {
+	email := string(request.Body.Email)
+
+	// письмо отправляем в фоне и сразу отвечаем 202:
+	// так и ответ, и время ответа одинаковые для существующих и несуществующих email
+	go func() {
+		if err := h.service.RequestPasswordReset(email); err != nil {
+			log.Printf("[POST] Password reset request failed: %v", err)
+		}
+	}()
+
+	return PostAuthPasswordReset202Response{}, nil
+}
+
+func (h *AuthHandler) PostAuthPasswordResetConfirm(_ context.Context, request PostAuthPasswordResetConfirmRequestObject) (PostAuthPasswordResetConfirmResponseObject, error) {
+	err := h.service.ConfirmPasswordReset(request.Body.Token, request.Body.NewPassword)
+	if err != nil {
+		// ошибки валидации - 400 (неверный токен тоже: он приходит в теле запроса)
+		if strings.Contains(err.Error(), "invalid or expired token") ||
+			strings.Contains(err.Error(), "invalid password") ||
+			strings.Contains(err.Error(), "is empty") {
+			return PostAuthPasswordResetConfirm400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Password reset confirmed")
+	return PostAuthPasswordResetConfirm204Response{}, nil
+}
+
+func (h *AuthHandler) GetAuthVerify(_ context.Context, request GetAuthVerifyRequestObject) (GetAuthVerifyResponseObject, error) {
+	user, err := h.service.VerifyEmail(request.Params.Token)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid or expired token") {
+			return GetAuthVerify400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[GET] User %d verified email", user.ID)
+
+	return GetAuthVerify200JSONResponse{
+		Email:      openapi_types.Email(user.Email),
+		VerifiedAt: *user.EmailVerifiedAt,
+	}, nil
+}
+
+// toAPITokenPair - маппим пару токенов в апи-модель
+func toAPITokenPair(pair *authService.TokenPair) TokenPair {
+	return TokenPair{
+		AccessToken:           pair.AccessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int(time.Until(pair.AccessTokenExpiresAt).Round(time.Second).Seconds()),
+		RefreshToken:          pair.RefreshToken,
+		RefreshTokenExpiresAt: pair.RefreshTokenExpiresAt,
+		SessionId:             pair.SessionID,
+	}
+}
+
+func (h *AuthHandler) PostAuthLogin(ctx context.Context, request PostAuthLoginRequestObject) (PostAuthLoginResponseObject, error) {
+	params := authService.LoginParams{
+		Email:    string(request.Body.Email),
+		Password: request.Body.Password,
+		IP:       ratelimit.ClientIP(ctx),
+	}
+	if request.Body.DeviceName != nil {
+		params.DeviceName = *request.Body.DeviceName
+	}
+	if request.Params.UserAgent != nil {
+		params.UserAgent = *request.Params.UserAgent
+	}
+
+	pair, err := h.service.Login(params)
+	if err != nil {
+		// включен второй фактор - токены выдаст POST /auth/login/2fa
+		var mfa *authService.SecondFactorRequiredError
+		if errors.As(err, &mfa) {
+			return PostAuthLogin202JSONResponse{
+				MfaRequired: true,
+				MfaToken:    mfa.Token,
+				ExpiresAt:   mfa.ExpiresAt,
+			}, nil
+		}
+		if strings.Contains(err.Error(), "invalid credentials") {
+			return PostAuthLogin401Response{}, nil
+		}
+		if strings.Contains(err.Error(), "account is disabled") {
+			return PostAuthLogin403Response{}, nil
+		}
+		var locked *userService.LockedError
+		if errors.As(err, &locked) {
+			retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+			return PostAuthLogin429Response{
+				Headers: PostAuthLogin429ResponseHeaders{RetryAfter: max(retryAfter, 1)},
+			}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Session %s started", pair.SessionID)
+	return PostAuthLogin200JSONResponse(toAPITokenPair(pair)), nil
+}
+
+func (h *AuthHandler) PostAuthLogin2fa(ctx context.Context, request PostAuthLogin2faRequestObject) (PostAuthLogin2faResponseObject, error) {
+	pair, err := h.service.CompleteSecondFactor(authService.SecondFactorParams{
+		Token: request.Body.MfaToken,
+		Code:  request.Body.Code,
+		IP:    ratelimit.ClientIP(ctx),
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invalid or expired mfa token"),
+			strings.Contains(err.Error(), "invalid code"),
+			strings.Contains(err.Error(), "user not found"):
+			return PostAuthLogin2fa401Response{}, nil
+		case strings.Contains(err.Error(), "account is disabled"):
+			return PostAuthLogin2fa403Response{}, nil
+		case strings.Contains(err.Error(), "too many attempts"):
+			return PostAuthLogin2fa429Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Session %s started after second factor", pair.SessionID)
+	return PostAuthLogin2fa200JSONResponse(toAPITokenPair(pair)), nil
+}
+
+func (h *AuthHandler) PostAuthRefresh(_ context.Context, request PostAuthRefreshRequestObject) (PostAuthRefreshResponseObject, error) {
+	pair, err := h.service.Refresh(request.Body.RefreshToken)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid refresh token") ||
+			strings.Contains(err.Error(), "reuse detected") {
+			return PostAuthRefresh401Response{}, nil
+		}
+		return nil, err
+	}
+
+	return PostAuthRefresh200JSONResponse(toAPITokenPair(pair)), nil
+}
+
+func (h *AuthHandler) GetAuthOidcLogin(ctx context.Context, _ GetAuthOidcLoginRequestObject) (GetAuthOidcLoginResponseObject, error) {
+	authURL, err := h.service.StartOIDCLogin(ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "not configured") {
+			return GetAuthOidcLogin404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "provider is unavailable") {
+			return GetAuthOidcLogin502Response{}, nil
+		}
+		return nil, err
+	}
+
+	return GetAuthOidcLogin302Response{
+		Headers: GetAuthOidcLogin302ResponseHeaders{Location: authURL},
+	}, nil
+}
+
+func (h *AuthHandler) GetAuthOidcCallback(ctx context.Context, request GetAuthOidcCallbackRequestObject) (GetAuthOidcCallbackResponseObject, error) {
+	params := authService.OIDCCallbackParams{State: request.Params.State, IP: ratelimit.ClientIP(ctx)}
+	if request.Params.Code != nil {
+		params.Code = *request.Params.Code
+	}
+	if request.Params.Error != nil {
+		params.Error = *request.Params.Error
+	}
+	if request.Params.UserAgent != nil {
+		params.UserAgent = *request.Params.UserAgent
+	}
+
+	pair, err := h.service.CompleteOIDCLogin(ctx, params)
+	if err != nil {
+		// включен второй фактор - токены выдаст POST /auth/login/2fa
+		var mfa *authService.SecondFactorRequiredError
+		if errors.As(err, &mfa) {
+			return GetAuthOidcCallback202JSONResponse{
+				MfaRequired: true,
+				MfaToken:    mfa.Token,
+				ExpiresAt:   mfa.ExpiresAt,
+			}, nil
+		}
+		switch {
+		case strings.Contains(err.Error(), "not configured"):
+			return GetAuthOidcCallback404Response{}, nil
+		case strings.Contains(err.Error(), "invalid or expired state"),
+			strings.Contains(err.Error(), "is empty"):
+			return GetAuthOidcCallback400JSONResponse{toValidationError(err)}, nil
+		case strings.Contains(err.Error(), "rejected the login"):
+			return GetAuthOidcCallback401Response{}, nil
+		case strings.Contains(err.Error(), "account is disabled"),
+			strings.Contains(err.Error(), "not verified"):
+			return GetAuthOidcCallback403Response{}, nil
+		}
+		var locked *userService.LockedError
+		if errors.As(err, &locked) {
+			retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+			return GetAuthOidcCallback429Response{
+				Headers: GetAuthOidcCallback429ResponseHeaders{RetryAfter: max(retryAfter, 1)},
+			}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[GET] Session %s started via OIDC", pair.SessionID)
+	return GetAuthOidcCallback200JSONResponse(toAPITokenPair(pair)), nil
+}
+
+func (h *AuthHandler) GetAuthSessions(ctx context.Context, _ GetAuthSessionsRequestObject) (GetAuthSessionsResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return GetAuthSessions401Response{}, nil
+	}
+
+	sessions, err := h.service.ListSessions(principal.UserID, principal.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	response := make(GetAuthSessions200JSONResponse, 0, len(sessions))
+	for _, session := range sessions {
+		deviceName := session.DeviceName
+		userAgent := session.UserAgent
+		response = append(response, Session{
+			Id:          session.ID,
+			DeviceName:  &deviceName,
+			UserAgent:   &userAgent,
+			StartedAt:   session.StartedAt,
+			RefreshedAt: session.RefreshedAt,
+			ExpiresAt:   session.ExpiresAt,
+			Current:     session.Current,
+		})
+	}
+	return response, nil
+}
+
+func (h *AuthHandler) DeleteAuthSessions(ctx context.Context, _ DeleteAuthSessionsRequestObject) (DeleteAuthSessionsResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return DeleteAuthSessions401Response{}, nil
+	}
+
+	if err := h.service.RevokeUserSessions(principal.UserID); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DELETE] All sessions of user %d revoked", principal.UserID)
+	return DeleteAuthSessions204Response{}, nil
+}
+
+func (h *AuthHandler) DeleteAuthSessionsId(ctx context.Context, request DeleteAuthSessionsIdRequestObject) (DeleteAuthSessionsIdResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return DeleteAuthSessionsId401Response{}, nil
+	}
+
+	if err := h.service.RevokeSession(principal.UserID, request.Id); err != nil {
+		if strings.Contains(err.Error(), "session not found") {
+			return DeleteAuthSessionsId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Session %s revoked", request.Id)
+	return DeleteAuthSessionsId204Response{}, nil
+}
+
+// toAPIPersonalToken - маппит бизнес-модель в апи-модель
+func toAPIPersonalToken(t authService.PersonalToken) PersonalAccessToken {
+	scopes := make([]TokenScope, 0, len(t.Scopes))
+	for _, scope := range t.Scopes {
+		scopes = append(scopes, TokenScope(scope))
+	}
+	return PersonalAccessToken{
+		Id:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+func (h *AuthHandler) GetAuthTokens(ctx context.Context, _ GetAuthTokensRequestObject) (GetAuthTokensResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return GetAuthTokens401Response{}, nil
+	}
+
+	tokens, err := h.service.ListPersonalTokens(principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(GetAuthTokens200JSONResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, toAPIPersonalToken(token))
+	}
+	return response, nil
+}
+
+func (h *AuthHandler) PostAuthTokens(ctx context.Context, request PostAuthTokensRequestObject) (PostAuthTokensResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return PostAuthTokens401Response{}, nil
+	}
+
+	params := authService.CreatePersonalTokenParams{
+		Name:      request.Body.Name,
+		ExpiresAt: request.Body.ExpiresAt,
+	}
+	for _, scope := range request.Body.Scopes {
+		params.Scopes = append(params.Scopes, string(scope))
+	}
+
+	created, err := h.service.CreatePersonalToken(principal.UserID, params)
+	if err != nil {
+		if strings.Contains(err.Error(), "token name") ||
+			strings.Contains(err.Error(), "scope") ||
+			strings.Contains(err.Error(), "token expiry") {
+			return PostAuthTokens400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Personal access token %d created for user %d", created.ID, principal.UserID)
+
+	token := toAPIPersonalToken(created.PersonalToken)
+	return PostAuthTokens201JSONResponse{
+		Id:         token.Id,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		Token:      created.Token,
+	}, nil
+}
+
+func (h *AuthHandler) DeleteAuthTokensId(ctx context.Context, request DeleteAuthTokensIdRequestObject) (DeleteAuthTokensIdResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return DeleteAuthTokensId401Response{}, nil
+	}
+
+	if err := h.service.RevokePersonalToken(principal.UserID, request.Id); err != nil {
+		if strings.Contains(err.Error(), "token not found") {
+			return DeleteAuthTokensId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Personal access token %d revoked", request.Id)
+	return DeleteAuthTokensId204Response{}, nil
+}
+
+func (h *AuthHandler) GetAuth2fa(ctx context.Context, _ GetAuth2faRequestObject) (GetAuth2faResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return GetAuth2fa401Response{}, nil
+	}
+
+	status, err := h.service.GetTwoFactorStatus(principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetAuth2fa200JSONResponse{
+		Enabled:           status.Enabled,
+		EnabledAt:         status.EnabledAt,
+		RecoveryCodesLeft: status.RecoveryCodesLeft,
+	}, nil
+}
+
+func (h *AuthHandler) PostAuth2faTotp(ctx context.Context, _ PostAuth2faTotpRequestObject) (PostAuth2faTotpResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return PostAuth2faTotp401Response{}, nil
+	}
+
+	enrollment, err := h.service.StartTOTPEnrollment(principal.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not configured") {
+			return PostAuth2faTotp404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "already enabled") {
+			return PostAuth2faTotp409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] TOTP enrollment started for user %d", principal.UserID)
+	return PostAuth2faTotp200JSONResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+func (h *AuthHandler) PostAuth2faTotpConfirm(ctx context.Context, request PostAuth2faTotpConfirmRequestObject) (PostAuth2faTotpConfirmResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return PostAuth2faTotpConfirm401Response{}, nil
+	}
+
+	codes, err := h.service.ConfirmTOTP(principal.UserID, request.Body.Code)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invalid code"):
+			return PostAuth2faTotpConfirm400JSONResponse{toValidationError(err)}, nil
+		case strings.Contains(err.Error(), "not configured"):
+			return PostAuth2faTotpConfirm404Response{}, nil
+		case strings.Contains(err.Error(), "already enabled"),
+			strings.Contains(err.Error(), "not started"):
+			return PostAuth2faTotpConfirm409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Two-factor authentication enabled for user %d", principal.UserID)
+	return PostAuth2faTotpConfirm200JSONResponse{RecoveryCodes: codes}, nil
+}
+
+func (h *AuthHandler) PostAuth2faTotpDisable(ctx context.Context, request PostAuth2faTotpDisableRequestObject) (PostAuth2faTotpDisableResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return PostAuth2faTotpDisable401Response{}, nil
+	}
+
+	if err := h.service.DisableTOTP(principal.UserID, request.Body.Code); err != nil {
+		if strings.Contains(err.Error(), "invalid code") {
+			return PostAuth2faTotpDisable400JSONResponse{toValidationError(err)}, nil
+		}
+		if strings.Contains(err.Error(), "not enabled") {
+			return PostAuth2faTotpDisable409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Two-factor authentication disabled for user %d", principal.UserID)
+	return PostAuth2faTotpDisable204Response{}, nil
+}
+
+func (h *AuthHandler) PostAuth2faRecoveryCodes(ctx context.Context, request PostAuth2faRecoveryCodesRequestObject) (PostAuth2faRecoveryCodesResponseObject, error) {
+	principal, ok := authn.FromContext(ctx)
+	if !ok {
+		return PostAuth2faRecoveryCodes401Response{}, nil
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(principal.UserID, request.Body.Code)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid code") {
+			return PostAuth2faRecoveryCodes400JSONResponse{toValidationError(err)}, nil
+		}
+		if strings.Contains(err.Error(), "not enabled") {
+			return PostAuth2faRecoveryCodes409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Recovery codes regenerated for user %d", principal.UserID)
+	return PostAuth2faRecoveryCodes200JSONResponse{RecoveryCodes: codes}, nil
+}
@@ -0,0 +1,115 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAuth - принимает только токен "good"
+type fakeAuth struct{}
+
+func (fakeAuth) Authenticate(token string) (*authService.Principal, error) {
+	if token == "good" {
+		return &authService.Principal{UserID: 7, SessionID: "family-1"}, nil
+	}
+	return nil, errors.New("invalid access token")
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotUser uint
+	var gotOK bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotOK = UserID(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewMiddleware(fakeAuth{}).Handler(next)
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUser   uint
+		wantOK     bool
+	}{
+		{name: "без заголовка - анонимно", header: "", wantStatus: http.StatusOK},
+		{name: "верный токен", header: "Bearer good", wantStatus: http.StatusOK, wantUser: 7, wantOK: true},
+		{name: "схема без учета регистра", header: "bearer good", wantStatus: http.StatusOK, wantUser: 7, wantOK: true},
+		{name: "неверный токен", header: "Bearer bad", wantStatus: http.StatusUnauthorized},
+		{name: "другая схема", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "пустой токен", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser, gotOK = 0, false
+			req := httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantUser, gotUser)
+			assert.Equal(t, tt.wantOK, gotOK)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestMiddlewareWebSocketToken(t *testing.T) {
+	var gotUser uint
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserID(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewMiddleware(fakeAuth{}).Handler(next)
+
+	tests := []struct {
+		name       string
+		upgrade    string
+		protocol   string
+		wantStatus int
+		wantUser   uint
+	}{
+		{name: "токен в подпротоколе", upgrade: "websocket", protocol: "tasks.v1, bearer.good", wantStatus: http.StatusOK, wantUser: 7},
+		{name: "неверный токен в подпротоколе", upgrade: "websocket", protocol: "tasks.v1, bearer.bad", wantStatus: http.StatusUnauthorized},
+		{name: "без токена - анонимно", upgrade: "websocket", protocol: "tasks.v1", wantStatus: http.StatusOK},
+		{name: "не WebSocket - подпротокол не смотрим", upgrade: "", protocol: "bearer.good", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser = 0
+			req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			if tt.upgrade != "" {
+				req.Header.Set("Upgrade", tt.upgrade)
+			}
+			req.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantUser, gotUser)
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, rbac.Actor{}, Actor(ctx))
+
+	ctx = WithPrincipal(ctx, &authService.Principal{UserID: 7, SessionID: "family-1", Role: rbac.RoleAdmin})
+	assert.Equal(t, rbac.Actor{UserID: 7, Role: rbac.RoleAdmin}, Actor(ctx))
+}
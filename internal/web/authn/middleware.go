@@ -0,0 +1,116 @@
+package authn
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/web/requestid"
+)
+
+// middleware аутентификации по access-токену (Authorization: Bearer <token>)
+//   • заголовка нет - запрос идет дальше анонимно (закрытые маршруты отвечают 401 в authz)
+//   • токен есть, но неверный, истек или его сессия отозвана - сразу 401
+//   • токен верный - кладем Principal в контекст запроса
+//   • браузер не дает задать заголовки при открытии WebSocket - там токен можно передать подпротоколом
+//     (Sec-WebSocket-Protocol: tasks.v1, bearer.<token>), если Authorization нет
+
+// Authenticator - проверка access-токена (реализует authService.AuthService)
+type Authenticator interface {
+	Authenticate(accessToken string) (*authService.Principal, error)
+}
+
+type Middleware struct {
+	auth Authenticator
+}
+
+func NewMiddleware(a Authenticator) *Middleware {
+	return &Middleware{auth: a}
+}
+
+type principalKey struct{}
+
+// WithPrincipal - кладет пользователя в контекст
+func WithPrincipal(ctx context.Context, p *authService.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext - достает пользователя из контекста (false - запрос анонимный)
+func FromContext(ctx context.Context) (*authService.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*authService.Principal)
+	return p, ok && p != nil
+}
+
+// Actor - от чьего имени выполняется запрос (для анонимного запроса - Actor без пользователя, которому ничего нельзя)
+// вместе с id запроса - его сервисы пишут в журнал аудита
+func Actor(ctx context.Context) rbac.Actor {
+	actor := rbac.Actor{}
+	if p, ok := FromContext(ctx); ok {
+		actor = p.Actor()
+	}
+	actor.RequestID = requestid.FromContext(ctx)
+	return actor
+}
+
+// UserID - id пользователя запроса (подходит под ratelimit.UserFunc)
+func UserID(r *http.Request) (uint, bool) {
+	p, ok := FromContext(r.Context())
+	if !ok {
+		return 0, false
+	}
+	return p.UserID, true
+}
+
+// Handler - сама middleware (подходит под тип MiddlewareFunc из api.gen.go)
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			header = websocketToken(r)
+		}
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			unauthorized(w, "invalid_request")
+			return
+		}
+
+		principal, err := m.auth.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			unauthorized(w, "invalid_token")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// префикс подпротокола с токеном
+const bearerProtocol = "bearer."
+
+// websocketToken - токен из подпротокола запроса на WebSocket в виде значения Authorization ("" - его нет)
+func websocketToken(r *http.Request) string {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return ""
+	}
+	for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(value, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), bearerProtocol); ok {
+				return "Bearer " + token
+			}
+		}
+	}
+	return ""
+}
+
+// unauthorized - 401 с заголовком WWW-Authenticate (RFC 6750)
+func unauthorized(w http.ResponseWriter, code string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="`+code+`"`)
+	http.Error(w, "invalid or expired access token", http.StatusUnauthorized)
+}
@@ -0,0 +1,120 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMux - маршруты регистрируются в ServeMux, как в сгенерированном коде (он заполняет r.Pattern)
+func newMux(m *Middleware) *http.ServeMux {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux := http.NewServeMux()
+	for _, pattern := range []string{"POST /users", "GET /users", "GET /users/{id}", "DELETE /users/{id}", "PUT /users/{id}/role"} {
+		mux.Handle(pattern, m.Handler(ok))
+	}
+	return mux
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := newMux(NewMiddleware(DefaultRules()))
+	user := &authService.Principal{UserID: 1, SessionID: "s1", Role: rbac.RoleUser}
+	admin := &authService.Principal{UserID: 2, SessionID: "s2", Role: rbac.RoleAdmin}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		principal  *authService.Principal
+		wantStatus int
+	}{
+		{name: "регистрация открыта", method: http.MethodPost, path: "/users", wantStatus: http.StatusOK},
+		{name: "профиль без входа", method: http.MethodGet, path: "/users/1", wantStatus: http.StatusUnauthorized},
+		{name: "профиль с входом", method: http.MethodGet, path: "/users/1", principal: user, wantStatus: http.StatusOK},
+		{name: "список пользователей без входа", method: http.MethodGet, path: "/users", wantStatus: http.StatusUnauthorized},
+		{name: "список пользователей - не админ", method: http.MethodGet, path: "/users", principal: user, wantStatus: http.StatusForbidden},
+		{name: "список пользователей - админ", method: http.MethodGet, path: "/users", principal: admin, wantStatus: http.StatusOK},
+		{name: "удаление - не админ", method: http.MethodDelete, path: "/users/1", principal: user, wantStatus: http.StatusForbidden},
+		{name: "удаление - админ", method: http.MethodDelete, path: "/users/1", principal: admin, wantStatus: http.StatusOK},
+		{name: "смена роли - не админ", method: http.MethodPut, path: "/users/1/role", principal: user, wantStatus: http.StatusForbidden},
+		{name: "смена роли - админ", method: http.MethodPut, path: "/users/1/role", principal: admin, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.principal != nil {
+				req = req.WithContext(authn.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestMiddlewareClosedByDefault(t *testing.T) {
+	// маршрута нет в правилах - нужен вход
+	mux := newMux(NewMiddleware(map[string]Access{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMiddlewarePersonalTokenScopes(t *testing.T) {
+	mux := newMux(NewMiddleware(DefaultRules()).WithScopes(map[string]string{
+		"GET /users/{id}":    rbac.ScopeUsersRead,
+		"DELETE /users/{id}": rbac.ScopeUsersWrite,
+	}))
+	reader := &authService.Principal{UserID: 1, TokenID: 5, Scopes: []string{rbac.ScopeUsersRead}, Role: rbac.RoleAdmin}
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "скоуп есть", method: http.MethodGet, path: "/users/1", wantStatus: http.StatusOK},
+		{name: "скоупа нет", method: http.MethodDelete, path: "/users/1", wantStatus: http.StatusForbidden,
+			wantChallenge: `Bearer error="insufficient_scope", scope="users:write"`},
+		{name: "маршрут без скоупа - только для сессии", method: http.MethodPut, path: "/users/1/role", wantStatus: http.StatusForbidden,
+			wantChallenge: `Bearer error="insufficient_scope"`},
+		{name: "открытый маршрут", method: http.MethodPost, path: "/users", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = req.WithContext(authn.WithPrincipal(req.Context(), reader))
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+		})
+	}
+
+	// без WithScopes персональные токены не принимаются
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req = req.WithContext(authn.WithPrincipal(req.Context(), reader))
+	rec := httptest.NewRecorder()
+	newMux(NewMiddleware(DefaultRules())).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,143 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+)
+
+// middleware авторизации на уровне маршрутов (стоит после authn - Principal уже в контексте)
+//   • открытые маршруты (регистрация, вход, сброс пароля...) пропускаются всем
+//   • маршруты только для админа: без входа - 401, не админ - 403
+//   • все остальные маршруты требуют входа (по умолчанию закрыто - новый маршрут не окажется открытым случайно)
+//   • персональный токен пускается только на маршруты, для которых задан скоуп, и только если этот скоуп у него есть;
+//     маршруты без скоупа (управление сессиями и токенами, смена пароля) - только после обычного входа
+// доступ к конкретным данным (свои / чужие задачи и профили) проверяют сами сервисы
+
+type Access int
+
+const (
+	Authenticated Access = iota // нужен вход (по умолчанию)
+	Public                      // можно без входа
+	Admin                       // только админ
+)
+
+// DefaultRules - правила для маршрутов API (ключ - шаблон маршрута ServeMux, как в r.Pattern)
+func DefaultRules() map[string]Access {
+	return map[string]Access{
+		"POST /users":                       Public,
+		"POST /auth/login":                  Public,
+		"POST /auth/login/2fa":              Public,
+		"POST /auth/refresh":                Public,
+		"POST /auth/password-reset":         Public,
+		"POST /auth/password-reset/confirm": Public,
+		"GET /auth/verify":                  Public,
+		"GET /auth/oidc/login":              Public,
+		"GET /auth/oidc/callback":           Public,
+
+		"GET /users":                  Admin,
+		"DELETE /users/{id}":          Admin,
+		"PUT /users/{id}/role":        Admin,
+		"POST /users/{id}/disable":    Admin,
+		"POST /users/{id}/enable":     Admin,
+		"GET /users/{id}/auth-events": Admin,
+		"POST /users/{id}/unlock":     Admin,
+
+		"GET /audit": Admin,
+	}
+}
+
+// DefaultScopes - какой скоуп персонального токена нужен для маршрута
+func DefaultScopes() map[string]string {
+	return map[string]string{
+		"GET /tasks":                                    rbac.ScopeTasksRead,
+		"GET /tasks/search":                             rbac.ScopeTasksRead,
+		"GET /tasks/{id}":                               rbac.ScopeTasksRead,
+		"GET /tasks/{id}/history":                       rbac.ScopeTasksRead,
+		"GET /tasks/{id}/revisions":                     rbac.ScopeTasksRead,
+		"GET /tasks/{id}/revisions/diff":                rbac.ScopeTasksRead,
+		"GET /users/{id}/tasks":                         rbac.ScopeTasksRead,
+		"POST /tasks":                                   rbac.ScopeTasksWrite,
+		"PATCH /tasks/{id}":                             rbac.ScopeTasksWrite,
+		"DELETE /tasks/{id}":                            rbac.ScopeTasksWrite,
+		"POST /tasks/{id}/revisions/{rev}/restore":      rbac.ScopeTasksWrite,
+		"PUT /tasks/{id}/assignees/{userId}":            rbac.ScopeTasksWrite,
+		"DELETE /tasks/{id}/assignees/{userId}":         rbac.ScopeTasksWrite,
+		"GET /tasks/{id}/comments":                      rbac.ScopeTasksRead,
+		"POST /tasks/{id}/comments":                     rbac.ScopeTasksWrite,
+		"PATCH /comments/{id}":                          rbac.ScopeTasksWrite,
+		"DELETE /comments/{id}":                         rbac.ScopeTasksWrite,
+		"GET /tasks/{id}/attachments":                   rbac.ScopeTasksRead,
+		"GET /tasks/{id}/attachments/{attachmentId}":    rbac.ScopeTasksRead,
+		"POST /tasks/{id}/attachments":                  rbac.ScopeTasksWrite,
+		"DELETE /tasks/{id}/attachments/{attachmentId}": rbac.ScopeTasksWrite,
+		"GET /events":                                   rbac.ScopeTasksRead,
+		"GET /ws":                                       rbac.ScopeTasksRead,
+
+		"GET /users":                  rbac.ScopeUsersRead,
+		"GET /users/{id}":             rbac.ScopeUsersRead,
+		"GET /users/{id}/auth-events": rbac.ScopeUsersRead,
+		"PATCH /users/{id}":           rbac.ScopeUsersWrite,
+		"DELETE /users/{id}":          rbac.ScopeUsersWrite,
+		"PUT /users/{id}/role":        rbac.ScopeUsersWrite,
+		"POST /users/{id}/disable":    rbac.ScopeUsersWrite,
+		"POST /users/{id}/enable":     rbac.ScopeUsersWrite,
+		"POST /users/{id}/unlock":     rbac.ScopeUsersWrite,
+	}
+}
+
+type Middleware struct {
+	rules  map[string]Access
+	scopes map[string]string // nil - персональные токены не принимаются нигде
+}
+
+func NewMiddleware(rules map[string]Access) *Middleware {
+	return &Middleware{rules: rules}
+}
+
+// WithScopes - включает доступ по персональным токенам (скоупы по маршрутам)
+func (m *Middleware) WithScopes(scopes map[string]string) *Middleware {
+	m.scopes = scopes
+	return m
+}
+
+// Handler - сама middleware (подходит под тип MiddlewareFunc из api.gen.go)
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// r.Pattern заполняет ServeMux - это шаблон маршрута, например "DELETE /users/{id}"
+		access := m.rules[r.Pattern]
+		if access == Public {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := authn.FromContext(r.Context())
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		if principal.IsPersonalToken() {
+			scope := m.scopes[r.Pattern]
+			if scope == "" || !principal.HasScope(scope) {
+				// RFC 6750: токен верный, но прав у него не хватает
+				challenge := `Bearer error="insufficient_scope"`
+				if scope != "" {
+					challenge += `, scope="` + scope + `"`
+				}
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, "personal access token is not allowed to do this", http.StatusForbidden)
+				return
+			}
+		}
+
+		if access == Admin && !principal.Actor().IsAdmin() {
+			http.Error(w, "admin role required", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
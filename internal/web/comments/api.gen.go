@@ -0,0 +1,851 @@
+//go:build go1.22
+
+// Package comments provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package comments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Comment defines model for Comment.
+type Comment struct {
+	AuthorId uint `json:"author_id"`
+
+	// Body Missing for deleted comments
+	Body      *string   `json:"body,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Deleted   bool      `json:"deleted"`
+	Id        uint      `json:"id"`
+	TaskId    uint      `json:"task_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint      `json:"version"`
+}
+
+// CreateCommentRequest defines model for CreateCommentRequest.
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// UpdateCommentRequest defines model for UpdateCommentRequest.
+type UpdateCommentRequest struct {
+	Body *string `json:"body,omitempty"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// DeleteCommentsIdParams defines parameters for DeleteCommentsId.
+type DeleteCommentsIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchCommentsIdParams defines parameters for PatchCommentsId.
+type PatchCommentsIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostTasksIdCommentsParams defines parameters for PostTasksIdComments.
+type PostTasksIdCommentsParams struct {
+	// IdempotencyKey Unique key of the request; retries with the same key replay the stored response
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PatchCommentsIdJSONRequestBody defines body for PatchCommentsId for application/json ContentType.
+type PatchCommentsIdJSONRequestBody = UpdateCommentRequest
+
+// PostTasksIdCommentsJSONRequestBody defines body for PostTasksIdComments for application/json ContentType.
+type PostTasksIdCommentsJSONRequestBody = CreateCommentRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Delete a comment (author or admin)
+	// (DELETE /comments/{id})
+	DeleteCommentsId(w http.ResponseWriter, r *http.Request, id uint, params DeleteCommentsIdParams)
+	// Edit a comment (author only)
+	// (PATCH /comments/{id})
+	PatchCommentsId(w http.ResponseWriter, r *http.Request, id uint, params PatchCommentsIdParams)
+	// Comments of a task, oldest first
+	// (GET /tasks/{id}/comments)
+	GetTasksIdComments(w http.ResponseWriter, r *http.Request, id uint)
+	// Add a comment to a task
+	// (POST /tasks/{id}/comments)
+	PostTasksIdComments(w http.ResponseWriter, r *http.Request, id uint, params PostTasksIdCommentsParams)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// DeleteCommentsId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCommentsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCommentsIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCommentsId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchCommentsId operation middleware
+func (siw *ServerInterfaceWrapper) PatchCommentsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchCommentsIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchCommentsId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksIdComments operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdComments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdComments(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTasksIdComments operation middleware
+func (siw *ServerInterfaceWrapper) PostTasksIdComments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTasksIdCommentsParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTasksIdComments(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("DELETE "+options.BaseURL+"/comments/{id}", wrapper.DeleteCommentsId)
+	m.HandleFunc("PATCH "+options.BaseURL+"/comments/{id}", wrapper.PatchCommentsId)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/comments", wrapper.GetTasksIdComments)
+	m.HandleFunc("POST "+options.BaseURL+"/tasks/{id}/comments", wrapper.PostTasksIdComments)
+
+	return m
+}
+
+type IdempotencyConflictResponse struct {
+}
+
+type IdempotencyKeyReusedResponse struct {
+}
+
+type PreconditionFailedResponse struct {
+}
+
+type PreconditionRequiredResponse struct {
+}
+
+type UnauthorizedResponse struct {
+}
+
+type DeleteCommentsIdRequestObject struct {
+	Id     uint `json:"id"`
+	Params DeleteCommentsIdParams
+}
+
+type DeleteCommentsIdResponseObject interface {
+	VisitDeleteCommentsIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteCommentsId204Response struct {
+}
+
+func (response DeleteCommentsId204Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteCommentsId401Response = UnauthorizedResponse
+
+func (response DeleteCommentsId401Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteCommentsId403Response struct {
+}
+
+func (response DeleteCommentsId403Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteCommentsId404Response struct {
+}
+
+func (response DeleteCommentsId404Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteCommentsId410Response struct {
+}
+
+func (response DeleteCommentsId410Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(410)
+	return nil
+}
+
+type DeleteCommentsId412Response = PreconditionFailedResponse
+
+func (response DeleteCommentsId412Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type DeleteCommentsId428Response = PreconditionRequiredResponse
+
+func (response DeleteCommentsId428Response) VisitDeleteCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type PatchCommentsIdRequestObject struct {
+	Id     uint `json:"id"`
+	Params PatchCommentsIdParams
+	Body   *PatchCommentsIdJSONRequestBody
+}
+
+type PatchCommentsIdResponseObject interface {
+	VisitPatchCommentsIdResponse(w http.ResponseWriter) error
+}
+
+type PatchCommentsId200ResponseHeaders struct {
+	ETag string
+}
+
+type PatchCommentsId200JSONResponse struct {
+	Body    Comment
+	Headers PatchCommentsId200ResponseHeaders
+}
+
+func (response PatchCommentsId200JSONResponse) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PatchCommentsId400Response struct {
+}
+
+func (response PatchCommentsId400Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PatchCommentsId401Response = UnauthorizedResponse
+
+func (response PatchCommentsId401Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PatchCommentsId403Response struct {
+}
+
+func (response PatchCommentsId403Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PatchCommentsId404Response struct {
+}
+
+func (response PatchCommentsId404Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchCommentsId410Response struct {
+}
+
+func (response PatchCommentsId410Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(410)
+	return nil
+}
+
+type PatchCommentsId412Response = PreconditionFailedResponse
+
+func (response PatchCommentsId412Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type PatchCommentsId428Response = PreconditionRequiredResponse
+
+func (response PatchCommentsId428Response) VisitPatchCommentsIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type GetTasksIdCommentsRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetTasksIdCommentsResponseObject interface {
+	VisitGetTasksIdCommentsResponse(w http.ResponseWriter) error
+}
+
+type GetTasksIdComments200JSONResponse []Comment
+
+func (response GetTasksIdComments200JSONResponse) VisitGetTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasksIdComments401Response = UnauthorizedResponse
+
+func (response GetTasksIdComments401Response) VisitGetTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksIdComments404Response struct {
+}
+
+func (response GetTasksIdComments404Response) VisitGetTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasksIdCommentsRequestObject struct {
+	Id     uint `json:"id"`
+	Params PostTasksIdCommentsParams
+	Body   *PostTasksIdCommentsJSONRequestBody
+}
+
+type PostTasksIdCommentsResponseObject interface {
+	VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error
+}
+
+type PostTasksIdComments201ResponseHeaders struct {
+	ETag string
+}
+
+type PostTasksIdComments201JSONResponse struct {
+	Body    Comment
+	Headers PostTasksIdComments201ResponseHeaders
+}
+
+func (response PostTasksIdComments201JSONResponse) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostTasksIdComments400Response struct {
+}
+
+func (response PostTasksIdComments400Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostTasksIdComments401Response = UnauthorizedResponse
+
+func (response PostTasksIdComments401Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostTasksIdComments403Response struct {
+}
+
+func (response PostTasksIdComments403Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostTasksIdComments404Response struct {
+}
+
+func (response PostTasksIdComments404Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasksIdComments409Response = IdempotencyConflictResponse
+
+func (response PostTasksIdComments409Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostTasksIdComments422Response = IdempotencyKeyReusedResponse
+
+func (response PostTasksIdComments422Response) VisitPostTasksIdCommentsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(422)
+	return nil
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Delete a comment (author or admin)
+	// (DELETE /comments/{id})
+	DeleteCommentsId(ctx context.Context, request DeleteCommentsIdRequestObject) (DeleteCommentsIdResponseObject, error)
+	// Edit a comment (author only)
+	// (PATCH /comments/{id})
+	PatchCommentsId(ctx context.Context, request PatchCommentsIdRequestObject) (PatchCommentsIdResponseObject, error)
+	// Comments of a task, oldest first
+	// (GET /tasks/{id}/comments)
+	GetTasksIdComments(ctx context.Context, request GetTasksIdCommentsRequestObject) (GetTasksIdCommentsResponseObject, error)
+	// Add a comment to a task
+	// (POST /tasks/{id}/comments)
+	PostTasksIdComments(ctx context.Context, request PostTasksIdCommentsRequestObject) (PostTasksIdCommentsResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// DeleteCommentsId operation middleware
+func (sh *strictHandler) DeleteCommentsId(w http.ResponseWriter, r *http.Request, id uint, params DeleteCommentsIdParams) {
+	var request DeleteCommentsIdRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteCommentsId(ctx, request.(DeleteCommentsIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteCommentsId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteCommentsIdResponseObject); ok {
+		if err := validResponse.VisitDeleteCommentsIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PatchCommentsId operation middleware
+func (sh *strictHandler) PatchCommentsId(w http.ResponseWriter, r *http.Request, id uint, params PatchCommentsIdParams) {
+	var request PatchCommentsIdRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body PatchCommentsIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PatchCommentsId(ctx, request.(PatchCommentsIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PatchCommentsId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PatchCommentsIdResponseObject); ok {
+		if err := validResponse.VisitPatchCommentsIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasksIdComments operation middleware
+func (sh *strictHandler) GetTasksIdComments(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetTasksIdCommentsRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdComments(ctx, request.(GetTasksIdCommentsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdComments")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdCommentsResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdCommentsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostTasksIdComments operation middleware
+func (sh *strictHandler) PostTasksIdComments(w http.ResponseWriter, r *http.Request, id uint, params PostTasksIdCommentsParams) {
+	var request PostTasksIdCommentsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body PostTasksIdCommentsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostTasksIdComments(ctx, request.(PostTasksIdCommentsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostTasksIdComments")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostTasksIdCommentsResponseObject); ok {
+		if err := validResponse.VisitPostTasksIdCommentsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -0,0 +1,156 @@
+package comments
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/commentService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/web/etag"
+)
+
+// handlers комментариев (как в tasks: только маппинг HTTP <-> сервис)
+
+type CommentHandler struct {
+	service *commentService.CommentService
+}
+
+func NewCommentHandler(s *commentService.CommentService) *CommentHandler {
+	return &CommentHandler{service: s}
+}
+
+// toAPIComment - маппит бизнес-модель в апи-модель (у надгробия нет body)
+func toAPIComment(c *commentService.Comment) Comment {
+	comment := Comment{
+		Id:        c.ID,
+		TaskId:    c.TaskID,
+		AuthorId:  c.AuthorID,
+		Deleted:   c.Deleted,
+		Version:   c.Version,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if !c.Deleted {
+		comment.Body = &c.Body
+	}
+	return comment
+}
+
+func (h *CommentHandler) GetTasksIdComments(ctx context.Context, req GetTasksIdCommentsRequestObject) (GetTasksIdCommentsResponseObject, error) {
+	comments, err := h.service.GetComments(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return GetTasksIdComments404Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetTasksIdComments200JSONResponse, 0, len(comments))
+	for i := range comments {
+		response = append(response, toAPIComment(&comments[i]))
+	}
+	return response, nil
+}
+
+func (h *CommentHandler) PostTasksIdComments(ctx context.Context, req PostTasksIdCommentsRequestObject) (PostTasksIdCommentsResponseObject, error) {
+	if req.Body == nil {
+		return PostTasksIdComments400Response{}, nil
+	}
+
+	comment, err := h.service.CreateComment(authn.Actor(ctx), req.Id, commentService.CreateCommentParams{Body: req.Body.Body})
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return PostTasksIdComments404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostTasksIdComments403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "comment is empty") ||
+			strings.Contains(err.Error(), "comment is too long") {
+			return PostTasksIdComments400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Comment %d added to task %d", comment.ID, req.Id)
+
+	return PostTasksIdComments201JSONResponse{
+		Body:    toAPIComment(comment),
+		Headers: PostTasksIdComments201ResponseHeaders{ETag: etag.Format(comment.Version)},
+	}, nil
+}
+
+func (h *CommentHandler) PatchCommentsId(ctx context.Context, req PatchCommentsIdRequestObject) (PatchCommentsIdResponseObject, error) {
+	// без If-Match не обновляем (как PATCH задачи)
+	if req.Params.IfMatch == nil {
+		return PatchCommentsId428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*req.Params.IfMatch)
+	if err != nil {
+		return PatchCommentsId412Response{}, nil
+	}
+	if req.Body == nil {
+		return PatchCommentsId400Response{}, nil
+	}
+
+	comment, err := h.service.UpdateComment(authn.Actor(ctx), req.Id, version, commentService.UpdateCommentParams{Body: req.Body.Body})
+	if err != nil {
+		if strings.Contains(err.Error(), "comment not found") {
+			return PatchCommentsId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "comment is deleted") {
+			return PatchCommentsId410Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PatchCommentsId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return PatchCommentsId412Response{}, nil
+		}
+		// ошибки валидации - 400
+		if strings.Contains(err.Error(), "comment is empty") ||
+			strings.Contains(err.Error(), "comment is too long") ||
+			strings.Contains(err.Error(), "no fields to update") {
+			return PatchCommentsId400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[PATCH] Comment %d updated successfully", req.Id)
+
+	return PatchCommentsId200JSONResponse{
+		Body:    toAPIComment(comment),
+		Headers: PatchCommentsId200ResponseHeaders{ETag: etag.Format(comment.Version)},
+	}, nil
+}
+
+func (h *CommentHandler) DeleteCommentsId(ctx context.Context, req DeleteCommentsIdRequestObject) (DeleteCommentsIdResponseObject, error) {
+	if req.Params.IfMatch == nil {
+		return DeleteCommentsId428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*req.Params.IfMatch)
+	if err != nil {
+		return DeleteCommentsId412Response{}, nil
+	}
+
+	if err := h.service.DeleteComment(authn.Actor(ctx), req.Id, version); err != nil {
+		if strings.Contains(err.Error(), "comment not found") {
+			return DeleteCommentsId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "comment is deleted") {
+			return DeleteCommentsId410Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteCommentsId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return DeleteCommentsId412Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Comment %d deleted successfully", req.Id)
+
+	return DeleteCommentsId204Response{}, nil
+}
@@ -0,0 +1,46 @@
+package custommethod
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /tasks/{id}/revisions/{rev}/restore", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id") + "@" + r.PathValue("rev")))
+	})
+	mux.HandleFunc("GET /files/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("name")))
+	})
+	handler := Handler(mux, "restore")
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "кастомный метод через двоеточие", method: http.MethodPost, path: "/tasks/7/revisions/3:restore", wantCode: http.StatusOK, wantBody: "7@3"},
+		{name: "обычный путь работает как раньше", method: http.MethodPost, path: "/tasks/7/revisions/3/restore", wantCode: http.StatusOK, wantBody: "7@3"},
+		{name: "неизвестный глагол не переписывается", method: http.MethodPost, path: "/tasks/7/revisions/3:purge", wantCode: http.StatusNotFound},
+		{name: "двоеточие в других путях не трогаем", method: http.MethodGet, path: "/files/a:restore.txt", wantCode: http.StatusOK, wantBody: "a:restore.txt"},
+		{name: "глагол без ресурса", method: http.MethodPost, path: "/tasks/7/revisions/:restore", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package custommethod
+
+import (
+	"net/http"
+	"strings"
+)
+
+// кастомные методы в стиле ":verb" (POST /tasks/7/revisions/3:restore)
+//   • шаблоны net/http не умеют "{rev}:restore" (wildcard должен занимать весь сегмент пути),
+//     поэтому в OpenAPI и роутере такой метод описан как ".../{rev}/restore"
+//   • middleware снаружи роутера переписывает ".../3:restore" в ".../3/restore" - дальше запрос идет как обычно
+//   • переписываются только перечисленные глаголы: двоеточие в других путях не трогаем
+
+// Handler - переписывает путь для глаголов verbs
+func Handler(next http.Handler, verbs ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, verb := range verbs {
+			suffix := ":" + verb
+			if !strings.HasSuffix(r.URL.Path, suffix) {
+				continue
+			}
+			base := strings.TrimSuffix(r.URL.Path, suffix)
+			if base == "" || strings.HasSuffix(base, "/") {
+				break // ":verb" без ресурса перед ним
+			}
+
+			r2 := new(http.Request)
+			*r2 = *r
+			u := *r.URL
+			u.Path = base + "/" + verb
+			if u.RawPath != "" {
+				u.RawPath = strings.TrimSuffix(u.RawPath, suffix) + "/" + verb
+			}
+			r2.URL = &u
+			r = r2
+			break
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,38 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// общий для tasks и users хелпер: версия ресурса <-> заголовки ETag / If-Match
+//   • ETag отдаём в виде строгого тега "N", где N - колонка version в бд
+//   • If-Match принимаем как "N" или * (любая текущая версия)
+//   • слабые теги (W/"N") для If-Match не подходят (RFC 9110), поэтому считаем их ошибкой
+
+// Format - превращает версию в значение заголовка ETag
+func Format(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// ParseIfMatch - разбирает заголовок If-Match
+// возвращает nil, если клиент передал * (тогда версию не проверяем)
+func ParseIfMatch(header string) (*uint, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return nil, nil
+	}
+
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return nil, errors.New("invalid If-Match header")
+	}
+
+	v, err := strconv.ParseUint(header[1:len(header)-1], 10, 0)
+	if err != nil || v == 0 {
+		return nil, errors.New("invalid If-Match header")
+	}
+
+	version := uint(v)
+	return &version, nil
+}
@@ -0,0 +1,40 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, `"7"`, Format(7))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    *uint
+		wantErr bool
+	}{
+		{name: "строгий тег", header: `"3"`, want: func() *uint { v := uint(3); return &v }()},
+		{name: "звездочка - любая версия", header: "*", want: nil},
+		{name: "пробелы вокруг значения", header: ` "12" `, want: func() *uint { v := uint(12); return &v }()},
+		{name: "слабый тег", header: `W/"3"`, wantErr: true},
+		{name: "без кавычек", header: "3", wantErr: true},
+		{name: "не число", header: `"abc"`, wantErr: true},
+		{name: "нулевая версия", header: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIfMatch(tt.header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
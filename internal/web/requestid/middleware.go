@@ -0,0 +1,57 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// id запроса (X-Request-ID)
+//   • клиент (или прокси перед нами) может прислать свой id - тогда берем его, чтобы запрос можно было
+//     найти по одному id во всех логах; иначе генерируем новый
+//   • id возвращается в ответе и лежит в контексте: по нему журнал аудита связывает изменения с запросом
+
+const Header = "X-Request-ID"
+
+// максимальная длина id от клиента (длиннее - генерируем свой)
+const maxLength = 128
+
+type requestIDKey struct{}
+
+// WithRequestID - кладет id запроса в контекст
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// FromContext - id текущего запроса (пусто - вне HTTP-запроса)
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Handler - сама middleware (подходит под тип MiddlewareFunc из api.gen.go)
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// valid - id от клиента попадает в логи и в бд, поэтому пускаем только короткие печатные ASCII-строки
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
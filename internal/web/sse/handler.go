@@ -0,0 +1,148 @@
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+)
+
+// GET /events - поток событий об изменении задач (Server-Sent Events)
+// написан руками, а не через oapi-codegen: strict-хендлер отдает тело целиком и не умеет сбрасывать его по частям
+//   • клиент получает события только тех задач, которые видит (права - как у GET /tasks/{id})
+//   • при переподключении браузер сам присылает Last-Event-ID - пропущенное досылается из буфера шины,
+//     а если там его уже нет - приходит событие reset (пора перечитать задачи целиком)
+//   • раз в heartbeat уходит комментарий, чтобы прокси не закрывали молчащее соединение
+//   • поток живет не дольше maxAge: токен проверен только при подключении, а отозванная сессия
+//     или смена роли должны вступить в силу - клиент переподключится и пройдет проверку заново
+
+// значения по умолчанию
+const (
+	DefaultHeartbeat = 25 * time.Second
+	DefaultMaxAge    = 15 * time.Minute
+)
+
+// через сколько миллисекунд браузеру переподключаться после обрыва
+const retryMillis = 3000
+
+// Subscriber - источник событий (реализует events.Bus)
+type Subscriber interface {
+	Subscribe(lastEventID string) (*events.Subscription, []events.Event, bool)
+}
+
+// Visibility - видит ли actor задачу из события (реализует taskService.TaskService)
+type Visibility interface {
+	CanSeeEvent(actor rbac.Actor, ev events.Event) bool
+}
+
+type Handler struct {
+	bus       Subscriber
+	tasks     Visibility
+	heartbeat time.Duration
+	maxAge    time.Duration
+}
+
+func NewHandler(bus Subscriber, tasks Visibility) *Handler {
+	return &Handler{bus: bus, tasks: tasks, heartbeat: DefaultHeartbeat, maxAge: DefaultMaxAge}
+}
+
+// WithTimeouts - как часто слать пинг и сколько держать поток открытым
+func (h *Handler) WithTimeouts(heartbeat, maxAge time.Duration) *Handler {
+	h.heartbeat = heartbeat
+	h.maxAge = maxAge
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	actor := authn.Actor(r.Context())
+	if actor.UserID == 0 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	sub, replay, complete := h.bus.Subscribe(r.Header.Get("Last-Event-ID"))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx не должен копить поток в буфере
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryMillis); err != nil {
+		return
+	}
+	if !complete {
+		if err := writeEvent(w, events.Event{Type: events.Reset}); err != nil {
+			return
+		}
+	}
+	for _, ev := range replay {
+		if err := h.send(w, actor, ev); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	maxAge := time.NewTimer(h.maxAge)
+	defer maxAge.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-maxAge.C:
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		case ev, ok := <-sub.C:
+			if !ok {
+				return // не успевали отдавать - шина отключила; клиент переподключится и догонит
+			}
+			err = h.send(w, actor, ev)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// send - пишет событие, если actor видит его задачу (reset - всем)
+func (h *Handler) send(w http.ResponseWriter, actor rbac.Actor, ev events.Event) error {
+	if ev.Type != events.Reset && !h.tasks.CanSeeEvent(actor, ev) {
+		return nil
+	}
+	return writeEvent(w, ev)
+}
+
+// writeEvent - событие в формате text/event-stream
+// без тела (не влезло в NOTIFY) клиент получает только id задачи и перечитывает ее сам
+func writeEvent(w http.ResponseWriter, ev events.Event) error {
+	data := string(ev.Data)
+	switch {
+	case ev.Type == events.Reset:
+		data = "{}"
+	case data == "":
+		data = fmt.Sprintf(`{"id":%d}`, ev.TaskID)
+	}
+
+	if ev.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", ev.ID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+	return err
+}
@@ -0,0 +1,149 @@
+package sse
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownerOnly - пользователь видит только задачи, где он владелец
+type ownerOnly struct{}
+
+func (ownerOnly) CanSeeEvent(actor rbac.Actor, ev events.Event) bool {
+	return ev.OwnerID == actor.UserID
+}
+
+// sseEvent - разобранное событие из потока
+type sseEvent struct {
+	id, event, data string
+}
+
+// startServer - сервер с хендлером; пользователь запроса - из заголовка X-User (как будто его проверил authn)
+func startServer(t *testing.T, h *Handler) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User") != "" {
+			id, err := strconv.ParseUint(r.Header.Get("X-User"), 10, 64)
+			require.NoError(t, err)
+			r = r.WithContext(authn.WithPrincipal(r.Context(), &authService.Principal{UserID: uint(id), Role: rbac.RoleUser}))
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// connect - открывает поток и отдает канал разобранных событий (закрывается вместе с потоком)
+func connect(t *testing.T, server *httptest.Server, user, lastEventID string) <-chan sseEvent {
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-User", user)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	out := make(chan sseEvent, 16)
+	go func() {
+		defer close(out)
+		var ev sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "id":
+				ev.id = value
+			case "event":
+				ev.event = value
+			case "data":
+				ev.data = value
+			case "":
+				if ev.event != "" {
+					out <- ev
+				}
+				ev = sseEvent{}
+			}
+		}
+	}()
+	return out
+}
+
+// next - следующее событие (или провал теста, если его нет)
+func next(t *testing.T, stream <-chan sseEvent) sseEvent {
+	select {
+	case ev, ok := <-stream:
+		require.True(t, ok, "stream closed")
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event")
+		return sseEvent{}
+	}
+}
+
+func TestHandlerStreamsVisibleEvents(t *testing.T) {
+	bus := events.NewBus(10)
+	server := startServer(t, NewHandler(bus, ownerOnly{}))
+	// connect возвращается после заголовков ответа, а хендлер отправляет их уже после подписки
+	stream := connect(t, server, "1", "")
+
+	bus.Publish(events.Event{ID: "e1", Type: events.TaskCreated, TaskID: 7, OwnerID: 2, Data: json.RawMessage(`{"id":7}`)})
+	bus.Publish(events.Event{ID: "e2", Type: events.TaskUpdated, TaskID: 8, OwnerID: 1, Data: json.RawMessage(`{"id":8,"is_done":true}`)})
+	bus.Publish(events.Event{ID: "e3", Type: events.TaskDeleted, TaskID: 9, OwnerID: 1})
+
+	// чужая задача 7 не приходит
+	assert.Equal(t, sseEvent{id: "e2", event: events.TaskUpdated, data: `{"id":8,"is_done":true}`}, next(t, stream))
+	// события без тела - только id задачи
+	assert.Equal(t, sseEvent{id: "e3", event: events.TaskDeleted, data: `{"id":9}`}, next(t, stream))
+}
+
+func TestHandlerResume(t *testing.T) {
+	bus := events.NewBus(10)
+	bus.Publish(events.Event{ID: "e1", Type: events.TaskCreated, TaskID: 7, OwnerID: 1})
+	bus.Publish(events.Event{ID: "e2", Type: events.TaskUpdated, TaskID: 7, OwnerID: 1})
+	server := startServer(t, NewHandler(bus, ownerOnly{}))
+
+	// догоняем пропущенное после e1
+	stream := connect(t, server, "1", "e1")
+	assert.Equal(t, "e2", next(t, stream).id)
+
+	// e0 в буфере нет - клиенту пора перечитать задачи
+	stream = connect(t, server, "1", "e0")
+	assert.Equal(t, sseEvent{event: events.Reset, data: "{}"}, next(t, stream))
+}
+
+func TestHandlerMaxAge(t *testing.T) {
+	bus := events.NewBus(10)
+	server := startServer(t, NewHandler(bus, ownerOnly{}).WithTimeouts(time.Hour, 50*time.Millisecond))
+	stream := connect(t, server, "1", "")
+
+	select {
+	case _, ok := <-stream:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream was not closed after maxAge")
+	}
+}
+
+func TestHandlerRequiresUser(t *testing.T) {
+	server := startServer(t, NewHandler(events.NewBus(10), ownerOnly{}))
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -10,31 +10,218 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuditEntryAction.
+const (
+	Created AuditEntryAction = "created"
+	Deleted AuditEntryAction = "deleted"
+	Updated AuditEntryAction = "updated"
+)
+
+// Defines values for AuditEntryEntityType.
+const (
+	AuditEntryEntityTypeTask AuditEntryEntityType = "task"
+	AuditEntryEntityTypeUser AuditEntryEntityType = "user"
+)
+
+// Defines values for TextChangeOp.
+const (
+	Delete TextChangeOp = "delete"
+	Equal  TextChangeOp = "equal"
+	Insert TextChangeOp = "insert"
+)
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action AuditEntryAction `json:"action"`
+
+	// ActorId Who made the change (missing for changes made by the system)
+	ActorId *uint `json:"actor_id,omitempty"`
+
+	// After State after the change (missing for deleted)
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// Before State before the change (missing for created)
+	Before *map[string]interface{} `json:"before,omitempty"`
+
+	// Changes Changed fields; secrets such as the password show up as "[redacted]"
+	Changes    map[string]AuditFieldChange `json:"changes"`
+	CreatedAt  time.Time                   `json:"created_at"`
+	EntityId   uint                        `json:"entity_id"`
+	EntityType AuditEntryEntityType        `json:"entity_type"`
+	Id         uint                        `json:"id"`
+
+	// RequestId X-Request-ID of the request that made the change
+	RequestId *string `json:"request_id,omitempty"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// AuditEntryEntityType defines model for AuditEntry.EntityType.
+type AuditEntryEntityType string
+
+// AuditFieldChange defines model for AuditFieldChange.
+type AuditFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
 // CreateTaskRequest defines model for CreateTaskRequest.
 type CreateTaskRequest struct {
 	IsDone *bool  `json:"is_done"`
 	Task   string `json:"task"`
 	UserId uint   `json:"user_id"`
+
+	// WorkspaceId Create the task in this workspace (the owner must be a member of it)
+	WorkspaceId *uint `json:"workspace_id"`
 }
 
+// MergePatch JSON Merge Patch document (RFC 7396) - null removes a field
+type MergePatch map[string]interface{}
+
 // Task defines model for Task.
 type Task struct {
-	Id     *uint   `json:"id,omitempty"`
-	IsDone *bool   `json:"is_done,omitempty"`
-	Task   *string `json:"task,omitempty"`
-	UserId *uint   `json:"user_id,omitempty"`
+	// AssigneeIds Users assigned to the task (the owner is not listed)
+	AssigneeIds *[]uint `json:"assignee_ids,omitempty"`
+
+	// CommentCount Number of comments that are not deleted
+	CommentCount *int64 `json:"comment_count,omitempty"`
+	Id           *uint  `json:"id,omitempty"`
+	IsDone       *bool  `json:"is_done,omitempty"`
+
+	// Position Place of the task on a board - clients order tasks by position, then by id
+	Position *int64  `json:"position,omitempty"`
+	Task     *string `json:"task,omitempty"`
+	UserId   *uint   `json:"user_id,omitempty"`
+	Version  *uint   `json:"version,omitempty"`
+
+	// WorkspaceId Workspace of the task (absent for personal tasks)
+	WorkspaceId *uint `json:"workspace_id,omitempty"`
+}
+
+// TaskRevision defines model for TaskRevision.
+type TaskRevision struct {
+	// ActorId Who made the change (missing for changes made by the system)
+	ActorId   *uint     `json:"actor_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	IsDone    bool      `json:"is_done"`
+
+	// RestoredFrom Set when this revision was created by restoring an older one
+	RestoredFrom *uint `json:"restored_from,omitempty"`
+
+	// Revision Task version after the change
+	Revision uint   `json:"revision"`
+	Task     string `json:"task"`
+	UserId   uint   `json:"user_id"`
+}
+
+// TaskRevisionDiff defines model for TaskRevisionDiff.
+type TaskRevisionDiff struct {
+	// Changes Joining equal and delete parts gives the old text, equal and insert parts - the new one
+	Changes []TextChange `json:"changes"`
+	From    uint         `json:"from"`
+	To      uint         `json:"to"`
+}
+
+// TaskSearchPage defines model for TaskSearchPage.
+type TaskSearchPage struct {
+	Items  *[]TaskSearchResult `json:"items,omitempty"`
+	Limit  *int                `json:"limit,omitempty"`
+	Offset *int                `json:"offset,omitempty"`
+	Total  *int64              `json:"total,omitempty"`
+}
+
+// TaskSearchResult defines model for TaskSearchResult.
+type TaskSearchResult struct {
+	// Highlight HTML-escaped task text with the matches wrapped in <b>...</b>
+	Highlight *string  `json:"highlight,omitempty"`
+	Rank      *float32 `json:"rank,omitempty"`
+	Task      *Task    `json:"task,omitempty"`
+}
+
+// TextChange defines model for TextChange.
+type TextChange struct {
+	Op   TextChangeOp `json:"op"`
+	Text string       `json:"text"`
 }
 
+// TextChangeOp defines model for TextChange.Op.
+type TextChangeOp string
+
 // UpdateTaskRequest defines model for UpdateTaskRequest.
 type UpdateTaskRequest struct {
-	IsDone *bool   `json:"is_done"`
-	Task   *string `json:"task"`
-	UserId *uint   `json:"user_id"`
+	IsDone *bool `json:"is_done"`
+
+	// Position Assignees can change it as well as is_done
+	Position *int64  `json:"position"`
+	Task     *string `json:"task"`
+	UserId   *uint   `json:"user_id"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// GetTasksParams defines parameters for GetTasks.
+type GetTasksParams struct {
+	WorkspaceId *uint `form:"workspace_id,omitempty" json:"workspace_id,omitempty"`
+}
+
+// PostTasksParams defines parameters for PostTasks.
+type PostTasksParams struct {
+	// IdempotencyKey Unique key of the request; retries with the same key replay the stored response
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetTasksSearchParams defines parameters for GetTasksSearch.
+type GetTasksSearchParams struct {
+	Q      string `form:"q" json:"q"`
+	UserId uint   `form:"user_id" json:"user_id"`
+	Limit  *int   `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int   `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// DeleteTasksIdParams defines parameters for DeleteTasksId.
+type DeleteTasksIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchTasksIdParams defines parameters for PatchTasksId.
+type PatchTasksIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetTasksIdHistoryParams defines parameters for GetTasksIdHistory.
+type GetTasksIdHistoryParams struct {
+	// Limit How many entries to return (default 50, at most 500)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetTasksIdRevisionsDiffParams defines parameters for GetTasksIdRevisionsDiff.
+type GetTasksIdRevisionsDiffParams struct {
+	From uint `form:"from" json:"from"`
+	To   uint `form:"to" json:"to"`
+}
+
+// PostTasksIdRevisionsRevRestoreParams defines parameters for PostTasksIdRevisionsRevRestore.
+type PostTasksIdRevisionsRevRestoreParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
 // PostTasksJSONRequestBody defines body for PostTasks for application/json ContentType.
@@ -43,20 +230,47 @@ type PostTasksJSONRequestBody = CreateTaskRequest
 // PatchTasksIdJSONRequestBody defines body for PatchTasksId for application/json ContentType.
 type PatchTasksIdJSONRequestBody = UpdateTaskRequest
 
+// PatchTasksIdApplicationMergePatchPlusJSONRequestBody defines body for PatchTasksId for application/merge-patch+json ContentType.
+type PatchTasksIdApplicationMergePatchPlusJSONRequestBody = MergePatch
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Get all tasks
 	// (GET /tasks)
-	GetTasks(w http.ResponseWriter, r *http.Request)
+	GetTasks(w http.ResponseWriter, r *http.Request, params GetTasksParams)
 	// Create a new task
 	// (POST /tasks)
-	PostTasks(w http.ResponseWriter, r *http.Request)
+	PostTasks(w http.ResponseWriter, r *http.Request, params PostTasksParams)
+	// Full-text search over user's tasks
+	// (GET /tasks/search)
+	GetTasksSearch(w http.ResponseWriter, r *http.Request, params GetTasksSearchParams)
 	// Delete a task by ID
 	// (DELETE /tasks/{id})
-	DeleteTasksId(w http.ResponseWriter, r *http.Request, id uint)
+	DeleteTasksId(w http.ResponseWriter, r *http.Request, id uint, params DeleteTasksIdParams)
+	// Get a task by ID
+	// (GET /tasks/{id})
+	GetTasksId(w http.ResponseWriter, r *http.Request, id uint)
 	// Update a task
 	// (PATCH /tasks/{id})
-	PatchTasksId(w http.ResponseWriter, r *http.Request, id uint)
+	PatchTasksId(w http.ResponseWriter, r *http.Request, id uint, params PatchTasksIdParams)
+	// Unassign a user from the task
+	// (DELETE /tasks/{id}/assignees/{userId})
+	DeleteTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint)
+	// Assign a user to the task
+	// (PUT /tasks/{id}/assignees/{userId})
+	PutTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint)
+	// Change history of a task, newest first
+	// (GET /tasks/{id}/history)
+	GetTasksIdHistory(w http.ResponseWriter, r *http.Request, id uint, params GetTasksIdHistoryParams)
+	// Saved revisions of a task, newest first
+	// (GET /tasks/{id}/revisions)
+	GetTasksIdRevisions(w http.ResponseWriter, r *http.Request, id uint)
+	// Word-level diff of the task text between two revisions
+	// (GET /tasks/{id}/revisions/diff)
+	GetTasksIdRevisionsDiff(w http.ResponseWriter, r *http.Request, id uint, params GetTasksIdRevisionsDiffParams)
+	// Roll the task back to a revision
+	// (POST /tasks/{id}/revisions/{rev}/restore)
+	PostTasksIdRevisionsRevRestore(w http.ResponseWriter, r *http.Request, id uint, rev uint, params PostTasksIdRevisionsRevRestoreParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -71,8 +285,27 @@ type MiddlewareFunc func(http.Handler) http.Handler
 // GetTasks operation middleware
 func (siw *ServerInterfaceWrapper) GetTasks(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksParams
+
+	// ------------- Optional query parameter "workspace_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "workspace_id", r.URL.Query(), &params.WorkspaceId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "workspace_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTasks(w, r)
+		siw.Handler.GetTasks(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -85,8 +318,111 @@ func (siw *ServerInterfaceWrapper) GetTasks(w http.ResponseWriter, r *http.Reque
 // PostTasks operation middleware
 func (siw *ServerInterfaceWrapper) PostTasks(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTasksParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTasks(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksSearch(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksSearchParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTasks(w, r)
+		siw.Handler.GetTasksSearch(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -110,8 +446,69 @@ func (siw *ServerInterfaceWrapper) DeleteTasksId(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteTasksIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTasksId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksId operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteTasksId(w, r, id)
+		siw.Handler.GetTasksId(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -135,8 +532,38 @@ func (siw *ServerInterfaceWrapper) PatchTasksId(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchTasksIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PatchTasksId(w, r, id)
+		siw.Handler.PatchTasksId(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -146,207 +573,1012 @@ func (siw *ServerInterfaceWrapper) PatchTasksId(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// DeleteTasksIdAssigneesUserId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request) {
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	var err error
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "id" -------------
+	var id uint
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	// ------------- Path parameter "userId" -------------
+	var userId uint
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", r.PathValue("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
 
-type RequiredParamError struct {
-	ParamName string
-}
+	ctx := r.Context()
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	r = r.WithContext(ctx)
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTasksIdAssigneesUserId(w, r, id, userId)
+	}))
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+	handler.ServeHTTP(w, r)
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+// PutTasksIdAssigneesUserId operation middleware
+func (siw *ServerInterfaceWrapper) PutTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request) {
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
-}
+	var err error
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+	// ------------- Path parameter "id" -------------
+	var id uint
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", r.PathValue("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutTasksIdAssigneesUserId(w, r, id, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksIdHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksIdHistoryParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdHistory(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksIdRevisions operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdRevisions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdRevisions(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasksIdRevisionsDiff operation middleware
+func (siw *ServerInterfaceWrapper) GetTasksIdRevisionsDiff(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksIdRevisionsDiffParams
+
+	// ------------- Required query parameter "from" -------------
+
+	if paramValue := r.URL.Query().Get("from"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "from"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "to" -------------
+
+	if paramValue := r.URL.Query().Get("to"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "to"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasksIdRevisionsDiff(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTasksIdRevisionsRevRestore operation middleware
+func (siw *ServerInterfaceWrapper) PostTasksIdRevisionsRevRestore(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "rev" -------------
+	var rev uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "rev", r.PathValue("rev"), &rev, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rev", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTasksIdRevisionsRevRestoreParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTasksIdRevisionsRevRestore(w, r, id, rev, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/tasks", wrapper.GetTasks)
+	m.HandleFunc("POST "+options.BaseURL+"/tasks", wrapper.PostTasks)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/search", wrapper.GetTasksSearch)
+	m.HandleFunc("DELETE "+options.BaseURL+"/tasks/{id}", wrapper.DeleteTasksId)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}", wrapper.GetTasksId)
+	m.HandleFunc("PATCH "+options.BaseURL+"/tasks/{id}", wrapper.PatchTasksId)
+	m.HandleFunc("DELETE "+options.BaseURL+"/tasks/{id}/assignees/{userId}", wrapper.DeleteTasksIdAssigneesUserId)
+	m.HandleFunc("PUT "+options.BaseURL+"/tasks/{id}/assignees/{userId}", wrapper.PutTasksIdAssigneesUserId)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/history", wrapper.GetTasksIdHistory)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/revisions", wrapper.GetTasksIdRevisions)
+	m.HandleFunc("GET "+options.BaseURL+"/tasks/{id}/revisions/diff", wrapper.GetTasksIdRevisionsDiff)
+	m.HandleFunc("POST "+options.BaseURL+"/tasks/{id}/revisions/{rev}/restore", wrapper.PostTasksIdRevisionsRevRestore)
+
+	return m
+}
+
+type IdempotencyConflictResponse struct {
+}
+
+type IdempotencyKeyReusedResponse struct {
+}
+
+type PreconditionFailedResponse struct {
+}
+
+type PreconditionRequiredResponse struct {
+}
+
+type UnauthorizedResponse struct {
+}
+
+type GetTasksRequestObject struct {
+	Params GetTasksParams
+}
+
+type GetTasksResponseObject interface {
+	VisitGetTasksResponse(w http.ResponseWriter) error
+}
+
+type GetTasks200JSONResponse []Task
+
+func (response GetTasks200JSONResponse) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasks401Response = UnauthorizedResponse
+
+func (response GetTasks401Response) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasks404Response struct {
+}
+
+func (response GetTasks404Response) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasksRequestObject struct {
+	Params PostTasksParams
+	Body   *PostTasksJSONRequestBody
+}
+
+type PostTasksResponseObject interface {
+	VisitPostTasksResponse(w http.ResponseWriter) error
+}
+
+type PostTasks201ResponseHeaders struct {
+	ETag string
+}
+
+type PostTasks201JSONResponse struct {
+	Body    Task
+	Headers PostTasks201ResponseHeaders
+}
+
+func (response PostTasks201JSONResponse) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostTasks400Response struct {
+}
+
+func (response PostTasks400Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostTasks401Response = UnauthorizedResponse
+
+func (response PostTasks401Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostTasks403Response struct {
+}
+
+func (response PostTasks403Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostTasks404Response struct {
+}
+
+func (response PostTasks404Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostTasks409Response = IdempotencyConflictResponse
+
+func (response PostTasks409Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostTasks422Response = IdempotencyKeyReusedResponse
+
+func (response PostTasks422Response) VisitPostTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(422)
+	return nil
+}
+
+type GetTasksSearchRequestObject struct {
+	Params GetTasksSearchParams
+}
+
+type GetTasksSearchResponseObject interface {
+	VisitGetTasksSearchResponse(w http.ResponseWriter) error
+}
+
+type GetTasksSearch200JSONResponse TaskSearchPage
+
+func (response GetTasksSearch200JSONResponse) VisitGetTasksSearchResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasksSearch400Response struct {
+}
+
+func (response GetTasksSearch400Response) VisitGetTasksSearchResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type GetTasksSearch401Response = UnauthorizedResponse
+
+func (response GetTasksSearch401Response) VisitGetTasksSearchResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksSearch403Response struct {
+}
+
+func (response GetTasksSearch403Response) VisitGetTasksSearchResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteTasksIdRequestObject struct {
+	Id     uint `json:"id"`
+	Params DeleteTasksIdParams
+}
+
+type DeleteTasksIdResponseObject interface {
+	VisitDeleteTasksIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteTasksId204Response struct {
+}
+
+func (response DeleteTasksId204Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteTasksId401Response = UnauthorizedResponse
+
+func (response DeleteTasksId401Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteTasksId403Response struct {
+}
+
+func (response DeleteTasksId403Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteTasksId404Response struct {
+}
+
+func (response DeleteTasksId404Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteTasksId412Response = PreconditionFailedResponse
+
+func (response DeleteTasksId412Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type DeleteTasksId428Response = PreconditionRequiredResponse
+
+func (response DeleteTasksId428Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type GetTasksIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetTasksIdResponseObject interface {
+	VisitGetTasksIdResponse(w http.ResponseWriter) error
+}
+
+type GetTasksId200ResponseHeaders struct {
+	ETag string
+}
+
+type GetTasksId200JSONResponse struct {
+	Body    Task
+	Headers GetTasksId200ResponseHeaders
+}
+
+func (response GetTasksId200JSONResponse) VisitGetTasksIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetTasksId401Response = UnauthorizedResponse
+
+func (response GetTasksId401Response) VisitGetTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksId404Response struct {
+}
+
+func (response GetTasksId404Response) VisitGetTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchTasksIdRequestObject struct {
+	Id                                uint `json:"id"`
+	Params                            PatchTasksIdParams
+	JSONBody                          *PatchTasksIdJSONRequestBody
+	ApplicationMergePatchPlusJSONBody *PatchTasksIdApplicationMergePatchPlusJSONRequestBody
+}
+
+type PatchTasksIdResponseObject interface {
+	VisitPatchTasksIdResponse(w http.ResponseWriter) error
+}
+
+type PatchTasksId200ResponseHeaders struct {
+	ETag string
+}
+
+type PatchTasksId200JSONResponse struct {
+	Body    Task
+	Headers PatchTasksId200ResponseHeaders
+}
+
+func (response PatchTasksId200JSONResponse) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PatchTasksId400Response struct {
+}
+
+func (response PatchTasksId400Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PatchTasksId401Response = UnauthorizedResponse
+
+func (response PatchTasksId401Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PatchTasksId403Response struct {
+}
+
+func (response PatchTasksId403Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PatchTasksId404Response struct {
+}
+
+func (response PatchTasksId404Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchTasksId412Response = PreconditionFailedResponse
+
+func (response PatchTasksId412Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type PatchTasksId428Response = PreconditionRequiredResponse
+
+func (response PatchTasksId428Response) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type DeleteTasksIdAssigneesUserIdRequestObject struct {
+	Id     uint `json:"id"`
+	UserId uint `json:"userId"`
+}
+
+type DeleteTasksIdAssigneesUserIdResponseObject interface {
+	VisitDeleteTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteTasksIdAssigneesUserId204Response struct {
+}
+
+func (response DeleteTasksIdAssigneesUserId204Response) VisitDeleteTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteTasksIdAssigneesUserId401Response = UnauthorizedResponse
+
+func (response DeleteTasksIdAssigneesUserId401Response) VisitDeleteTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteTasksIdAssigneesUserId403Response struct {
+}
+
+func (response DeleteTasksIdAssigneesUserId403Response) VisitDeleteTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteTasksIdAssigneesUserId404Response struct {
+}
+
+func (response DeleteTasksIdAssigneesUserId404Response) VisitDeleteTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PutTasksIdAssigneesUserIdRequestObject struct {
+	Id     uint `json:"id"`
+	UserId uint `json:"userId"`
+}
+
+type PutTasksIdAssigneesUserIdResponseObject interface {
+	VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error
+}
+
+type PutTasksIdAssigneesUserId204Response struct {
+}
+
+func (response PutTasksIdAssigneesUserId204Response) VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type PutTasksIdAssigneesUserId400Response struct {
+}
+
+func (response PutTasksIdAssigneesUserId400Response) VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PutTasksIdAssigneesUserId401Response = UnauthorizedResponse
+
+func (response PutTasksIdAssigneesUserId401Response) VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PutTasksIdAssigneesUserId403Response struct {
+}
+
+func (response PutTasksIdAssigneesUserId403Response) VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PutTasksIdAssigneesUserId404Response struct {
+}
+
+func (response PutTasksIdAssigneesUserId404Response) VisitPutTasksIdAssigneesUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetTasksIdHistoryRequestObject struct {
+	Id     uint `json:"id"`
+	Params GetTasksIdHistoryParams
+}
+
+type GetTasksIdHistoryResponseObject interface {
+	VisitGetTasksIdHistoryResponse(w http.ResponseWriter) error
+}
+
+type GetTasksIdHistory200JSONResponse []AuditEntry
+
+func (response GetTasksIdHistory200JSONResponse) VisitGetTasksIdHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasksIdHistory400Response struct {
+}
+
+func (response GetTasksIdHistory400Response) VisitGetTasksIdHistoryResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type GetTasksIdHistory401Response = UnauthorizedResponse
+
+func (response GetTasksIdHistory401Response) VisitGetTasksIdHistoryResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetTasksIdHistory404Response struct {
+}
+
+func (response GetTasksIdHistory404Response) VisitGetTasksIdHistoryResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetTasksIdRevisionsRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetTasksIdRevisionsResponseObject interface {
+	VisitGetTasksIdRevisionsResponse(w http.ResponseWriter) error
+}
+
+type GetTasksIdRevisions200JSONResponse []TaskRevision
+
+func (response GetTasksIdRevisions200JSONResponse) VisitGetTasksIdRevisionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
+type GetTasksIdRevisions401Response = UnauthorizedResponse
+
+func (response GetTasksIdRevisions401Response) VisitGetTasksIdRevisionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
 }
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
+type GetTasksIdRevisions404Response struct {
 }
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+func (response GetTasksIdRevisions404Response) VisitGetTasksIdRevisionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
+type GetTasksIdRevisionsDiffRequestObject struct {
+	Id     uint `json:"id"`
+	Params GetTasksIdRevisionsDiffParams
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+type GetTasksIdRevisionsDiffResponseObject interface {
+	VisitGetTasksIdRevisionsDiffResponse(w http.ResponseWriter) error
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+type GetTasksIdRevisionsDiff200JSONResponse TaskRevisionDiff
 
-	if m == nil {
-		m = http.NewServeMux()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
+func (response GetTasksIdRevisionsDiff200JSONResponse) VisitGetTasksIdRevisionsDiffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+	return json.NewEncoder(w).Encode(response)
+}
 
-	m.HandleFunc("GET "+options.BaseURL+"/tasks", wrapper.GetTasks)
-	m.HandleFunc("POST "+options.BaseURL+"/tasks", wrapper.PostTasks)
-	m.HandleFunc("DELETE "+options.BaseURL+"/tasks/{id}", wrapper.DeleteTasksId)
-	m.HandleFunc("PATCH "+options.BaseURL+"/tasks/{id}", wrapper.PatchTasksId)
+type GetTasksIdRevisionsDiff401Response = UnauthorizedResponse
 
-	return m
+func (response GetTasksIdRevisionsDiff401Response) VisitGetTasksIdRevisionsDiffResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
 }
 
-type GetTasksRequestObject struct {
+type GetTasksIdRevisionsDiff404Response struct {
 }
 
-type GetTasksResponseObject interface {
-	VisitGetTasksResponse(w http.ResponseWriter) error
+func (response GetTasksIdRevisionsDiff404Response) VisitGetTasksIdRevisionsDiffResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
 }
 
-type GetTasks200JSONResponse []Task
-
-func (response GetTasks200JSONResponse) VisitGetTasksResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-
-	return json.NewEncoder(w).Encode(response)
+type PostTasksIdRevisionsRevRestoreRequestObject struct {
+	Id     uint `json:"id"`
+	Rev    uint `json:"rev"`
+	Params PostTasksIdRevisionsRevRestoreParams
 }
 
-type PostTasksRequestObject struct {
-	Body *PostTasksJSONRequestBody
+type PostTasksIdRevisionsRevRestoreResponseObject interface {
+	VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error
 }
 
-type PostTasksResponseObject interface {
-	VisitPostTasksResponse(w http.ResponseWriter) error
+type PostTasksIdRevisionsRevRestore200ResponseHeaders struct {
+	ETag string
 }
 
-type PostTasks201JSONResponse Task
+type PostTasksIdRevisionsRevRestore200JSONResponse struct {
+	Body    Task
+	Headers PostTasksIdRevisionsRevRestore200ResponseHeaders
+}
 
-func (response PostTasks201JSONResponse) VisitPostTasksResponse(w http.ResponseWriter) error {
+func (response PostTasksIdRevisionsRevRestore200JSONResponse) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
 
-	return json.NewEncoder(w).Encode(response)
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type DeleteTasksIdRequestObject struct {
-	Id uint `json:"id"`
-}
+type PostTasksIdRevisionsRevRestore401Response = UnauthorizedResponse
 
-type DeleteTasksIdResponseObject interface {
-	VisitDeleteTasksIdResponse(w http.ResponseWriter) error
+func (response PostTasksIdRevisionsRevRestore401Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
 }
 
-type DeleteTasksId204Response struct {
+type PostTasksIdRevisionsRevRestore403Response struct {
 }
 
-func (response DeleteTasksId204Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
+func (response PostTasksIdRevisionsRevRestore403Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
 	return nil
 }
 
-type DeleteTasksId404Response struct {
+type PostTasksIdRevisionsRevRestore404Response struct {
 }
 
-func (response DeleteTasksId404Response) VisitDeleteTasksIdResponse(w http.ResponseWriter) error {
+func (response PostTasksIdRevisionsRevRestore404Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
 	w.WriteHeader(404)
 	return nil
 }
 
-type PatchTasksIdRequestObject struct {
-	Id   uint `json:"id"`
-	Body *PatchTasksIdJSONRequestBody
+type PostTasksIdRevisionsRevRestore409Response struct {
 }
 
-type PatchTasksIdResponseObject interface {
-	VisitPatchTasksIdResponse(w http.ResponseWriter) error
+func (response PostTasksIdRevisionsRevRestore409Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
 }
 
-type PatchTasksId200JSONResponse Task
+type PostTasksIdRevisionsRevRestore412Response = PreconditionFailedResponse
 
-func (response PatchTasksId200JSONResponse) VisitPatchTasksIdResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+func (response PostTasksIdRevisionsRevRestore412Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
 
-	return json.NewEncoder(w).Encode(response)
+type PostTasksIdRevisionsRevRestore428Response = PreconditionRequiredResponse
+
+func (response PostTasksIdRevisionsRevRestore428Response) VisitPostTasksIdRevisionsRevRestoreResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
 }
 
 // StrictServerInterface represents all server handlers.
@@ -357,12 +1589,36 @@ type StrictServerInterface interface {
 	// Create a new task
 	// (POST /tasks)
 	PostTasks(ctx context.Context, request PostTasksRequestObject) (PostTasksResponseObject, error)
+	// Full-text search over user's tasks
+	// (GET /tasks/search)
+	GetTasksSearch(ctx context.Context, request GetTasksSearchRequestObject) (GetTasksSearchResponseObject, error)
 	// Delete a task by ID
 	// (DELETE /tasks/{id})
 	DeleteTasksId(ctx context.Context, request DeleteTasksIdRequestObject) (DeleteTasksIdResponseObject, error)
+	// Get a task by ID
+	// (GET /tasks/{id})
+	GetTasksId(ctx context.Context, request GetTasksIdRequestObject) (GetTasksIdResponseObject, error)
 	// Update a task
 	// (PATCH /tasks/{id})
 	PatchTasksId(ctx context.Context, request PatchTasksIdRequestObject) (PatchTasksIdResponseObject, error)
+	// Unassign a user from the task
+	// (DELETE /tasks/{id}/assignees/{userId})
+	DeleteTasksIdAssigneesUserId(ctx context.Context, request DeleteTasksIdAssigneesUserIdRequestObject) (DeleteTasksIdAssigneesUserIdResponseObject, error)
+	// Assign a user to the task
+	// (PUT /tasks/{id}/assignees/{userId})
+	PutTasksIdAssigneesUserId(ctx context.Context, request PutTasksIdAssigneesUserIdRequestObject) (PutTasksIdAssigneesUserIdResponseObject, error)
+	// Change history of a task, newest first
+	// (GET /tasks/{id}/history)
+	GetTasksIdHistory(ctx context.Context, request GetTasksIdHistoryRequestObject) (GetTasksIdHistoryResponseObject, error)
+	// Saved revisions of a task, newest first
+	// (GET /tasks/{id}/revisions)
+	GetTasksIdRevisions(ctx context.Context, request GetTasksIdRevisionsRequestObject) (GetTasksIdRevisionsResponseObject, error)
+	// Word-level diff of the task text between two revisions
+	// (GET /tasks/{id}/revisions/diff)
+	GetTasksIdRevisionsDiff(ctx context.Context, request GetTasksIdRevisionsDiffRequestObject) (GetTasksIdRevisionsDiffResponseObject, error)
+	// Roll the task back to a revision
+	// (POST /tasks/{id}/revisions/{rev}/restore)
+	PostTasksIdRevisionsRevRestore(ctx context.Context, request PostTasksIdRevisionsRevRestoreRequestObject) (PostTasksIdRevisionsRevRestoreResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -395,9 +1651,11 @@ type strictHandler struct {
 }
 
 // GetTasks operation middleware
-func (sh *strictHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) GetTasks(w http.ResponseWriter, r *http.Request, params GetTasksParams) {
 	var request GetTasksRequestObject
 
+	request.Params = params
+
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.GetTasks(ctx, request.(GetTasksRequestObject))
 	}
@@ -419,9 +1677,11 @@ func (sh *strictHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
 }
 
 // PostTasks operation middleware
-func (sh *strictHandler) PostTasks(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) PostTasks(w http.ResponseWriter, r *http.Request, params PostTasksParams) {
 	var request PostTasksRequestObject
 
+	request.Params = params
+
 	var body PostTasksJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
@@ -449,11 +1709,38 @@ func (sh *strictHandler) PostTasks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetTasksSearch operation middleware
+func (sh *strictHandler) GetTasksSearch(w http.ResponseWriter, r *http.Request, params GetTasksSearchParams) {
+	var request GetTasksSearchRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksSearch(ctx, request.(GetTasksSearchRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksSearch")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksSearchResponseObject); ok {
+		if err := validResponse.VisitGetTasksSearchResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // DeleteTasksId operation middleware
-func (sh *strictHandler) DeleteTasksId(w http.ResponseWriter, r *http.Request, id uint) {
+func (sh *strictHandler) DeleteTasksId(w http.ResponseWriter, r *http.Request, id uint, params DeleteTasksIdParams) {
 	var request DeleteTasksIdRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.DeleteTasksId(ctx, request.(DeleteTasksIdRequestObject))
@@ -475,18 +1762,56 @@ func (sh *strictHandler) DeleteTasksId(w http.ResponseWriter, r *http.Request, i
 	}
 }
 
+// GetTasksId operation middleware
+func (sh *strictHandler) GetTasksId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetTasksIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksId(ctx, request.(GetTasksIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // PatchTasksId operation middleware
-func (sh *strictHandler) PatchTasksId(w http.ResponseWriter, r *http.Request, id uint) {
+func (sh *strictHandler) PatchTasksId(w http.ResponseWriter, r *http.Request, id uint, params PatchTasksIdParams) {
 	var request PatchTasksIdRequestObject
 
 	request.Id = id
+	request.Params = params
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
 
-	var body PatchTasksIdJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
+		var body PatchTasksIdJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/merge-patch+json") {
+
+		var body PatchTasksIdApplicationMergePatchPlusJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.ApplicationMergePatchPlusJSONBody = &body
 	}
-	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.PatchTasksId(ctx, request.(PatchTasksIdRequestObject))
@@ -507,3 +1832,165 @@ func (sh *strictHandler) PatchTasksId(w http.ResponseWriter, r *http.Request, id
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// DeleteTasksIdAssigneesUserId operation middleware
+func (sh *strictHandler) DeleteTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint) {
+	var request DeleteTasksIdAssigneesUserIdRequestObject
+
+	request.Id = id
+	request.UserId = userId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteTasksIdAssigneesUserId(ctx, request.(DeleteTasksIdAssigneesUserIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteTasksIdAssigneesUserId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteTasksIdAssigneesUserIdResponseObject); ok {
+		if err := validResponse.VisitDeleteTasksIdAssigneesUserIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PutTasksIdAssigneesUserId operation middleware
+func (sh *strictHandler) PutTasksIdAssigneesUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint) {
+	var request PutTasksIdAssigneesUserIdRequestObject
+
+	request.Id = id
+	request.UserId = userId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PutTasksIdAssigneesUserId(ctx, request.(PutTasksIdAssigneesUserIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PutTasksIdAssigneesUserId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PutTasksIdAssigneesUserIdResponseObject); ok {
+		if err := validResponse.VisitPutTasksIdAssigneesUserIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasksIdHistory operation middleware
+func (sh *strictHandler) GetTasksIdHistory(w http.ResponseWriter, r *http.Request, id uint, params GetTasksIdHistoryParams) {
+	var request GetTasksIdHistoryRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdHistory(ctx, request.(GetTasksIdHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdHistory")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdHistoryResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdHistoryResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasksIdRevisions operation middleware
+func (sh *strictHandler) GetTasksIdRevisions(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetTasksIdRevisionsRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdRevisions(ctx, request.(GetTasksIdRevisionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdRevisions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdRevisionsResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdRevisionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasksIdRevisionsDiff operation middleware
+func (sh *strictHandler) GetTasksIdRevisionsDiff(w http.ResponseWriter, r *http.Request, id uint, params GetTasksIdRevisionsDiffParams) {
+	var request GetTasksIdRevisionsDiffRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasksIdRevisionsDiff(ctx, request.(GetTasksIdRevisionsDiffRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasksIdRevisionsDiff")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksIdRevisionsDiffResponseObject); ok {
+		if err := validResponse.VisitGetTasksIdRevisionsDiffResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostTasksIdRevisionsRevRestore operation middleware
+func (sh *strictHandler) PostTasksIdRevisionsRevRestore(w http.ResponseWriter, r *http.Request, id uint, rev uint, params PostTasksIdRevisionsRevRestoreParams) {
+	var request PostTasksIdRevisionsRevRestoreRequestObject
+
+	request.Id = id
+	request.Rev = rev
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostTasksIdRevisionsRevRestore(ctx, request.(PostTasksIdRevisionsRevRestoreRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostTasksIdRevisionsRevRestore")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostTasksIdRevisionsRevRestoreResponseObject); ok {
+		if err := validResponse.VisitPostTasksIdRevisionsRevRestoreResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -0,0 +1,58 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+)
+
+// PutTasksIdAssigneesUserId - назначает пользователя исполнителем задачи
+func (h *TaskHandler) PutTasksIdAssigneesUserId(ctx context.Context, req PutTasksIdAssigneesUserIdRequestObject) (PutTasksIdAssigneesUserIdResponseObject, error) {
+	err := h.service.AssignTask(authn.Actor(ctx), req.Id, req.UserId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return PutTasksIdAssigneesUserId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PutTasksIdAssigneesUserId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "owner cannot be an assignee") ||
+			strings.Contains(err.Error(), "user_id cannot be 0") ||
+			strings.Contains(err.Error(), "user is not a workspace member") {
+			return PutTasksIdAssigneesUserId400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[PUT] User %d assigned to task %d", req.UserId, req.Id)
+
+	return PutTasksIdAssigneesUserId204Response{}, nil
+}
+
+// DeleteTasksIdAssigneesUserId - снимает исполнителя с задачи
+func (h *TaskHandler) DeleteTasksIdAssigneesUserId(ctx context.Context, req DeleteTasksIdAssigneesUserIdRequestObject) (DeleteTasksIdAssigneesUserIdResponseObject, error) {
+	err := h.service.UnassignTask(authn.Actor(ctx), req.Id, req.UserId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return DeleteTasksIdAssigneesUserId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteTasksIdAssigneesUserId403Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] User %d unassigned from task %d", req.UserId, req.Id)
+
+	return DeleteTasksIdAssigneesUserId204Response{}, nil
+}
+
+// assigneeIDs - id исполнителей для апи-модели (nil - поле не попадает в ответ)
+func assigneeIDs(ids []uint) *[]uint {
+	if len(ids) == 0 {
+		return nil
+	}
+	return &ids
+}
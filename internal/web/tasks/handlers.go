@@ -3,8 +3,12 @@ package tasks
 import (
 	"context"
 	"log"
+	"strings"
 
+	"github.com/AntonRadchenko/WebPet1/internal/audit"
 	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/web/etag"
 )
 
 // слой handlers:
@@ -26,16 +30,44 @@ func NewTaskHandler(s *taskService.TaskService) *TaskHandler {
 	return &TaskHandler{service: s}
 }
 
-func (h *TaskHandler) PostTasks(_ context.Context, req PostTasksRequestObject) (PostTasksResponseObject, error) {
+// toAPITask - маппит бизнес-модель в апи-модель
+func toAPITask(t *taskService.Task) Task {
+	return Task{
+		Id:           &t.ID,
+		Task:         &t.Task,
+		IsDone:       t.IsDone,
+		UserId:       &t.UserId,
+		Version:      &t.Version,
+		Position:     &t.Position,
+		CommentCount: &t.CommentCount,
+		AssigneeIds:  assigneeIDs(t.Assignees),
+		WorkspaceId:  t.WorkspaceID,
+	}
+}
+
+func (h *TaskHandler) PostTasks(ctx context.Context, req PostTasksRequestObject) (PostTasksResponseObject, error) {
 	params := taskService.CreateTaskParams{
 		Task: req.Body.Task,
 		IsDone: req.Body.IsDone,
 		UserId: req.Body.UserId,
+		WorkspaceID: req.Body.WorkspaceId,
 	}
 
 	// передаем данные с тела запроса в сервис (который уже передаст их в репозиторий)
-	newTask, err := h.service.CreateTask(params) // передаю таску и флаг из тела запроса
+	newTask, err := h.service.CreateTask(authn.Actor(ctx), params) // передаю таску и флаг из тела запроса
 	if err != nil {
+		// задача для другого пользователя (не админ) или email не подтвержден (если это включено в конфиге)
+		if strings.Contains(err.Error(), "forbidden") ||
+			strings.Contains(err.Error(), "email is not verified") {
+			return PostTasks403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "workspace not found") {
+			return PostTasks404Response{}, nil
+		}
+		// владелец задачи пространства должен быть его участником
+		if strings.Contains(err.Error(), "user is not a workspace member") {
+			return PostTasks400Response{}, nil
+		}
 		return nil, err
 	}
 
@@ -43,58 +75,173 @@ func (h *TaskHandler) PostTasks(_ context.Context, req PostTasksRequestObject) (
 
 	// маппим бизнес-модель в апи-модель
 	response := PostTasks201JSONResponse{
-		Id:     &newTask.ID,
-		Task:   &newTask.Task,
-		IsDone: newTask.IsDone,
-		UserId: &newTask.UserId,
+		Body:    toAPITask(newTask),
+		Headers: PostTasks201ResponseHeaders{ETag: etag.Format(newTask.Version)},
 	}
 	return response, nil // отправляем клиенту ответ
 }
 
-func (h *TaskHandler) GetTasks(_ context.Context, _ GetTasksRequestObject) (GetTasksResponseObject, error) {
+func (h *TaskHandler) GetTasks(ctx context.Context, req GetTasksRequestObject) (GetTasksResponseObject, error) {
 	// инициализируем слайс данным способом, чтобы при ошибке вернулся пустой массив, вместо null
 	response := make(GetTasks200JSONResponse, 0)
 
-	// получаем модель бд
-	tasks, err := h.service.GetTasks()
+	// получаем модель бд (с workspace_id - все задачи пространства)
+	var tasks []taskService.Task
+	var err error
+	if req.Params.WorkspaceId != nil {
+		tasks, err = h.service.GetWorkspaceTasks(authn.Actor(ctx), *req.Params.WorkspaceId)
+	} else {
+		tasks, err = h.service.GetTasks(authn.Actor(ctx))
+	}
 	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetTasks401Response{}, nil
+		}
+		if strings.Contains(err.Error(), "workspace not found") {
+			return GetTasks404Response{}, nil
+		}
 		return nil, err
 	}
 
 	for _, t := range tasks {
 		// маппинг в API-модель
-		response = append(response, Task{
-			Id:     &t.ID,
-			Task:   &t.Task,
-			IsDone: t.IsDone,
-			UserId: &t.UserId,
-		})
+		response = append(response, toAPITask(&t))
 
 	}
 	log.Printf("[GET] Returned %d tasks", len(tasks))
 	return response, nil
 }
 
-func (h *TaskHandler) PatchTasksId(_ context.Context, req PatchTasksIdRequestObject) (PatchTasksIdResponseObject, error) {
-	params := taskService.UpdateTaskParams{}
+func (h *TaskHandler) GetTasksSearch(ctx context.Context, req GetTasksSearchRequestObject) (GetTasksSearchResponseObject, error) {
+	params := taskService.SearchTasksParams{
+		Query:  req.Params.Q,
+		UserId: req.Params.UserId,
+		Limit:  req.Params.Limit,
+		Offset: req.Params.Offset,
+	}
+
+	page, err := h.service.SearchTasks(authn.Actor(ctx), params)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetTasksSearch403Response{}, nil
+		}
+		// ошибки валидации запроса - 400
+		if strings.Contains(err.Error(), "search query is empty") ||
+			strings.Contains(err.Error(), "user_id is required") ||
+			strings.Contains(err.Error(), "limit must be") ||
+			strings.Contains(err.Error(), "offset cannot be") {
+			return GetTasksSearch400Response{}, nil
+		}
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	items := make([]TaskSearchResult, 0, len(page.Items))
+	for _, item := range page.Items {
+		rank := float32(item.Rank)
+		task := toAPITask(&item.Task)
+		items = append(items, TaskSearchResult{
+			Task:      &task,
+			Rank:      &rank,
+			Highlight: &item.Highlight,
+		})
+	}
+
+	log.Printf("[GET] Search %q returned %d of %d tasks", req.Params.Q, len(items), page.Total)
+
+	response := GetTasksSearch200JSONResponse{
+		Items:  &items,
+		Total:  &page.Total,
+		Limit:  &page.Limit,
+		Offset: &page.Offset,
+	}
+	return response, nil
+}
+
+func (h *TaskHandler) GetTasksId(ctx context.Context, req GetTasksIdRequestObject) (GetTasksIdResponseObject, error) {
+	task, err := h.service.GetTask(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return GetTasksId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[GET] Returned task %d", req.Id)
 
-	if req.Body.Task != nil {
-		task := *req.Body.Task
-		params.Task = &task
+	response := GetTasksId200JSONResponse{
+		Body:    toAPITask(task),
+		Headers: GetTasksId200ResponseHeaders{ETag: etag.Format(task.Version)},
 	}
+	return response, nil
+}
 
-	if req.Body.IsDone != nil {
-		isDone := *req.Body.IsDone
-		params.IsDone = &isDone
+func (h *TaskHandler) PatchTasksId(ctx context.Context, req PatchTasksIdRequestObject) (PatchTasksIdResponseObject, error) {
+	// без If-Match не обновляем: иначе два клиента молча перезапишут изменения друг друга
+	if req.Params.IfMatch == nil {
+		return PatchTasksId428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*req.Params.IfMatch)
+	if err != nil {
+		return PatchTasksId412Response{}, nil
 	}
 
-	if req.Body.UserId != nil {
-		userId := *req.Body.UserId
-		params.UserId = &userId
+	params := taskService.UpdateTaskParams{}
+
+	switch {
+	case req.JSONBody != nil:
+		if req.JSONBody.Task != nil {
+			task := *req.JSONBody.Task
+			params.Task = &task
+		}
+
+		if req.JSONBody.IsDone != nil {
+			isDone := *req.JSONBody.IsDone
+			params.IsDone = &isDone
+		}
+
+		if req.JSONBody.UserId != nil {
+			userId := *req.JSONBody.UserId
+			params.UserId = &userId
+		}
+
+		if req.JSONBody.Position != nil {
+			position := *req.JSONBody.Position
+			params.Position = &position
+		}
+
+	case req.ApplicationMergePatchPlusJSONBody != nil:
+		// application/merge-patch+json (RFC 7396)
+		params, err = mergePatchToParams(*req.ApplicationMergePatchPlusJSONBody)
+		if err != nil {
+			return PatchTasksId400Response{}, nil
+		}
+
+	default:
+		// неподдерживаемый Content-Type - тело не распарсилось
+		return PatchTasksId400Response{}, nil
 	}
 
-	updatedTask, err := h.service.UpdateTask(req.Id, params)
+	updatedTask, err := h.service.UpdateTask(authn.Actor(ctx), req.Id, version, params)
 	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return PatchTasksId404Response{}, nil
+		}
+		// передать задачу другому пользователю может только админ
+		if strings.Contains(err.Error(), "forbidden") {
+			return PatchTasksId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return PatchTasksId412Response{}, nil
+		}
+		// ошибки валидации - 400
+		if strings.Contains(err.Error(), "task is empty") ||
+			strings.Contains(err.Error(), "user_id cannot be 0") ||
+			strings.Contains(err.Error(), "no fields to update") ||
+			strings.Contains(err.Error(), "position cannot be negative") ||
+			strings.Contains(err.Error(), "user is not a workspace member") {
+			return PatchTasksId400Response{}, nil
+		}
 		return nil, err
 	}
 
@@ -102,18 +249,34 @@ func (h *TaskHandler) PatchTasksId(_ context.Context, req PatchTasksIdRequestObj
 
 	// маппим бизнес-модель в апи-модель
 	response := PatchTasksId200JSONResponse{
-		Id:     &updatedTask.ID,
-		Task:   &updatedTask.Task,
-		IsDone: updatedTask.IsDone,
-		UserId: &updatedTask.UserId,
+		Body:    toAPITask(updatedTask),
+		Headers: PatchTasksId200ResponseHeaders{ETag: etag.Format(updatedTask.Version)},
 	}
 	return response, nil
 }
 
-func (h *TaskHandler) DeleteTasksId(_ context.Context, req DeleteTasksIdRequestObject) (DeleteTasksIdResponseObject, error) {
+func (h *TaskHandler) DeleteTasksId(ctx context.Context, req DeleteTasksIdRequestObject) (DeleteTasksIdResponseObject, error) {
 	urlID := req.Id
 
-	if err := h.service.DeleteTask(urlID); err != nil {
+	if req.Params.IfMatch == nil {
+		return DeleteTasksId428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*req.Params.IfMatch)
+	if err != nil {
+		return DeleteTasksId412Response{}, nil
+	}
+
+	if err := h.service.DeleteTask(authn.Actor(ctx), urlID, version); err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return DeleteTasksId404Response{}, nil
+		}
+		// исполнитель видит задачу, но удалить ее не может
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteTasksId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return DeleteTasksId412Response{}, nil
+		}
 		return nil, err
 	}
 
@@ -121,3 +284,49 @@ func (h *TaskHandler) DeleteTasksId(_ context.Context, req DeleteTasksIdRequestO
 
 	return DeleteTasksId204Response{}, nil
 }
+
+// GetTasksIdHistory - журнал изменений задачи (сначала новые)
+func (h *TaskHandler) GetTasksIdHistory(ctx context.Context, req GetTasksIdHistoryRequestObject) (GetTasksIdHistoryResponseObject, error) {
+	entries, err := h.service.GetTaskHistory(authn.Actor(ctx), req.Id, req.Params.Limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return GetTasksIdHistory404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "limit must be") {
+			return GetTasksIdHistory400Response{}, nil
+		}
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	response := make(GetTasksIdHistory200JSONResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, toAPIAuditEntry(e))
+	}
+	return response, nil
+}
+
+func toAPIAuditEntry(e audit.Entry) AuditEntry {
+	entry := AuditEntry{
+		Id:         e.ID,
+		EntityType: AuditEntryEntityType(e.EntityType),
+		EntityId:   e.EntityID,
+		Action:     AuditEntryAction(e.Action),
+		ActorId:    e.ActorID,
+		Changes:    make(map[string]AuditFieldChange, len(e.Changes)),
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.RequestID != "" {
+		entry.RequestId = &e.RequestID
+	}
+	if e.Before != nil {
+		entry.Before = &e.Before
+	}
+	if e.After != nil {
+		entry.After = &e.After
+	}
+	for field, c := range e.Changes {
+		entry.Changes[field] = AuditFieldChange{From: c.From, To: c.To}
+	}
+	return entry
+}
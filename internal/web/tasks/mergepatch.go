@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+)
+
+// JSON Merge Patch (RFC 7396) для задач:
+//   • ключа нет в документе - поле не меняется
+//   • ключ со значением - поле заменяется
+//   • ключ со значением null - поле "удаляется", то есть сбрасывается в значение по умолчанию
+//     (для обязательных полей task и user_id это ошибка, position сбрасывается в 0)
+
+// mergePatchToParams - переводит merge-patch документ в маску полей для сервиса
+func mergePatchToParams(patch MergePatch) (taskService.UpdateTaskParams, error) {
+	params := taskService.UpdateTaskParams{}
+
+	for key, value := range patch {
+		switch key {
+		case "task":
+			task, ok := value.(string)
+			if !ok {
+				return params, errors.New("task must be a string")
+			}
+			params.Task = &task
+
+		case "is_done":
+			isDone := false // null - сбрасываем в значение по умолчанию
+			if value != nil {
+				b, ok := value.(bool)
+				if !ok {
+					return params, errors.New("is_done must be a boolean")
+				}
+				isDone = b
+			}
+			params.IsDone = &isDone
+
+		case "user_id":
+			// json без схемы декодирует числа в float64
+			f, ok := value.(float64)
+			if !ok || f < 1 || f != math.Trunc(f) || f > math.MaxUint32 {
+				return params, errors.New("user_id must be a positive integer")
+			}
+			userId := uint(f)
+			params.UserId = &userId
+
+		case "position":
+			var position int64 // null - в начало доски
+			if value != nil {
+				f, ok := value.(float64)
+				if !ok || f < 0 || f != math.Trunc(f) || f >= math.MaxInt64 {
+					return params, errors.New("position must be a non-negative integer")
+				}
+				position = int64(f)
+			}
+			params.Position = &position
+
+		default:
+			return params, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,68 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatchToParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		patch      MergePatch
+		wantTask   *string
+		wantIsDone *bool
+		wantUserId *uint
+		wantPos    *int64
+		wantErr    bool
+	}{
+		{
+			name:     "меняем только текст",
+			patch:    MergePatch{"task": "new"},
+			wantTask: func() *string { s := "new"; return &s }(),
+		},
+		{
+			name:       "null сбрасывает is_done в false",
+			patch:      MergePatch{"is_done": nil},
+			wantIsDone: func() *bool { b := false; return &b }(),
+		},
+		{
+			name:       "меняем владельца и статус",
+			patch:      MergePatch{"user_id": float64(3), "is_done": true},
+			wantIsDone: func() *bool { b := true; return &b }(),
+			wantUserId: func() *uint { u := uint(3); return &u }(),
+		},
+		{
+			name:    "переносим на доске",
+			patch:   MergePatch{"position": float64(1024)},
+			wantPos: func() *int64 { p := int64(1024); return &p }(),
+		},
+		{
+			name:    "null ставит в начало доски",
+			patch:   MergePatch{"position": nil},
+			wantPos: func() *int64 { p := int64(0); return &p }(),
+		},
+		{name: "пустой документ", patch: MergePatch{}},
+		{name: "ошибка - null для обязательного task", patch: MergePatch{"task": nil}, wantErr: true},
+		{name: "ошибка - null для user_id", patch: MergePatch{"user_id": nil}, wantErr: true},
+		{name: "ошибка - дробный user_id", patch: MergePatch{"user_id": 1.5}, wantErr: true},
+		{name: "ошибка - неверный тип is_done", patch: MergePatch{"is_done": "yes"}, wantErr: true},
+		{name: "ошибка - отрицательная position", patch: MergePatch{"position": float64(-1)}, wantErr: true},
+		{name: "ошибка - неизвестное поле", patch: MergePatch{"version": float64(2)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := mergePatchToParams(tt.patch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTask, params.Task)
+			assert.Equal(t, tt.wantIsDone, params.IsDone)
+			assert.Equal(t, tt.wantUserId, params.UserId)
+			assert.Equal(t, tt.wantPos, params.Position)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/web/etag"
+)
+
+// GetTasksIdRevisions - ревизии задачи (сначала новые)
+func (h *TaskHandler) GetTasksIdRevisions(ctx context.Context, req GetTasksIdRevisionsRequestObject) (GetTasksIdRevisionsResponseObject, error) {
+	revisions, err := h.service.GetTaskRevisions(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "task not found") {
+			return GetTasksIdRevisions404Response{}, nil
+		}
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	response := make(GetTasksIdRevisions200JSONResponse, 0, len(revisions))
+	for _, r := range revisions {
+		response = append(response, toAPITaskRevision(r))
+	}
+	return response, nil
+}
+
+// GetTasksIdRevisionsDiff - пословная разница текста задачи между двумя ревизиями
+func (h *TaskHandler) GetTasksIdRevisionsDiff(ctx context.Context, req GetTasksIdRevisionsDiffRequestObject) (GetTasksIdRevisionsDiffResponseObject, error) {
+	diff, err := h.service.DiffTaskRevisions(authn.Actor(ctx), req.Id, req.Params.From, req.Params.To)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return GetTasksIdRevisionsDiff404Response{}, nil
+		}
+		return nil, err
+	}
+
+	changes := make([]TextChange, 0, len(diff.Changes))
+	for _, op := range diff.Changes {
+		changes = append(changes, TextChange{Op: TextChangeOp(op.Type), Text: op.Text})
+	}
+	return GetTasksIdRevisionsDiff200JSONResponse{From: diff.From, To: diff.To, Changes: changes}, nil
+}
+
+// PostTasksIdRevisionsRevRestore - откат задачи к ревизии (POST /tasks/{id}/revisions/{rev}:restore)
+func (h *TaskHandler) PostTasksIdRevisionsRevRestore(ctx context.Context, req PostTasksIdRevisionsRevRestoreRequestObject) (PostTasksIdRevisionsRevRestoreResponseObject, error) {
+	// откат перезаписывает задачу целиком - как и PATCH, только с If-Match
+	if req.Params.IfMatch == nil {
+		return PostTasksIdRevisionsRevRestore428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*req.Params.IfMatch)
+	if err != nil {
+		return PostTasksIdRevisionsRevRestore412Response{}, nil
+	}
+
+	task, err := h.service.RestoreTaskRevision(authn.Actor(ctx), req.Id, req.Rev, version)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return PostTasksIdRevisionsRevRestore404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostTasksIdRevisionsRevRestore403Response{}, nil
+		}
+		// прежний владелец задачи пространства уже не участник - вернуть задачу некому
+		if strings.Contains(err.Error(), "user is not a workspace member") {
+			return PostTasksIdRevisionsRevRestore409Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return PostTasksIdRevisionsRevRestore412Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Task %d restored to revision %d", req.Id, req.Rev)
+
+	return PostTasksIdRevisionsRevRestore200JSONResponse{
+		Body:    toAPITask(task),
+		Headers: PostTasksIdRevisionsRevRestore200ResponseHeaders{ETag: etag.Format(task.Version)},
+	}, nil
+}
+
+func toAPITaskRevision(r taskService.TaskRevision) TaskRevision {
+	return TaskRevision{
+		Revision:     r.Revision,
+		Task:         r.Task,
+		IsDone:       r.IsDone,
+		UserId:       r.UserId,
+		ActorId:      r.ActorID,
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
@@ -10,61 +10,268 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuthEventReason.
+const (
+	AuthEventReasonAccountDisabled  AuthEventReason = "account_disabled"
+	AuthEventReasonAccountLocked    AuthEventReason = "account_locked"
+	AuthEventReasonEmailNotVerified AuthEventReason = "email_not_verified"
+	AuthEventReasonInvalidCode      AuthEventReason = "invalid_code"
+	AuthEventReasonInvalidPassword  AuthEventReason = "invalid_password"
+	AuthEventReasonProviderRejected AuthEventReason = "provider_rejected"
+	AuthEventReasonTooManyAttempts  AuthEventReason = "too_many_attempts"
+	AuthEventReasonUnknownEmail     AuthEventReason = "unknown_email"
+)
+
+// Defines values for AuthEventType.
+const (
+	AuthEventTypeAccountLocked         AuthEventType = "account_locked"
+	AuthEventTypeAccountUnlocked       AuthEventType = "account_unlocked"
+	AuthEventTypeLoginFailed           AuthEventType = "login_failed"
+	AuthEventTypeLoginSucceeded        AuthEventType = "login_succeeded"
+	AuthEventTypeMfaFailed             AuthEventType = "mfa_failed"
+	AuthEventTypeMfaSucceeded          AuthEventType = "mfa_succeeded"
+	AuthEventTypeOidcFirstFactorPassed AuthEventType = "oidc_first_factor_passed"
+	AuthEventTypeOidcLoginFailed       AuthEventType = "oidc_login_failed"
+	AuthEventTypeOidcLoginSucceeded    AuthEventType = "oidc_login_succeeded"
+)
+
+// Defines values for SetRoleRequestRole.
+const (
+	SetRoleRequestRoleAdmin SetRoleRequestRole = "admin"
+	SetRoleRequestRoleUser  SetRoleRequestRole = "user"
+)
+
+// Defines values for UserRole.
+const (
+	UserRoleAdmin UserRole = "admin"
+	UserRoleUser  UserRole = "user"
+)
+
+// Defines values for GetUsersIdTasksParamsRole.
+const (
+	Assignee GetUsersIdTasksParamsRole = "assignee"
+	Owner    GetUsersIdTasksParamsRole = "owner"
+)
+
+// AuthEvent defines model for AuthEvent.
+type AuthEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Email Email the attempt was made with
+	Email string  `json:"email"`
+	Id    uint    `json:"id"`
+	Ip    *string `json:"ip,omitempty"`
+
+	// Reason Why the login failed
+	Reason    *AuthEventReason `json:"reason,omitempty"`
+	Type      AuthEventType    `json:"type"`
+	UserAgent *string          `json:"user_agent,omitempty"`
+}
+
+// AuthEventReason Why the login failed
+type AuthEventReason string
+
+// AuthEventType defines model for AuthEvent.Type.
+type AuthEventType string
+
+// ChangePasswordRequest defines model for ChangePasswordRequest.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
 // CreateUserRequest defines model for CreateUserRequest.
 type CreateUserRequest struct {
 	Email    openapi_types.Email `json:"email"`
 	Password string              `json:"password"`
 }
 
+// LoginStatus defines model for LoginStatus.
+type LoginStatus struct {
+	Events []AuthEvent `json:"events"`
+
+	// FailedLogins Failed logins in a row since the last successful one
+	FailedLogins int `json:"failed_logins"`
+
+	// LockedUntil Set while logging in is locked
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// MergePatch JSON Merge Patch document (RFC 7396) - null removes a field
+type MergePatch map[string]interface{}
+
+// PolicyViolation defines model for PolicyViolation.
+type PolicyViolation struct {
+	Message string `json:"message"`
+	Rule    string `json:"rule"`
+}
+
+// SetRoleRequest defines model for SetRoleRequest.
+type SetRoleRequest struct {
+	Role SetRoleRequestRole `json:"role"`
+}
+
+// SetRoleRequestRole defines model for SetRoleRequest.Role.
+type SetRoleRequestRole string
+
 // Task defines model for Task.
 type Task struct {
-	Id     *uint   `json:"id,omitempty"`
-	IsDone *bool   `json:"is_done,omitempty"`
-	Task   *string `json:"task,omitempty"`
-	UserId *uint   `json:"user_id,omitempty"`
+	// AssigneeIds Users assigned to the task (the owner is not listed)
+	AssigneeIds *[]uint `json:"assignee_ids,omitempty"`
+
+	// CommentCount Number of comments that are not deleted
+	CommentCount *int64 `json:"comment_count,omitempty"`
+	Id           *uint  `json:"id,omitempty"`
+	IsDone       *bool  `json:"is_done,omitempty"`
+
+	// Position Place of the task on a board - clients order tasks by position, then by id
+	Position *int64  `json:"position,omitempty"`
+	Task     *string `json:"task,omitempty"`
+	UserId   *uint   `json:"user_id,omitempty"`
+	Version  *uint   `json:"version,omitempty"`
+
+	// WorkspaceId Workspace of the task (absent for personal tasks)
+	WorkspaceId *uint `json:"workspace_id,omitempty"`
 }
 
 // UpdateUserRequest defines model for UpdateUserRequest.
 type UpdateUserRequest struct {
-	Email    *openapi_types.Email `json:"email"`
-	Password *string              `json:"password"`
+	Email *openapi_types.Email `json:"email"`
 }
 
 // User defines model for User.
 type User struct {
-	Email *openapi_types.Email `json:"email,omitempty"`
-	Id    *uint                `json:"id,omitempty"`
+	Disabled      *bool                `json:"disabled,omitempty"`
+	Email         *openapi_types.Email `json:"email,omitempty"`
+	EmailVerified *bool                `json:"email_verified,omitempty"`
+	Id            *uint                `json:"id,omitempty"`
+	Role          *UserRole            `json:"role,omitempty"`
+	Version       *uint                `json:"version,omitempty"`
+}
+
+// UserRole defines model for User.Role.
+type UserRole string
+
+// ValidationError defines model for ValidationError.
+type ValidationError struct {
+	Error      string             `json:"error"`
+	Field      *string            `json:"field,omitempty"`
+	Violations *[]PolicyViolation `json:"violations,omitempty"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// ValidationFailed defines model for ValidationFailed.
+type ValidationFailed = ValidationError
+
+// PostUsersParams defines parameters for PostUsers.
+type PostUsersParams struct {
+	// IdempotencyKey Unique key of the request; retries with the same key replay the stored response
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// DeleteUsersIdParams defines parameters for DeleteUsersId.
+type DeleteUsersIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchUsersIdParams defines parameters for PatchUsersId.
+type PatchUsersIdParams struct {
+	// IfMatch ETag of the version the client is changing (or * for any)
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetUsersIdAuthEventsParams defines parameters for GetUsersIdAuthEvents.
+type GetUsersIdAuthEventsParams struct {
+	// Limit How many recent events to return (default 50, at most 500)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostUsersIdPasswordParams defines parameters for PostUsersIdPassword.
+type PostUsersIdPasswordParams struct {
+	// IdempotencyKey Unique key of the request; retries with the same key replay the stored response
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetUsersIdTasksParams defines parameters for GetUsersIdTasks.
+type GetUsersIdTasksParams struct {
+	// Role owner - only tasks the user created, assignee - only tasks the user is assigned to
+	Role *GetUsersIdTasksParamsRole `form:"role,omitempty" json:"role,omitempty"`
 }
 
+// GetUsersIdTasksParamsRole defines parameters for GetUsersIdTasks.
+type GetUsersIdTasksParamsRole string
+
 // PostUsersJSONRequestBody defines body for PostUsers for application/json ContentType.
 type PostUsersJSONRequestBody = CreateUserRequest
 
 // PatchUsersIdJSONRequestBody defines body for PatchUsersId for application/json ContentType.
 type PatchUsersIdJSONRequestBody = UpdateUserRequest
 
+// PatchUsersIdApplicationMergePatchPlusJSONRequestBody defines body for PatchUsersId for application/merge-patch+json ContentType.
+type PatchUsersIdApplicationMergePatchPlusJSONRequestBody = MergePatch
+
+// PostUsersIdPasswordJSONRequestBody defines body for PostUsersIdPassword for application/json ContentType.
+type PostUsersIdPasswordJSONRequestBody = ChangePasswordRequest
+
+// PutUsersIdRoleJSONRequestBody defines body for PutUsersIdRole for application/json ContentType.
+type PutUsersIdRoleJSONRequestBody = SetRoleRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Get all users
+	// Get all users (admin only)
 	// (GET /users)
 	GetUsers(w http.ResponseWriter, r *http.Request)
 	// Create a new user
 	// (POST /users)
-	PostUsers(w http.ResponseWriter, r *http.Request)
-	// Delete a user by ID
+	PostUsers(w http.ResponseWriter, r *http.Request, params PostUsersParams)
+	// Delete a user by ID (admin only)
 	// (DELETE /users/{id})
-	DeleteUsersId(w http.ResponseWriter, r *http.Request, id uint)
+	DeleteUsersId(w http.ResponseWriter, r *http.Request, id uint, params DeleteUsersIdParams)
+	// Get a user by ID
+	// (GET /users/{id})
+	GetUsersId(w http.ResponseWriter, r *http.Request, id uint)
 	// Update a user
 	// (PATCH /users/{id})
-	PatchUsersId(w http.ResponseWriter, r *http.Request, id uint)
+	PatchUsersId(w http.ResponseWriter, r *http.Request, id uint, params PatchUsersIdParams)
+	// Login status and recent login attempts of a user (admin only)
+	// (GET /users/{id}/auth-events)
+	GetUsersIdAuthEvents(w http.ResponseWriter, r *http.Request, id uint, params GetUsersIdAuthEventsParams)
+	// Disable a user account (admin only)
+	// (POST /users/{id}/disable)
+	PostUsersIdDisable(w http.ResponseWriter, r *http.Request, id uint)
+	// Enable a disabled user account (admin only)
+	// (POST /users/{id}/enable)
+	PostUsersIdEnable(w http.ResponseWriter, r *http.Request, id uint)
+	// Change the user's password (requires the current password)
+	// (POST /users/{id}/password)
+	PostUsersIdPassword(w http.ResponseWriter, r *http.Request, id uint, params PostUsersIdPasswordParams)
+	// Promote or demote a user (admin only)
+	// (PUT /users/{id}/role)
+	PutUsersIdRole(w http.ResponseWriter, r *http.Request, id uint)
 	// Get all tasks for a specific user
 	// (GET /users/{id}/tasks)
-	GetUsersIdTasks(w http.ResponseWriter, r *http.Request, id uint)
+	GetUsersIdTasks(w http.ResponseWriter, r *http.Request, id uint, params GetUsersIdTasksParams)
+	// Lift the temporary login lock of a user (admin only)
+	// (POST /users/{id}/unlock)
+	PostUsersIdUnlock(w http.ResponseWriter, r *http.Request, id uint)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -79,6 +286,12 @@ type MiddlewareFunc func(http.Handler) http.Handler
 // GetUsers operation middleware
 func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Request) {
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetUsers(w, r)
 	}))
@@ -93,8 +306,34 @@ func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Reque
 // PostUsers operation middleware
 func (siw *ServerInterfaceWrapper) PostUsers(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostUsersParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostUsers(w, r)
+		siw.Handler.PostUsers(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -118,8 +357,38 @@ func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUsersIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteUsersId(w, r, id)
+		siw.Handler.DeleteUsersId(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -129,8 +398,8 @@ func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// PatchUsersId operation middleware
-func (siw *ServerInterfaceWrapper) PatchUsersId(w http.ResponseWriter, r *http.Request) {
+// GetUsersId operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -143,8 +412,14 @@ func (siw *ServerInterfaceWrapper) PatchUsersId(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PatchUsersId(w, r, id)
+		siw.Handler.GetUsersId(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -154,8 +429,8 @@ func (siw *ServerInterfaceWrapper) PatchUsersId(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r)
 }
 
-// GetUsersIdTasks operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersIdTasks(w http.ResponseWriter, r *http.Request) {
+// PatchUsersId operation middleware
+func (siw *ServerInterfaceWrapper) PatchUsersId(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -168,8 +443,38 @@ func (siw *ServerInterfaceWrapper) GetUsersIdTasks(w http.ResponseWriter, r *htt
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchUsersIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersIdTasks(w, r, id)
+		siw.Handler.PatchUsersId(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -179,212 +484,963 @@ func (siw *ServerInterfaceWrapper) GetUsersIdTasks(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// GetUsersIdAuthEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersIdAuthEvents(w http.ResponseWriter, r *http.Request) {
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	var err error
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "id" -------------
+	var id uint
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	ctx := r.Context()
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type RequiredParamError struct {
-	ParamName string
-}
+	r = r.WithContext(ctx)
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersIdAuthEventsParams
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	// ------------- Optional query parameter "limit" -------------
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersIdAuthEvents(w, r, id, params)
+	}))
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+	handler.ServeHTTP(w, r)
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
-}
+// PostUsersIdDisable operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersIdDisable(w http.ResponseWriter, r *http.Request) {
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+	var err error
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
-}
+	// ------------- Path parameter "id" -------------
+	var id uint
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
-}
+	ctx := r.Context()
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
-}
+	r = r.WithContext(ctx)
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersIdDisable(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+// PostUsersIdEnable operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersIdEnable(w http.ResponseWriter, r *http.Request) {
 
-	if m == nil {
-		m = http.NewServeMux()
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersIdEnable(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	handler.ServeHTTP(w, r)
+}
+
+// PostUsersIdPassword operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersIdPassword(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
 
-	m.HandleFunc("GET "+options.BaseURL+"/users", wrapper.GetUsers)
-	m.HandleFunc("POST "+options.BaseURL+"/users", wrapper.PostUsers)
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostUsersIdPasswordParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersIdPassword(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PutUsersIdRole operation middleware
+func (siw *ServerInterfaceWrapper) PutUsersIdRole(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutUsersIdRole(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsersIdTasks operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersIdTasks(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersIdTasksParams
+
+	// ------------- Optional query parameter "role" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "role", r.URL.Query(), &params.Role)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "role", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersIdTasks(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostUsersIdUnlock operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersIdUnlock(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersIdUnlock(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/users", wrapper.GetUsers)
+	m.HandleFunc("POST "+options.BaseURL+"/users", wrapper.PostUsers)
 	m.HandleFunc("DELETE "+options.BaseURL+"/users/{id}", wrapper.DeleteUsersId)
+	m.HandleFunc("GET "+options.BaseURL+"/users/{id}", wrapper.GetUsersId)
 	m.HandleFunc("PATCH "+options.BaseURL+"/users/{id}", wrapper.PatchUsersId)
+	m.HandleFunc("GET "+options.BaseURL+"/users/{id}/auth-events", wrapper.GetUsersIdAuthEvents)
+	m.HandleFunc("POST "+options.BaseURL+"/users/{id}/disable", wrapper.PostUsersIdDisable)
+	m.HandleFunc("POST "+options.BaseURL+"/users/{id}/enable", wrapper.PostUsersIdEnable)
+	m.HandleFunc("POST "+options.BaseURL+"/users/{id}/password", wrapper.PostUsersIdPassword)
+	m.HandleFunc("PUT "+options.BaseURL+"/users/{id}/role", wrapper.PutUsersIdRole)
 	m.HandleFunc("GET "+options.BaseURL+"/users/{id}/tasks", wrapper.GetUsersIdTasks)
+	m.HandleFunc("POST "+options.BaseURL+"/users/{id}/unlock", wrapper.PostUsersIdUnlock)
+
+	return m
+}
+
+type ForbiddenResponse struct {
+}
+
+type IdempotencyConflictResponse struct {
+}
+
+type IdempotencyKeyReusedResponse struct {
+}
+
+type PreconditionFailedResponse struct {
+}
+
+type PreconditionRequiredResponse struct {
+}
+
+type UnauthorizedResponse struct {
+}
+
+type ValidationFailedJSONResponse ValidationError
+
+type GetUsersRequestObject struct {
+}
+
+type GetUsersResponseObject interface {
+	VisitGetUsersResponse(w http.ResponseWriter) error
+}
+
+type GetUsers200JSONResponse []User
+
+func (response GetUsers200JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsers401Response = UnauthorizedResponse
+
+func (response GetUsers401Response) VisitGetUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetUsers403Response = ForbiddenResponse
+
+func (response GetUsers403Response) VisitGetUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostUsersRequestObject struct {
+	Params PostUsersParams
+	Body   *PostUsersJSONRequestBody
+}
+
+type PostUsersResponseObject interface {
+	VisitPostUsersResponse(w http.ResponseWriter) error
+}
+
+type PostUsers201ResponseHeaders struct {
+	ETag string
+}
+
+type PostUsers201JSONResponse struct {
+	Body    User
+	Headers PostUsers201ResponseHeaders
+}
+
+func (response PostUsers201JSONResponse) VisitPostUsersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostUsers400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostUsers400JSONResponse) VisitPostUsersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUsers409Response struct {
+}
+
+func (response PostUsers409Response) VisitPostUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostUsers422Response = IdempotencyKeyReusedResponse
+
+func (response PostUsers422Response) VisitPostUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(422)
+	return nil
+}
+
+type DeleteUsersIdRequestObject struct {
+	Id     uint `json:"id"`
+	Params DeleteUsersIdParams
+}
+
+type DeleteUsersIdResponseObject interface {
+	VisitDeleteUsersIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteUsersId204Response struct {
+}
+
+func (response DeleteUsersId204Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUsersId401Response = UnauthorizedResponse
+
+func (response DeleteUsersId401Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteUsersId403Response = ForbiddenResponse
+
+func (response DeleteUsersId403Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteUsersId404Response struct {
+}
+
+func (response DeleteUsersId404Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteUsersId412Response = PreconditionFailedResponse
+
+func (response DeleteUsersId412Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type DeleteUsersId428Response = PreconditionRequiredResponse
+
+func (response DeleteUsersId428Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type GetUsersIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetUsersIdResponseObject interface {
+	VisitGetUsersIdResponse(w http.ResponseWriter) error
+}
+
+type GetUsersId200ResponseHeaders struct {
+	ETag string
+}
+
+type GetUsersId200JSONResponse struct {
+	Body    User
+	Headers GetUsersId200ResponseHeaders
+}
+
+func (response GetUsersId200JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetUsersId401Response = UnauthorizedResponse
+
+func (response GetUsersId401Response) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetUsersId404Response struct {
+}
+
+func (response GetUsersId404Response) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchUsersIdRequestObject struct {
+	Id                                uint `json:"id"`
+	Params                            PatchUsersIdParams
+	JSONBody                          *PatchUsersIdJSONRequestBody
+	ApplicationMergePatchPlusJSONBody *PatchUsersIdApplicationMergePatchPlusJSONRequestBody
+}
+
+type PatchUsersIdResponseObject interface {
+	VisitPatchUsersIdResponse(w http.ResponseWriter) error
+}
+
+type PatchUsersId200ResponseHeaders struct {
+	ETag string
+}
+
+type PatchUsersId200JSONResponse struct {
+	Body    User
+	Headers PatchUsersId200ResponseHeaders
+}
+
+func (response PatchUsersId200JSONResponse) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PatchUsersId400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PatchUsersId400JSONResponse) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PatchUsersId401Response = UnauthorizedResponse
+
+func (response PatchUsersId401Response) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PatchUsersId404Response struct {
+}
+
+func (response PatchUsersId404Response) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchUsersId409Response struct {
+}
+
+func (response PatchUsersId409Response) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PatchUsersId412Response = PreconditionFailedResponse
+
+func (response PatchUsersId412Response) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(412)
+	return nil
+}
+
+type PatchUsersId428Response = PreconditionRequiredResponse
+
+func (response PatchUsersId428Response) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(428)
+	return nil
+}
+
+type GetUsersIdAuthEventsRequestObject struct {
+	Id     uint `json:"id"`
+	Params GetUsersIdAuthEventsParams
+}
+
+type GetUsersIdAuthEventsResponseObject interface {
+	VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error
+}
+
+type GetUsersIdAuthEvents200JSONResponse LoginStatus
+
+func (response GetUsersIdAuthEvents200JSONResponse) VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsersIdAuthEvents400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response GetUsersIdAuthEvents400JSONResponse) VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsersIdAuthEvents401Response = UnauthorizedResponse
+
+func (response GetUsersIdAuthEvents401Response) VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetUsersIdAuthEvents403Response = ForbiddenResponse
+
+func (response GetUsersIdAuthEvents403Response) VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type GetUsersIdAuthEvents404Response struct {
+}
+
+func (response GetUsersIdAuthEvents404Response) VisitGetUsersIdAuthEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostUsersIdDisableRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type PostUsersIdDisableResponseObject interface {
+	VisitPostUsersIdDisableResponse(w http.ResponseWriter) error
+}
+
+type PostUsersIdDisable200ResponseHeaders struct {
+	ETag string
+}
+
+type PostUsersIdDisable200JSONResponse struct {
+	Body    User
+	Headers PostUsersIdDisable200ResponseHeaders
+}
+
+func (response PostUsersIdDisable200JSONResponse) VisitPostUsersIdDisableResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostUsersIdDisable400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostUsersIdDisable400JSONResponse) VisitPostUsersIdDisableResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUsersIdDisable401Response = UnauthorizedResponse
+
+func (response PostUsersIdDisable401Response) VisitPostUsersIdDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostUsersIdDisable403Response = ForbiddenResponse
+
+func (response PostUsersIdDisable403Response) VisitPostUsersIdDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostUsersIdDisable404Response struct {
+}
+
+func (response PostUsersIdDisable404Response) VisitPostUsersIdDisableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostUsersIdEnableRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type PostUsersIdEnableResponseObject interface {
+	VisitPostUsersIdEnableResponse(w http.ResponseWriter) error
+}
+
+type PostUsersIdEnable200ResponseHeaders struct {
+	ETag string
+}
+
+type PostUsersIdEnable200JSONResponse struct {
+	Body    User
+	Headers PostUsersIdEnable200ResponseHeaders
+}
+
+func (response PostUsersIdEnable200JSONResponse) VisitPostUsersIdEnableResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostUsersIdEnable400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PostUsersIdEnable400JSONResponse) VisitPostUsersIdEnableResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUsersIdEnable401Response = UnauthorizedResponse
+
+func (response PostUsersIdEnable401Response) VisitPostUsersIdEnableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
 
-	return m
+type PostUsersIdEnable403Response = ForbiddenResponse
+
+func (response PostUsersIdEnable403Response) VisitPostUsersIdEnableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
 }
 
-type GetUsersRequestObject struct {
+type PostUsersIdEnable404Response struct {
 }
 
-type GetUsersResponseObject interface {
-	VisitGetUsersResponse(w http.ResponseWriter) error
+func (response PostUsersIdEnable404Response) VisitPostUsersIdEnableResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
 }
 
-type GetUsers200JSONResponse []User
+type PostUsersIdPasswordRequestObject struct {
+	Id     uint `json:"id"`
+	Params PostUsersIdPasswordParams
+	Body   *PostUsersIdPasswordJSONRequestBody
+}
 
-func (response GetUsers200JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+type PostUsersIdPasswordResponseObject interface {
+	VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error
+}
 
-	return json.NewEncoder(w).Encode(response)
+type PostUsersIdPassword204ResponseHeaders struct {
+	ETag string
 }
 
-type PostUsersRequestObject struct {
-	Body *PostUsersJSONRequestBody
+type PostUsersIdPassword204Response struct {
+	Headers PostUsersIdPassword204ResponseHeaders
 }
 
-type PostUsersResponseObject interface {
-	VisitPostUsersResponse(w http.ResponseWriter) error
+func (response PostUsersIdPassword204Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(204)
+	return nil
 }
 
-type PostUsers201JSONResponse User
+type PostUsersIdPassword400JSONResponse struct{ ValidationFailedJSONResponse }
 
-func (response PostUsers201JSONResponse) VisitPostUsersResponse(w http.ResponseWriter) error {
+func (response PostUsersIdPassword400JSONResponse) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteUsersIdRequestObject struct {
-	Id uint `json:"id"`
-}
+type PostUsersIdPassword401Response = UnauthorizedResponse
 
-type DeleteUsersIdResponseObject interface {
-	VisitDeleteUsersIdResponse(w http.ResponseWriter) error
+func (response PostUsersIdPassword401Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
 }
 
-type DeleteUsersId204Response struct {
+type PostUsersIdPassword403Response struct {
 }
 
-func (response DeleteUsersId204Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
+func (response PostUsersIdPassword403Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
 	return nil
 }
 
-type DeleteUsersId404Response struct {
+type PostUsersIdPassword404Response struct {
 }
 
-func (response DeleteUsersId404Response) VisitDeleteUsersIdResponse(w http.ResponseWriter) error {
+func (response PostUsersIdPassword404Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
 	w.WriteHeader(404)
 	return nil
 }
 
-type PatchUsersIdRequestObject struct {
+type PostUsersIdPassword409Response = IdempotencyConflictResponse
+
+func (response PostUsersIdPassword409Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostUsersIdPassword422Response = IdempotencyKeyReusedResponse
+
+func (response PostUsersIdPassword422Response) VisitPostUsersIdPasswordResponse(w http.ResponseWriter) error {
+	w.WriteHeader(422)
+	return nil
+}
+
+type PutUsersIdRoleRequestObject struct {
 	Id   uint `json:"id"`
-	Body *PatchUsersIdJSONRequestBody
+	Body *PutUsersIdRoleJSONRequestBody
 }
 
-type PatchUsersIdResponseObject interface {
-	VisitPatchUsersIdResponse(w http.ResponseWriter) error
+type PutUsersIdRoleResponseObject interface {
+	VisitPutUsersIdRoleResponse(w http.ResponseWriter) error
 }
 
-type PatchUsersId200JSONResponse User
+type PutUsersIdRole200ResponseHeaders struct {
+	ETag string
+}
 
-func (response PatchUsersId200JSONResponse) VisitPatchUsersIdResponse(w http.ResponseWriter) error {
+type PutUsersIdRole200JSONResponse struct {
+	Body    User
+	Headers PutUsersIdRole200ResponseHeaders
+}
+
+func (response PutUsersIdRole200JSONResponse) VisitPutUsersIdRoleResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.WriteHeader(200)
 
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PutUsersIdRole400JSONResponse struct{ ValidationFailedJSONResponse }
+
+func (response PutUsersIdRole400JSONResponse) VisitPutUsersIdRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
 	return json.NewEncoder(w).Encode(response)
 }
 
+type PutUsersIdRole401Response = UnauthorizedResponse
+
+func (response PutUsersIdRole401Response) VisitPutUsersIdRoleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PutUsersIdRole403Response = ForbiddenResponse
+
+func (response PutUsersIdRole403Response) VisitPutUsersIdRoleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PutUsersIdRole404Response struct {
+}
+
+func (response PutUsersIdRole404Response) VisitPutUsersIdRoleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
 type GetUsersIdTasksRequestObject struct {
-	Id uint `json:"id"`
+	Id     uint `json:"id"`
+	Params GetUsersIdTasksParams
 }
 
 type GetUsersIdTasksResponseObject interface {
@@ -400,6 +1456,21 @@ func (response GetUsersIdTasks200JSONResponse) VisitGetUsersIdTasksResponse(w ht
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetUsersIdTasks400Response struct {
+}
+
+func (response GetUsersIdTasks400Response) VisitGetUsersIdTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type GetUsersIdTasks401Response = UnauthorizedResponse
+
+func (response GetUsersIdTasks401Response) VisitGetUsersIdTasksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
 type GetUsersIdTasks404Response struct {
 }
 
@@ -408,23 +1479,82 @@ func (response GetUsersIdTasks404Response) VisitGetUsersIdTasksResponse(w http.R
 	return nil
 }
 
+type PostUsersIdUnlockRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type PostUsersIdUnlockResponseObject interface {
+	VisitPostUsersIdUnlockResponse(w http.ResponseWriter) error
+}
+
+type PostUsersIdUnlock204Response struct {
+}
+
+func (response PostUsersIdUnlock204Response) VisitPostUsersIdUnlockResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type PostUsersIdUnlock401Response = UnauthorizedResponse
+
+func (response PostUsersIdUnlock401Response) VisitPostUsersIdUnlockResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostUsersIdUnlock403Response = ForbiddenResponse
+
+func (response PostUsersIdUnlock403Response) VisitPostUsersIdUnlockResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostUsersIdUnlock404Response struct {
+}
+
+func (response PostUsersIdUnlock404Response) VisitPostUsersIdUnlockResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
-	// Get all users
+	// Get all users (admin only)
 	// (GET /users)
 	GetUsers(ctx context.Context, request GetUsersRequestObject) (GetUsersResponseObject, error)
 	// Create a new user
 	// (POST /users)
 	PostUsers(ctx context.Context, request PostUsersRequestObject) (PostUsersResponseObject, error)
-	// Delete a user by ID
+	// Delete a user by ID (admin only)
 	// (DELETE /users/{id})
 	DeleteUsersId(ctx context.Context, request DeleteUsersIdRequestObject) (DeleteUsersIdResponseObject, error)
+	// Get a user by ID
+	// (GET /users/{id})
+	GetUsersId(ctx context.Context, request GetUsersIdRequestObject) (GetUsersIdResponseObject, error)
 	// Update a user
 	// (PATCH /users/{id})
 	PatchUsersId(ctx context.Context, request PatchUsersIdRequestObject) (PatchUsersIdResponseObject, error)
+	// Login status and recent login attempts of a user (admin only)
+	// (GET /users/{id}/auth-events)
+	GetUsersIdAuthEvents(ctx context.Context, request GetUsersIdAuthEventsRequestObject) (GetUsersIdAuthEventsResponseObject, error)
+	// Disable a user account (admin only)
+	// (POST /users/{id}/disable)
+	PostUsersIdDisable(ctx context.Context, request PostUsersIdDisableRequestObject) (PostUsersIdDisableResponseObject, error)
+	// Enable a disabled user account (admin only)
+	// (POST /users/{id}/enable)
+	PostUsersIdEnable(ctx context.Context, request PostUsersIdEnableRequestObject) (PostUsersIdEnableResponseObject, error)
+	// Change the user's password (requires the current password)
+	// (POST /users/{id}/password)
+	PostUsersIdPassword(ctx context.Context, request PostUsersIdPasswordRequestObject) (PostUsersIdPasswordResponseObject, error)
+	// Promote or demote a user (admin only)
+	// (PUT /users/{id}/role)
+	PutUsersIdRole(ctx context.Context, request PutUsersIdRoleRequestObject) (PutUsersIdRoleResponseObject, error)
 	// Get all tasks for a specific user
 	// (GET /users/{id}/tasks)
 	GetUsersIdTasks(ctx context.Context, request GetUsersIdTasksRequestObject) (GetUsersIdTasksResponseObject, error)
+	// Lift the temporary login lock of a user (admin only)
+	// (POST /users/{id}/unlock)
+	PostUsersIdUnlock(ctx context.Context, request PostUsersIdUnlockRequestObject) (PostUsersIdUnlockResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -481,9 +1611,11 @@ func (sh *strictHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 // PostUsers operation middleware
-func (sh *strictHandler) PostUsers(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) PostUsers(w http.ResponseWriter, r *http.Request, params PostUsersParams) {
 	var request PostUsersRequestObject
 
+	request.Params = params
+
 	var body PostUsersJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
@@ -512,10 +1644,11 @@ func (sh *strictHandler) PostUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteUsersId operation middleware
-func (sh *strictHandler) DeleteUsersId(w http.ResponseWriter, r *http.Request, id uint) {
+func (sh *strictHandler) DeleteUsersId(w http.ResponseWriter, r *http.Request, id uint, params DeleteUsersIdParams) {
 	var request DeleteUsersIdRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.DeleteUsersId(ctx, request.(DeleteUsersIdRequestObject))
@@ -537,18 +1670,56 @@ func (sh *strictHandler) DeleteUsersId(w http.ResponseWriter, r *http.Request, i
 	}
 }
 
+// GetUsersId operation middleware
+func (sh *strictHandler) GetUsersId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetUsersIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUsersId(ctx, request.(GetUsersIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUsersId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetUsersIdResponseObject); ok {
+		if err := validResponse.VisitGetUsersIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // PatchUsersId operation middleware
-func (sh *strictHandler) PatchUsersId(w http.ResponseWriter, r *http.Request, id uint) {
+func (sh *strictHandler) PatchUsersId(w http.ResponseWriter, r *http.Request, id uint, params PatchUsersIdParams) {
 	var request PatchUsersIdRequestObject
 
 	request.Id = id
+	request.Params = params
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
 
-	var body PatchUsersIdJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
+		var body PatchUsersIdJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/merge-patch+json") {
+
+		var body PatchUsersIdApplicationMergePatchPlusJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.ApplicationMergePatchPlusJSONBody = &body
 	}
-	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.PatchUsersId(ctx, request.(PatchUsersIdRequestObject))
@@ -570,11 +1741,158 @@ func (sh *strictHandler) PatchUsersId(w http.ResponseWriter, r *http.Request, id
 	}
 }
 
+// GetUsersIdAuthEvents operation middleware
+func (sh *strictHandler) GetUsersIdAuthEvents(w http.ResponseWriter, r *http.Request, id uint, params GetUsersIdAuthEventsParams) {
+	var request GetUsersIdAuthEventsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUsersIdAuthEvents(ctx, request.(GetUsersIdAuthEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUsersIdAuthEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetUsersIdAuthEventsResponseObject); ok {
+		if err := validResponse.VisitGetUsersIdAuthEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUsersIdDisable operation middleware
+func (sh *strictHandler) PostUsersIdDisable(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PostUsersIdDisableRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUsersIdDisable(ctx, request.(PostUsersIdDisableRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUsersIdDisable")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUsersIdDisableResponseObject); ok {
+		if err := validResponse.VisitPostUsersIdDisableResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUsersIdEnable operation middleware
+func (sh *strictHandler) PostUsersIdEnable(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PostUsersIdEnableRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUsersIdEnable(ctx, request.(PostUsersIdEnableRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUsersIdEnable")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUsersIdEnableResponseObject); ok {
+		if err := validResponse.VisitPostUsersIdEnableResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUsersIdPassword operation middleware
+func (sh *strictHandler) PostUsersIdPassword(w http.ResponseWriter, r *http.Request, id uint, params PostUsersIdPasswordParams) {
+	var request PostUsersIdPasswordRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body PostUsersIdPasswordJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUsersIdPassword(ctx, request.(PostUsersIdPasswordRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUsersIdPassword")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUsersIdPasswordResponseObject); ok {
+		if err := validResponse.VisitPostUsersIdPasswordResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PutUsersIdRole operation middleware
+func (sh *strictHandler) PutUsersIdRole(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PutUsersIdRoleRequestObject
+
+	request.Id = id
+
+	var body PutUsersIdRoleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PutUsersIdRole(ctx, request.(PutUsersIdRoleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PutUsersIdRole")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PutUsersIdRoleResponseObject); ok {
+		if err := validResponse.VisitPutUsersIdRoleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // GetUsersIdTasks operation middleware
-func (sh *strictHandler) GetUsersIdTasks(w http.ResponseWriter, r *http.Request, id uint) {
+func (sh *strictHandler) GetUsersIdTasks(w http.ResponseWriter, r *http.Request, id uint, params GetUsersIdTasksParams) {
 	var request GetUsersIdTasksRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.GetUsersIdTasks(ctx, request.(GetUsersIdTasksRequestObject))
@@ -595,3 +1913,29 @@ func (sh *strictHandler) GetUsersIdTasks(w http.ResponseWriter, r *http.Request,
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// PostUsersIdUnlock operation middleware
+func (sh *strictHandler) PostUsersIdUnlock(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PostUsersIdUnlockRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUsersIdUnlock(ctx, request.(PostUsersIdUnlockRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUsersIdUnlock")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUsersIdUnlockResponseObject); ok {
+		if err := validResponse.VisitPostUsersIdUnlockResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
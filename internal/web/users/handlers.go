@@ -2,10 +2,13 @@ package users
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
 
 	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/web/etag"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
@@ -17,57 +20,122 @@ func NewUserHandler(s *userService.UserService) *UserHandler {
 	return &UserHandler{service: s}
 }
 
-func (h *UserHandler) PostUsers(_ context.Context, request PostUsersRequestObject) (PostUsersResponseObject, error) {
+// toAPIUser - маппит бизнес-модель в апи-модель
+func toAPIUser(u *userService.User) User {
+	// Конвертируем string в openapi_types.Email для API ответа
+	email := openapi_types.Email(u.Email)
+	verified := u.EmailVerifiedAt != nil
+	role := UserRole(u.Role)
+	disabled := u.DisabledAt != nil
+	return User{
+		Id:            &u.ID,
+		Email:         &email,
+		Version:       &u.Version,
+		EmailVerified: &verified,
+		Role:          &role,
+		Disabled:      &disabled,
+	}
+}
+
+// toValidationError - тело ответа 400; для нарушений политики паролей перечисляем все правила
+func toValidationError(err error) ValidationFailedJSONResponse {
+	var verr *userService.ValidationError
+	if !errors.As(err, &verr) {
+		return ValidationFailedJSONResponse{Error: err.Error()}
+	}
+
+	violations := make([]PolicyViolation, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		violations = append(violations, PolicyViolation{Rule: v.Rule, Message: v.Message})
+	}
+	return ValidationFailedJSONResponse{
+		Error:      verr.Error(),
+		Field:      &verr.Field,
+		Violations: &violations,
+	}
+}
+
+func (h *UserHandler) PostUsers(ctx context.Context, request PostUsersRequestObject) (PostUsersResponseObject, error) {
 	params := userService.CreateUserParams{
 		Email: string(request.Body.Email),
 		Password: request.Body.Password,
 	}
 
-	newUser, err := h.service.CreateUser(params)
+	newUser, err := h.service.CreateUser(authn.Actor(ctx), params)
 	if err != nil {
+		if strings.Contains(err.Error(), "email already exists") {
+			return PostUsers409Response{}, nil
+		}
+		// ошибки валидации - 400
+		if strings.Contains(err.Error(), "invalid email") ||
+			strings.Contains(err.Error(), "invalid password") ||
+			strings.Contains(err.Error(), "is empty") {
+			return PostUsers400JSONResponse{toValidationError(err)}, nil
+		}
 		return nil, err
 	}
 
 	log.Printf("[POST] User %d created successfully", newUser.ID)
 
-	// Конвертируем string в openapi_types.Email для API ответа
-	email := openapi_types.Email(newUser.Email)
-
 	// маппим бизнес-модель в апи-модель
 	response := PostUsers201JSONResponse{
-		Id: &newUser.ID,
-		Email: &email,
+		Body:    toAPIUser(newUser),
+		Headers: PostUsers201ResponseHeaders{ETag: etag.Format(newUser.Version)},
 	}
 	return response, nil
 }
 
-func (h *UserHandler) GetUsers(_ context.Context, _ GetUsersRequestObject) (GetUsersResponseObject, error) {
+func (h *UserHandler) GetUsers(ctx context.Context, _ GetUsersRequestObject) (GetUsersResponseObject, error) {
 	response := make(GetUsers200JSONResponse, 0)
 
-	users, err := h.service.GetUsers()
+	users, err := h.service.GetUsers(authn.Actor(ctx))
 	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetUsers403Response{}, nil
+		}
 		return nil, err
 	}	
 
 	for _, u := range users {
-		email := openapi_types.Email(u.Email)
-
 		// маппим бизнес-модель в апи-модель
-		response = append(response, User{
-			Id: &u.ID,
-			Email: &email,
-		})
+		response = append(response, toAPIUser(&u))
 	}
 	log.Printf("[GET] Returned %d users", len(users))
 	return response, nil
 }
 
+func (h *UserHandler) GetUsersId(ctx context.Context, request GetUsersIdRequestObject) (GetUsersIdResponseObject, error) {
+	user, err := h.service.GetUser(authn.Actor(ctx), request.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			return GetUsersId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[GET] Returned user %d", request.Id)
+
+	response := GetUsersId200JSONResponse{
+		Body:    toAPIUser(user),
+		Headers: GetUsersId200ResponseHeaders{ETag: etag.Format(user.Version)},
+	}
+	return response, nil
+}
+
 func (h *UserHandler) GetUsersIdTasks(ctx context.Context, request GetUsersIdTasksRequestObject) (GetUsersIdTasksResponseObject, error) {
-	tasks, err := h.service.GetTasksForUser(request.Id)
+	role := "" // без фильтра - и свои задачи, и назначенные
+	if request.Params.Role != nil {
+		role = string(*request.Params.Role)
+	}
+
+	tasks, err := h.service.GetTasksForUser(authn.Actor(ctx), request.Id, role)
 	if err != nil {
         if strings.Contains(err.Error(), "user not found") {
             return GetUsersIdTasks404Response{}, nil
         }
+        if strings.Contains(err.Error(), "invalid role") {
+            return GetUsersIdTasks400Response{}, nil
+        }
         return nil, err
 	}
 
@@ -75,60 +143,292 @@ func (h *UserHandler) GetUsersIdTasks(ctx context.Context, request GetUsersIdTas
 	response := make(GetUsersIdTasks200JSONResponse, 0, len(tasks))
 
     for _, t := range tasks {
-        response = append(response, Task{
+        task := Task{
             Id:     &t.ID,
             Task:   &t.Task,
             IsDone: t.IsDone,
             UserId: &t.UserId,
-        })
+            Version: &t.Version,
+            Position: &t.Position,
+            CommentCount: &t.CommentCount,
+            WorkspaceId: t.WorkspaceID,
+        }
+        if len(t.Assignees) > 0 {
+            task.AssigneeIds = &t.Assignees
+        }
+        response = append(response, task)
     }
 
 	log.Printf("[GET] Returned %d tasks for user ID %d", len(tasks), request.Id)
 	return response, nil
 }
 
-func (h *UserHandler) PatchUsersId(_ context.Context, request PatchUsersIdRequestObject) (PatchUsersIdResponseObject, error) {
+func (h *UserHandler) PatchUsersId(ctx context.Context, request PatchUsersIdRequestObject) (PatchUsersIdResponseObject, error) {
+	// без If-Match не обновляем (защита от потерянных обновлений)
+	if request.Params.IfMatch == nil {
+		return PatchUsersId428Response{}, nil
+	}
+	version, err := etag.ParseIfMatch(*request.Params.IfMatch)
+	if err != nil {
+		return PatchUsersId412Response{}, nil
+	}
+
 	params := userService.UpdateUserParams{}
 
-    // Если поля бади не пустые, то кладем эти поля кладем в структурку 
-    if request.Body.Email != nil {
-        email := string(*request.Body.Email) 
-        params.Email = &email
-    }
-    
-    if request.Body.Password != nil {
-        params.Password = request.Body.Password 
-    }
+	switch {
+	case request.JSONBody != nil:
+		// Если поля бади не пустые, то кладем эти поля кладем в структурку
+		if request.JSONBody.Email != nil {
+			email := string(*request.JSONBody.Email)
+			params.Email = &email
+		}
+
+	case request.ApplicationMergePatchPlusJSONBody != nil:
+		// application/merge-patch+json (RFC 7396)
+		params, err = mergePatchToParams(*request.ApplicationMergePatchPlusJSONBody)
+		if err != nil {
+			return PatchUsersId400JSONResponse{toValidationError(err)}, nil
+		}
+
+	default:
+		// неподдерживаемый Content-Type - тело не распарсилось
+		return PatchUsersId400JSONResponse{ValidationFailedJSONResponse{Error: "request body is required"}}, nil
+	}
 
-	updatedUser, err := h.service.UpdateUser(request.Id, params)
+	updatedUser, err := h.service.UpdateUser(authn.Actor(ctx), request.Id, version, params)
 	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			return PatchUsersId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			return PatchUsersId412Response{}, nil
+		}
+		if strings.Contains(err.Error(), "email already exists") {
+			return PatchUsersId409Response{}, nil
+		}
+		// ошибки валидации - 400
+		if strings.Contains(err.Error(), "invalid email") ||
+			strings.Contains(err.Error(), "invalid password") ||
+			strings.Contains(err.Error(), "is empty") ||
+			strings.Contains(err.Error(), "no fields to update") {
+			return PatchUsersId400JSONResponse{toValidationError(err)}, nil
+		}
 		return nil, err
 	}
 
 	log.Printf("[PATCH] User %d updated successfully", request.Id)
 
-	email := openapi_types.Email(updatedUser.Email)
-
 	// маппим бизнес-модель в апи-модель
 	response := PatchUsersId200JSONResponse{
-		Id: &updatedUser.ID,
-		Email: &email,
+		Body:    toAPIUser(updatedUser),
+		Headers: PatchUsersId200ResponseHeaders{ETag: etag.Format(updatedUser.Version)},
 	}
 	return response, nil
 }
 
-func (h *UserHandler) DeleteUsersId(_ context.Context, request DeleteUsersIdRequestObject) (DeleteUsersIdResponseObject, error) {
+func (h *UserHandler) DeleteUsersId(ctx context.Context, request DeleteUsersIdRequestObject) (DeleteUsersIdResponseObject, error) {
     urlID := request.Id
 
-    if err := h.service.DeleteUser(urlID); err != nil {
+    if request.Params.IfMatch == nil {
+        return DeleteUsersId428Response{}, nil
+    }
+    version, err := etag.ParseIfMatch(*request.Params.IfMatch)
+    if err != nil {
+        return DeleteUsersId412Response{}, nil
+    }
+
+    if err := h.service.DeleteUser(authn.Actor(ctx), urlID, version); err != nil {
+        // удалять пользователей может только админ
+        if strings.Contains(err.Error(), "forbidden") {
+            return DeleteUsersId403Response{}, nil
+        }
         // Если "user not found" - возвращаем 404
         if strings.Contains(err.Error(), "user not found") {
             return DeleteUsersId404Response{}, nil
         }
+        // Если версия не совпала - 412
+        if strings.Contains(err.Error(), "version mismatch") {
+            return DeleteUsersId412Response{}, nil
+        }
         // Другие ошибки - 500
         return nil, err
     }
 
     log.Printf("[DELETE] User %d deleted successfully", urlID)
     return DeleteUsersId204Response{}, nil
-}
\ No newline at end of file
+}
+
+func (h *UserHandler) PostUsersIdPassword(ctx context.Context, request PostUsersIdPasswordRequestObject) (PostUsersIdPasswordResponseObject, error) {
+	params := userService.ChangePasswordParams{
+		CurrentPassword: request.Body.CurrentPassword,
+		NewPassword:     request.Body.NewPassword,
+	}
+
+	updatedUser, err := h.service.ChangePassword(authn.Actor(ctx), request.Id, params)
+	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			return PostUsersIdPassword404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "current password is incorrect") {
+			return PostUsersIdPassword403Response{}, nil
+		}
+		// ошибки валидации - 400
+		if strings.Contains(err.Error(), "invalid password") ||
+			strings.Contains(err.Error(), "is empty") ||
+			strings.Contains(err.Error(), "must differ") {
+			return PostUsersIdPassword400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] User %d changed password", request.Id)
+
+	// версия пользователя выросла - отдаем новый ETag
+	return PostUsersIdPassword204Response{
+		Headers: PostUsersIdPassword204ResponseHeaders{ETag: etag.Format(updatedUser.Version)},
+	}, nil
+}
+
+// PutUsersIdRole - повысить до админа / понизить до пользователя (только админ)
+func (h *UserHandler) PutUsersIdRole(ctx context.Context, request PutUsersIdRoleRequestObject) (PutUsersIdRoleResponseObject, error) {
+	updatedUser, err := h.service.SetRole(authn.Actor(ctx), request.Id, string(request.Body.Role))
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PutUsersIdRole403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return PutUsersIdRole404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "invalid role") ||
+			strings.Contains(err.Error(), "your own role") {
+			return PutUsersIdRole400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[PUT] User %d now has role %s", request.Id, updatedUser.Role)
+
+	return PutUsersIdRole200JSONResponse{
+		Body:    toAPIUser(updatedUser),
+		Headers: PutUsersIdRole200ResponseHeaders{ETag: etag.Format(updatedUser.Version)},
+	}, nil
+}
+
+// PostUsersIdDisable - отключить аккаунт (только админ); все сессии пользователя отзываются
+func (h *UserHandler) PostUsersIdDisable(ctx context.Context, request PostUsersIdDisableRequestObject) (PostUsersIdDisableResponseObject, error) {
+	updatedUser, err := h.service.SetDisabled(authn.Actor(ctx), request.Id, true)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostUsersIdDisable403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return PostUsersIdDisable404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "your own account") {
+			return PostUsersIdDisable400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] User %d disabled", request.Id)
+
+	return PostUsersIdDisable200JSONResponse{
+		Body:    toAPIUser(updatedUser),
+		Headers: PostUsersIdDisable200ResponseHeaders{ETag: etag.Format(updatedUser.Version)},
+	}, nil
+}
+
+// PostUsersIdEnable - снова включить отключенный аккаунт (только админ)
+func (h *UserHandler) PostUsersIdEnable(ctx context.Context, request PostUsersIdEnableRequestObject) (PostUsersIdEnableResponseObject, error) {
+	updatedUser, err := h.service.SetDisabled(authn.Actor(ctx), request.Id, false)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostUsersIdEnable403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return PostUsersIdEnable404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "your own account") {
+			return PostUsersIdEnable400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] User %d enabled", request.Id)
+
+	return PostUsersIdEnable200JSONResponse{
+		Body:    toAPIUser(updatedUser),
+		Headers: PostUsersIdEnable200ResponseHeaders{ETag: etag.Format(updatedUser.Version)},
+	}, nil
+}
+
+// optionalString - пустая строка в ответе не показывается
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// GetUsersIdAuthEvents - счетчик неудачных входов, блокировка и последние попытки входа (только админ)
+func (h *UserHandler) GetUsersIdAuthEvents(ctx context.Context, request GetUsersIdAuthEventsRequestObject) (GetUsersIdAuthEventsResponseObject, error) {
+	limit := 0
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+
+	status, err := h.service.GetLoginStatus(authn.Actor(ctx), request.Id, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetUsersIdAuthEvents403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return GetUsersIdAuthEvents404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "invalid limit") {
+			return GetUsersIdAuthEvents400JSONResponse{toValidationError(err)}, nil
+		}
+		return nil, err
+	}
+
+	// маппим бизнес-модель в апи-модель
+	events := make([]AuthEvent, 0, len(status.Events))
+	for _, e := range status.Events {
+		event := AuthEvent{
+			Id:        e.ID,
+			Email:     e.Email,
+			Type:      AuthEventType(e.Type),
+			Ip:        optionalString(e.IP),
+			UserAgent: optionalString(e.UserAgent),
+			CreatedAt: e.CreatedAt,
+		}
+		if e.Reason != "" {
+			reason := AuthEventReason(e.Reason)
+			event.Reason = &reason
+		}
+		events = append(events, event)
+	}
+
+	return GetUsersIdAuthEvents200JSONResponse{
+		FailedLogins: status.FailedLogins,
+		LockedUntil:  status.LockedUntil,
+		Events:       events,
+	}, nil
+}
+
+// PostUsersIdUnlock - снять блокировку входа после серии неудачных попыток (только админ)
+func (h *UserHandler) PostUsersIdUnlock(ctx context.Context, request PostUsersIdUnlockRequestObject) (PostUsersIdUnlockResponseObject, error) {
+	err := h.service.Unlock(authn.Actor(ctx), request.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostUsersIdUnlock403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return PostUsersIdUnlock404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] User %d unlocked", request.Id)
+	return PostUsersIdUnlock204Response{}, nil
+}
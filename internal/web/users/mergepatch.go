@@ -0,0 +1,34 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+)
+
+// JSON Merge Patch (RFC 7396) для пользователей:
+//   • ключа нет в документе - поле не меняется
+//   • ключ со значением - поле заменяется
+//   • null означает удаление поля, но email обязательный, поэтому null - ошибка
+//   • password через PATCH не меняется (только POST /users/{id}/password), поэтому это неизвестный ключ
+
+// mergePatchToParams - переводит merge-patch документ в маску полей для сервиса
+func mergePatchToParams(patch MergePatch) (userService.UpdateUserParams, error) {
+	params := userService.UpdateUserParams{}
+
+	for key, value := range patch {
+		switch key {
+		case "email":
+			email, ok := value.(string)
+			if !ok {
+				return params, errors.New("email must be a string")
+			}
+			params.Email = &email
+
+		default:
+			return params, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return params, nil
+}
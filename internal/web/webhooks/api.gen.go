@@ -0,0 +1,1199 @@
+//go:build go1.22
+
+// Package webhooks provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	Failed    WebhookDeliveryStatus = "failed"
+	Pending   WebhookDeliveryStatus = "pending"
+	Succeeded WebhookDeliveryStatus = "succeeded"
+)
+
+// Defines values for WebhookEvent.
+const (
+	TaskCompleted WebhookEvent = "task.completed"
+	TaskCreated   WebhookEvent = "task.created"
+	TaskDeleted   WebhookEvent = "task.deleted"
+	TaskUpdated   WebhookEvent = "task.updated"
+)
+
+// CreateWebhookRequest defines model for CreateWebhookRequest.
+type CreateWebhookRequest struct {
+	Events []WebhookEvent `json:"events"`
+
+	// Url Absolute http or https URL
+	Url string `json:"url"`
+}
+
+// CreatedWebhook defines model for CreatedWebhook.
+type CreatedWebhook struct {
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+	Events    []WebhookEvent `json:"events"`
+	Id        uint           `json:"id"`
+
+	// Secret Key of the X-Webhook-Signature HMAC - shown only once
+	Secret    string    `json:"secret"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Url       string    `json:"url"`
+	UserId    uint      `json:"user_id"`
+}
+
+// UpdateWebhookRequest defines model for UpdateWebhookRequest.
+type UpdateWebhookRequest struct {
+	Active *bool           `json:"active,omitempty"`
+	Events *[]WebhookEvent `json:"events,omitempty"`
+	Url    *string         `json:"url,omitempty"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+	Events    []WebhookEvent `json:"events"`
+	Id        uint           `json:"id"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Url       string         `json:"url"`
+	UserId    uint           `json:"user_id"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	// Attempts Attempts since the delivery was queued (or redelivered)
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// EventId Same for every attempt and redelivery - use it to drop duplicates
+	EventId       string       `json:"event_id"`
+	EventType     WebhookEvent `json:"event_type"`
+	Id            uint         `json:"id"`
+	LastAttemptAt *time.Time   `json:"last_attempt_at,omitempty"`
+	LastError     *string      `json:"last_error,omitempty"`
+
+	// LastStatusCode Absent when the receiver did not answer
+	LastStatusCode *int `json:"last_status_code,omitempty"`
+
+	// NextAttemptAt Only while pending
+	NextAttemptAt *time.Time            `json:"next_attempt_at,omitempty"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	WebhookId     uint                  `json:"webhook_id"`
+}
+
+// WebhookDeliveryAttempt defines model for WebhookDeliveryAttempt.
+type WebhookDeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error"`
+
+	// StatusCode Absent when the receiver did not answer
+	StatusCode *int `json:"status_code,omitempty"`
+}
+
+// WebhookDeliveryDetails defines model for WebhookDeliveryDetails.
+type WebhookDeliveryDetails struct {
+	AttemptLog []WebhookDeliveryAttempt `json:"attempt_log"`
+
+	// Attempts Attempts since the delivery was queued (or redelivered)
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// EventId Same for every attempt and redelivery - use it to drop duplicates
+	EventId       string       `json:"event_id"`
+	EventType     WebhookEvent `json:"event_type"`
+	Id            uint         `json:"id"`
+	LastAttemptAt *time.Time   `json:"last_attempt_at,omitempty"`
+	LastError     *string      `json:"last_error,omitempty"`
+
+	// LastStatusCode Absent when the receiver did not answer
+	LastStatusCode *int `json:"last_status_code,omitempty"`
+
+	// NextAttemptAt Only while pending
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// Payload The exact request body
+	Payload   string                `json:"payload"`
+	Status    WebhookDeliveryStatus `json:"status"`
+	WebhookId uint                  `json:"webhook_id"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDeliveryStatus.
+type WebhookDeliveryStatus string
+
+// WebhookEvent defines model for WebhookEvent.
+type WebhookEvent string
+
+// GetWebhooksIdDeliveriesParams defines parameters for GetWebhooksIdDeliveries.
+type GetWebhooksIdDeliveriesParams struct {
+	// Limit How many deliveries to return (default 50, at most 100)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostWebhooksJSONRequestBody defines body for PostWebhooks for application/json ContentType.
+type PostWebhooksJSONRequestBody = CreateWebhookRequest
+
+// PatchWebhooksIdJSONRequestBody defines body for PatchWebhooksId for application/json ContentType.
+type PatchWebhooksIdJSONRequestBody = UpdateWebhookRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Webhooks of the caller
+	// (GET /webhooks)
+	GetWebhooks(w http.ResponseWriter, r *http.Request)
+	// Register a webhook
+	// (POST /webhooks)
+	PostWebhooks(w http.ResponseWriter, r *http.Request)
+	// Delete a webhook with its delivery log
+	// (DELETE /webhooks/{id})
+	DeleteWebhooksId(w http.ResponseWriter, r *http.Request, id uint)
+	// Get a webhook
+	// (GET /webhooks/{id})
+	GetWebhooksId(w http.ResponseWriter, r *http.Request, id uint)
+	// Change the URL or events, or pause a webhook
+	// (PATCH /webhooks/{id})
+	PatchWebhooksId(w http.ResponseWriter, r *http.Request, id uint)
+	// Delivery log of a webhook
+	// (GET /webhooks/{id}/deliveries)
+	GetWebhooksIdDeliveries(w http.ResponseWriter, r *http.Request, id uint, params GetWebhooksIdDeliveriesParams)
+	// A delivery with its payload and every attempt
+	// (GET /webhooks/{id}/deliveries/{deliveryId})
+	GetWebhooksIdDeliveriesDeliveryId(w http.ResponseWriter, r *http.Request, id uint, deliveryId uint)
+	// Send a delivery again
+	// (POST /webhooks/{id}/deliveries/{deliveryId}/redeliver)
+	PostWebhooksIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request, id uint, deliveryId uint)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteWebhooksId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhooksId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWebhooksId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWebhooksId operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooksId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchWebhooksId operation middleware
+func (siw *ServerInterfaceWrapper) PatchWebhooksId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchWebhooksId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWebhooksIdDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksIdDeliveries(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetWebhooksIdDeliveriesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooksIdDeliveries(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWebhooksIdDeliveriesDeliveryId operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksIdDeliveriesDeliveryId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "deliveryId", r.PathValue("deliveryId"), &deliveryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deliveryId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooksIdDeliveriesDeliveryId(w, r, id, deliveryId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWebhooksIdDeliveriesDeliveryIdRedeliver operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "deliveryId", r.PathValue("deliveryId"), &deliveryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deliveryId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksIdDeliveriesDeliveryIdRedeliver(w, r, id, deliveryId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks", wrapper.GetWebhooks)
+	m.HandleFunc("POST "+options.BaseURL+"/webhooks", wrapper.PostWebhooks)
+	m.HandleFunc("DELETE "+options.BaseURL+"/webhooks/{id}", wrapper.DeleteWebhooksId)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks/{id}", wrapper.GetWebhooksId)
+	m.HandleFunc("PATCH "+options.BaseURL+"/webhooks/{id}", wrapper.PatchWebhooksId)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks/{id}/deliveries", wrapper.GetWebhooksIdDeliveries)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks/{id}/deliveries/{deliveryId}", wrapper.GetWebhooksIdDeliveriesDeliveryId)
+	m.HandleFunc("POST "+options.BaseURL+"/webhooks/{id}/deliveries/{deliveryId}/redeliver", wrapper.PostWebhooksIdDeliveriesDeliveryIdRedeliver)
+
+	return m
+}
+
+type UnauthorizedResponse struct {
+}
+
+type WebhooksDisabledResponse struct {
+}
+
+type GetWebhooksRequestObject struct {
+}
+
+type GetWebhooksResponseObject interface {
+	VisitGetWebhooksResponse(w http.ResponseWriter) error
+}
+
+type GetWebhooks200JSONResponse []Webhook
+
+func (response GetWebhooks200JSONResponse) VisitGetWebhooksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhooks401Response = UnauthorizedResponse
+
+func (response GetWebhooks401Response) VisitGetWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWebhooks403Response struct {
+}
+
+func (response GetWebhooks403Response) VisitGetWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type GetWebhooks503Response = WebhooksDisabledResponse
+
+func (response GetWebhooks503Response) VisitGetWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type PostWebhooksRequestObject struct {
+	Body *PostWebhooksJSONRequestBody
+}
+
+type PostWebhooksResponseObject interface {
+	VisitPostWebhooksResponse(w http.ResponseWriter) error
+}
+
+type PostWebhooks201JSONResponse CreatedWebhook
+
+func (response PostWebhooks201JSONResponse) VisitPostWebhooksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebhooks400Response struct {
+}
+
+func (response PostWebhooks400Response) VisitPostWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostWebhooks401Response = UnauthorizedResponse
+
+func (response PostWebhooks401Response) VisitPostWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWebhooks403Response struct {
+}
+
+func (response PostWebhooks403Response) VisitPostWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostWebhooks503Response = WebhooksDisabledResponse
+
+func (response PostWebhooks503Response) VisitPostWebhooksResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type DeleteWebhooksIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type DeleteWebhooksIdResponseObject interface {
+	VisitDeleteWebhooksIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteWebhooksId204Response struct {
+}
+
+func (response DeleteWebhooksId204Response) VisitDeleteWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteWebhooksId401Response = UnauthorizedResponse
+
+func (response DeleteWebhooksId401Response) VisitDeleteWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteWebhooksId404Response struct {
+}
+
+func (response DeleteWebhooksId404Response) VisitDeleteWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteWebhooksId503Response = WebhooksDisabledResponse
+
+func (response DeleteWebhooksId503Response) VisitDeleteWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type GetWebhooksIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetWebhooksIdResponseObject interface {
+	VisitGetWebhooksIdResponse(w http.ResponseWriter) error
+}
+
+type GetWebhooksId200JSONResponse Webhook
+
+func (response GetWebhooksId200JSONResponse) VisitGetWebhooksIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhooksId401Response = UnauthorizedResponse
+
+func (response GetWebhooksId401Response) VisitGetWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWebhooksId404Response struct {
+}
+
+func (response GetWebhooksId404Response) VisitGetWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetWebhooksId503Response = WebhooksDisabledResponse
+
+func (response GetWebhooksId503Response) VisitGetWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type PatchWebhooksIdRequestObject struct {
+	Id   uint `json:"id"`
+	Body *PatchWebhooksIdJSONRequestBody
+}
+
+type PatchWebhooksIdResponseObject interface {
+	VisitPatchWebhooksIdResponse(w http.ResponseWriter) error
+}
+
+type PatchWebhooksId200JSONResponse Webhook
+
+func (response PatchWebhooksId200JSONResponse) VisitPatchWebhooksIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PatchWebhooksId400Response struct {
+}
+
+func (response PatchWebhooksId400Response) VisitPatchWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PatchWebhooksId401Response = UnauthorizedResponse
+
+func (response PatchWebhooksId401Response) VisitPatchWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PatchWebhooksId404Response struct {
+}
+
+func (response PatchWebhooksId404Response) VisitPatchWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PatchWebhooksId503Response = WebhooksDisabledResponse
+
+func (response PatchWebhooksId503Response) VisitPatchWebhooksIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type GetWebhooksIdDeliveriesRequestObject struct {
+	Id     uint `json:"id"`
+	Params GetWebhooksIdDeliveriesParams
+}
+
+type GetWebhooksIdDeliveriesResponseObject interface {
+	VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error
+}
+
+type GetWebhooksIdDeliveries200JSONResponse []WebhookDelivery
+
+func (response GetWebhooksIdDeliveries200JSONResponse) VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhooksIdDeliveries400Response struct {
+}
+
+func (response GetWebhooksIdDeliveries400Response) VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type GetWebhooksIdDeliveries401Response = UnauthorizedResponse
+
+func (response GetWebhooksIdDeliveries401Response) VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWebhooksIdDeliveries404Response struct {
+}
+
+func (response GetWebhooksIdDeliveries404Response) VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetWebhooksIdDeliveries503Response = WebhooksDisabledResponse
+
+func (response GetWebhooksIdDeliveries503Response) VisitGetWebhooksIdDeliveriesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type GetWebhooksIdDeliveriesDeliveryIdRequestObject struct {
+	Id         uint `json:"id"`
+	DeliveryId uint `json:"deliveryId"`
+}
+
+type GetWebhooksIdDeliveriesDeliveryIdResponseObject interface {
+	VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w http.ResponseWriter) error
+}
+
+type GetWebhooksIdDeliveriesDeliveryId200JSONResponse WebhookDeliveryDetails
+
+func (response GetWebhooksIdDeliveriesDeliveryId200JSONResponse) VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhooksIdDeliveriesDeliveryId401Response = UnauthorizedResponse
+
+func (response GetWebhooksIdDeliveriesDeliveryId401Response) VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWebhooksIdDeliveriesDeliveryId404Response struct {
+}
+
+func (response GetWebhooksIdDeliveriesDeliveryId404Response) VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetWebhooksIdDeliveriesDeliveryId503Response = WebhooksDisabledResponse
+
+func (response GetWebhooksIdDeliveriesDeliveryId503Response) VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliverRequestObject struct {
+	Id         uint `json:"id"`
+	DeliveryId uint `json:"deliveryId"`
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliverResponseObject interface {
+	VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w http.ResponseWriter) error
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliver202JSONResponse WebhookDelivery
+
+func (response PostWebhooksIdDeliveriesDeliveryIdRedeliver202JSONResponse) VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliver401Response = UnauthorizedResponse
+
+func (response PostWebhooksIdDeliveriesDeliveryIdRedeliver401Response) VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliver404Response struct {
+}
+
+func (response PostWebhooksIdDeliveriesDeliveryIdRedeliver404Response) VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostWebhooksIdDeliveriesDeliveryIdRedeliver503Response = WebhooksDisabledResponse
+
+func (response PostWebhooksIdDeliveriesDeliveryIdRedeliver503Response) VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w http.ResponseWriter) error {
+	w.WriteHeader(503)
+	return nil
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Webhooks of the caller
+	// (GET /webhooks)
+	GetWebhooks(ctx context.Context, request GetWebhooksRequestObject) (GetWebhooksResponseObject, error)
+	// Register a webhook
+	// (POST /webhooks)
+	PostWebhooks(ctx context.Context, request PostWebhooksRequestObject) (PostWebhooksResponseObject, error)
+	// Delete a webhook with its delivery log
+	// (DELETE /webhooks/{id})
+	DeleteWebhooksId(ctx context.Context, request DeleteWebhooksIdRequestObject) (DeleteWebhooksIdResponseObject, error)
+	// Get a webhook
+	// (GET /webhooks/{id})
+	GetWebhooksId(ctx context.Context, request GetWebhooksIdRequestObject) (GetWebhooksIdResponseObject, error)
+	// Change the URL or events, or pause a webhook
+	// (PATCH /webhooks/{id})
+	PatchWebhooksId(ctx context.Context, request PatchWebhooksIdRequestObject) (PatchWebhooksIdResponseObject, error)
+	// Delivery log of a webhook
+	// (GET /webhooks/{id}/deliveries)
+	GetWebhooksIdDeliveries(ctx context.Context, request GetWebhooksIdDeliveriesRequestObject) (GetWebhooksIdDeliveriesResponseObject, error)
+	// A delivery with its payload and every attempt
+	// (GET /webhooks/{id}/deliveries/{deliveryId})
+	GetWebhooksIdDeliveriesDeliveryId(ctx context.Context, request GetWebhooksIdDeliveriesDeliveryIdRequestObject) (GetWebhooksIdDeliveriesDeliveryIdResponseObject, error)
+	// Send a delivery again
+	// (POST /webhooks/{id}/deliveries/{deliveryId}/redeliver)
+	PostWebhooksIdDeliveriesDeliveryIdRedeliver(ctx context.Context, request PostWebhooksIdDeliveriesDeliveryIdRedeliverRequestObject) (PostWebhooksIdDeliveriesDeliveryIdRedeliverResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetWebhooks operation middleware
+func (sh *strictHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	var request GetWebhooksRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWebhooks(ctx, request.(GetWebhooksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWebhooks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWebhooksResponseObject); ok {
+		if err := validResponse.VisitGetWebhooksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostWebhooks operation middleware
+func (sh *strictHandler) PostWebhooks(w http.ResponseWriter, r *http.Request) {
+	var request PostWebhooksRequestObject
+
+	var body PostWebhooksJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostWebhooks(ctx, request.(PostWebhooksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostWebhooks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostWebhooksResponseObject); ok {
+		if err := validResponse.VisitPostWebhooksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteWebhooksId operation middleware
+func (sh *strictHandler) DeleteWebhooksId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request DeleteWebhooksIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteWebhooksId(ctx, request.(DeleteWebhooksIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteWebhooksId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteWebhooksIdResponseObject); ok {
+		if err := validResponse.VisitDeleteWebhooksIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWebhooksId operation middleware
+func (sh *strictHandler) GetWebhooksId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetWebhooksIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWebhooksId(ctx, request.(GetWebhooksIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWebhooksId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWebhooksIdResponseObject); ok {
+		if err := validResponse.VisitGetWebhooksIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PatchWebhooksId operation middleware
+func (sh *strictHandler) PatchWebhooksId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PatchWebhooksIdRequestObject
+
+	request.Id = id
+
+	var body PatchWebhooksIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PatchWebhooksId(ctx, request.(PatchWebhooksIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PatchWebhooksId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PatchWebhooksIdResponseObject); ok {
+		if err := validResponse.VisitPatchWebhooksIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWebhooksIdDeliveries operation middleware
+func (sh *strictHandler) GetWebhooksIdDeliveries(w http.ResponseWriter, r *http.Request, id uint, params GetWebhooksIdDeliveriesParams) {
+	var request GetWebhooksIdDeliveriesRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWebhooksIdDeliveries(ctx, request.(GetWebhooksIdDeliveriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWebhooksIdDeliveries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWebhooksIdDeliveriesResponseObject); ok {
+		if err := validResponse.VisitGetWebhooksIdDeliveriesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWebhooksIdDeliveriesDeliveryId operation middleware
+func (sh *strictHandler) GetWebhooksIdDeliveriesDeliveryId(w http.ResponseWriter, r *http.Request, id uint, deliveryId uint) {
+	var request GetWebhooksIdDeliveriesDeliveryIdRequestObject
+
+	request.Id = id
+	request.DeliveryId = deliveryId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWebhooksIdDeliveriesDeliveryId(ctx, request.(GetWebhooksIdDeliveriesDeliveryIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWebhooksIdDeliveriesDeliveryId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWebhooksIdDeliveriesDeliveryIdResponseObject); ok {
+		if err := validResponse.VisitGetWebhooksIdDeliveriesDeliveryIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostWebhooksIdDeliveriesDeliveryIdRedeliver operation middleware
+func (sh *strictHandler) PostWebhooksIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request, id uint, deliveryId uint) {
+	var request PostWebhooksIdDeliveriesDeliveryIdRedeliverRequestObject
+
+	request.Id = id
+	request.DeliveryId = deliveryId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostWebhooksIdDeliveriesDeliveryIdRedeliver(ctx, request.(PostWebhooksIdDeliveriesDeliveryIdRedeliverRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostWebhooksIdDeliveriesDeliveryIdRedeliver")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostWebhooksIdDeliveriesDeliveryIdRedeliverResponseObject); ok {
+		if err := validResponse.VisitPostWebhooksIdDeliveriesDeliveryIdRedeliverResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
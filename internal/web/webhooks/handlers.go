@@ -0,0 +1,294 @@
+package webhooks
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/webhookService"
+)
+
+// handlers вебхуков (как в tasks: только маппинг HTTP <-> сервис)
+
+type WebhookHandler struct {
+	service *webhookService.WebhookService
+}
+
+func NewWebhookHandler(s *webhookService.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: s}
+}
+
+// toAPIEvents - типы событий в апи-модель
+func toAPIEvents(types []string) []WebhookEvent {
+	events := make([]WebhookEvent, 0, len(types))
+	for _, t := range types {
+		events = append(events, WebhookEvent(t))
+	}
+	return events
+}
+
+// fromAPIEvents - типы событий из апи-модели
+func fromAPIEvents(events []WebhookEvent) []string {
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, string(e))
+	}
+	return types
+}
+
+// toAPIWebhook - маппит бизнес-модель в апи-модель (секрета здесь нет)
+func toAPIWebhook(w *webhookService.Webhook) Webhook {
+	return Webhook{
+		Id:        w.ID,
+		UserId:    w.UserID,
+		Url:       w.URL,
+		Events:    toAPIEvents(w.Events),
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// toAPIDelivery - маппит доставку в апи-модель (без тела и журнала попыток)
+func toAPIDelivery(d *webhookService.Delivery) WebhookDelivery {
+	delivery := WebhookDelivery{
+		Id:             d.ID,
+		WebhookId:      d.WebhookID,
+		EventId:        d.EventID,
+		EventType:      WebhookEvent(d.EventType),
+		Status:         WebhookDeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.LastError != "" {
+		lastError := d.LastError
+		delivery.LastError = &lastError
+	}
+	return delivery
+}
+
+// isNotConfigured - вебхуки выключены на сервере: не задан ключ шифрования (-> 503)
+func isNotConfigured(err error) bool {
+	return strings.Contains(err.Error(), "webhooks are not configured")
+}
+
+// isValidationError - ошибки входных данных (-> 400)
+func isValidationError(err error) bool {
+	return strings.Contains(err.Error(), "invalid url") ||
+		strings.Contains(err.Error(), "url points to a private address") ||
+		strings.Contains(err.Error(), "at least one event is required") ||
+		strings.Contains(err.Error(), "invalid event") ||
+		strings.Contains(err.Error(), "too many webhooks") ||
+		strings.Contains(err.Error(), "no fields to update")
+}
+
+func (h *WebhookHandler) GetWebhooks(ctx context.Context, _ GetWebhooksRequestObject) (GetWebhooksResponseObject, error) {
+	webhooks, err := h.service.GetWebhooks(authn.Actor(ctx))
+	if err != nil {
+		if isNotConfigured(err) {
+			return GetWebhooks503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetWebhooks401Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetWebhooks200JSONResponse, 0, len(webhooks))
+	for i := range webhooks {
+		response = append(response, toAPIWebhook(&webhooks[i]))
+	}
+	return response, nil
+}
+
+func (h *WebhookHandler) PostWebhooks(ctx context.Context, req PostWebhooksRequestObject) (PostWebhooksResponseObject, error) {
+	if req.Body == nil {
+		return PostWebhooks400Response{}, nil
+	}
+
+	created, err := h.service.CreateWebhook(authn.Actor(ctx), webhookService.CreateWebhookParams{
+		URL:    req.Body.Url,
+		Events: fromAPIEvents(req.Body.Events),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostWebhooks401Response{}, nil
+		}
+		if isNotConfigured(err) {
+			return PostWebhooks503Response{}, nil
+		}
+		if isValidationError(err) {
+			return PostWebhooks400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Webhook %d created successfully", created.ID)
+
+	webhook := toAPIWebhook(&created.Webhook)
+	return PostWebhooks201JSONResponse{
+		Id:        webhook.Id,
+		UserId:    webhook.UserId,
+		Url:       webhook.Url,
+		Events:    webhook.Events,
+		Active:    webhook.Active,
+		Secret:    created.Secret,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}, nil
+}
+
+func (h *WebhookHandler) GetWebhooksId(ctx context.Context, req GetWebhooksIdRequestObject) (GetWebhooksIdResponseObject, error) {
+	webhook, err := h.service.GetWebhook(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if isNotConfigured(err) {
+			return GetWebhooksId503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") {
+			return GetWebhooksId404Response{}, nil
+		}
+		return nil, err
+	}
+	return GetWebhooksId200JSONResponse(toAPIWebhook(webhook)), nil
+}
+
+func (h *WebhookHandler) PatchWebhooksId(ctx context.Context, req PatchWebhooksIdRequestObject) (PatchWebhooksIdResponseObject, error) {
+	if req.Body == nil {
+		return PatchWebhooksId400Response{}, nil
+	}
+
+	params := webhookService.UpdateWebhookParams{
+		URL:    req.Body.Url,
+		Active: req.Body.Active,
+	}
+	if req.Body.Events != nil {
+		params.Events = fromAPIEvents(*req.Body.Events)
+	}
+
+	webhook, err := h.service.UpdateWebhook(authn.Actor(ctx), req.Id, params)
+	if err != nil {
+		if isNotConfigured(err) {
+			return PatchWebhooksId503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") {
+			return PatchWebhooksId404Response{}, nil
+		}
+		if isValidationError(err) {
+			return PatchWebhooksId400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[PATCH] Webhook %d updated successfully", webhook.ID)
+
+	return PatchWebhooksId200JSONResponse(toAPIWebhook(webhook)), nil
+}
+
+func (h *WebhookHandler) DeleteWebhooksId(ctx context.Context, req DeleteWebhooksIdRequestObject) (DeleteWebhooksIdResponseObject, error) {
+	err := h.service.DeleteWebhook(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if isNotConfigured(err) {
+			return DeleteWebhooksId503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") {
+			return DeleteWebhooksId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Webhook %d deleted successfully", req.Id)
+
+	return DeleteWebhooksId204Response{}, nil
+}
+
+func (h *WebhookHandler) GetWebhooksIdDeliveries(ctx context.Context, req GetWebhooksIdDeliveriesRequestObject) (GetWebhooksIdDeliveriesResponseObject, error) {
+	limit := 0 // 0 - значение по умолчанию сервиса
+	if req.Params.Limit != nil {
+		if *req.Params.Limit < 1 {
+			return GetWebhooksIdDeliveries400Response{}, nil
+		}
+		limit = *req.Params.Limit
+	}
+
+	deliveries, err := h.service.GetDeliveries(authn.Actor(ctx), req.Id, limit)
+	if err != nil {
+		if isNotConfigured(err) {
+			return GetWebhooksIdDeliveries503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") {
+			return GetWebhooksIdDeliveries404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "limit must be between") {
+			return GetWebhooksIdDeliveries400Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetWebhooksIdDeliveries200JSONResponse, 0, len(deliveries))
+	for i := range deliveries {
+		response = append(response, toAPIDelivery(&deliveries[i]))
+	}
+	return response, nil
+}
+
+func (h *WebhookHandler) GetWebhooksIdDeliveriesDeliveryId(ctx context.Context, req GetWebhooksIdDeliveriesDeliveryIdRequestObject) (GetWebhooksIdDeliveriesDeliveryIdResponseObject, error) {
+	delivery, err := h.service.GetDelivery(authn.Actor(ctx), req.Id, req.DeliveryId)
+	if err != nil {
+		if isNotConfigured(err) {
+			return GetWebhooksIdDeliveriesDeliveryId503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") ||
+			strings.Contains(err.Error(), "delivery not found") {
+			return GetWebhooksIdDeliveriesDeliveryId404Response{}, nil
+		}
+		return nil, err
+	}
+
+	d := toAPIDelivery(delivery)
+	response := GetWebhooksIdDeliveriesDeliveryId200JSONResponse{
+		Id:             d.Id,
+		WebhookId:      d.WebhookId,
+		EventId:        d.EventId,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		Payload:        delivery.Payload,
+		AttemptLog:     make([]WebhookDeliveryAttempt, 0, len(delivery.Log)),
+	}
+	for _, a := range delivery.Log {
+		response.AttemptLog = append(response.AttemptLog, WebhookDeliveryAttempt{
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+			At:         a.At,
+		})
+	}
+	return response, nil
+}
+
+func (h *WebhookHandler) PostWebhooksIdDeliveriesDeliveryIdRedeliver(ctx context.Context, req PostWebhooksIdDeliveriesDeliveryIdRedeliverRequestObject) (PostWebhooksIdDeliveriesDeliveryIdRedeliverResponseObject, error) {
+	delivery, err := h.service.Redeliver(authn.Actor(ctx), req.Id, req.DeliveryId)
+	if err != nil {
+		if isNotConfigured(err) {
+			return PostWebhooksIdDeliveriesDeliveryIdRedeliver503Response{}, nil
+		}
+		if strings.Contains(err.Error(), "webhook not found") ||
+			strings.Contains(err.Error(), "delivery not found") {
+			return PostWebhooksIdDeliveriesDeliveryIdRedeliver404Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Delivery %d of webhook %d queued for redelivery", delivery.ID, req.Id)
+
+	return PostWebhooksIdDeliveriesDeliveryIdRedeliver202JSONResponse(toAPIDelivery(delivery)), nil
+}
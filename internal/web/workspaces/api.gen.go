@@ -0,0 +1,1504 @@
+//go:build go1.22
+
+// Package workspaces provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package workspaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for WorkspaceRole.
+const (
+	Admin  WorkspaceRole = "admin"
+	Guest  WorkspaceRole = "guest"
+	Member WorkspaceRole = "member"
+	Owner  WorkspaceRole = "owner"
+)
+
+// AcceptWorkspaceInvitationRequest defines model for AcceptWorkspaceInvitationRequest.
+type AcceptWorkspaceInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// CreateWorkspaceInvitationRequest defines model for CreateWorkspaceInvitationRequest.
+type CreateWorkspaceInvitationRequest struct {
+	Email openapi_types.Email `json:"email"`
+
+	// Role owner and admin manage members and all tasks of the workspace, member creates tasks and works on them, guest only reads tasks
+	Role WorkspaceRole `json:"role"`
+}
+
+// CreateWorkspaceRequest defines model for CreateWorkspaceRequest.
+type CreateWorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// SetWorkspaceRoleRequest defines model for SetWorkspaceRoleRequest.
+type SetWorkspaceRoleRequest struct {
+	// Role owner and admin manage members and all tasks of the workspace, member creates tasks and works on them, guest only reads tasks
+	Role WorkspaceRole `json:"role"`
+}
+
+// Workspace defines model for Workspace.
+type Workspace struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        uint      `json:"id"`
+	Name      string    `json:"name"`
+
+	// Role Role of the caller (absent when an admin looks at a workspace they are not a member of)
+	Role *WorkspaceRole `json:"role,omitempty"`
+}
+
+// WorkspaceInvitation defines model for WorkspaceInvitation.
+type WorkspaceInvitation struct {
+	CreatedAt time.Time           `json:"created_at"`
+	Email     openapi_types.Email `json:"email"`
+	ExpiresAt time.Time           `json:"expires_at"`
+	Id        uint                `json:"id"`
+	InvitedBy uint                `json:"invited_by"`
+
+	// Role owner and admin manage members and all tasks of the workspace, member creates tasks and works on them, guest only reads tasks
+	Role        WorkspaceRole `json:"role"`
+	WorkspaceId uint          `json:"workspace_id"`
+}
+
+// WorkspaceMember defines model for WorkspaceMember.
+type WorkspaceMember struct {
+	JoinedAt time.Time `json:"joined_at"`
+
+	// Role owner and admin manage members and all tasks of the workspace, member creates tasks and works on them, guest only reads tasks
+	Role   WorkspaceRole `json:"role"`
+	UserId uint          `json:"user_id"`
+}
+
+// WorkspaceRole owner and admin manage members and all tasks of the workspace, member creates tasks and works on them, guest only reads tasks
+type WorkspaceRole string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// PostWorkspacesParams defines parameters for PostWorkspaces.
+type PostWorkspacesParams struct {
+	// IdempotencyKey Unique key of the request; retries with the same key replay the stored response
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostWorkspacesJSONRequestBody defines body for PostWorkspaces for application/json ContentType.
+type PostWorkspacesJSONRequestBody = CreateWorkspaceRequest
+
+// PostWorkspacesJoinJSONRequestBody defines body for PostWorkspacesJoin for application/json ContentType.
+type PostWorkspacesJoinJSONRequestBody = AcceptWorkspaceInvitationRequest
+
+// PostWorkspacesIdInvitationsJSONRequestBody defines body for PostWorkspacesIdInvitations for application/json ContentType.
+type PostWorkspacesIdInvitationsJSONRequestBody = CreateWorkspaceInvitationRequest
+
+// PutWorkspacesIdMembersUserIdJSONRequestBody defines body for PutWorkspacesIdMembersUserId for application/json ContentType.
+type PutWorkspacesIdMembersUserIdJSONRequestBody = SetWorkspaceRoleRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Workspaces of the caller
+	// (GET /workspaces)
+	GetWorkspaces(w http.ResponseWriter, r *http.Request)
+	// Create a workspace
+	// (POST /workspaces)
+	PostWorkspaces(w http.ResponseWriter, r *http.Request, params PostWorkspacesParams)
+	// Accept an invitation
+	// (POST /workspaces/join)
+	PostWorkspacesJoin(w http.ResponseWriter, r *http.Request)
+	// Delete a workspace (owner only)
+	// (DELETE /workspaces/{id})
+	DeleteWorkspacesId(w http.ResponseWriter, r *http.Request, id uint)
+	// Get a workspace
+	// (GET /workspaces/{id})
+	GetWorkspacesId(w http.ResponseWriter, r *http.Request, id uint)
+	// Pending invitations of a workspace (owners and admins)
+	// (GET /workspaces/{id}/invitations)
+	GetWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request, id uint)
+	// Invite a user by email
+	// (POST /workspaces/{id}/invitations)
+	PostWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request, id uint)
+	// Revoke a pending invitation
+	// (DELETE /workspaces/{id}/invitations/{invitationId})
+	DeleteWorkspacesIdInvitationsInvitationId(w http.ResponseWriter, r *http.Request, id uint, invitationId uint)
+	// Members of a workspace
+	// (GET /workspaces/{id}/members)
+	GetWorkspacesIdMembers(w http.ResponseWriter, r *http.Request, id uint)
+	// Remove a member from a workspace
+	// (DELETE /workspaces/{id}/members/{userId})
+	DeleteWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint)
+	// Change the role of a member
+	// (PUT /workspaces/{id}/members/{userId})
+	PutWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetWorkspaces operation middleware
+func (siw *ServerInterfaceWrapper) GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWorkspaces(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWorkspaces operation middleware
+func (siw *ServerInterfaceWrapper) PostWorkspaces(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostWorkspacesParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWorkspaces(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWorkspacesJoin operation middleware
+func (siw *ServerInterfaceWrapper) PostWorkspacesJoin(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWorkspacesJoin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteWorkspacesId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWorkspacesId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWorkspacesId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWorkspacesId operation middleware
+func (siw *ServerInterfaceWrapper) GetWorkspacesId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWorkspacesId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWorkspacesIdInvitations operation middleware
+func (siw *ServerInterfaceWrapper) GetWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWorkspacesIdInvitations(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWorkspacesIdInvitations operation middleware
+func (siw *ServerInterfaceWrapper) PostWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWorkspacesIdInvitations(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteWorkspacesIdInvitationsInvitationId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWorkspacesIdInvitationsInvitationId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "invitationId" -------------
+	var invitationId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "invitationId", r.PathValue("invitationId"), &invitationId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invitationId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWorkspacesIdInvitationsInvitationId(w, r, id, invitationId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWorkspacesIdMembers operation middleware
+func (siw *ServerInterfaceWrapper) GetWorkspacesIdMembers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWorkspacesIdMembers(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteWorkspacesIdMembersUserId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", r.PathValue("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWorkspacesIdMembersUserId(w, r, id, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PutWorkspacesIdMembersUserId operation middleware
+func (siw *ServerInterfaceWrapper) PutWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId uint
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", r.PathValue("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutWorkspacesIdMembersUserId(w, r, id, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/workspaces", wrapper.GetWorkspaces)
+	m.HandleFunc("POST "+options.BaseURL+"/workspaces", wrapper.PostWorkspaces)
+	m.HandleFunc("POST "+options.BaseURL+"/workspaces/join", wrapper.PostWorkspacesJoin)
+	m.HandleFunc("DELETE "+options.BaseURL+"/workspaces/{id}", wrapper.DeleteWorkspacesId)
+	m.HandleFunc("GET "+options.BaseURL+"/workspaces/{id}", wrapper.GetWorkspacesId)
+	m.HandleFunc("GET "+options.BaseURL+"/workspaces/{id}/invitations", wrapper.GetWorkspacesIdInvitations)
+	m.HandleFunc("POST "+options.BaseURL+"/workspaces/{id}/invitations", wrapper.PostWorkspacesIdInvitations)
+	m.HandleFunc("DELETE "+options.BaseURL+"/workspaces/{id}/invitations/{invitationId}", wrapper.DeleteWorkspacesIdInvitationsInvitationId)
+	m.HandleFunc("GET "+options.BaseURL+"/workspaces/{id}/members", wrapper.GetWorkspacesIdMembers)
+	m.HandleFunc("DELETE "+options.BaseURL+"/workspaces/{id}/members/{userId}", wrapper.DeleteWorkspacesIdMembersUserId)
+	m.HandleFunc("PUT "+options.BaseURL+"/workspaces/{id}/members/{userId}", wrapper.PutWorkspacesIdMembersUserId)
+
+	return m
+}
+
+type IdempotencyConflictResponse struct {
+}
+
+type IdempotencyKeyReusedResponse struct {
+}
+
+type UnauthorizedResponse struct {
+}
+
+type GetWorkspacesRequestObject struct {
+}
+
+type GetWorkspacesResponseObject interface {
+	VisitGetWorkspacesResponse(w http.ResponseWriter) error
+}
+
+type GetWorkspaces200JSONResponse []Workspace
+
+func (response GetWorkspaces200JSONResponse) VisitGetWorkspacesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWorkspaces401Response = UnauthorizedResponse
+
+func (response GetWorkspaces401Response) VisitGetWorkspacesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWorkspacesRequestObject struct {
+	Params PostWorkspacesParams
+	Body   *PostWorkspacesJSONRequestBody
+}
+
+type PostWorkspacesResponseObject interface {
+	VisitPostWorkspacesResponse(w http.ResponseWriter) error
+}
+
+type PostWorkspaces201JSONResponse Workspace
+
+func (response PostWorkspaces201JSONResponse) VisitPostWorkspacesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWorkspaces400Response struct {
+}
+
+func (response PostWorkspaces400Response) VisitPostWorkspacesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostWorkspaces401Response = UnauthorizedResponse
+
+func (response PostWorkspaces401Response) VisitPostWorkspacesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWorkspaces409Response = IdempotencyConflictResponse
+
+func (response PostWorkspaces409Response) VisitPostWorkspacesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PostWorkspaces422Response = IdempotencyKeyReusedResponse
+
+func (response PostWorkspaces422Response) VisitPostWorkspacesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(422)
+	return nil
+}
+
+type PostWorkspacesJoinRequestObject struct {
+	Body *PostWorkspacesJoinJSONRequestBody
+}
+
+type PostWorkspacesJoinResponseObject interface {
+	VisitPostWorkspacesJoinResponse(w http.ResponseWriter) error
+}
+
+type PostWorkspacesJoin200JSONResponse Workspace
+
+func (response PostWorkspacesJoin200JSONResponse) VisitPostWorkspacesJoinResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWorkspacesJoin400Response struct {
+}
+
+func (response PostWorkspacesJoin400Response) VisitPostWorkspacesJoinResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostWorkspacesJoin401Response = UnauthorizedResponse
+
+func (response PostWorkspacesJoin401Response) VisitPostWorkspacesJoinResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWorkspacesJoin403Response struct {
+}
+
+func (response PostWorkspacesJoin403Response) VisitPostWorkspacesJoinResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteWorkspacesIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type DeleteWorkspacesIdResponseObject interface {
+	VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteWorkspacesId204Response struct {
+}
+
+func (response DeleteWorkspacesId204Response) VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteWorkspacesId401Response = UnauthorizedResponse
+
+func (response DeleteWorkspacesId401Response) VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteWorkspacesId403Response struct {
+}
+
+func (response DeleteWorkspacesId403Response) VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteWorkspacesId404Response struct {
+}
+
+func (response DeleteWorkspacesId404Response) VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteWorkspacesId409Response struct {
+}
+
+func (response DeleteWorkspacesId409Response) VisitDeleteWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type GetWorkspacesIdRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetWorkspacesIdResponseObject interface {
+	VisitGetWorkspacesIdResponse(w http.ResponseWriter) error
+}
+
+type GetWorkspacesId200JSONResponse Workspace
+
+func (response GetWorkspacesId200JSONResponse) VisitGetWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWorkspacesId401Response = UnauthorizedResponse
+
+func (response GetWorkspacesId401Response) VisitGetWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWorkspacesId404Response struct {
+}
+
+func (response GetWorkspacesId404Response) VisitGetWorkspacesIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetWorkspacesIdInvitationsRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetWorkspacesIdInvitationsResponseObject interface {
+	VisitGetWorkspacesIdInvitationsResponse(w http.ResponseWriter) error
+}
+
+type GetWorkspacesIdInvitations200JSONResponse []WorkspaceInvitation
+
+func (response GetWorkspacesIdInvitations200JSONResponse) VisitGetWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWorkspacesIdInvitations401Response = UnauthorizedResponse
+
+func (response GetWorkspacesIdInvitations401Response) VisitGetWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWorkspacesIdInvitations403Response struct {
+}
+
+func (response GetWorkspacesIdInvitations403Response) VisitGetWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type GetWorkspacesIdInvitations404Response struct {
+}
+
+func (response GetWorkspacesIdInvitations404Response) VisitGetWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostWorkspacesIdInvitationsRequestObject struct {
+	Id   uint `json:"id"`
+	Body *PostWorkspacesIdInvitationsJSONRequestBody
+}
+
+type PostWorkspacesIdInvitationsResponseObject interface {
+	VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error
+}
+
+type PostWorkspacesIdInvitations201JSONResponse WorkspaceInvitation
+
+func (response PostWorkspacesIdInvitations201JSONResponse) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWorkspacesIdInvitations400Response struct {
+}
+
+func (response PostWorkspacesIdInvitations400Response) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PostWorkspacesIdInvitations401Response = UnauthorizedResponse
+
+func (response PostWorkspacesIdInvitations401Response) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PostWorkspacesIdInvitations403Response struct {
+}
+
+func (response PostWorkspacesIdInvitations403Response) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PostWorkspacesIdInvitations404Response struct {
+}
+
+func (response PostWorkspacesIdInvitations404Response) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostWorkspacesIdInvitations409Response struct {
+}
+
+func (response PostWorkspacesIdInvitations409Response) VisitPostWorkspacesIdInvitationsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type DeleteWorkspacesIdInvitationsInvitationIdRequestObject struct {
+	Id           uint `json:"id"`
+	InvitationId uint `json:"invitationId"`
+}
+
+type DeleteWorkspacesIdInvitationsInvitationIdResponseObject interface {
+	VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteWorkspacesIdInvitationsInvitationId204Response struct {
+}
+
+func (response DeleteWorkspacesIdInvitationsInvitationId204Response) VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteWorkspacesIdInvitationsInvitationId401Response = UnauthorizedResponse
+
+func (response DeleteWorkspacesIdInvitationsInvitationId401Response) VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteWorkspacesIdInvitationsInvitationId403Response struct {
+}
+
+func (response DeleteWorkspacesIdInvitationsInvitationId403Response) VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteWorkspacesIdInvitationsInvitationId404Response struct {
+}
+
+func (response DeleteWorkspacesIdInvitationsInvitationId404Response) VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetWorkspacesIdMembersRequestObject struct {
+	Id uint `json:"id"`
+}
+
+type GetWorkspacesIdMembersResponseObject interface {
+	VisitGetWorkspacesIdMembersResponse(w http.ResponseWriter) error
+}
+
+type GetWorkspacesIdMembers200JSONResponse []WorkspaceMember
+
+func (response GetWorkspacesIdMembers200JSONResponse) VisitGetWorkspacesIdMembersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWorkspacesIdMembers401Response = UnauthorizedResponse
+
+func (response GetWorkspacesIdMembers401Response) VisitGetWorkspacesIdMembersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetWorkspacesIdMembers404Response struct {
+}
+
+func (response GetWorkspacesIdMembers404Response) VisitGetWorkspacesIdMembersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteWorkspacesIdMembersUserIdRequestObject struct {
+	Id     uint `json:"id"`
+	UserId uint `json:"userId"`
+}
+
+type DeleteWorkspacesIdMembersUserIdResponseObject interface {
+	VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteWorkspacesIdMembersUserId204Response struct {
+}
+
+func (response DeleteWorkspacesIdMembersUserId204Response) VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteWorkspacesIdMembersUserId401Response = UnauthorizedResponse
+
+func (response DeleteWorkspacesIdMembersUserId401Response) VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteWorkspacesIdMembersUserId403Response struct {
+}
+
+func (response DeleteWorkspacesIdMembersUserId403Response) VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteWorkspacesIdMembersUserId404Response struct {
+}
+
+func (response DeleteWorkspacesIdMembersUserId404Response) VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteWorkspacesIdMembersUserId409Response struct {
+}
+
+func (response DeleteWorkspacesIdMembersUserId409Response) VisitDeleteWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type PutWorkspacesIdMembersUserIdRequestObject struct {
+	Id     uint `json:"id"`
+	UserId uint `json:"userId"`
+	Body   *PutWorkspacesIdMembersUserIdJSONRequestBody
+}
+
+type PutWorkspacesIdMembersUserIdResponseObject interface {
+	VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error
+}
+
+type PutWorkspacesIdMembersUserId200JSONResponse WorkspaceMember
+
+func (response PutWorkspacesIdMembersUserId200JSONResponse) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PutWorkspacesIdMembersUserId400Response struct {
+}
+
+func (response PutWorkspacesIdMembersUserId400Response) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type PutWorkspacesIdMembersUserId401Response = UnauthorizedResponse
+
+func (response PutWorkspacesIdMembersUserId401Response) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PutWorkspacesIdMembersUserId403Response struct {
+}
+
+func (response PutWorkspacesIdMembersUserId403Response) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PutWorkspacesIdMembersUserId404Response struct {
+}
+
+func (response PutWorkspacesIdMembersUserId404Response) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PutWorkspacesIdMembersUserId409Response struct {
+}
+
+func (response PutWorkspacesIdMembersUserId409Response) VisitPutWorkspacesIdMembersUserIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Workspaces of the caller
+	// (GET /workspaces)
+	GetWorkspaces(ctx context.Context, request GetWorkspacesRequestObject) (GetWorkspacesResponseObject, error)
+	// Create a workspace
+	// (POST /workspaces)
+	PostWorkspaces(ctx context.Context, request PostWorkspacesRequestObject) (PostWorkspacesResponseObject, error)
+	// Accept an invitation
+	// (POST /workspaces/join)
+	PostWorkspacesJoin(ctx context.Context, request PostWorkspacesJoinRequestObject) (PostWorkspacesJoinResponseObject, error)
+	// Delete a workspace (owner only)
+	// (DELETE /workspaces/{id})
+	DeleteWorkspacesId(ctx context.Context, request DeleteWorkspacesIdRequestObject) (DeleteWorkspacesIdResponseObject, error)
+	// Get a workspace
+	// (GET /workspaces/{id})
+	GetWorkspacesId(ctx context.Context, request GetWorkspacesIdRequestObject) (GetWorkspacesIdResponseObject, error)
+	// Pending invitations of a workspace (owners and admins)
+	// (GET /workspaces/{id}/invitations)
+	GetWorkspacesIdInvitations(ctx context.Context, request GetWorkspacesIdInvitationsRequestObject) (GetWorkspacesIdInvitationsResponseObject, error)
+	// Invite a user by email
+	// (POST /workspaces/{id}/invitations)
+	PostWorkspacesIdInvitations(ctx context.Context, request PostWorkspacesIdInvitationsRequestObject) (PostWorkspacesIdInvitationsResponseObject, error)
+	// Revoke a pending invitation
+	// (DELETE /workspaces/{id}/invitations/{invitationId})
+	DeleteWorkspacesIdInvitationsInvitationId(ctx context.Context, request DeleteWorkspacesIdInvitationsInvitationIdRequestObject) (DeleteWorkspacesIdInvitationsInvitationIdResponseObject, error)
+	// Members of a workspace
+	// (GET /workspaces/{id}/members)
+	GetWorkspacesIdMembers(ctx context.Context, request GetWorkspacesIdMembersRequestObject) (GetWorkspacesIdMembersResponseObject, error)
+	// Remove a member from a workspace
+	// (DELETE /workspaces/{id}/members/{userId})
+	DeleteWorkspacesIdMembersUserId(ctx context.Context, request DeleteWorkspacesIdMembersUserIdRequestObject) (DeleteWorkspacesIdMembersUserIdResponseObject, error)
+	// Change the role of a member
+	// (PUT /workspaces/{id}/members/{userId})
+	PutWorkspacesIdMembersUserId(ctx context.Context, request PutWorkspacesIdMembersUserIdRequestObject) (PutWorkspacesIdMembersUserIdResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetWorkspaces operation middleware
+func (sh *strictHandler) GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+	var request GetWorkspacesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWorkspaces(ctx, request.(GetWorkspacesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWorkspaces")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWorkspacesResponseObject); ok {
+		if err := validResponse.VisitGetWorkspacesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostWorkspaces operation middleware
+func (sh *strictHandler) PostWorkspaces(w http.ResponseWriter, r *http.Request, params PostWorkspacesParams) {
+	var request PostWorkspacesRequestObject
+
+	request.Params = params
+
+	var body PostWorkspacesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostWorkspaces(ctx, request.(PostWorkspacesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostWorkspaces")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostWorkspacesResponseObject); ok {
+		if err := validResponse.VisitPostWorkspacesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostWorkspacesJoin operation middleware
+func (sh *strictHandler) PostWorkspacesJoin(w http.ResponseWriter, r *http.Request) {
+	var request PostWorkspacesJoinRequestObject
+
+	var body PostWorkspacesJoinJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostWorkspacesJoin(ctx, request.(PostWorkspacesJoinRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostWorkspacesJoin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostWorkspacesJoinResponseObject); ok {
+		if err := validResponse.VisitPostWorkspacesJoinResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteWorkspacesId operation middleware
+func (sh *strictHandler) DeleteWorkspacesId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request DeleteWorkspacesIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteWorkspacesId(ctx, request.(DeleteWorkspacesIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteWorkspacesId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteWorkspacesIdResponseObject); ok {
+		if err := validResponse.VisitDeleteWorkspacesIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWorkspacesId operation middleware
+func (sh *strictHandler) GetWorkspacesId(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetWorkspacesIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWorkspacesId(ctx, request.(GetWorkspacesIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWorkspacesId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWorkspacesIdResponseObject); ok {
+		if err := validResponse.VisitGetWorkspacesIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWorkspacesIdInvitations operation middleware
+func (sh *strictHandler) GetWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetWorkspacesIdInvitationsRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWorkspacesIdInvitations(ctx, request.(GetWorkspacesIdInvitationsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWorkspacesIdInvitations")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWorkspacesIdInvitationsResponseObject); ok {
+		if err := validResponse.VisitGetWorkspacesIdInvitationsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostWorkspacesIdInvitations operation middleware
+func (sh *strictHandler) PostWorkspacesIdInvitations(w http.ResponseWriter, r *http.Request, id uint) {
+	var request PostWorkspacesIdInvitationsRequestObject
+
+	request.Id = id
+
+	var body PostWorkspacesIdInvitationsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostWorkspacesIdInvitations(ctx, request.(PostWorkspacesIdInvitationsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostWorkspacesIdInvitations")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostWorkspacesIdInvitationsResponseObject); ok {
+		if err := validResponse.VisitPostWorkspacesIdInvitationsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteWorkspacesIdInvitationsInvitationId operation middleware
+func (sh *strictHandler) DeleteWorkspacesIdInvitationsInvitationId(w http.ResponseWriter, r *http.Request, id uint, invitationId uint) {
+	var request DeleteWorkspacesIdInvitationsInvitationIdRequestObject
+
+	request.Id = id
+	request.InvitationId = invitationId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteWorkspacesIdInvitationsInvitationId(ctx, request.(DeleteWorkspacesIdInvitationsInvitationIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteWorkspacesIdInvitationsInvitationId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteWorkspacesIdInvitationsInvitationIdResponseObject); ok {
+		if err := validResponse.VisitDeleteWorkspacesIdInvitationsInvitationIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetWorkspacesIdMembers operation middleware
+func (sh *strictHandler) GetWorkspacesIdMembers(w http.ResponseWriter, r *http.Request, id uint) {
+	var request GetWorkspacesIdMembersRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetWorkspacesIdMembers(ctx, request.(GetWorkspacesIdMembersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetWorkspacesIdMembers")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetWorkspacesIdMembersResponseObject); ok {
+		if err := validResponse.VisitGetWorkspacesIdMembersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteWorkspacesIdMembersUserId operation middleware
+func (sh *strictHandler) DeleteWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint) {
+	var request DeleteWorkspacesIdMembersUserIdRequestObject
+
+	request.Id = id
+	request.UserId = userId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteWorkspacesIdMembersUserId(ctx, request.(DeleteWorkspacesIdMembersUserIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteWorkspacesIdMembersUserId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteWorkspacesIdMembersUserIdResponseObject); ok {
+		if err := validResponse.VisitDeleteWorkspacesIdMembersUserIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PutWorkspacesIdMembersUserId operation middleware
+func (sh *strictHandler) PutWorkspacesIdMembersUserId(w http.ResponseWriter, r *http.Request, id uint, userId uint) {
+	var request PutWorkspacesIdMembersUserIdRequestObject
+
+	request.Id = id
+	request.UserId = userId
+
+	var body PutWorkspacesIdMembersUserIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PutWorkspacesIdMembersUserId(ctx, request.(PutWorkspacesIdMembersUserIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PutWorkspacesIdMembersUserId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PutWorkspacesIdMembersUserIdResponseObject); ok {
+		if err := validResponse.VisitPutWorkspacesIdMembersUserIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
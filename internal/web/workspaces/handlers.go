@@ -0,0 +1,283 @@
+package workspaces
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/AntonRadchenko/WebPet1/internal/workspaceService"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// handlers рабочих пространств (как в tasks: только маппинг HTTP <-> сервис)
+
+type WorkspaceHandler struct {
+	service *workspaceService.WorkspaceService
+}
+
+func NewWorkspaceHandler(s *workspaceService.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{service: s}
+}
+
+// toAPIWorkspace - маппит бизнес-модель в апи-модель (без роли, если спрашивает не участник)
+func toAPIWorkspace(w *workspaceService.Workspace) Workspace {
+	workspace := Workspace{
+		Id:        w.ID,
+		Name:      w.Name,
+		CreatedAt: w.CreatedAt,
+	}
+	if w.Role != "" {
+		role := WorkspaceRole(w.Role)
+		workspace.Role = &role
+	}
+	return workspace
+}
+
+// toAPIMember - маппит участника в апи-модель
+func toAPIMember(m *workspaceService.Member) WorkspaceMember {
+	return WorkspaceMember{
+		UserId:   m.UserID,
+		Role:     WorkspaceRole(m.Role),
+		JoinedAt: m.JoinedAt,
+	}
+}
+
+// toAPIInvitation - маппит приглашение в апи-модель
+func toAPIInvitation(i *workspaceService.Invitation) WorkspaceInvitation {
+	return WorkspaceInvitation{
+		Id:          i.ID,
+		WorkspaceId: i.WorkspaceID,
+		Email:       openapi_types.Email(i.Email),
+		Role:        WorkspaceRole(i.Role),
+		InvitedBy:   i.InvitedBy,
+		ExpiresAt:   i.ExpiresAt,
+		CreatedAt:   i.CreatedAt,
+	}
+}
+
+func (h *WorkspaceHandler) GetWorkspaces(ctx context.Context, _ GetWorkspacesRequestObject) (GetWorkspacesResponseObject, error) {
+	workspaces, err := h.service.GetWorkspaces(authn.Actor(ctx))
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetWorkspaces401Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetWorkspaces200JSONResponse, 0, len(workspaces))
+	for i := range workspaces {
+		response = append(response, toAPIWorkspace(&workspaces[i]))
+	}
+	return response, nil
+}
+
+func (h *WorkspaceHandler) PostWorkspaces(ctx context.Context, req PostWorkspacesRequestObject) (PostWorkspacesResponseObject, error) {
+	if req.Body == nil {
+		return PostWorkspaces400Response{}, nil
+	}
+
+	workspace, err := h.service.CreateWorkspace(authn.Actor(ctx), workspaceService.CreateWorkspaceParams{Name: req.Body.Name})
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostWorkspaces401Response{}, nil
+		}
+		if strings.Contains(err.Error(), "name is empty") ||
+			strings.Contains(err.Error(), "name is too long") {
+			return PostWorkspaces400Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Workspace %d created successfully", workspace.ID)
+
+	return PostWorkspaces201JSONResponse(toAPIWorkspace(workspace)), nil
+}
+
+func (h *WorkspaceHandler) GetWorkspacesId(ctx context.Context, req GetWorkspacesIdRequestObject) (GetWorkspacesIdResponseObject, error) {
+	workspace, err := h.service.GetWorkspace(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "workspace not found") {
+			return GetWorkspacesId404Response{}, nil
+		}
+		return nil, err
+	}
+	return GetWorkspacesId200JSONResponse(toAPIWorkspace(workspace)), nil
+}
+
+func (h *WorkspaceHandler) DeleteWorkspacesId(ctx context.Context, req DeleteWorkspacesIdRequestObject) (DeleteWorkspacesIdResponseObject, error) {
+	err := h.service.DeleteWorkspace(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "workspace not found") {
+			return DeleteWorkspacesId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteWorkspacesId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "workspace is not empty") {
+			return DeleteWorkspacesId409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Workspace %d deleted successfully", req.Id)
+
+	return DeleteWorkspacesId204Response{}, nil
+}
+
+func (h *WorkspaceHandler) GetWorkspacesIdMembers(ctx context.Context, req GetWorkspacesIdMembersRequestObject) (GetWorkspacesIdMembersResponseObject, error) {
+	members, err := h.service.GetMembers(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "workspace not found") {
+			return GetWorkspacesIdMembers404Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetWorkspacesIdMembers200JSONResponse, 0, len(members))
+	for i := range members {
+		response = append(response, toAPIMember(&members[i]))
+	}
+	return response, nil
+}
+
+func (h *WorkspaceHandler) PutWorkspacesIdMembersUserId(ctx context.Context, req PutWorkspacesIdMembersUserIdRequestObject) (PutWorkspacesIdMembersUserIdResponseObject, error) {
+	if req.Body == nil {
+		return PutWorkspacesIdMembersUserId400Response{}, nil
+	}
+
+	member, err := h.service.SetMemberRole(authn.Actor(ctx), req.Id, req.UserId, string(req.Body.Role))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return PutWorkspacesIdMembersUserId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PutWorkspacesIdMembersUserId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "invalid role") {
+			return PutWorkspacesIdMembersUserId400Response{}, nil
+		}
+		// последнего владельца понизить нельзя
+		if strings.Contains(err.Error(), "workspace must have an owner") {
+			return PutWorkspacesIdMembersUserId409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[PUT] User %d is now %s of workspace %d", req.UserId, member.Role, req.Id)
+
+	return PutWorkspacesIdMembersUserId200JSONResponse(toAPIMember(member)), nil
+}
+
+func (h *WorkspaceHandler) DeleteWorkspacesIdMembersUserId(ctx context.Context, req DeleteWorkspacesIdMembersUserIdRequestObject) (DeleteWorkspacesIdMembersUserIdResponseObject, error) {
+	err := h.service.RemoveMember(authn.Actor(ctx), req.Id, req.UserId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return DeleteWorkspacesIdMembersUserId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteWorkspacesIdMembersUserId403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "workspace must have an owner") {
+			return DeleteWorkspacesIdMembersUserId409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] User %d removed from workspace %d", req.UserId, req.Id)
+
+	return DeleteWorkspacesIdMembersUserId204Response{}, nil
+}
+
+func (h *WorkspaceHandler) GetWorkspacesIdInvitations(ctx context.Context, req GetWorkspacesIdInvitationsRequestObject) (GetWorkspacesIdInvitationsResponseObject, error) {
+	invitations, err := h.service.GetInvitations(authn.Actor(ctx), req.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), "workspace not found") {
+			return GetWorkspacesIdInvitations404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return GetWorkspacesIdInvitations403Response{}, nil
+		}
+		return nil, err
+	}
+
+	response := make(GetWorkspacesIdInvitations200JSONResponse, 0, len(invitations))
+	for i := range invitations {
+		response = append(response, toAPIInvitation(&invitations[i]))
+	}
+	return response, nil
+}
+
+func (h *WorkspaceHandler) PostWorkspacesIdInvitations(ctx context.Context, req PostWorkspacesIdInvitationsRequestObject) (PostWorkspacesIdInvitationsResponseObject, error) {
+	if req.Body == nil {
+		return PostWorkspacesIdInvitations400Response{}, nil
+	}
+
+	invitation, err := h.service.Invite(authn.Actor(ctx), req.Id, workspaceService.InviteParams{
+		Email: string(req.Body.Email),
+		Role:  string(req.Body.Role),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "workspace not found") {
+			return PostWorkspacesIdInvitations404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostWorkspacesIdInvitations403Response{}, nil
+		}
+		if strings.Contains(err.Error(), "invalid role") ||
+			strings.Contains(err.Error(), "invalid email") {
+			return PostWorkspacesIdInvitations400Response{}, nil
+		}
+		if strings.Contains(err.Error(), "user is already a member") {
+			return PostWorkspacesIdInvitations409Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] Invitation %d to workspace %d sent", invitation.ID, req.Id)
+
+	return PostWorkspacesIdInvitations201JSONResponse(toAPIInvitation(invitation)), nil
+}
+
+func (h *WorkspaceHandler) DeleteWorkspacesIdInvitationsInvitationId(ctx context.Context, req DeleteWorkspacesIdInvitationsInvitationIdRequestObject) (DeleteWorkspacesIdInvitationsInvitationIdResponseObject, error) {
+	err := h.service.RevokeInvitation(authn.Actor(ctx), req.Id, req.InvitationId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return DeleteWorkspacesIdInvitationsInvitationId404Response{}, nil
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			return DeleteWorkspacesIdInvitationsInvitationId403Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[DELETE] Invitation %d to workspace %d revoked", req.InvitationId, req.Id)
+
+	return DeleteWorkspacesIdInvitationsInvitationId204Response{}, nil
+}
+
+func (h *WorkspaceHandler) PostWorkspacesJoin(ctx context.Context, req PostWorkspacesJoinRequestObject) (PostWorkspacesJoinResponseObject, error) {
+	if req.Body == nil {
+		return PostWorkspacesJoin400Response{}, nil
+	}
+
+	workspace, err := h.service.AcceptInvitation(authn.Actor(ctx), req.Body.Token)
+	if err != nil {
+		if strings.Contains(err.Error(), "forbidden") {
+			return PostWorkspacesJoin401Response{}, nil
+		}
+		if strings.Contains(err.Error(), "invalid or expired invitation") {
+			return PostWorkspacesJoin400Response{}, nil
+		}
+		// приглашение выдано на другой адрес или адрес не подтвержден
+		if strings.Contains(err.Error(), "invitation is for another email") ||
+			strings.Contains(err.Error(), "email is not verified") {
+			return PostWorkspacesJoin403Response{}, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("[POST] User %d joined workspace %d", authn.Actor(ctx).UserID, workspace.ID)
+
+	return PostWorkspacesJoin200JSONResponse(toAPIWorkspace(workspace)), nil
+}
@@ -0,0 +1,514 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/gorilla/websocket"
+)
+
+// GET /ws - двунаправленный канал для доски задач (WebSocket, подпротокол tasks.v1)
+// написан руками, как и GET /events: oapi-codegen не умеет WebSocket
+//   • вход - при рукопожатии: токен в Authorization или подпротоколом bearer.<token> (см. authn);
+//     в ответ сервер называет только tasks.v1, токен обратно не уходит
+//   • клиент подписывается на темы workspace:<id> (задачи пространства) и user:<id> (свои и назначенные
+//     задачи пользователя); в ответ на подписку - текущие задачи темы, дальше - события их изменений
+//     (права - как у GET /events: событие задачи, которую клиент не видит, не придет)
+//   • команды toggle_done и reorder идут через TaskService с теми же проверками, что PATCH /tasks/{id};
+//     персональному токену для них нужен скоуп tasks:write
+//   • раз в pingPeriod сервер шлет ping; нет pong дольше двух периодов - соединение закрывается
+//   • медленный клиент (не успевает забирать события или ответы) отключается с кодом 1013 -
+//     пусть переподключится и заново подпишется
+//   • соединение живет не дольше maxAge - по той же причине, что поток GET /events
+
+// Subprotocol - подпротокол, который сервер подтверждает клиенту
+const Subprotocol = "tasks.v1"
+
+// значения по умолчанию
+const (
+	DefaultPingPeriod = 25 * time.Second
+	DefaultMaxAge     = 15 * time.Minute
+)
+
+const (
+	writeWait      = 10 * time.Second // сколько ждать записи одного сообщения
+	maxMessageSize = 4096             // команды маленькие - большее сообщение закрывает соединение
+	sendBuffer     = 64               // очередь ответов соединения
+	maxTopics      = 50               // подписок на одно соединение
+)
+
+// типы сообщений клиента
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeToggleDone  = "toggle_done"
+	TypeReorder     = "reorder"
+)
+
+// типы ответов сервера (события идут со своими типами: task.created, task.updated, task.deleted, reset)
+const (
+	TypeSnapshot = "snapshot"
+	TypeAck      = "ack"
+	TypeError    = "error"
+)
+
+// коды ошибок в ответе
+const (
+	CodeInvalid         = "invalid"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeVersionMismatch = "version_mismatch"
+	CodeInternal        = "internal"
+)
+
+// темы подписки
+const (
+	TopicWorkspace = "workspace"
+	TopicUser      = "user"
+)
+
+// Subscriber - источник событий (реализует events.Bus)
+type Subscriber interface {
+	Subscribe(lastEventID string) (*events.Subscription, []events.Event, bool)
+}
+
+// Tasks - задачи и права на них (реализует taskService.TaskService)
+type Tasks interface {
+	CanSeeEvent(actor rbac.Actor, ev events.Event) bool
+	GetWorkspaceTasks(actor rbac.Actor, workspaceID uint) ([]taskService.Task, error)
+	GetTask(actor rbac.Actor, id uint) (*taskService.Task, error)
+	UpdateTask(actor rbac.Actor, id uint, version *uint, params taskService.UpdateTaskParams) (*taskService.Task, error)
+}
+
+// UserTasks - задачи пользователя (реализует userService.UserService)
+type UserTasks interface {
+	GetTasksForUser(actor rbac.Actor, userID uint, role string) ([]taskService.Task, error)
+}
+
+type Handler struct {
+	bus        Subscriber
+	tasks      Tasks
+	users      UserTasks
+	upgrader   websocket.Upgrader
+	pingPeriod time.Duration
+	maxAge     time.Duration
+}
+
+func NewHandler(bus Subscriber, tasks Tasks, users UserTasks) *Handler {
+	return &Handler{
+		bus:   bus,
+		tasks: tasks,
+		users: users,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{Subprotocol},
+			// токен передается явно, а не cookie - чужой сайт не откроет соединение от имени пользователя
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		pingPeriod: DefaultPingPeriod,
+		maxAge:     DefaultMaxAge,
+	}
+}
+
+// WithTimeouts - как часто слать ping и сколько держать соединение открытым
+func (h *Handler) WithTimeouts(pingPeriod, maxAge time.Duration) *Handler {
+	h.pingPeriod = pingPeriod
+	h.maxAge = maxAge
+	return h
+}
+
+// request - сообщение клиента
+type request struct {
+	ID       string `json:"id,omitempty"` // вернется в ответе, чтобы клиент его сопоставил
+	Type     string `json:"type"`
+	Topic    string `json:"topic,omitempty"`
+	TaskID   uint   `json:"task_id,omitempty"`
+	Version  *uint  `json:"version,omitempty"` // как If-Match у PATCH
+	Position *int64 `json:"position,omitempty"`
+}
+
+// message - ответ на команду или событие
+type message struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	EventID string          `json:"event_id,omitempty"`
+	TaskID  uint            `json:"task_id,omitempty"`
+	Code    string          `json:"code,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Task    *task           `json:"task,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// snapshot - задачи темы в ответ на подписку
+type snapshot struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Topic string `json:"topic"`
+	Tasks []task `json:"tasks"`
+}
+
+// task - задача в том же виде, что в ответах апи
+type task struct {
+	ID           uint   `json:"id"`
+	Task         string `json:"task"`
+	IsDone       bool   `json:"is_done"`
+	UserId       uint   `json:"user_id"`
+	Version      uint   `json:"version"`
+	Position     int64  `json:"position"`
+	CommentCount int64  `json:"comment_count"`
+	AssigneeIDs  []uint `json:"assignee_ids,omitempty"`
+	WorkspaceID  *uint  `json:"workspace_id,omitempty"`
+}
+
+func toTask(t taskService.Task) task {
+	result := task{
+		ID:           t.ID,
+		Task:         t.Task,
+		UserId:       t.UserId,
+		Version:      t.Version,
+		Position:     t.Position,
+		CommentCount: t.CommentCount,
+		AssigneeIDs:  t.Assignees,
+		WorkspaceID:  t.WorkspaceID,
+	}
+	if t.IsDone != nil {
+		result.IsDone = *t.IsDone
+	}
+	return result
+}
+
+// topic - разобранная тема подписки
+type topic struct {
+	kind string
+	id   uint
+}
+
+func parseTopic(s string) (topic, error) {
+	kind, rawID, _ := strings.Cut(s, ":")
+	id, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil || id == 0 || kind != TopicWorkspace && kind != TopicUser {
+		return topic{}, fmt.Errorf("invalid topic %q", s)
+	}
+	return topic{kind: kind, id: uint(id)}, nil
+}
+
+// matches - относится ли событие к теме
+func (t topic) matches(ev events.Event) bool {
+	switch t.kind {
+	case TopicWorkspace:
+		return ev.WorkspaceID != nil && *ev.WorkspaceID == t.id
+	case TopicUser:
+		if ev.OwnerID == t.id {
+			return true
+		}
+		for _, id := range ev.AssigneeIDs {
+			if id == t.id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authn.FromContext(r.Context())
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade уже ответил клиенту ошибкой
+	}
+	sub, _, _ := h.bus.Subscribe("")
+
+	c := &client{
+		h:        h,
+		conn:     conn,
+		actor:    authn.Actor(r.Context()),
+		canWrite: principal.HasScope(rbac.ScopeTasksWrite),
+		send:     make(chan any, sendBuffer),
+		slow:     make(chan struct{}),
+		done:     make(chan struct{}),
+		topics:   make(map[topic]struct{}),
+	}
+	go c.readLoop()
+	c.writeLoop(sub)
+}
+
+// client - одно соединение
+// читает и выполняет команды readLoop, а пишет в соединение только writeLoop
+type client struct {
+	h        *Handler
+	conn     *websocket.Conn
+	actor    rbac.Actor
+	canWrite bool
+
+	send     chan any      // ответы на команды
+	slow     chan struct{} // закрывается, когда очередь ответов переполнилась
+	slowOnce sync.Once
+	done     chan struct{} // закрывается, когда readLoop завершился
+
+	mu     sync.Mutex
+	topics map[topic]struct{}
+}
+
+// readLoop - читает сообщения клиента, пока соединение живо
+func (c *client) readLoop() {
+	defer close(c.done)
+
+	pongWait := 2 * c.h.pingPeriod
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		kind, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req request
+		if kind != websocket.TextMessage || json.Unmarshal(data, &req) != nil {
+			c.reply(message{Type: TypeError, Code: CodeInvalid, Message: "message must be a JSON object"})
+			continue
+		}
+		c.handle(req)
+	}
+}
+
+// writeLoop - отправляет ответы, события и ping, пока соединение живо
+func (c *client) writeLoop(sub *events.Subscription) {
+	defer c.conn.Close()
+	defer sub.Close()
+
+	ping := time.NewTicker(c.h.pingPeriod)
+	defer ping.Stop()
+	maxAge := time.NewTimer(c.h.maxAge)
+	defer maxAge.Stop()
+
+	for {
+		var err error
+		select {
+		case <-c.done:
+			return
+		case <-c.slow:
+			c.close(websocket.CloseTryAgainLater, "client is too slow")
+			return
+		case <-maxAge.C:
+			c.close(websocket.CloseNormalClosure, "connection expired, reconnect")
+			return
+		case <-ping.C:
+			err = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+		case msg := <-c.send:
+			err = c.write(msg)
+		case ev, ok := <-sub.C:
+			if !ok {
+				// не успевали забирать события - шина отключила
+				c.close(websocket.CloseTryAgainLater, "client is too slow")
+				return
+			}
+			if c.wants(ev) {
+				err = c.write(eventMessage(ev))
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) write(msg any) error {
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteJSON(msg)
+}
+
+// close - закрывает соединение с кодом (клиент узнает, почему)
+func (c *client) close(code int, reason string) {
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
+
+// reply - ставит ответ в очередь; очередь переполнена - клиент не читает, отключаем
+func (c *client) reply(msg any) {
+	select {
+	case c.send <- msg:
+	default:
+		c.slowOnce.Do(func() { close(c.slow) })
+	}
+}
+
+// wants - нужно ли событие клиенту: оно из темы, на которую он подписан, и задача ему видна
+// reset получают все подписанные - пропущенное уже не восстановить
+func (c *client) wants(ev events.Event) bool {
+	c.mu.Lock()
+	subscribed := false
+	for t := range c.topics {
+		if ev.Type == events.Reset || t.matches(ev) {
+			subscribed = true
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	return subscribed && (ev.Type == events.Reset || c.h.tasks.CanSeeEvent(c.actor, ev))
+}
+
+// eventMessage - событие для клиента; без тела (не влезло в NOTIFY) - только id задачи
+func eventMessage(ev events.Event) message {
+	if ev.Type == events.Reset {
+		return message{Type: events.Reset}
+	}
+	data := ev.Data
+	if len(data) == 0 {
+		data = json.RawMessage(fmt.Sprintf(`{"id":%d}`, ev.TaskID))
+	}
+	return message{Type: ev.Type, EventID: ev.ID, TaskID: ev.TaskID, Data: data}
+}
+
+// handle - выполняет команду клиента
+func (c *client) handle(req request) {
+	switch req.Type {
+	case TypeSubscribe:
+		c.subscribe(req)
+	case TypeUnsubscribe:
+		t, err := parseTopic(req.Topic)
+		if err != nil {
+			c.replyError(req, err)
+			return
+		}
+		c.mu.Lock()
+		delete(c.topics, t)
+		c.mu.Unlock()
+		c.reply(message{Type: TypeAck, ID: req.ID})
+	case TypeToggleDone, TypeReorder:
+		c.command(req)
+	default:
+		c.reply(message{Type: TypeError, ID: req.ID, Code: CodeInvalid, Message: fmt.Sprintf("unknown message type %q", req.Type)})
+	}
+}
+
+// subscribe - подписка на тему и ее текущие задачи
+// тему регистрируем до чтения задач: изменение между чтением и подпиской придет событием, а не потеряется
+func (c *client) subscribe(req request) {
+	t, err := parseTopic(req.Topic)
+	if err != nil {
+		c.replyError(req, err)
+		return
+	}
+
+	c.mu.Lock()
+	_, already := c.topics[t]
+	if !already && len(c.topics) >= maxTopics {
+		c.mu.Unlock()
+		c.replyError(req, errors.New("too many subscriptions"))
+		return
+	}
+	c.topics[t] = struct{}{}
+	c.mu.Unlock()
+
+	var tasks []taskService.Task
+	if t.kind == TopicWorkspace {
+		tasks, err = c.h.tasks.GetWorkspaceTasks(c.actor, t.id)
+	} else {
+		tasks, err = c.h.users.GetTasksForUser(c.actor, t.id, "")
+	}
+	if err != nil {
+		if !already {
+			c.mu.Lock()
+			delete(c.topics, t)
+			c.mu.Unlock()
+		}
+		c.replyError(req, err)
+		return
+	}
+
+	result := snapshot{Type: TypeSnapshot, ID: req.ID, Topic: req.Topic, Tasks: make([]task, 0, len(tasks))}
+	for _, item := range tasks {
+		result.Tasks = append(result.Tasks, toTask(item))
+	}
+	c.reply(result)
+}
+
+// command - toggle_done и reorder: то же, что PATCH /tasks/{id}
+func (c *client) command(req request) {
+	if !c.canWrite {
+		c.replyError(req, errors.New("forbidden"))
+		return
+	}
+	if req.TaskID == 0 {
+		c.replyError(req, errors.New("task_id is required"))
+		return
+	}
+
+	var params taskService.UpdateTaskParams
+	version := req.Version
+	switch req.Type {
+	case TypeToggleDone:
+		current, err := c.h.tasks.GetTask(c.actor, req.TaskID)
+		if err != nil {
+			c.replyError(req, err)
+			return
+		}
+		// без версии от клиента переключаем именно то состояние, которое прочитали:
+		// если задачу успели изменить, вернется version_mismatch, а не обратное переключение
+		if version == nil {
+			version = &current.Version
+		}
+		isDone := current.IsDone == nil || !*current.IsDone
+		params.IsDone = &isDone
+	case TypeReorder:
+		if req.Position == nil {
+			c.replyError(req, errors.New("position is required"))
+			return
+		}
+		params.Position = req.Position
+	}
+
+	updated, err := c.h.tasks.UpdateTask(c.actor, req.TaskID, version, params)
+	if err != nil {
+		c.replyError(req, err)
+		return
+	}
+	result := toTask(*updated)
+	c.reply(message{Type: TypeAck, ID: req.ID, Task: &result})
+}
+
+// replyError - ошибка сервиса в ответ клиенту (коды - как статусы у PATCH /tasks/{id})
+func (c *client) replyError(req request, err error) {
+	code := CodeInternal
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		code = CodeNotFound
+	case strings.Contains(err.Error(), "forbidden") ||
+		strings.Contains(err.Error(), "user is not a workspace member"):
+		code = CodeForbidden
+	case strings.Contains(err.Error(), "version mismatch"):
+		code = CodeVersionMismatch
+	case strings.Contains(err.Error(), "invalid topic") ||
+		strings.Contains(err.Error(), "too many subscriptions") ||
+		strings.Contains(err.Error(), "is required") ||
+		strings.Contains(err.Error(), "position cannot be negative") ||
+		strings.Contains(err.Error(), "no fields to update"):
+		code = CodeInvalid
+	}
+
+	text := err.Error()
+	if code == CodeInternal {
+		log.Printf("[WS] %s failed for user %d: %v", req.Type, c.actor.UserID, err)
+		text = "internal error"
+	}
+	c.reply(message{Type: TypeError, ID: req.ID, Code: code, Message: text})
+}
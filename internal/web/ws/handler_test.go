@@ -0,0 +1,308 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/authService"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/taskService"
+	"github.com/AntonRadchenko/WebPet1/internal/web/authn"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTasks - задачи в памяти; пользователь видит только свои задачи и задачи пространства 3
+type fakeTasks struct {
+	mu    sync.Mutex
+	tasks map[uint]*taskService.Task
+}
+
+func newFakeTasks() *fakeTasks {
+	ws := uint(3)
+	done := false
+	return &fakeTasks{tasks: map[uint]*taskService.Task{
+		7: {ID: 7, Task: "Task", IsDone: &done, UserId: 1, WorkspaceID: &ws, Version: 1},
+	}}
+}
+
+func (f *fakeTasks) CanSeeEvent(actor rbac.Actor, ev events.Event) bool {
+	return ev.OwnerID == actor.UserID
+}
+
+func (f *fakeTasks) GetWorkspaceTasks(actor rbac.Actor, workspaceID uint) ([]taskService.Task, error) {
+	if workspaceID != 3 {
+		return nil, errors.New("workspace not found")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return []taskService.Task{*f.tasks[7]}, nil
+}
+
+func (f *fakeTasks) GetTask(actor rbac.Actor, id uint) (*taskService.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.tasks[id]
+	if !ok || t.UserId != actor.UserID {
+		return nil, errors.New("task not found")
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (f *fakeTasks) UpdateTask(actor rbac.Actor, id uint, version *uint, params taskService.UpdateTaskParams) (*taskService.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.tasks[id]
+	if !ok || t.UserId != actor.UserID {
+		return nil, errors.New("task not found")
+	}
+	if version != nil && *version != t.Version {
+		return nil, errors.New("version mismatch")
+	}
+	if params.Position != nil && *params.Position < 0 {
+		return nil, errors.New("position cannot be negative")
+	}
+	if params.IsDone != nil {
+		isDone := *params.IsDone
+		t.IsDone = &isDone
+	}
+	if params.Position != nil {
+		t.Position = *params.Position
+	}
+	t.Version++
+	copied := *t
+	return &copied, nil
+}
+
+// fakeUsers - пользователь видит только свои задачи
+type fakeUsers struct{}
+
+func (fakeUsers) GetTasksForUser(actor rbac.Actor, userID uint, role string) ([]taskService.Task, error) {
+	if userID != actor.UserID {
+		return nil, errors.New("user not found")
+	}
+	return []taskService.Task{}, nil
+}
+
+// recordingBus - шина, которая запоминает подписки (чтобы отключить «медленного» клиента)
+type recordingBus struct {
+	*events.Bus
+	subs chan *events.Subscription
+}
+
+func (b *recordingBus) Subscribe(lastEventID string) (*events.Subscription, []events.Event, bool) {
+	sub, replay, complete := b.Bus.Subscribe(lastEventID)
+	b.subs <- sub
+	return sub, replay, complete
+}
+
+// startServer - сервер с хендлером; пользователь - из заголовка X-User, скоупы - из X-Scopes (как будто их проверил authn)
+func startServer(t *testing.T, h *Handler) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User") != "" {
+			id, err := strconv.ParseUint(r.Header.Get("X-User"), 10, 64)
+			require.NoError(t, err)
+			principal := &authService.Principal{UserID: uint(id), Role: rbac.RoleUser}
+			if scopes := r.Header.Get("X-Scopes"); scopes != "" {
+				principal.TokenID = 1
+				principal.Scopes = strings.Split(scopes, ",")
+			}
+			r = r.WithContext(authn.WithPrincipal(r.Context(), principal))
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dial - открывает соединение от имени пользователя
+func dial(t *testing.T, server *httptest.Server, user, scopes string) *websocket.Conn {
+	header := http.Header{"X-User": {user}}
+	if scopes != "" {
+		header.Set("X-Scopes", scopes)
+	}
+	dialer := websocket.Dialer{Subprotocols: []string{Subprotocol}}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	assert.Equal(t, Subprotocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	return conn
+}
+
+// roundTrip - отправляет сообщение и читает ответ
+func roundTrip(t *testing.T, conn *websocket.Conn, req string) map[string]any {
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+	return read(t, conn)
+}
+
+func read(t *testing.T, conn *websocket.Conn) map[string]any {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg map[string]any
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestHandlerSubscribe(t *testing.T) {
+	bus := events.NewBus(10)
+	server := startServer(t, NewHandler(bus, newFakeTasks(), fakeUsers{}))
+	conn := dial(t, server, "1", "")
+
+	// подписка отдает текущие задачи темы
+	msg := roundTrip(t, conn, `{"id":"s1","type":"subscribe","topic":"workspace:3"}`)
+	assert.Equal(t, TypeSnapshot, msg["type"])
+	assert.Equal(t, "s1", msg["id"])
+	require.Len(t, msg["tasks"], 1)
+	assert.Equal(t, float64(7), msg["tasks"].([]any)[0].(map[string]any)["id"])
+
+	// чужое пространство и чужой пользователь - ошибка, подписки нет
+	msg = roundTrip(t, conn, `{"id":"s2","type":"subscribe","topic":"workspace:4"}`)
+	assert.Equal(t, map[string]any{"type": TypeError, "id": "s2", "code": CodeNotFound, "message": "workspace not found"}, msg)
+	msg = roundTrip(t, conn, `{"id":"s3","type":"subscribe","topic":"user:2"}`)
+	assert.Equal(t, CodeNotFound, msg["code"])
+	msg = roundTrip(t, conn, `{"id":"s4","type":"subscribe","topic":"project:1"}`)
+	assert.Equal(t, CodeInvalid, msg["code"])
+
+	ws, other := uint(3), uint(4)
+	bus.Publish(events.Event{ID: "e1", Type: events.TaskUpdated, TaskID: 8, OwnerID: 1, WorkspaceID: &other})
+	bus.Publish(events.Event{ID: "e2", Type: events.TaskUpdated, TaskID: 9, OwnerID: 2, WorkspaceID: &ws})
+	bus.Publish(events.Event{ID: "e3", Type: events.TaskUpdated, TaskID: 7, OwnerID: 1, WorkspaceID: &ws, Data: json.RawMessage(`{"id":7,"is_done":true}`)})
+	bus.Publish(events.Event{ID: "e4", Type: events.TaskDeleted, TaskID: 7, OwnerID: 1, WorkspaceID: &ws})
+
+	// e1 - не из темы, e2 - задача, которую пользователь не видит
+	msg = read(t, conn)
+	assert.Equal(t, map[string]any{"type": events.TaskUpdated, "event_id": "e3", "task_id": float64(7),
+		"data": map[string]any{"id": float64(7), "is_done": true}}, msg)
+	// событие без тела - только id задачи
+	msg = read(t, conn)
+	assert.Equal(t, "e4", msg["event_id"])
+	assert.Equal(t, map[string]any{"id": float64(7)}, msg["data"])
+
+	// после отписки события темы не приходят
+	msg = roundTrip(t, conn, `{"id":"u1","type":"unsubscribe","topic":"workspace:3"}`)
+	assert.Equal(t, map[string]any{"type": TypeAck, "id": "u1"}, msg)
+	bus.Publish(events.Event{ID: "e5", Type: events.TaskUpdated, TaskID: 7, OwnerID: 1, WorkspaceID: &ws})
+	msg = roundTrip(t, conn, `{"id":"x","type":"dance"}`)
+	assert.Equal(t, TypeError, msg["type"])
+	assert.Equal(t, "x", msg["id"])
+}
+
+func TestHandlerCommands(t *testing.T) {
+	tasks := newFakeTasks()
+	server := startServer(t, NewHandler(events.NewBus(10), tasks, fakeUsers{}))
+	conn := dial(t, server, "1", "")
+
+	tests := []struct {
+		name     string
+		req      string
+		wantCode string
+		wantDone bool
+		wantPos  float64
+	}{
+		{name: "отметить выполненной", req: `{"id":"1","type":"toggle_done","task_id":7}`, wantDone: true},
+		{name: "снять отметку", req: `{"id":"2","type":"toggle_done","task_id":7}`, wantDone: false},
+		{name: "устаревшая версия", req: `{"id":"3","type":"toggle_done","task_id":7,"version":1}`, wantCode: CodeVersionMismatch},
+		{name: "перенести на доске", req: `{"id":"4","type":"reorder","task_id":7,"position":1024}`, wantPos: 1024},
+		{name: "отрицательная позиция", req: `{"id":"5","type":"reorder","task_id":7,"position":-1}`, wantCode: CodeInvalid},
+		{name: "без позиции", req: `{"id":"6","type":"reorder","task_id":7}`, wantCode: CodeInvalid},
+		{name: "без задачи", req: `{"id":"7","type":"toggle_done"}`, wantCode: CodeInvalid},
+		{name: "чужая задача", req: `{"id":"8","type":"toggle_done","task_id":9}`, wantCode: CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := roundTrip(t, conn, tt.req)
+			if tt.wantCode != "" {
+				assert.Equal(t, TypeError, msg["type"])
+				assert.Equal(t, tt.wantCode, msg["code"])
+				return
+			}
+			require.Equal(t, TypeAck, msg["type"], msg)
+			task := msg["task"].(map[string]any)
+			assert.Equal(t, tt.wantDone, task["is_done"])
+			assert.Equal(t, tt.wantPos, task["position"])
+		})
+	}
+
+	// персональному токену без tasks:write команды запрещены
+	readOnly := dial(t, server, "1", rbac.ScopeTasksRead)
+	msg := roundTrip(t, readOnly, `{"id":"1","type":"toggle_done","task_id":7}`)
+	assert.Equal(t, CodeForbidden, msg["code"])
+	msg = roundTrip(t, readOnly, `not json`)
+	assert.Equal(t, CodeInvalid, msg["code"])
+}
+
+func TestHandlerHeartbeat(t *testing.T) {
+	server := startServer(t, NewHandler(events.NewBus(10), newFakeTasks(), fakeUsers{}).WithTimeouts(20*time.Millisecond, time.Hour))
+
+	// клиент читает (и этим отвечает на ping) - соединение живет дольше двух периодов
+	alive := dial(t, server, "1", "")
+	received := make(chan map[string]any, 1)
+	go func() {
+		for {
+			var msg map[string]any
+			if err := alive.ReadJSON(&msg); err != nil {
+				close(received)
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	// клиент не читает - pong не приходят, сервер закрывает соединение
+	silent := dial(t, server, "1", "")
+	time.Sleep(200 * time.Millisecond)
+	silent.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := silent.ReadMessage()
+	var netErr net.Error
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "connection was not closed: %v", err)
+
+	require.NoError(t, alive.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","type":"subscribe","topic":"user:1"}`)))
+	select {
+	case msg, ok := <-received:
+		require.True(t, ok, "connection was closed")
+		assert.Equal(t, TypeSnapshot, msg["type"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("no snapshot")
+	}
+}
+
+func TestHandlerSlowClient(t *testing.T) {
+	bus := &recordingBus{Bus: events.NewBus(10), subs: make(chan *events.Subscription, 1)}
+	server := startServer(t, NewHandler(bus, newFakeTasks(), fakeUsers{}))
+	conn := dial(t, server, "1", "")
+
+	// шина отключает подписчика, который отстал
+	(<-bus.subs).Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), err)
+}
+
+func TestHandlerMaxAge(t *testing.T) {
+	server := startServer(t, NewHandler(events.NewBus(10), newFakeTasks(), fakeUsers{}).WithTimeouts(time.Hour, 50*time.Millisecond))
+	conn := dial(t, server, "1", "")
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+}
+
+func TestHandlerRequiresUser(t *testing.T) {
+	server := startServer(t, NewHandler(events.NewBus(10), newFakeTasks(), fakeUsers{}))
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
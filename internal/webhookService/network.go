@@ -0,0 +1,85 @@
+package webhookService
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// защита от SSRF: адрес вебхука задает пользователь, а запрос уходит изнутри нашей сети
+//   • validateURL сразу отказывает адресам вида http://127.0.0.1, http://10.0.0.5, http://localhost
+//   • но имя может резолвиться во внутренний адрес (или начать резолвиться позже - DNS rebinding),
+//     поэтому главная проверка - при соединении: Control видит уже выбранный IP
+//   • прокси из окружения не используем: через него запрос ушел бы куда угодно в обход проверки
+
+var errBlockedAddress = errors.New("address is not allowed")
+
+// сети, которые не покрывают методы netip.Addr:
+//   - 100.64.0.0/10 - общее адресное пространство провайдеров (CGNAT)
+//   - 0.0.0.0/8 - "эта сеть": на многих системах соединение с 0.x.x.x уходит на локальную машину
+//   - 64:ff9b::/96 - NAT64: за таким адресом может оказаться любой IPv4, в том числе внутренний
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// isBlockedAddr - loopback, частные, link-local, unique-local (fc00::/7), multicast, 0.0.0.0/:: и blockedPrefixes
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap() // ::ffff:127.0.0.1 - тот же loopback
+	return !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		inBlockedPrefix(addr)
+}
+
+func inBlockedPrefix(addr netip.Addr) bool {
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlockedHost - хост из URL, который точно внутренний (IP или localhost); имена проверяются при соединении
+func isBlockedHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && isBlockedAddr(addr)
+}
+
+// dialControl - вызывается перед каждым соединением с уже резолвленным адресом
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || isBlockedAddr(addrPort.Addr()) {
+		return errBlockedAddress
+	}
+	return nil
+}
+
+// newTransport - транспорт для отправки вебхуков (control nil - без проверки адресов, только для тестов)
+func newTransport(control func(network, address string, c syscall.RawConn) error) *http.Transport {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second, Control: control}
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
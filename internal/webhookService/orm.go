@@ -0,0 +1,55 @@
+package webhookService
+
+import "time"
+
+// модель базы данных: вебхук пользователя
+type WebhookStruct struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    uint   `gorm:"not null"`
+	URL       string `gorm:"column:url;not null"`
+	Events    string `gorm:"not null"` // типы событий через пробел (как скоупы у персональных токенов)
+	Secret    string `gorm:"not null"` // ключ подписи, зашифрован (encryption.Cipher)
+	Active    bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (WebhookStruct) TableName() string {
+	return "webhooks" // как в миграции
+}
+
+// доставка: событие для одного вебхука; она же запись в очереди (outbox), пока status = pending
+type DeliveryStruct struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	WebhookID      uint   `gorm:"not null"`
+	EventID        string `gorm:"not null"`
+	EventType      string `gorm:"not null"`
+	Payload        string `gorm:"not null"` // тело запроса - одинаковое во всех попытках
+	Status         string `gorm:"not null"` // Delivery*
+	Attempts       int    `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int   // nil - ответа не было
+	LastError      string `gorm:"not null;default:''"`
+	CreatedAt      time.Time
+
+	Webhook WebhookStruct `gorm:"foreignKey:WebhookID"` // заполняет ClaimDue: куда и с каким ключом отправлять
+}
+
+func (DeliveryStruct) TableName() string {
+	return "webhook_deliveries" // как в миграции
+}
+
+// попытка отправки (журнал доставки)
+type AttemptStruct struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	DeliveryID uint   `gorm:"not null"`
+	StatusCode *int   // nil - ответа не было (таймаут, соединение не установлено, адрес запрещен)
+	Error      string `gorm:"not null;default:''"` // коротко, без тела ответа
+	DurationMs int64  `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+func (AttemptStruct) TableName() string {
+	return "webhook_delivery_attempts" // как в миграции
+}
@@ -0,0 +1,201 @@
+package webhookService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 2. repo-слой вебхуков (только работа с бд)
+// очередь доставок живет в таблице webhook_deliveries: несколько экземпляров приложения разбирают ее
+// вместе - ClaimDue берет строки через FOR UPDATE SKIP LOCKED и сдвигает им время следующей попытки,
+// так что одну доставку не отправят двое одновременно
+
+type WebhookRepoInterface interface {
+	Create(webhook *WebhookStruct) (*WebhookStruct, error)
+	GetByID(id uint) (WebhookStruct, error)
+	GetForUser(userID uint) ([]WebhookStruct, error)
+	Update(webhook *WebhookStruct, fields []string) (*WebhookStruct, error)
+	Delete(id uint) error
+	GetActiveForEvent(tx *gorm.DB, userIDs []uint, workspaceID *uint) ([]WebhookStruct, error)
+	CreateDeliveries(tx *gorm.DB, deliveries []DeliveryStruct) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]DeliveryStruct, error)
+	RecordAttempt(delivery *DeliveryStruct, attempt *AttemptStruct) error
+	GetDeliveries(webhookID uint, limit int) ([]DeliveryStruct, error)
+	GetDelivery(id uint) (DeliveryStruct, error)
+	GetAttempts(deliveryID uint) ([]AttemptStruct, error)
+	Redeliver(id uint, at time.Time) (*DeliveryStruct, error)
+}
+
+type WebhookRepo struct{}
+
+// Create - сохраняет вебхук
+func (r *WebhookRepo) Create(webhook *WebhookStruct) (*WebhookStruct, error) {
+	if err := db.DB.Create(webhook).Error; err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetByID - вебхук по ID
+func (r *WebhookRepo) GetByID(id uint) (WebhookStruct, error) {
+	var webhook WebhookStruct
+	err := db.DB.First(&webhook, "id = ?", id).Error
+	return webhook, err
+}
+
+// GetForUser - вебхуки пользователя (сначала старые)
+func (r *WebhookRepo) GetForUser(userID uint) ([]WebhookStruct, error) {
+	var webhooks []WebhookStruct
+	err := db.DB.Where("user_id = ?", userID).Order("id").Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Update - обновляет только переданные колонки
+func (r *WebhookRepo) Update(webhook *WebhookStruct, fields []string) (*WebhookStruct, error) {
+	err := db.DB.Model(webhook).Select(append(fields, "updated_at")).Updates(webhook).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete - удаляет вебхук вместе с его доставками
+func (r *WebhookRepo) Delete(id uint) error {
+	res := db.DB.Delete(&WebhookStruct{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// GetActiveForEvent - включенные вебхуки тех, кто может видеть задачу: пользователей userIDs
+// и участников ее пространства (точную проверку прав делает сервис)
+func (r *WebhookRepo) GetActiveForEvent(tx *gorm.DB, userIDs []uint, workspaceID *uint) ([]WebhookStruct, error) {
+	query := tx.Where("active")
+	if workspaceID != nil {
+		query = query.Where("(user_id IN ? OR user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = ?))", userIDs, *workspaceID)
+	} else {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+
+	var webhooks []WebhookStruct
+	if err := query.Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateDeliveries - ставит доставки в очередь (tx - транзакция, в которой меняется задача)
+func (r *WebhookRepo) CreateDeliveries(tx *gorm.DB, deliveries []DeliveryStruct) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Omit("Webhook").Create(&deliveries).Error
+}
+
+// ClaimDue - забирает до limit доставок, которым пора уходить, вместе с их вебхуками
+// забранным доставкам следующая попытка сдвигается на lease: если экземпляр упадет посреди отправки,
+// доставку подберут после lease (получатель может получить ее дважды - отличит по id события)
+func (r *WebhookRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]DeliveryStruct, error) {
+	var deliveries []DeliveryStruct
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Where("webhook_id IN (SELECT id FROM webhooks WHERE active)"). // выключенный вебхук - доставки ждут
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		return tx.Model(&DeliveryStruct{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		if err := db.DB.First(&deliveries[i].Webhook, "id = ?", deliveries[i].WebhookID).Error; err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt - записывает попытку в журнал и новое состояние доставки
+func (r *WebhookRepo) RecordAttempt(delivery *DeliveryStruct, attempt *AttemptStruct) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&DeliveryStruct{ID: delivery.ID}).Updates(map[string]any{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_attempt_at":  delivery.LastAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+		}).Error
+	})
+}
+
+// GetDeliveries - последние доставки вебхука (сначала новые)
+func (r *WebhookRepo) GetDeliveries(webhookID uint, limit int) ([]DeliveryStruct, error) {
+	var deliveries []DeliveryStruct
+	err := db.DB.Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDelivery - доставка по ID
+func (r *WebhookRepo) GetDelivery(id uint) (DeliveryStruct, error) {
+	var delivery DeliveryStruct
+	err := db.DB.First(&delivery, "id = ?", id).Error
+	return delivery, err
+}
+
+// GetAttempts - попытки доставки по порядку
+func (r *WebhookRepo) GetAttempts(deliveryID uint) ([]AttemptStruct, error) {
+	var attempts []AttemptStruct
+	err := db.DB.Where("delivery_id = ?", deliveryID).Order("id").Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// Redeliver - возвращает доставку в очередь с новым счетчиком попыток (журнал прежних попыток остается)
+func (r *WebhookRepo) Redeliver(id uint, at time.Time) (*DeliveryStruct, error) {
+	res := db.DB.Model(&DeliveryStruct{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": DeliveryPending, "attempts": 0, "next_attempt_at": at})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, errors.New("delivery not found")
+	}
+
+	delivery, err := r.GetDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,677 @@
+package webhookService
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/encryption"
+	"github.com/AntonRadchenko/WebPet1/internal/events"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"gorm.io/gorm"
+)
+
+// 3. service-слой вебхуков
+// пользователь подписывает свой URL на события задач (task.created, task.updated, task.completed, task.deleted)
+//   • вебхук получает события только тех задач, которые его владелец видит как обычный пользователь
+//     (права - как у GET /events; админ через вебхук чужие задачи не получает)
+//   • событие не отправляется сразу: Enqueue кладет по доставке на каждый вебхук в таблицу-очередь (outbox)
+//     той же транзакцией, что меняет задачу (зовет taskService.TaskRepo), - упади процесс сразу после коммита,
+//     событие не потеряется; RunDeliveries разбирает очередь в фоне - медленный получатель не тормозит апи
+//   • неудачная попытка (нет ответа или ответ не 2xx) повторяется через backoff, 2*backoff, 4*backoff...
+//     (не больше maxBackoff); после maxAttempts попыток доставка помечается failed
+//   • журнал: у доставки - статус и последний ответ, у каждой попытки - код, ошибка и время;
+//     тело ответа не сохраняем - иначе вебхук читал бы чужие ответы через журнал. Любую доставку можно отправить заново (Redeliver)
+//   • во внутреннюю сеть (loopback, частные адреса, метаданные облака) вебхук не отправляется - см. network.go
+//   • без ключа шифрования (WEBHOOK_ENCRYPTION_KEY) вебхуки выключены: все методы отвечают "webhooks are not configured"
+// подпись: секрет показывается один раз при создании вебхука, в бд хранится зашифрованным
+//   X-Webhook-Timestamp: <unix-время отправки>
+//   X-Webhook-Signature: sha256=<hex HMAC-SHA256(секрет, timestamp + "." + тело)>
+// получатель проверяет подпись (VerifySignature) и что timestamp свежий - тогда перехваченный запрос не повторить
+
+// настройки по умолчанию
+const (
+	DefaultMaxAttempts  = 10
+	DefaultTimeout      = 10 * time.Second
+	DefaultPollInterval = 5 * time.Second
+	DefaultBackoff      = 30 * time.Second
+)
+
+// статусы доставки
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// заголовки запроса к получателю
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	maxBackoff          = 6 * time.Hour
+	deliveryBatch       = 20              // сколько доставок забирать за раз
+	deliveryLease       = 5 * time.Minute // за это время экземпляр должен успеть отправить забранное
+	maxErrorLength      = 200             // сколько символов ошибки сохранять в журнал
+	maxWebhooksPerUser  = 20
+	maxURLLength        = 2048
+	secretPrefix        = "whsec_"
+	secretBytes         = 32
+	defaultDeliveries   = 50
+	maxDeliveriesPerGet = 100
+)
+
+// EventTypes - события, на которые можно подписать вебхук
+var EventTypes = []string{events.TaskCreated, events.TaskUpdated, events.TaskCompleted, events.TaskDeleted}
+
+// структура параметров метода CreateWebhook
+type CreateWebhookParams struct {
+	URL    string
+	Events []string
+}
+
+// структура параметров метода UpdateWebhook (nil - не менять)
+type UpdateWebhookParams struct {
+	URL    *string
+	Events []string
+	Active *bool
+}
+
+// бизнес-модель вебхука (без секрета)
+type Webhook struct {
+	ID        uint
+	UserID    uint
+	URL       string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CreatedWebhook - только что созданный вебхук вместе с секретом (отдается клиенту один раз)
+type CreatedWebhook struct {
+	Webhook
+	Secret string
+}
+
+// доставка события
+type Delivery struct {
+	ID             uint
+	WebhookID      uint
+	EventID        string
+	EventType      string
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time // только у ожидающей доставки
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      string
+	CreatedAt      time.Time
+	Payload        string    // заполняется в GetDelivery
+	Log            []Attempt // заполняется в GetDelivery
+}
+
+// попытка отправки
+type Attempt struct {
+	StatusCode *int
+	Error      string
+	DurationMs int64
+	At         time.Time
+}
+
+// Visibility - видит ли пользователь задачу из события (реализует taskService.TaskService)
+type Visibility interface {
+	CanSeeEvent(actor rbac.Actor, ev events.Event) bool
+}
+
+type WebhookService struct {
+	repo        WebhookRepoInterface
+	cipher      *encryption.Cipher // nil - вебхуки выключены (WEBHOOK_ENCRYPTION_KEY не задан)
+	tasks       Visibility         // nil - события в очередь не ставятся
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+
+	now func() time.Time // подменяется в тестах
+}
+
+func NewWebhookService(r WebhookRepoInterface, cipher *encryption.Cipher) *WebhookService {
+	return &WebhookService{
+		repo:   r,
+		cipher: cipher,
+		client: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: newTransport(dialControl),
+			// редирект не повторяем: подписанный запрос уходит только на адрес, который задал пользователь
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+		now:         time.Now,
+	}
+}
+
+// WithVisibility - по чьим правам решать, кому положено событие
+func (s *WebhookService) WithVisibility(v Visibility) *WebhookService {
+	s.tasks = v
+	return s
+}
+
+// WithDelivery - сколько раз пытаться доставить событие и сколько ждать ответа (из конфига)
+func (s *WebhookService) WithDelivery(maxAttempts int, timeout time.Duration) *WebhookService {
+	s.maxAttempts = maxAttempts
+	s.client.Timeout = timeout
+	return s
+}
+
+// toWebhook - маппит бд-модель в бизнес-модель
+func toWebhook(w *WebhookStruct) *Webhook {
+	return &Webhook{
+		ID:        w.ID,
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Events:    strings.Fields(w.Events),
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// toDelivery - маппит доставку в бизнес-модель (без тела и журнала)
+func toDelivery(d *DeliveryStruct) *Delivery {
+	delivery := &Delivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == DeliveryPending {
+		next := d.NextAttemptAt
+		delivery.NextAttemptAt = &next
+	}
+	return delivery
+}
+
+// parseEvents - проверяет типы событий, убирает повторы и сортирует
+func parseEvents(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return nil, errors.New("at least one event is required")
+	}
+	result := make([]string, 0, len(types))
+	for _, t := range types {
+		if !slices.Contains(EventTypes, t) {
+			return nil, fmt.Errorf("invalid event: %q", t)
+		}
+		result = append(result, t)
+	}
+	slices.Sort(result)
+	return slices.Compact(result), nil
+}
+
+// validateURL - адрес получателя: абсолютный http(s) и не во внутренней сети
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || len(raw) > maxURLLength || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" || u.User != nil {
+		return "", errors.New("invalid url")
+	}
+	if isBlockedHost(u.Hostname()) {
+		return "", errors.New("url points to a private address")
+	}
+	return raw, nil
+}
+
+// secretAAD - привязывает зашифрованный секрет к владельцу вебхука
+func secretAAD(userID uint) []byte {
+	return []byte("webhook:" + strconv.FormatUint(uint64(userID), 10))
+}
+
+// Sign - подпись тела запроса: hex HMAC-SHA256(secret, timestamp + "." + body)
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature - проверка заголовка X-Webhook-Signature на стороне получателя
+func VerifySignature(secret, timestamp string, body []byte, header string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	return ok && hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// getWebhook - вебхук, которым actor может управлять (свой; админу - любой)
+func (s *WebhookService) getWebhook(actor rbac.Actor, id uint) (WebhookStruct, error) {
+	if s.cipher == nil {
+		return WebhookStruct{}, errors.New("webhooks are not configured")
+	}
+	if actor.UserID == 0 {
+		return WebhookStruct{}, errors.New("webhook not found")
+	}
+	webhook, err := s.repo.GetByID(id)
+	if err != nil || webhook.ID == 0 || !actor.CanAccessUser(webhook.UserID) {
+		return WebhookStruct{}, errors.New("webhook not found")
+	}
+	return webhook, nil
+}
+
+// CreateWebhook - создает вебхук; секрет для проверки подписи возвращается только здесь
+func (s *WebhookService) CreateWebhook(actor rbac.Actor, params CreateWebhookParams) (*CreatedWebhook, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+	if s.cipher == nil {
+		return nil, errors.New("webhooks are not configured")
+	}
+
+	target, err := validateURL(params.URL)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseEvents(params.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetForUser(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, errors.New("too many webhooks")
+	}
+
+	raw := make([]byte, secretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	encrypted, err := s.cipher.Encrypt([]byte(secret), secretAAD(actor.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(&WebhookStruct{
+		UserID: actor.UserID,
+		URL:    target,
+		Events: strings.Join(types, " "),
+		Secret: encrypted,
+		Active: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{Webhook: *toWebhook(created), Secret: secret}, nil
+}
+
+// GetWebhooks - вебхуки actor
+func (s *WebhookService) GetWebhooks(actor rbac.Actor) ([]Webhook, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+	if s.cipher == nil {
+		return nil, errors.New("webhooks are not configured")
+	}
+
+	dbWebhooks, err := s.repo.GetForUser(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	webhooks := make([]Webhook, 0, len(dbWebhooks))
+	for i := range dbWebhooks {
+		webhooks = append(webhooks, *toWebhook(&dbWebhooks[i]))
+	}
+	return webhooks, nil
+}
+
+// GetWebhook - вебхук по ID
+func (s *WebhookService) GetWebhook(actor rbac.Actor, id uint) (*Webhook, error) {
+	webhook, err := s.getWebhook(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhook(&webhook), nil
+}
+
+// UpdateWebhook - меняет адрес, события или включает/выключает вебхук
+// у выключенного вебхука новые события не копятся, а уже поставленные доставки ждут включения
+func (s *WebhookService) UpdateWebhook(actor rbac.Actor, id uint, params UpdateWebhookParams) (*Webhook, error) {
+	webhook, err := s.getWebhook(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// маска изменённых колонок
+	var fields []string
+
+	if params.URL != nil {
+		target, err := validateURL(*params.URL)
+		if err != nil {
+			return nil, err
+		}
+		webhook.URL = target
+		fields = append(fields, "url")
+	}
+
+	if params.Events != nil {
+		types, err := parseEvents(params.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = strings.Join(types, " ")
+		fields = append(fields, "events")
+	}
+
+	if params.Active != nil {
+		webhook.Active = *params.Active
+		fields = append(fields, "active")
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+
+	updated, err := s.repo.Update(&webhook, fields)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhook(updated), nil
+}
+
+// DeleteWebhook - удаляет вебхук вместе с журналом доставок
+func (s *WebhookService) DeleteWebhook(actor rbac.Actor, id uint) error {
+	if _, err := s.getWebhook(actor, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// GetDeliveries - последние доставки вебхука (limit: 0 - по умолчанию)
+func (s *WebhookService) GetDeliveries(actor rbac.Actor, webhookID uint, limit int) ([]Delivery, error) {
+	if limit == 0 {
+		limit = defaultDeliveries
+	}
+	if limit < 1 || limit > maxDeliveriesPerGet {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	if _, err := s.getWebhook(actor, webhookID); err != nil {
+		return nil, err
+	}
+
+	dbDeliveries, err := s.repo.GetDeliveries(webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	deliveries := make([]Delivery, 0, len(dbDeliveries))
+	for i := range dbDeliveries {
+		deliveries = append(deliveries, *toDelivery(&dbDeliveries[i]))
+	}
+	return deliveries, nil
+}
+
+// getDelivery - доставка вебхука, которым actor может управлять
+func (s *WebhookService) getDelivery(actor rbac.Actor, webhookID, id uint) (DeliveryStruct, error) {
+	if _, err := s.getWebhook(actor, webhookID); err != nil {
+		return DeliveryStruct{}, err
+	}
+	delivery, err := s.repo.GetDelivery(id)
+	if err != nil || delivery.ID == 0 || delivery.WebhookID != webhookID {
+		return DeliveryStruct{}, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+// GetDelivery - доставка с телом запроса и журналом попыток
+func (s *WebhookService) GetDelivery(actor rbac.Actor, webhookID, id uint) (*Delivery, error) {
+	dbDelivery, err := s.getDelivery(actor, webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.GetAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := toDelivery(&dbDelivery)
+	delivery.Payload = dbDelivery.Payload
+	delivery.Log = make([]Attempt, 0, len(attempts))
+	for _, a := range attempts {
+		delivery.Log = append(delivery.Log, Attempt{
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+			At:         a.CreatedAt,
+		})
+	}
+	return delivery, nil
+}
+
+// Redeliver - отправить доставку заново (то же тело и id события, новый счетчик попыток)
+func (s *WebhookService) Redeliver(actor rbac.Actor, webhookID, id uint) (*Delivery, error) {
+	if _, err := s.getDelivery(actor, webhookID, id); err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.Redeliver(id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	return toDelivery(delivery), nil
+}
+
+// payload - тело запроса к получателю
+type payload struct {
+	ID        string          `json:"id"` // id события: одинаковый во всех попытках, по нему получатель отсеет повторы
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Task      json.RawMessage `json:"task"` // задача, как в ответах апи; у task.deleted - только id
+}
+
+// Enqueue - ставит событие в очередь вебхуков, которым оно положено, в транзакции tx (реализует taskService.Outbox)
+// ошибка откатывает и само изменение задачи: иначе событие пропало бы молча
+func (s *WebhookService) Enqueue(tx *gorm.DB, ev events.Event) error {
+	if s.tasks == nil || !slices.Contains(EventTypes, ev.Type) {
+		return nil
+	}
+
+	webhooks, err := s.repo.GetActiveForEvent(tx, append([]uint{ev.OwnerID}, ev.AssigneeIDs...), ev.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	var body []byte
+	var deliveries []DeliveryStruct
+	for _, webhook := range webhooks {
+		if !slices.Contains(strings.Fields(webhook.Events), ev.Type) {
+			continue
+		}
+		if !s.tasks.CanSeeEvent(rbac.Actor{UserID: webhook.UserID, Role: rbac.RoleUser}, ev) {
+			continue
+		}
+
+		if body == nil {
+			if body, err = encodePayload(ev, now); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, DeliveryStruct{
+			WebhookID:     webhook.ID,
+			EventID:       ev.ID,
+			EventType:     ev.Type,
+			Payload:       string(body),
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return s.repo.CreateDeliveries(tx, deliveries)
+}
+
+// encodePayload - тело запроса для события
+func encodePayload(ev events.Event, now time.Time) ([]byte, error) {
+	p := payload{ID: ev.ID, Type: ev.Type, CreatedAt: ev.At, Task: ev.Data}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	if len(p.Task) == 0 {
+		p.Task = json.RawMessage(fmt.Sprintf(`{"id":%d}`, ev.TaskID))
+	}
+	return json.Marshal(p)
+}
+
+// RunDeliveries - разбирает очередь доставок, пока не отменен ctx (запускать в горутине)
+func (s *WebhookService) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// забрали полную пачку - возможно, ждут еще: берем следующую сразу
+		for ctx.Err() == nil {
+			claimed, err := s.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Webhook deliveries failed: %v", err)
+				break
+			}
+			if claimed < deliveryBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue - один проход по очереди: отправляет доставки, которым пора; возвращает, сколько забрал
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(s.now(), deliveryLease, deliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		s.deliver(ctx, &deliveries[i])
+	}
+	return len(deliveries), nil
+}
+
+// deliver - одна попытка: отправляет запрос и записывает результат в журнал
+func (s *WebhookService) deliver(ctx context.Context, d *DeliveryStruct) {
+	startedAt := s.now()
+	started := time.Now()
+	statusCode, err := s.send(ctx, d, startedAt)
+
+	attempt := &AttemptStruct{
+		DeliveryID: d.ID,
+		StatusCode: statusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+		CreatedAt:  startedAt,
+	}
+	d.Attempts++
+	d.LastAttemptAt = &startedAt
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	switch {
+	case err == nil:
+		d.Status = DeliverySucceeded
+	case d.Attempts >= s.maxAttempts:
+		d.Status = DeliveryFailed
+	default:
+		d.NextAttemptAt = startedAt.Add(s.retryDelay(d.Attempts))
+	}
+	if err != nil {
+		attempt.Error = shortError(err)
+		d.LastError = attempt.Error
+	}
+
+	if err := s.repo.RecordAttempt(d, attempt); err != nil {
+		// доставка останется забранной до конца lease, после чего уйдет еще раз
+		log.Printf("Failed to record webhook delivery %d: %v", d.ID, err)
+	}
+}
+
+// shortError - ошибка для журнала: не длиннее maxErrorLength символов
+func shortError(err error) string {
+	msg := []rune(err.Error())
+	if len(msg) > maxErrorLength {
+		return string(msg[:maxErrorLength])
+	}
+	return string(msg)
+}
+
+// retryDelay - пауза перед следующей попыткой после attempts неудачных
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	delay := s.backoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// send - подписанный POST получателю; ошибка - если ответа нет или он не 2xx
+func (s *WebhookService) send(ctx context.Context, d *DeliveryStruct, at time.Time) (*int, error) {
+	if s.cipher == nil {
+		return nil, errors.New("webhooks are not configured")
+	}
+	secret, err := s.cipher.Decrypt(d.Webhook.Secret, secretAAD(d.Webhook.UserID))
+	if err != nil {
+		return nil, errors.New("cannot decrypt webhook secret")
+	}
+
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Webhook.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WebPet1-Webhooks")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(string(secret), timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// тело ответа не нужно: дочитываем немного, чтобы соединение вернулось в пул
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	code := resp.StatusCode
+	if code < 200 || code > 299 {
+		return &code, fmt.Errorf("unexpected response status %d", code)
+	}
+	return &code, nil
+}
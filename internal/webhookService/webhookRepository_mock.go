@@ -0,0 +1,117 @@
+package webhookService
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) Create(webhook *WebhookStruct) (*WebhookStruct, error) {
+	args := m.Called(webhook)
+	var result *WebhookStruct
+	if res := args.Get(0); res != nil {
+		result = res.(*WebhookStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetByID(id uint) (WebhookStruct, error) {
+	args := m.Called(id)
+	var result WebhookStruct
+	if res := args.Get(0); res != nil {
+		result = res.(WebhookStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetForUser(userID uint) ([]WebhookStruct, error) {
+	args := m.Called(userID)
+	var result []WebhookStruct
+	if res := args.Get(0); res != nil {
+		result = res.([]WebhookStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) Update(webhook *WebhookStruct, fields []string) (*WebhookStruct, error) {
+	args := m.Called(webhook, fields)
+	var result *WebhookStruct
+	if res := args.Get(0); res != nil {
+		result = res.(*WebhookStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) GetActiveForEvent(tx *gorm.DB, userIDs []uint, workspaceID *uint) ([]WebhookStruct, error) {
+	args := m.Called(userIDs, workspaceID)
+	var result []WebhookStruct
+	if res := args.Get(0); res != nil {
+		result = res.([]WebhookStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) CreateDeliveries(tx *gorm.DB, deliveries []DeliveryStruct) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]DeliveryStruct, error) {
+	args := m.Called(now, lease, limit)
+	var result []DeliveryStruct
+	if res := args.Get(0); res != nil {
+		result = res.([]DeliveryStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) RecordAttempt(delivery *DeliveryStruct, attempt *AttemptStruct) error {
+	args := m.Called(delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) GetDeliveries(webhookID uint, limit int) ([]DeliveryStruct, error) {
+	args := m.Called(webhookID, limit)
+	var result []DeliveryStruct
+	if res := args.Get(0); res != nil {
+		result = res.([]DeliveryStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetDelivery(id uint) (DeliveryStruct, error) {
+	args := m.Called(id)
+	var result DeliveryStruct
+	if res := args.Get(0); res != nil {
+		result = res.(DeliveryStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetAttempts(deliveryID uint) ([]AttemptStruct, error) {
+	args := m.Called(deliveryID)
+	var result []AttemptStruct
+	if res := args.Get(0); res != nil {
+		result = res.([]AttemptStruct)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockWebhookRepo) Redeliver(id uint, at time.Time) (*DeliveryStruct, error) {
+	args := m.Called(id, at)
+	var result *DeliveryStruct
+	if res := args.Get(0); res != nil {
+		result = res.(*DeliveryStruct)
+	}
+	return result, args.Error(1)
+}
@@ -0,0 +1,52 @@
+package workspaceService
+
+import "time"
+
+// модель базы данных: рабочее пространство команды
+// задачи пространства ссылаются на него через task_structs.workspace_id
+type WorkspaceStruct struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Name      string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (WorkspaceStruct) TableName() string {
+	return "workspaces" // как в миграции
+}
+
+// участник пространства и его роль в нем (rbac.WorkspaceRole)
+type MemberStruct struct {
+	WorkspaceID uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"primaryKey"`
+	Role        string `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+func (MemberStruct) TableName() string {
+	return "workspace_members" // как в миграции
+}
+
+// приглашение по email: как и остальные токены из писем, в бд лежит только sha256 токена
+// принятое приглашение остается в бд (AcceptedAt), чтобы было видно, кто кого позвал
+type InvitationStruct struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint   `gorm:"not null"`
+	Email       string `gorm:"not null"`
+	Role        string `gorm:"not null"`
+	TokenHash   string `gorm:"not null;uniqueIndex"` // sha256 токена (hex)
+	InvitedBy   uint   `gorm:"not null"`
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time // nil - еще не принято
+	CreatedAt   time.Time
+}
+
+func (InvitationStruct) TableName() string {
+	return "workspace_invitations" // как в миграции
+}
+
+// пространство вместе с ролью в нем конкретного пользователя (результат WorkspaceRepo.GetForUser)
+type MembershipRow struct {
+	WorkspaceStruct
+	Role string
+}
@@ -0,0 +1,264 @@
+package workspaceService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/db"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 2. repo-слой рабочих пространств (только работа с бд)
+// проверки "в пространстве всегда остается владелец" делаются здесь, в транзакции с блокировкой
+// строки пространства: иначе два владельца могли бы одновременно разжаловать друг друга
+
+type WorkspaceRepoInterface interface {
+	Create(workspace *WorkspaceStruct, ownerID uint) (*WorkspaceStruct, error)
+	GetByID(id uint) (WorkspaceStruct, error)
+	GetForUser(userID uint) ([]MembershipRow, error)
+	Delete(id uint) error
+	GetMember(workspaceID, userID uint) (MemberStruct, error)
+	GetMembers(workspaceID uint) ([]MemberStruct, error)
+	SetMemberRole(workspaceID, userID uint, role string) (*MemberStruct, error)
+	RemoveMember(workspaceID, userID uint) error
+	CreateInvitation(invitation *InvitationStruct) (*InvitationStruct, error)
+	GetInvitation(id uint) (InvitationStruct, error)
+	GetInvitationByToken(tokenHash string) (InvitationStruct, error)
+	GetInvitations(workspaceID uint) ([]InvitationStruct, error)
+	DeleteInvitation(id uint) error
+	AcceptInvitation(invitation *InvitationStruct, userID uint) error
+}
+
+type WorkspaceRepo struct{}
+
+// код ошибки Postgres (SQLSTATE) для нарушения внешнего ключа
+const pgForeignKeyViolation = "23503"
+
+// Create - создает пространство, создатель сразу становится его владельцем
+func (r *WorkspaceRepo) Create(workspace *WorkspaceStruct, ownerID uint) (*WorkspaceStruct, error) {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&MemberStruct{
+			WorkspaceID: workspace.ID,
+			UserID:      ownerID,
+			Role:        string(rbac.WorkspaceOwner),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// GetByID - пространство по ID
+func (r *WorkspaceRepo) GetByID(id uint) (WorkspaceStruct, error) {
+	var workspace WorkspaceStruct
+	err := db.DB.First(&workspace, "id = ?", id).Error
+	return workspace, err
+}
+
+// GetForUser - пространства, в которых состоит пользователь, и его роль в каждом
+func (r *WorkspaceRepo) GetForUser(userID uint) ([]MembershipRow, error) {
+	rows := make([]MembershipRow, 0)
+	err := db.DB.Table("workspaces").
+		Select("workspaces.*, workspace_members.role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Delete - удаляет пространство вместе с участниками и приглашениями
+// задачи каскадом не удаляются (ON DELETE RESTRICT): пока они есть - "workspace is not empty"
+func (r *WorkspaceRepo) Delete(id uint) error {
+	res := db.DB.Delete(&WorkspaceStruct{}, "id = ?", id)
+	if res.Error != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(res.Error, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return errors.New("workspace is not empty")
+		}
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("workspace not found")
+	}
+	return nil
+}
+
+// GetMember - участник пространства (gorm.ErrRecordNotFound - не состоит)
+func (r *WorkspaceRepo) GetMember(workspaceID, userID uint) (MemberStruct, error) {
+	var member MemberStruct
+	err := db.DB.First(&member, "workspace_id = ? AND user_id = ?", workspaceID, userID).Error
+	return member, err
+}
+
+// GetMembers - участники пространства в порядке вступления
+func (r *WorkspaceRepo) GetMembers(workspaceID uint) ([]MemberStruct, error) {
+	var members []MemberStruct
+	err := db.DB.Where("workspace_id = ?", workspaceID).Order("created_at, user_id").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// SetMemberRole - меняет роль участника (последнего владельца разжаловать нельзя)
+func (r *WorkspaceRepo) SetMemberRole(workspaceID, userID uint, role string) (*MemberStruct, error) {
+	var member MemberStruct
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := lockMember(tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if role != string(rbac.WorkspaceOwner) {
+			if err := keepOwner(tx, current); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&MemberStruct{}).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Update("role", role).Error
+		if err != nil {
+			return err
+		}
+		member = current
+		member.Role = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveMember - убирает участника (последнего владельца убрать нельзя)
+func (r *WorkspaceRepo) RemoveMember(workspaceID, userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := lockMember(tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if err := keepOwner(tx, current); err != nil {
+			return err
+		}
+		return tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&MemberStruct{}).Error
+	})
+}
+
+// lockMember - блокирует пространство (все изменения состава идут по очереди) и читает участника
+func lockMember(tx *gorm.DB, workspaceID, userID uint) (MemberStruct, error) {
+	var workspace WorkspaceStruct
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&workspace, "id = ?", workspaceID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return MemberStruct{}, errors.New("workspace not found")
+		}
+		return MemberStruct{}, err
+	}
+
+	var member MemberStruct
+	err = tx.First(&member, "workspace_id = ? AND user_id = ?", workspaceID, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return MemberStruct{}, errors.New("member not found")
+		}
+		return MemberStruct{}, err
+	}
+	return member, nil
+}
+
+// keepOwner - ошибка, если member - единственный владелец пространства
+func keepOwner(tx *gorm.DB, member MemberStruct) error {
+	if member.Role != string(rbac.WorkspaceOwner) {
+		return nil
+	}
+	var owners int64
+	err := tx.Model(&MemberStruct{}).
+		Where("workspace_id = ? AND role = ?", member.WorkspaceID, string(rbac.WorkspaceOwner)).
+		Count(&owners).Error
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return errors.New("workspace must have an owner")
+	}
+	return nil
+}
+
+// CreateInvitation - сохраняет приглашение; прежние непринятые приглашения
+// того же адреса в это пространство перестают действовать (работает только последняя ссылка)
+func (r *WorkspaceRepo) CreateInvitation(invitation *InvitationStruct) (*InvitationStruct, error) {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("workspace_id = ? AND lower(email) = lower(?) AND accepted_at IS NULL", invitation.WorkspaceID, invitation.Email).
+			Delete(&InvitationStruct{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(invitation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// GetInvitation - приглашение по ID
+func (r *WorkspaceRepo) GetInvitation(id uint) (InvitationStruct, error) {
+	var invitation InvitationStruct
+	err := db.DB.First(&invitation, "id = ?", id).Error
+	return invitation, err
+}
+
+// GetInvitationByToken - непринятое приглашение по хэшу токена (срок действия проверяет сервис)
+func (r *WorkspaceRepo) GetInvitationByToken(tokenHash string) (InvitationStruct, error) {
+	var invitation InvitationStruct
+	err := db.DB.First(&invitation, "token_hash = ? AND accepted_at IS NULL", tokenHash).Error
+	return invitation, err
+}
+
+// GetInvitations - непринятые приглашения пространства (сначала новые)
+func (r *WorkspaceRepo) GetInvitations(workspaceID uint) ([]InvitationStruct, error) {
+	var invitations []InvitationStruct
+	err := db.DB.Where("workspace_id = ? AND accepted_at IS NULL", workspaceID).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// DeleteInvitation - отзывает приглашение
+func (r *WorkspaceRepo) DeleteInvitation(id uint) error {
+	return db.DB.Delete(&InvitationStruct{}, "id = ?", id).Error
+}
+
+// AcceptInvitation - отмечает приглашение принятым и добавляет пользователя в пространство
+// (если он уже участник - его роль не меняется); одно приглашение принимается только один раз
+func (r *WorkspaceRepo) AcceptInvitation(invitation *InvitationStruct, userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&InvitationStruct{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("invalid or expired invitation")
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&MemberStruct{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      userID,
+			Role:        invitation.Role,
+		}).Error
+	})
+}
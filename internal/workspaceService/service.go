@@ -0,0 +1,505 @@
+package workspaceService
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AntonRadchenko/WebPet1/internal/mailer"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"gorm.io/gorm"
+)
+
+// 3. service-слой рабочих пространств
+// права (роли - rbac.WorkspaceRole):
+//   • пространство и его участников видят только участники; для остальных оно не существует
+//     ("workspace not found"), глобальный админ видит и может все
+//   • приглашать, отзывать приглашения, менять роли и убирать участников - owner и admin,
+//     но admin раздает и трогает только роли member и guest; владельцев назначают только владельцы
+//   • уйти из пространства может любой участник, кроме последнего владельца
+//   • удалить пространство - только владелец и только пустое (без задач)
+// приглашение:
+//   1) POST /workspaces/{id}/invitations - на email уходит ссылка с одноразовым токеном
+//   2) POST /workspaces/join - вошедший пользователь с этим (подтвержденным) email принимает приглашение
+
+// настройки по умолчанию
+const (
+	DefaultInviteURL = "http://localhost:9092/join-workspace"
+	DefaultInviteTTL = 7 * 24 * time.Hour
+)
+
+// максимальная длина названия пространства (в символах)
+const maxNameLength = 100
+
+// размер токена приглашения в байтах (до кодирования в base64)
+const tokenBytes = 32
+
+// структура параметров метода CreateWorkspace
+type CreateWorkspaceParams struct {
+	Name string
+}
+
+// структура параметров метода Invite
+type InviteParams struct {
+	Email string
+	Role  string
+}
+
+// бизнес-модель пространства; Role - роль того, кто спрашивает ("" - не участник, например глобальный админ)
+type Workspace struct {
+	ID        uint
+	Name      string
+	Role      rbac.WorkspaceRole
+	CreatedAt time.Time
+}
+
+// участник пространства
+type Member struct {
+	UserID   uint
+	Role     rbac.WorkspaceRole
+	JoinedAt time.Time
+}
+
+// непринятое приглашение
+type Invitation struct {
+	ID          uint
+	WorkspaceID uint
+	Email       string
+	Role        rbac.WorkspaceRole
+	InvitedBy   uint
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+// Users - то, что нужно от userService (интерфейс, чтобы в тестах подставлять заглушку)
+type Users interface {
+	GetAccount(id uint) (*userService.User, error)
+	GetUserByEmail(email string) (*userService.User, error)
+}
+
+type WorkspaceService struct {
+	repo      WorkspaceRepoInterface
+	users     Users
+	mailer    mailer.Mailer
+	inviteURL string // страница фронтенда, к ней добавляется ?token=...
+	inviteTTL time.Duration
+
+	now func() time.Time // подменяется в тестах
+}
+
+func NewWorkspaceService(r WorkspaceRepoInterface, users Users, m mailer.Mailer) *WorkspaceService {
+	return &WorkspaceService{
+		repo:      r,
+		users:     users,
+		mailer:    m,
+		inviteURL: DefaultInviteURL,
+		inviteTTL: DefaultInviteTTL,
+		now:       time.Now,
+	}
+}
+
+// WithInvitations - задает адрес страницы принятия приглашения и время жизни ссылки (из конфига)
+func (s *WorkspaceService) WithInvitations(inviteURL string, ttl time.Duration) *WorkspaceService {
+	s.inviteURL = inviteURL
+	s.inviteTTL = ttl
+	return s
+}
+
+// WorkspaceRole - роль пользователя в пространстве ("" - не участник)
+// (реализует taskService.WorkspaceAccess)
+func (s *WorkspaceService) WorkspaceRole(workspaceID, userID uint) (rbac.WorkspaceRole, error) {
+	member, err := s.repo.GetMember(workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return rbac.WorkspaceRole(member.Role), nil
+}
+
+// access - роль actor в пространстве, если оно ему видно (глобальному админу видно любое)
+// второе значение - права, с которыми actor действует: глобальный админ действует как владелец
+func (s *WorkspaceService) access(actor rbac.Actor, id uint) (rbac.WorkspaceRole, rbac.WorkspaceRole, error) {
+	if actor.UserID == 0 {
+		return "", "", errors.New("workspace not found")
+	}
+	role, err := s.WorkspaceRole(id, actor.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	switch {
+	case actor.IsAdmin():
+		return role, rbac.WorkspaceOwner, nil
+	case role == "":
+		return "", "", errors.New("workspace not found")
+	default:
+		return role, role, nil
+	}
+}
+
+// canGrant - может ли участник с ролью actorRole выдать роль role (или трогать участника с этой ролью)
+func canGrant(actorRole, role rbac.WorkspaceRole) bool {
+	switch actorRole {
+	case rbac.WorkspaceOwner:
+		return true
+	case rbac.WorkspaceAdmin:
+		return role == rbac.WorkspaceMember || role == rbac.WorkspaceGuest
+	default:
+		return false
+	}
+}
+
+// CreateWorkspace - создает пространство; создатель становится владельцем
+func (s *WorkspaceService) CreateWorkspace(actor rbac.Actor, params CreateWorkspaceParams) (*Workspace, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return nil, errors.New("name is empty")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return nil, errors.New("name is too long")
+	}
+
+	created, err := s.repo.Create(&WorkspaceStruct{Name: name}, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return toWorkspace(created, rbac.WorkspaceOwner), nil
+}
+
+// GetWorkspaces - пространства, в которых состоит actor
+func (s *WorkspaceService) GetWorkspaces(actor rbac.Actor) ([]Workspace, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+
+	rows, err := s.repo.GetForUser(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	workspaces := make([]Workspace, 0, len(rows))
+	for i := range rows {
+		workspaces = append(workspaces, *toWorkspace(&rows[i].WorkspaceStruct, rbac.WorkspaceRole(rows[i].Role)))
+	}
+	return workspaces, nil
+}
+
+// GetWorkspace - пространство по ID
+func (s *WorkspaceService) GetWorkspace(actor rbac.Actor, id uint) (*Workspace, error) {
+	role, _, err := s.access(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbWorkspace, err := s.repo.GetByID(id)
+	if err != nil || dbWorkspace.ID == 0 {
+		return nil, errors.New("workspace not found")
+	}
+	return toWorkspace(&dbWorkspace, role), nil
+}
+
+// DeleteWorkspace - удаляет пустое пространство (только владелец)
+func (s *WorkspaceService) DeleteWorkspace(actor rbac.Actor, id uint) error {
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return err
+	}
+	if effective != rbac.WorkspaceOwner {
+		return errors.New("forbidden")
+	}
+	return s.repo.Delete(id)
+}
+
+// GetMembers - участники пространства
+func (s *WorkspaceService) GetMembers(actor rbac.Actor, id uint) ([]Member, error) {
+	if _, _, err := s.access(actor, id); err != nil {
+		return nil, err
+	}
+
+	dbMembers, err := s.repo.GetMembers(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	members := make([]Member, 0, len(dbMembers))
+	for i := range dbMembers {
+		members = append(members, *toMember(&dbMembers[i]))
+	}
+	return members, nil
+}
+
+// SetMemberRole - меняет роль участника
+func (s *WorkspaceService) SetMemberRole(actor rbac.Actor, id, userID uint, role string) (*Member, error) {
+	newRole, err := rbac.ParseWorkspaceRole(role)
+	if err != nil {
+		return nil, errors.New("invalid role")
+	}
+
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !effective.CanManageMembers() {
+		return nil, errors.New("forbidden")
+	}
+
+	target, err := s.repo.GetMember(id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("member not found")
+		}
+		return nil, err
+	}
+	// админ пространства не трогает владельцев и других админов и не раздает эти роли
+	if !canGrant(effective, rbac.WorkspaceRole(target.Role)) || !canGrant(effective, newRole) {
+		return nil, errors.New("forbidden")
+	}
+
+	updated, err := s.repo.SetMemberRole(id, userID, string(newRole))
+	if err != nil {
+		return nil, err
+	}
+	return toMember(updated), nil
+}
+
+// RemoveMember - убирает участника из пространства (или участник уходит сам)
+func (s *WorkspaceService) RemoveMember(actor rbac.Actor, id, userID uint) error {
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return err
+	}
+
+	if actor.UserID != userID {
+		if !effective.CanManageMembers() {
+			return errors.New("forbidden")
+		}
+		target, err := s.repo.GetMember(id, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("member not found")
+			}
+			return err
+		}
+		if !canGrant(effective, rbac.WorkspaceRole(target.Role)) {
+			return errors.New("forbidden")
+		}
+	}
+
+	return s.repo.RemoveMember(id, userID)
+}
+
+// Invite - приглашает в пространство по email: сохраняет приглашение и отправляет письмо со ссылкой
+func (s *WorkspaceService) Invite(actor rbac.Actor, id uint, params InviteParams) (*Invitation, error) {
+	role, err := rbac.ParseWorkspaceRole(params.Role)
+	if err != nil {
+		return nil, errors.New("invalid role")
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(params.Email))
+	if err != nil || address.Name != "" {
+		return nil, errors.New("invalid email")
+	}
+	email := strings.ToLower(address.Address)
+
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !effective.CanManageMembers() || !canGrant(effective, role) {
+		return nil, errors.New("forbidden")
+	}
+
+	dbWorkspace, err := s.repo.GetByID(id)
+	if err != nil || dbWorkspace.ID == 0 {
+		return nil, errors.New("workspace not found")
+	}
+
+	// уже состоит - приглашать незачем (незарегистрированный адрес - обычное приглашение)
+	if user, err := s.users.GetUserByEmail(email); err == nil {
+		existing, err := s.WorkspaceRole(id, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != "" {
+			return nil, errors.New("user is already a member")
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.CreateInvitation(&InvitationStruct{
+		WorkspaceID: id,
+		Email:       email,
+		Role:        string(role),
+		TokenHash:   hashToken(token),
+		InvitedBy:   actor.UserID,
+		ExpiresAt:   s.now().Add(s.inviteTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := tokenLink(s.inviteURL, token)
+	if err != nil {
+		return nil, err
+	}
+	err = s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "You are invited to a workspace",
+		Body: fmt.Sprintf("You are invited to join the workspace %q as %s.\n"+
+			"Open this link to accept the invitation (valid for %s):\n%s\n\n"+
+			"If you don't have an account yet, sign up with this email first.",
+			dbWorkspace.Name, role, s.inviteTTL, link),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toInvitation(created), nil
+}
+
+// GetInvitations - непринятые приглашения пространства
+func (s *WorkspaceService) GetInvitations(actor rbac.Actor, id uint) ([]Invitation, error) {
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !effective.CanManageMembers() {
+		return nil, errors.New("forbidden")
+	}
+
+	dbInvitations, err := s.repo.GetInvitations(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// маппим бд-модель в бизнес-модель
+	invitations := make([]Invitation, 0, len(dbInvitations))
+	for i := range dbInvitations {
+		invitations = append(invitations, *toInvitation(&dbInvitations[i]))
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation - отзывает приглашение (ссылка из письма перестает работать)
+func (s *WorkspaceService) RevokeInvitation(actor rbac.Actor, id, invitationID uint) error {
+	_, effective, err := s.access(actor, id)
+	if err != nil {
+		return err
+	}
+	if !effective.CanManageMembers() {
+		return errors.New("forbidden")
+	}
+
+	invitation, err := s.repo.GetInvitation(invitationID)
+	if err != nil || invitation.ID == 0 || invitation.WorkspaceID != id || invitation.AcceptedAt != nil {
+		return errors.New("invitation not found")
+	}
+	return s.repo.DeleteInvitation(invitationID)
+}
+
+// AcceptInvitation - принимает приглашение по токену из письма
+// приглашение действует только для того адреса, на который ушло письмо, и только если этот адрес подтвержден
+// (иначе его мог бы принять любой, кто зарегистрировался с чужим email)
+func (s *WorkspaceService) AcceptInvitation(actor rbac.Actor, token string) (*Workspace, error) {
+	if actor.UserID == 0 {
+		return nil, errors.New("forbidden")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New("invalid or expired invitation")
+	}
+
+	invitation, err := s.repo.GetInvitationByToken(hashToken(token))
+	if err != nil || invitation.ID == 0 || !s.now().Before(invitation.ExpiresAt) {
+		return nil, errors.New("invalid or expired invitation")
+	}
+
+	user, err := s.users.GetAccount(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("invitation is for another email")
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, errors.New("email is not verified")
+	}
+
+	if err := s.repo.AcceptInvitation(&invitation, actor.UserID); err != nil {
+		return nil, err
+	}
+	return s.GetWorkspace(actor, invitation.WorkspaceID)
+}
+
+// tokenLink - ссылка с токеном в query (?token=...)
+func tokenLink(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid link url %q: %w", base, err)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// newToken - случайный токен для ссылки (base64url без паддинга)
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken - в бд храним sha256 токена (утечка таблицы не дает рабочих ссылок)
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toWorkspace(w *WorkspaceStruct, role rbac.WorkspaceRole) *Workspace {
+	return &Workspace{
+		ID:        w.ID,
+		Name:      w.Name,
+		Role:      role,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func toMember(m *MemberStruct) *Member {
+	return &Member{
+		UserID:   m.UserID,
+		Role:     rbac.WorkspaceRole(m.Role),
+		JoinedAt: m.CreatedAt,
+	}
+}
+
+func toInvitation(i *InvitationStruct) *Invitation {
+	return &Invitation{
+		ID:          i.ID,
+		WorkspaceID: i.WorkspaceID,
+		Email:       i.Email,
+		Role:        rbac.WorkspaceRole(i.Role),
+		InvitedBy:   i.InvitedBy,
+		ExpiresAt:   i.ExpiresAt,
+		CreatedAt:   i.CreatedAt,
+	}
+}
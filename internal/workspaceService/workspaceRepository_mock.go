@@ -0,0 +1,117 @@
+package workspaceService
+
+import "github.com/stretchr/testify/mock"
+
+type MockWorkspaceRepo struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceRepo) Create(workspace *WorkspaceStruct, ownerID uint) (*WorkspaceStruct, error) {
+	args := m.Called(workspace, ownerID)
+	var w *WorkspaceStruct
+	if res := args.Get(0); res != nil {
+		w = res.(*WorkspaceStruct)
+	}
+	return w, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetByID(id uint) (WorkspaceStruct, error) {
+	args := m.Called(id)
+	var w WorkspaceStruct
+	if res := args.Get(0); res != nil {
+		w = res.(WorkspaceStruct)
+	}
+	return w, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetForUser(userID uint) ([]MembershipRow, error) {
+	args := m.Called(userID)
+	var rows []MembershipRow
+	if res := args.Get(0); res != nil {
+		rows = res.([]MembershipRow)
+	}
+	return rows, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepo) GetMember(workspaceID, userID uint) (MemberStruct, error) {
+	args := m.Called(workspaceID, userID)
+	var member MemberStruct
+	if res := args.Get(0); res != nil {
+		member = res.(MemberStruct)
+	}
+	return member, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetMembers(workspaceID uint) ([]MemberStruct, error) {
+	args := m.Called(workspaceID)
+	var members []MemberStruct
+	if res := args.Get(0); res != nil {
+		members = res.([]MemberStruct)
+	}
+	return members, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) SetMemberRole(workspaceID, userID uint, role string) (*MemberStruct, error) {
+	args := m.Called(workspaceID, userID, role)
+	var member *MemberStruct
+	if res := args.Get(0); res != nil {
+		member = res.(*MemberStruct)
+	}
+	return member, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) RemoveMember(workspaceID, userID uint) error {
+	args := m.Called(workspaceID, userID)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepo) CreateInvitation(invitation *InvitationStruct) (*InvitationStruct, error) {
+	args := m.Called(invitation)
+	var i *InvitationStruct
+	if res := args.Get(0); res != nil {
+		i = res.(*InvitationStruct)
+	}
+	return i, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetInvitation(id uint) (InvitationStruct, error) {
+	args := m.Called(id)
+	var i InvitationStruct
+	if res := args.Get(0); res != nil {
+		i = res.(InvitationStruct)
+	}
+	return i, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetInvitationByToken(tokenHash string) (InvitationStruct, error) {
+	args := m.Called(tokenHash)
+	var i InvitationStruct
+	if res := args.Get(0); res != nil {
+		i = res.(InvitationStruct)
+	}
+	return i, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) GetInvitations(workspaceID uint) ([]InvitationStruct, error) {
+	args := m.Called(workspaceID)
+	var invitations []InvitationStruct
+	if res := args.Get(0); res != nil {
+		invitations = res.([]InvitationStruct)
+	}
+	return invitations, args.Error(1)
+}
+
+func (m *MockWorkspaceRepo) DeleteInvitation(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepo) AcceptInvitation(invitation *InvitationStruct, userID uint) error {
+	args := m.Called(invitation, userID)
+	return args.Error(0)
+}
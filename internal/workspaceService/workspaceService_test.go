@@ -0,0 +1,339 @@
+package workspaceService
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonRadchenko/WebPet1/internal/mailer"
+	"github.com/AntonRadchenko/WebPet1/internal/rbac"
+	"github.com/AntonRadchenko/WebPet1/internal/userService"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// в пространстве 5: owner (1) - владелец, manager (2) - админ, member (3) - участник, guest (4) - гость
+var (
+	owner    = rbac.Actor{UserID: 1, Role: rbac.RoleUser}
+	manager  = rbac.Actor{UserID: 2, Role: rbac.RoleUser}
+	member   = rbac.Actor{UserID: 3, Role: rbac.RoleUser}
+	guest    = rbac.Actor{UserID: 4, Role: rbac.RoleUser}
+	stranger = rbac.Actor{UserID: 9, Role: rbac.RoleUser}
+	admin    = rbac.Actor{UserID: 100, Role: rbac.RoleAdmin}
+)
+
+var roles = map[uint]rbac.WorkspaceRole{
+	1: rbac.WorkspaceOwner,
+	2: rbac.WorkspaceAdmin,
+	3: rbac.WorkspaceMember,
+	4: rbac.WorkspaceGuest,
+}
+
+// newRepo - мок-репозиторий с составом пространства 5
+func newRepo() *MockWorkspaceRepo {
+	m := new(MockWorkspaceRepo)
+	for userID := uint(1); userID <= 100; userID++ {
+		if role, ok := roles[userID]; ok {
+			m.On("GetMember", uint(5), userID).Return(MemberStruct{WorkspaceID: 5, UserID: userID, Role: string(role)}, nil).Maybe()
+		} else {
+			m.On("GetMember", uint(5), userID).Return(MemberStruct{}, gorm.ErrRecordNotFound).Maybe()
+		}
+	}
+	m.On("GetByID", uint(5)).Return(WorkspaceStruct{ID: 5, Name: "Команда"}, nil).Maybe()
+	return m
+}
+
+// fakeUsers - пользователи по id и email
+type fakeUsers map[uint]*userService.User
+
+func (f fakeUsers) GetAccount(id uint) (*userService.User, error) {
+	if u, ok := f[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f fakeUsers) GetUserByEmail(email string) (*userService.User, error) {
+	for _, u := range f {
+		if strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+// fakeMailer - складывает письма в слайс
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (f *fakeMailer) Send(msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// tokenFromMail - токен из ссылки в письме
+func tokenFromMail(t *testing.T, body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			require.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatal("no link in mail body")
+	return ""
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		wsName  string
+		wantErr string
+	}{
+		{name: "создатель становится владельцем", actor: owner, wsName: "  Команда  "},
+		{name: "пустое название", actor: owner, wsName: "   ", wantErr: "name is empty"},
+		{name: "слишком длинное название", actor: owner, wsName: strings.Repeat("я", maxNameLength+1), wantErr: "name is too long"},
+		{name: "аноним", actor: rbac.Actor{}, wsName: "Команда", wantErr: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWorkspaceRepo)
+			if tt.wantErr == "" {
+				mockRepo.On("Create", mock.MatchedBy(func(w *WorkspaceStruct) bool { return w.Name == "Команда" }), tt.actor.UserID).
+					Return(&WorkspaceStruct{ID: 5, Name: "Команда"}, nil).Once()
+			}
+
+			workspace, err := NewWorkspaceService(mockRepo, fakeUsers{}, &fakeMailer{}).CreateWorkspace(tt.actor, CreateWorkspaceParams{Name: tt.wsName})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, rbac.WorkspaceOwner, workspace.Role)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkspaceVisibility(t *testing.T) {
+	service := NewWorkspaceService(newRepo(), fakeUsers{}, &fakeMailer{})
+
+	workspace, err := service.GetWorkspace(guest, 5)
+	require.NoError(t, err)
+	assert.Equal(t, rbac.WorkspaceGuest, workspace.Role)
+
+	// чужое пространство выглядит как несуществующее
+	_, err = service.GetWorkspace(stranger, 5)
+	assert.EqualError(t, err, "workspace not found")
+	_, err = service.GetMembers(stranger, 5)
+	assert.EqualError(t, err, "workspace not found")
+
+	// глобальный админ видит любое пространство, хоть и не состоит в нем
+	workspace, err = service.GetWorkspace(admin, 5)
+	require.NoError(t, err)
+	assert.Empty(t, workspace.Role)
+}
+
+func TestSetMemberRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		userID  uint
+		role    string
+		wantErr string
+	}{
+		{name: "владелец назначает админа", actor: owner, userID: 3, role: "admin"},
+		{name: "админ делает участника гостем", actor: manager, userID: 3, role: "guest"},
+		{name: "админ не назначает админов", actor: manager, userID: 3, role: "admin", wantErr: "forbidden"},
+		{name: "админ не трогает владельца", actor: manager, userID: 1, role: "member", wantErr: "forbidden"},
+		{name: "участник не меняет роли", actor: member, userID: 4, role: "member", wantErr: "forbidden"},
+		{name: "неизвестная роль", actor: owner, userID: 3, role: "viewer", wantErr: "invalid role"},
+		{name: "не участник", actor: owner, userID: 9, role: "member", wantErr: "member not found"},
+		{name: "посторонний", actor: stranger, userID: 3, role: "guest", wantErr: "workspace not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newRepo()
+			if tt.wantErr == "" {
+				mockRepo.On("SetMemberRole", uint(5), tt.userID, tt.role).
+					Return(&MemberStruct{WorkspaceID: 5, UserID: tt.userID, Role: tt.role}, nil).Once()
+			}
+
+			result, err := NewWorkspaceService(mockRepo, fakeUsers{}, &fakeMailer{}).SetMemberRole(tt.actor, 5, tt.userID, tt.role)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "SetMemberRole", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, rbac.WorkspaceRole(tt.role), result.Role)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   rbac.Actor
+		userID  uint
+		wantErr string
+	}{
+		{name: "гость уходит сам", actor: guest, userID: 4},
+		{name: "админ убирает участника", actor: manager, userID: 3},
+		{name: "админ не убирает владельца", actor: manager, userID: 1, wantErr: "forbidden"},
+		{name: "участник не убирает других", actor: member, userID: 4, wantErr: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newRepo()
+			if tt.wantErr == "" {
+				mockRepo.On("RemoveMember", uint(5), tt.userID).Return(nil).Once()
+			}
+
+			err := NewWorkspaceService(mockRepo, fakeUsers{}, &fakeMailer{}).RemoveMember(tt.actor, 5, tt.userID)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteWorkspace(t *testing.T) {
+	mockRepo := newRepo()
+	mockRepo.On("Delete", uint(5)).Return(errors.New("workspace is not empty")).Once()
+	service := NewWorkspaceService(mockRepo, fakeUsers{}, &fakeMailer{})
+
+	assert.EqualError(t, service.DeleteWorkspace(manager, 5), "forbidden")
+	assert.EqualError(t, service.DeleteWorkspace(owner, 5), "workspace is not empty")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInvitation(t *testing.T) {
+	now := time.Date(2025, 12, 18, 12, 0, 0, 0, time.UTC)
+	verified := now.Add(-time.Hour)
+	users := fakeUsers{
+		3:  {ID: 3, Email: "member@example.com"},
+		10: {ID: 10, Email: "new@example.com", EmailVerifiedAt: &verified},
+		11: {ID: 11, Email: "other@example.com", EmailVerifiedAt: &verified},
+		12: {ID: 12, Email: "unverified@example.com"},
+	}
+
+	newService := func(m *MockWorkspaceRepo, mail *fakeMailer) *WorkspaceService {
+		service := NewWorkspaceService(m, users, mail).WithInvitations("https://app.example.com/join", time.Hour)
+		service.now = func() time.Time { return now }
+		return service
+	}
+
+	t.Run("приглашение уходит письмом со ссылкой", func(t *testing.T) {
+		mockRepo := newRepo()
+		mockRepo.On("CreateInvitation", mock.MatchedBy(func(i *InvitationStruct) bool {
+			return i.Email == "new@example.com" && i.Role == "member" && i.InvitedBy == 2 && i.ExpiresAt.Equal(now.Add(time.Hour))
+		})).Return(&InvitationStruct{ID: 1, WorkspaceID: 5, Email: "new@example.com", Role: "member"}, nil).Once()
+		mail := &fakeMailer{}
+
+		invitation, err := newService(mockRepo, mail).Invite(manager, 5, InviteParams{Email: " New@Example.com ", Role: "member"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), invitation.ID)
+		require.Len(t, mail.sent, 1)
+		assert.Equal(t, "new@example.com", mail.sent[0].To)
+
+		// в бд хранится только хэш того токена, что ушел в письме
+		token := tokenFromMail(t, mail.sent[0].Body)
+		saved := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(0).(*InvitationStruct)
+		assert.Equal(t, hashToken(token), saved.TokenHash)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибки приглашения", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			actor   rbac.Actor
+			params  InviteParams
+			wantErr string
+		}{
+			{name: "участник не приглашает", actor: member, params: InviteParams{Email: "new@example.com", Role: "guest"}, wantErr: "forbidden"},
+			{name: "админ не приглашает владельцев", actor: manager, params: InviteParams{Email: "new@example.com", Role: "owner"}, wantErr: "forbidden"},
+			{name: "уже участник", actor: owner, params: InviteParams{Email: "member@example.com", Role: "guest"}, wantErr: "user is already a member"},
+			{name: "кривой email", actor: owner, params: InviteParams{Email: "not-an-email", Role: "guest"}, wantErr: "invalid email"},
+			{name: "неизвестная роль", actor: owner, params: InviteParams{Email: "new@example.com", Role: "viewer"}, wantErr: "invalid role"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := newRepo()
+				mail := &fakeMailer{}
+
+				_, err := newService(mockRepo, mail).Invite(tt.actor, 5, tt.params)
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, mail.sent)
+				mockRepo.AssertNotCalled(t, "CreateInvitation", mock.Anything)
+			})
+		}
+	})
+
+	t.Run("принятие приглашения", func(t *testing.T) {
+		pending := InvitationStruct{ID: 1, WorkspaceID: 5, Email: "new@example.com", Role: "member", TokenHash: hashToken("token"), ExpiresAt: now.Add(time.Hour)}
+		expired := InvitationStruct{ID: 2, WorkspaceID: 5, Email: "new@example.com", Role: "member", TokenHash: hashToken("old"), ExpiresAt: now.Add(-time.Minute)}
+
+		tests := []struct {
+			name       string
+			actor      rbac.Actor
+			token      string
+			wantErr    string
+			wantAccept bool
+		}{
+			{name: "приглашенный принимает", actor: rbac.Actor{UserID: 10, Role: rbac.RoleUser}, token: "token", wantAccept: true},
+			{name: "другой пользователь", actor: rbac.Actor{UserID: 11, Role: rbac.RoleUser}, token: "token", wantErr: "invitation is for another email"},
+			{name: "просроченное приглашение", actor: rbac.Actor{UserID: 10, Role: rbac.RoleUser}, token: "old", wantErr: "invalid or expired invitation"},
+			{name: "неизвестный токен", actor: rbac.Actor{UserID: 10, Role: rbac.RoleUser}, token: "nope", wantErr: "invalid or expired invitation"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := newRepo()
+				mockRepo.On("GetInvitationByToken", hashToken("token")).Return(pending, nil).Maybe()
+				mockRepo.On("GetInvitationByToken", hashToken("old")).Return(expired, nil).Maybe()
+				mockRepo.On("GetInvitationByToken", hashToken("nope")).Return(InvitationStruct{}, gorm.ErrRecordNotFound).Maybe()
+				if tt.wantAccept {
+					mockRepo.On("AcceptInvitation", mock.Anything, tt.actor.UserID).Return(nil).Once()
+					// после принятия пользователь уже участник
+					mockRepo.On("GetMember", uint(5), tt.actor.UserID).Unset()
+					mockRepo.On("GetMember", uint(5), tt.actor.UserID).Return(MemberStruct{WorkspaceID: 5, UserID: tt.actor.UserID, Role: "member"}, nil)
+				}
+
+				workspace, err := newService(mockRepo, &fakeMailer{}).AcceptInvitation(tt.actor, tt.token)
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+					mockRepo.AssertNotCalled(t, "AcceptInvitation", mock.Anything, mock.Anything)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, rbac.WorkspaceMember, workspace.Role)
+				mockRepo.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("неподтвержденный email не принимает приглашение", func(t *testing.T) {
+		mockRepo := newRepo()
+		mockRepo.On("GetInvitationByToken", hashToken("token")).
+			Return(InvitationStruct{ID: 3, WorkspaceID: 5, Email: "unverified@example.com", Role: "guest", ExpiresAt: now.Add(time.Hour)}, nil)
+
+		_, err := newService(mockRepo, &fakeMailer{}).AcceptInvitation(rbac.Actor{UserID: 12, Role: rbac.RoleUser}, "token")
+		assert.EqualError(t, err, "email is not verified")
+		mockRepo.AssertNotCalled(t, "AcceptInvitation", mock.Anything, mock.Anything)
+	})
+}
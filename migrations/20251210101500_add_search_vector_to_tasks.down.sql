@@ -0,0 +1,5 @@
+-- Откат: удаляем индекс, триггер, функцию и колонку
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+DROP TRIGGER IF EXISTS trg_task_structs_search_vector ON task_structs;
+DROP FUNCTION IF EXISTS task_structs_search_vector_update();
+ALTER TABLE task_structs DROP COLUMN search_vector;
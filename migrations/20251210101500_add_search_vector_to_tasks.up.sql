@@ -0,0 +1,20 @@
+-- Полнотекстовый поиск по задачам:
+-- колонка search_vector хранит уже разобранный текст задачи (tsvector),
+-- по ней строится GIN-индекс, а актуальность поддерживает триггер
+ALTER TABLE task_structs ADD COLUMN search_vector tsvector;
+
+CREATE FUNCTION task_structs_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := to_tsvector('simple', coalesce(NEW.task, ''));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_task_structs_search_vector
+BEFORE INSERT OR UPDATE OF task ON task_structs
+FOR EACH ROW EXECUTE FUNCTION task_structs_search_vector_update();
+
+-- заполняем колонку для уже существующих задач
+UPDATE task_structs SET search_vector = to_tsvector('simple', coalesce(task, ''));
+
+CREATE INDEX idx_tasks_search_vector ON task_structs USING GIN (search_vector);
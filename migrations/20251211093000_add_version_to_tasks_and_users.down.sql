@@ -0,0 +1,3 @@
+-- Откат: удаляем колонки версий
+ALTER TABLE user_structs DROP COLUMN version;
+ALTER TABLE task_structs DROP COLUMN version;
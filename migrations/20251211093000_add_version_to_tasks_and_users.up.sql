@@ -0,0 +1,5 @@
+-- Версия строки для оптимистичной блокировки:
+-- каждое изменение увеличивает version на 1, а UPDATE/DELETE выполняются только
+-- при совпадении версии (UPDATE ... WHERE id = ? AND version = ?)
+ALTER TABLE task_structs ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE user_structs ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,19 @@
+-- Ключи идемпотентности для POST-запросов:
+-- храним ключ из заголовка Idempotency-Key, хеш тела запроса и сохраненный ответ,
+-- чтобы повтор того же запроса получил тот же ответ, а не создал дубликат.
+-- status_code = 0 означает, что запрос еще выполняется
+CREATE TABLE idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL,
+    scope VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_headers TEXT NOT NULL DEFAULT '{}',
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- один ключ может использоваться только один раз в рамках одного маршрута
+CREATE UNIQUE INDEX idx_idempotency_keys_key_scope ON idempotency_keys(idempotency_key, scope);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,3 @@
+-- Откат: возвращаем обычное (регистрозависимое) ограничение уникальности
+DROP INDEX IF EXISTS idx_users_email_lower;
+ALTER TABLE user_structs ADD CONSTRAINT user_structs_email_key UNIQUE (email);
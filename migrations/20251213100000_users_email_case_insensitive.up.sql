@@ -0,0 +1,11 @@
+-- Email уникален без учета регистра:
+-- вместо UNIQUE(email) - уникальный индекс по lower(email)
+-- (если в таблице уже есть адреса, отличающиеся только регистром, миграция упадет - их нужно разобрать вручную)
+ALTER TABLE user_structs DROP CONSTRAINT IF EXISTS user_structs_email_key;
+
+-- домен нормализуем так же, как это делает сервис (локальная часть остается как есть)
+UPDATE user_structs
+SET email = split_part(email, '@', 1) || '@' || lower(split_part(email, '@', 2))
+WHERE email LIKE '%@%' AND split_part(email, '@', 2) <> lower(split_part(email, '@', 2));
+
+CREATE UNIQUE INDEX idx_users_email_lower ON user_structs (lower(email));
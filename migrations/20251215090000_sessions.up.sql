@@ -0,0 +1,20 @@
+-- Сессии входа: одна строка на каждый выданный refresh-токен (храним только sha256 токена).
+-- Все токены одной сессии имеют общий family_id: при обновлении старая строка помечается rotated_at,
+-- и повторное предъявление такого токена отзывает все семейство.
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    family_id UUID NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    refresh_token_hash CHAR(64) NOT NULL,
+    device_name VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_sessions_refresh_token_hash ON sessions(refresh_token_hash);
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE user_structs DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE user_structs DROP CONSTRAINT IF EXISTS user_structs_role_check;
+ALTER TABLE user_structs DROP COLUMN IF EXISTS role;
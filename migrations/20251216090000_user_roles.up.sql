@@ -0,0 +1,8 @@
+-- Роли и отключение аккаунтов:
+-- role - user (только свои данные) или admin (все данные и управление пользователями)
+-- disabled_at - время отключения аккаунта админом (NULL - аккаунт активен)
+-- первого админа назначаем вручную:
+--   UPDATE user_structs SET role = 'admin' WHERE lower(email) = lower('admin@example.com');
+ALTER TABLE user_structs ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE user_structs ADD CONSTRAINT user_structs_role_check CHECK (role IN ('user', 'admin'));
+ALTER TABLE user_structs ADD COLUMN disabled_at TIMESTAMP DEFAULT NULL;
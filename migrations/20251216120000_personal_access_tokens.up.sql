@@ -0,0 +1,18 @@
+-- Персональные токены для скриптов и CI:
+-- храним только sha256 токена и его первые символы (чтобы пользователь узнал токен в списке),
+-- скоупы - через пробел; expires_at NULL - бессрочный токен
+CREATE TABLE personal_access_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    scopes TEXT NOT NULL,
+    expires_at TIMESTAMP DEFAULT NULL,
+    last_used_at TIMESTAMP DEFAULT NULL,
+    revoked_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens(token_hash);
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
@@ -0,0 +1,27 @@
+-- Вход через внешний OIDC-провайдер.
+-- oidc_login_states - начатые входы: state (только sha256), nonce и code_verifier (PKCE),
+-- строка удаляется при возврате с провайдера, живет несколько минут
+CREATE TABLE oidc_login_states (
+    id SERIAL PRIMARY KEY,
+    state_hash CHAR(64) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_oidc_login_states_state_hash ON oidc_login_states(state_hash);
+CREATE INDEX idx_oidc_login_states_expires_at ON oidc_login_states(expires_at);
+
+-- user_identities - внешние аккаунты пользователей: (issuer, subject) однозначно определяет аккаунт у провайдера
+CREATE TABLE user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_user_identities_issuer_subject ON user_identities(issuer, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,5 @@
+ALTER TABLE user_structs
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS failed_logins;
+
+DROP TABLE IF EXISTS auth_events;
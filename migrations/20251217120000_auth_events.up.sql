@@ -0,0 +1,20 @@
+-- Журнал попыток входа и блокировка после серии неудач.
+-- user_id пустой, если пытались войти с несуществующим email;
+-- при удалении пользователя его события остаются (user_id обнуляется) - журнал нужен и после удаления
+CREATE TABLE auth_events (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES user_structs(id) ON DELETE SET NULL,
+    email VARCHAR(255) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    reason VARCHAR(32),
+    ip VARCHAR(64),
+    user_agent VARCHAR(512),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_events_user_id_created_at ON auth_events(user_id, created_at DESC);
+
+-- счетчик неудачных входов подряд и срок блокировки
+ALTER TABLE user_structs
+    ADD COLUMN failed_logins INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMP DEFAULT NULL;
@@ -0,0 +1,36 @@
+-- Двухфакторная аутентификация (TOTP).
+-- user_totp - секрет приложения-аутентификатора, зашифрованный ключом из конфига (AES-256-GCM);
+-- confirmed_at пустой, пока пользователь не подтвердил подключение первым кодом
+CREATE TABLE user_totp (
+    user_id INTEGER PRIMARY KEY REFERENCES user_structs(id) ON DELETE CASCADE,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    confirmed_at TIMESTAMP DEFAULT NULL,
+    last_used_step BIGINT DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- recovery_codes - одноразовые коды восстановления (только sha256)
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_recovery_codes_user_id_code_hash ON recovery_codes(user_id, code_hash);
+
+-- mfa_challenges - входы, где пароль уже проверен и ждем код (токен - только sha256), живут несколько минут
+CREATE TABLE mfa_challenges (
+    id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    device_name VARCHAR(100),
+    user_agent VARCHAR(512),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_mfa_challenges_token_hash ON mfa_challenges(token_hash);
+CREATE INDEX idx_mfa_challenges_expires_at ON mfa_challenges(expires_at);
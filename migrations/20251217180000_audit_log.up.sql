@@ -0,0 +1,20 @@
+-- Журнал аудита изменений задач и пользователей.
+-- before / after - снимки сущности (без секретов), changes - изменившиеся поля {"поле": {"from": ..., "to": ...}};
+-- actor_id пустой, если изменение сделала система; без внешнего ключа - запись должна пережить удаление пользователя
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor_id INTEGER DEFAULT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    before JSONB DEFAULT NULL,
+    after JSONB DEFAULT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at DESC);
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX idx_audit_log_request_id ON audit_log(request_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC);
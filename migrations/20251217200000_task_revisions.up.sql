@@ -0,0 +1,21 @@
+-- Ревизии задач: состояние задачи после каждого изменения, revision = версия задачи.
+-- restored_from - ревизия, к которой откатили задачу (откат - новая ревизия, история линейная)
+CREATE TABLE task_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES task_structs(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    task TEXT NOT NULL,
+    is_done BOOLEAN NOT NULL DEFAULT FALSE,
+    actor_id INTEGER DEFAULT NULL,
+    restored_from INTEGER DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_task_revisions_task_id_revision ON task_revisions(task_id, revision);
+
+-- у уже существующих задач история начинается с текущего состояния
+INSERT INTO task_revisions (task_id, revision, user_id, task, is_done, created_at)
+SELECT id, version, user_id, task, COALESCE(is_done, FALSE), updated_at
+FROM task_structs
+WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- Комментарии к задачам.
+-- удаленный комментарий остается надгробием: deleted_at заполнен, текст стерт
+CREATE TABLE task_comments (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES task_structs(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    body TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+-- лента комментариев задачи
+CREATE INDEX idx_task_comments_task_id_created_at ON task_comments(task_id, created_at);
+
+-- счетчики комментариев у списка задач (только неудаленные)
+CREATE INDEX idx_task_comments_task_id_active ON task_comments(task_id) WHERE deleted_at IS NULL;
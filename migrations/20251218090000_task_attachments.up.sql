@@ -0,0 +1,16 @@
+-- Файлы, приложенные к задачам.
+-- здесь только метаданные: содержимое лежит в хранилище (каталог или S3) под storage_key
+CREATE TABLE task_attachments (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES task_structs(id) ON DELETE CASCADE,
+    uploader_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    content_type TEXT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- вложения задачи
+CREATE INDEX idx_task_attachments_task_id_created_at ON task_attachments(task_id, created_at);
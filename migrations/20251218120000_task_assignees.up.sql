@@ -0,0 +1,12 @@
+-- Исполнители задач (многие ко многим).
+-- владелец задачи остается в task_structs.user_id и сюда не попадает
+CREATE TABLE task_assignees (
+    task_id INTEGER NOT NULL REFERENCES task_structs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    assigned_by INTEGER DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+-- задачи, назначенные пользователю (GET /users/{id}/tasks?role=assignee)
+CREATE INDEX idx_task_assignees_user_id ON task_assignees(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_task_structs_workspace_id;
+ALTER TABLE task_structs DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,38 @@
+-- Рабочие пространства команд: участники с ролями и приглашения по email.
+CREATE TABLE workspaces (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE workspace_members (
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'guest')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- пространства пользователя (и фильтр задач по членству)
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- в бд только sha256 токена из письма; принятое приглашение остается с accepted_at
+CREATE TABLE workspace_invitations (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'guest')),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    invited_by INTEGER NOT NULL REFERENCES user_structs(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_workspace_invitations_workspace_id ON workspace_invitations(workspace_id) WHERE accepted_at IS NULL;
+
+-- задачи пространства (NULL - личная задача)
+-- пространство с задачами удалить нельзя: задачи не должны исчезать вместе с ним незаметно
+ALTER TABLE task_structs ADD COLUMN workspace_id INTEGER DEFAULT NULL REFERENCES workspaces(id) ON DELETE RESTRICT;
+CREATE INDEX idx_task_structs_workspace_id ON task_structs(workspace_id);
@@ -0,0 +1 @@
+ALTER TABLE task_structs DROP COLUMN IF EXISTS position;
@@ -0,0 +1,2 @@
+-- место задачи на доске (команда reorder по WebSocket); клиенты упорядочивают по (position, id)
+ALTER TABLE task_structs ADD COLUMN position BIGINT NOT NULL DEFAULT 0;